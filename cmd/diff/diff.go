@@ -0,0 +1,315 @@
+package diff
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	awsinternal "cloudsift/internal/aws"
+
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	outputFormat string // Output format for the comparison (text or json)
+}
+
+// scanFile mirrors the per-account JSON written by the scan command
+type scanFile struct {
+	AccountID   string                             `json:"account_id"`
+	AccountName string                             `json:"account_name"`
+	Results     map[string]awsinternal.ScanResults `json:"results"`
+}
+
+// finding identifies a single flagged resource in a scan result file
+type finding struct {
+	AccountID    string  `json:"account_id"`
+	AccountName  string  `json:"account_name"`
+	Scanner      string  `json:"scanner"`
+	Region       string  `json:"region"`
+	ResourceID   string  `json:"resource_id"`
+	ResourceName string  `json:"resource_name"`
+	ARN          string  `json:"arn,omitempty"`
+	Reason       string  `json:"reason"`
+	MonthlyCost  float64 `json:"monthly_cost"`
+}
+
+// key returns the identity used to match a finding between two scans
+func (f finding) key() string {
+	return f.AccountID + "\x00" + f.Scanner + "\x00" + f.Region + "\x00" + f.ResourceID
+}
+
+// costChange is the change in estimated monthly cost for one account and scanner
+type costChange struct {
+	AccountID   string  `json:"account_id"`
+	AccountName string  `json:"account_name"`
+	Scanner     string  `json:"scanner"`
+	OldMonthly  float64 `json:"old_monthly_cost"`
+	NewMonthly  float64 `json:"new_monthly_cost"`
+	NetMonthly  float64 `json:"net_monthly_change"`
+	OldFindings int     `json:"old_findings"`
+	NewFindings int     `json:"new_findings"`
+}
+
+// diffReport is the comparison between two scans
+type diffReport struct {
+	NewFindings      []finding    `json:"new_findings"`
+	ResolvedFindings []finding    `json:"resolved_findings"`
+	CostChanges      []costChange `json:"cost_changes"`
+	NetMonthlyChange float64      `json:"net_monthly_change"`
+}
+
+// NewDiffCmd creates and returns the diff command
+func NewDiffCmd() *cobra.Command {
+	opts := &diffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Compare two scan result files",
+		Long: `Compare two JSON scan result files and report how waste has changed between them.
+
+Both files must be JSON output from the scan command, either gzipped (.json.gz) as written
+by the scan command or uncompressed. The comparison lists resources that are newly flagged,
+resources that are no longer flagged, and the net change in estimated monthly cost per
+account and scanner.`,
+		Example: `  # Compare last week's scan with today's
+  cloudsift diff output/2024/01/01/123456789012/09-00-00+0000.json.gz output/2024/01/08/123456789012/09-00-00+0000.json.gz
+
+  # Output the comparison as JSON
+  cloudsift diff old.json new.json --output-format json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.outputFormat != "text" && opts.outputFormat != "json" {
+				return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", opts.outputFormat)
+			}
+			return runDiff(cmd.OutOrStdout(), args[0], args[1], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "text", "Output format (text or json)")
+
+	return cmd
+}
+
+func runDiff(w io.Writer, oldPath, newPath string, opts *diffOptions) error {
+	oldScan, err := loadScanFile(oldPath)
+	if err != nil {
+		return err
+	}
+	newScan, err := loadScanFile(newPath)
+	if err != nil {
+		return err
+	}
+
+	report := compareScans(collectFindings(oldScan), collectFindings(newScan))
+
+	if opts.outputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	return writeSummary(w, report)
+}
+
+// combinedScanFile is either a single account's scan file or a --combined-output report
+type combinedScanFile struct {
+	scanFile
+	Accounts map[string]scanFile `json:"accounts"`
+}
+
+// loadScanFile reads a scan result file, which may be gzipped and may hold a single account's
+// result, a list of them or a combined report of every account
+func loadScanFile(path string) ([]scanFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// Scan output is gzipped; detect it by the gzip magic number rather than the file extension
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+		defer gz.Close()
+
+		data, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var scans []scanFile
+		if err := json.Unmarshal(trimmed, &scans); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return scans, nil
+	}
+
+	var scan combinedScanFile
+	if err := json.Unmarshal(trimmed, &scan); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// A combined report holds every account keyed by account ID
+	if scan.Accounts != nil {
+		scans := make([]scanFile, 0, len(scan.Accounts))
+		for _, account := range scan.Accounts {
+			scans = append(scans, account)
+		}
+		return scans, nil
+	}
+	return []scanFile{scan.scanFile}, nil
+}
+
+// monthlyCost returns the estimated monthly cost of a finding decoded from JSON, in US dollars so
+// scans reported in different currencies can be compared
+func monthlyCost(result awsinternal.ScanResult) float64 {
+	total, ok := result.Cost["total_usd"].(map[string]interface{})
+	if !ok {
+		total, ok = result.Cost["total"].(map[string]interface{})
+	}
+	if !ok {
+		return 0
+	}
+	monthly, _ := total["monthly_rate"].(float64)
+	return monthly
+}
+
+// collectFindings flattens scan result files into findings
+func collectFindings(scans []scanFile) []finding {
+	var findings []finding
+	for _, scan := range scans {
+		for scannerLabel, results := range scan.Results {
+			for _, result := range results {
+				region := result.SavedRegion()
+				findings = append(findings, finding{
+					AccountID:    scan.AccountID,
+					AccountName:  scan.AccountName,
+					Scanner:      scannerLabel,
+					Region:       region,
+					ResourceID:   result.ResourceID,
+					ResourceName: result.ResourceName,
+					ARN:          result.ARN,
+					Reason:       result.Reason,
+					MonthlyCost:  monthlyCost(result),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// compareScans reports findings that appear only in newFindings or only in oldFindings, along
+// with the change in monthly cost per account and scanner
+func compareScans(oldFindings, newFindings []finding) diffReport {
+	report := diffReport{
+		NewFindings:      []finding{},
+		ResolvedFindings: []finding{},
+		CostChanges:      []costChange{},
+	}
+
+	oldKeys := make(map[string]bool, len(oldFindings))
+	for _, f := range oldFindings {
+		oldKeys[f.key()] = true
+	}
+	newKeys := make(map[string]bool, len(newFindings))
+	for _, f := range newFindings {
+		newKeys[f.key()] = true
+	}
+
+	changes := make(map[string]*costChange)
+	changeFor := func(f finding) *costChange {
+		key := f.AccountID + "\x00" + f.Scanner
+		if changes[key] == nil {
+			changes[key] = &costChange{
+				AccountID:   f.AccountID,
+				AccountName: f.AccountName,
+				Scanner:     f.Scanner,
+			}
+		}
+		return changes[key]
+	}
+
+	for _, f := range oldFindings {
+		change := changeFor(f)
+		change.OldMonthly += f.MonthlyCost
+		change.OldFindings++
+		if !newKeys[f.key()] {
+			report.ResolvedFindings = append(report.ResolvedFindings, f)
+		}
+	}
+	for _, f := range newFindings {
+		change := changeFor(f)
+		// Prefer the newer account name in case the account was renamed
+		change.AccountName = f.AccountName
+		change.NewMonthly += f.MonthlyCost
+		change.NewFindings++
+		if !oldKeys[f.key()] {
+			report.NewFindings = append(report.NewFindings, f)
+		}
+	}
+
+	for _, change := range changes {
+		change.NetMonthly = change.NewMonthly - change.OldMonthly
+		report.NetMonthlyChange += change.NetMonthly
+		report.CostChanges = append(report.CostChanges, *change)
+	}
+
+	sortFindings(report.NewFindings)
+	sortFindings(report.ResolvedFindings)
+	sort.Slice(report.CostChanges, func(i, j int) bool {
+		a, b := report.CostChanges[i], report.CostChanges[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		return a.Scanner < b.Scanner
+	})
+
+	return report
+}
+
+// sortFindings orders findings so the output is stable between runs
+func sortFindings(findings []finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].key() < findings[j].key()
+	})
+}
+
+// writeSummary writes a human readable summary of the comparison
+func writeSummary(w io.Writer, report diffReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	writeFindings := func(title string, findings []finding) {
+		fmt.Fprintf(tw, "%s (%d)\n", title, len(findings))
+		for _, f := range findings {
+			name := f.ResourceID
+			if f.ResourceName != "" && f.ResourceName != f.ResourceID {
+				name = fmt.Sprintf("%s (%s)", f.ResourceName, f.ResourceID)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t$%.2f/mo\n", f.AccountName, f.Scanner, f.Region, name, f.MonthlyCost)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	writeFindings("New findings", report.NewFindings)
+	writeFindings("Resolved findings", report.ResolvedFindings)
+
+	fmt.Fprintln(tw, "Monthly cost by account and scanner")
+	fmt.Fprintln(tw, "  ACCOUNT\tSCANNER\tOLD\tNEW\tCHANGE")
+	for _, change := range report.CostChanges {
+		fmt.Fprintf(tw, "  %s\t%s\t$%.2f\t$%.2f\t%+.2f\n", change.AccountName, change.Scanner, change.OldMonthly, change.NewMonthly, change.NetMonthly)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Net monthly change: %+.2f USD\n", report.NetMonthlyChange)
+
+	return tw.Flush()
+}
@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeScanFile writes a scan result file in the format produced by the scan command
+func writeScanFile(t *testing.T, path string, scan map[string]interface{}, compress bool) {
+	data, err := json.Marshal(scan)
+	require.NoError(t, err)
+
+	if compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err = gz.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		data = buf.Bytes()
+	}
+
+	require.NoError(t, os.WriteFile(path, data, 0644))
+}
+
+// scanResultJSON builds a finding as it appears in scan output
+func scanResultJSON(id, region string, monthly float64) map[string]interface{} {
+	return map[string]interface{}{
+		"resource_id":   id,
+		"resource_name": id,
+		"details":       map[string]interface{}{"region": region},
+		"cost":          map[string]interface{}{"total": map[string]interface{}{"monthly_rate": monthly}},
+	}
+}
+
+// TestNewDiffCmd tests the creation of the diff command
+func TestNewDiffCmd(t *testing.T) {
+	cmd := NewDiffCmd()
+	assert.NotNil(t, cmd)
+	assert.Equal(t, "diff <old.json> <new.json>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short)
+	assert.NotEmpty(t, cmd.Long)
+	assert.NotEmpty(t, cmd.Example)
+
+	outputFormatFlag := cmd.Flags().Lookup("output-format")
+	assert.NotNil(t, outputFormatFlag)
+	assert.Equal(t, "text", outputFormatFlag.DefValue)
+
+	cmd.SetArgs([]string{"only-one.json"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	assert.Error(t, cmd.Execute())
+}
+
+// TestCompareScans tests matching findings between two scans
+func TestCompareScans(t *testing.T) {
+	oldFindings := []finding{
+		{AccountID: "111", AccountName: "prod", Scanner: "EBS Volumes", Region: "us-east-1", ResourceID: "vol-1", MonthlyCost: 10},
+		{AccountID: "111", AccountName: "prod", Scanner: "EBS Volumes", Region: "us-east-1", ResourceID: "vol-2", MonthlyCost: 5},
+	}
+	newFindings := []finding{
+		{AccountID: "111", AccountName: "prod", Scanner: "EBS Volumes", Region: "us-east-1", ResourceID: "vol-2", MonthlyCost: 5},
+		{AccountID: "111", AccountName: "prod", Scanner: "EBS Volumes", Region: "us-west-2", ResourceID: "vol-1", MonthlyCost: 8},
+		{AccountID: "222", AccountName: "dev", Scanner: "Elastic IPs", Region: "us-east-1", ResourceID: "eip-1", MonthlyCost: 3.6},
+	}
+
+	report := compareScans(oldFindings, newFindings)
+
+	// The same ID in another region is a different resource
+	require.Len(t, report.NewFindings, 2)
+	assert.Equal(t, "vol-1", report.NewFindings[0].ResourceID)
+	assert.Equal(t, "us-west-2", report.NewFindings[0].Region)
+	assert.Equal(t, "eip-1", report.NewFindings[1].ResourceID)
+
+	require.Len(t, report.ResolvedFindings, 1)
+	assert.Equal(t, "vol-1", report.ResolvedFindings[0].ResourceID)
+	assert.Equal(t, "us-east-1", report.ResolvedFindings[0].Region)
+
+	require.Len(t, report.CostChanges, 2)
+	assert.Equal(t, "111", report.CostChanges[0].AccountID)
+	assert.InDelta(t, 15.0, report.CostChanges[0].OldMonthly, 0.001)
+	assert.InDelta(t, 13.0, report.CostChanges[0].NewMonthly, 0.001)
+	assert.InDelta(t, -2.0, report.CostChanges[0].NetMonthly, 0.001)
+	assert.Equal(t, "222", report.CostChanges[1].AccountID)
+	assert.InDelta(t, 3.6, report.CostChanges[1].NetMonthly, 0.001)
+
+	assert.InDelta(t, 1.6, report.NetMonthlyChange, 0.001)
+}
+
+// TestRunDiff tests comparing scan files on disk in both output formats
+func TestRunDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldPath := filepath.Join(tmpDir, "old.json.gz")
+	newPath := filepath.Join(tmpDir, "new.json")
+
+	writeScanFile(t, oldPath, map[string]interface{}{
+		"account_id":   "111",
+		"account_name": "prod",
+		"results": map[string]interface{}{
+			"EBS Volumes": []interface{}{scanResultJSON("vol-1", "us-east-1", 10)},
+		},
+	}, true)
+	writeScanFile(t, newPath, map[string]interface{}{
+		"account_id":   "111",
+		"account_name": "prod",
+		"results": map[string]interface{}{
+			"EBS Volumes": []interface{}{scanResultJSON("vol-2", "us-east-1", 4)},
+		},
+	}, false)
+
+	var text bytes.Buffer
+	require.NoError(t, runDiff(&text, oldPath, newPath, &diffOptions{outputFormat: "text"}))
+	assert.Contains(t, text.String(), "New findings (1)")
+	assert.Contains(t, text.String(), "Resolved findings (1)")
+	assert.Contains(t, text.String(), "Net monthly change: -6.00 USD")
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, runDiff(&jsonOut, oldPath, newPath, &diffOptions{outputFormat: "json"}))
+	var report diffReport
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &report))
+	require.Len(t, report.NewFindings, 1)
+	assert.Equal(t, "vol-2", report.NewFindings[0].ResourceID)
+	require.Len(t, report.ResolvedFindings, 1)
+	assert.Equal(t, "vol-1", report.ResolvedFindings[0].ResourceID)
+	assert.InDelta(t, -6.0, report.NetMonthlyChange, 0.001)
+
+	assert.Error(t, runDiff(&text, filepath.Join(tmpDir, "missing.json"), newPath, &diffOptions{outputFormat: "text"}))
+
+	// Combined reports from --combined-output hold every account keyed by account ID
+	combinedPath := filepath.Join(tmpDir, "combined.json")
+	writeScanFile(t, combinedPath, map[string]interface{}{
+		"cloudsift_version": "dev",
+		"accounts": map[string]interface{}{
+			"111": map[string]interface{}{
+				"account_id":   "111",
+				"account_name": "prod",
+				"results": map[string]interface{}{
+					"EBS Volumes": []interface{}{scanResultJSON("vol-2", "us-east-1", 4)},
+				},
+			},
+		},
+	}, false)
+
+	var combined bytes.Buffer
+	require.NoError(t, runDiff(&combined, oldPath, combinedPath, &diffOptions{outputFormat: "text"}))
+	assert.Contains(t, combined.String(), "New findings (1)")
+	assert.Contains(t, combined.String(), "Resolved findings (1)")
+}
@@ -15,12 +15,14 @@ aws:
   profile: default  # AWS profile to use (supports SSO profiles)
   organization_role: ""  # Role name to assume for organization-wide operations
   scanner_role: ""  # Role name to assume for scanning operations
+  assume_role_chain: []  # Roles to assume in order before the organization role (names or ARNs)
 
 # Application Configuration
 app:
   max_workers: 8  # Maximum number of concurrent workers
   log_format: text  # Log output format (text or json)
   log_level: INFO  # Set logging level (DEBUG, INFO, WARN, ERROR)
+  quiet: false  # Only log errors and the final scan summary
 
 # List Command Configuration
 list:
@@ -43,11 +45,59 @@ scan:
    # - 123456789012  # Example account ID
    # - 098765432109  # Example account ID
   
-  output: filesystem  # Output type (filesystem or s3)
-  output_format: html  # Output format (json or html)
+  output: filesystem  # Output type (filesystem, s3 or http)
+  output_format: html  # Output format (json, html or junit)
   bucket: ""  # S3 bucket name (required when output=s3)
   bucket_region: ""  # S3 bucket region (required when output=s3)
   days_unused: 90  # Number of days a resource must be unused to be reported
+  scanner_timeout: 3m  # Maximum time a single scanner may run in one account and region
+  fail_over_cost: 0  # Exit with an error when estimated monthly cost of findings exceeds this amount (0 disables)
+  output_dir: ""  # Base directory for filesystem output (default: output/ for JSON, reports/ for HTML)
+  report_name: ""  # File name for the HTML or JUnit report (default: scan_report, timestamped when output_dir is set)
+  profiles: ""  # Comma-separated list of AWS profiles to scan as standalone accounts
+  ignore_file: ""  # Path to a YAML or JSON file of ignore rules, merged with the ignore lists below
+  max_tasks_per_account: 0  # Maximum scanner tasks running at once against a single account (0 disables)
+  emit_remediation: ""  # Write suggested remediation commands to this path (.json for an action list, otherwise a shell script)
+  remediation_uncomment: false  # Leave destructive commands uncommented in the remediation script
+  tui: false  # Show a live terminal progress view (text progress is used when stdout is not a terminal)
+  s3_kms_key_id: ""  # KMS key ARN used to encrypt S3 output (default: AWS managed key)
+  include_tags: ""  # Only report resources carrying at least one of these KEY=VALUE tags, e.g. "team=data"
+  filename_template: ""  # JSON output file name template, e.g. "{date}/{account_id}.json" (default: YYYY/MM/DD/<account_id>/HH-MM-SS.json.gz)
+  s3_layout: "flat"  # S3 key layout: flat, or partitioned (scan_date=/account_id= JSON lines for Athena)
+  max_tasks_per_region: 0  # Maximum scanner tasks running at once in a single region (0 disables)
+  combined_output: false  # Write one JSON file for all accounts instead of one per account
+  skip_scanners: ""  # Comma-separated list of scanners to leave out, applied after scanners
+  min_age_days: 0  # Don't report resources created fewer than this many days ago (0 disables)
+  organizational_units: ""  # Only scan accounts in these OU IDs, including nested OUs, e.g. "ou-ab12-cdef3456"
+  webhook_url: ""  # URL each account's JSON results are POSTed to with output: http
+  webhook_token: ""  # Bearer token sent with webhook requests (prefer CLOUDSIFT_SCAN_WEBHOOK_TOKEN)
+  require_all_accounts: false  # Fail instead of scanning a subset of the organization's accounts
+  report_title: ""  # Title shown at the top of the HTML report (default: CloudSift Scan Report)
+  report_logo: ""  # Image file or http(s) URL shown beside the HTML report title
+  max_results_per_scanner: 0  # Maximum findings kept per scanner in each account and region (0 disables)
+  checkpoint: ""  # File to periodically save completed scanner tasks to, for resuming a failed scan with --resume
+  currency: "USD"  # Currency to report cost estimates in, e.g. EUR (converted from USD with exchange_rate)
+  exchange_rate: 0.0  # Units of currency one US dollar buys, e.g. 0.92 for EUR
+  only_accounts_with_findings: false  # Skip per-account output for accounts without findings
+  ec2_rightsizing: false  # Recommend smaller instance types for running EC2 instances
+  actual_spend: false  # Report actual spend from Cost Explorer alongside estimates
+  s3_acl: ""  # Canned ACL for S3 output objects, e.g. bucket-owner-full-control
+  s3_storage_class: ""  # Storage class of S3 output objects, e.g. STANDARD_IA
+  apply_tags: false  # Tag flagged resources with flag_tag and the reason they were flagged
+  flag_tag: "cloudsift:flagged=true"  # KEY=VALUE tag applied with apply_tags
+  dry_run: false  # Log the tags apply_tags would apply without applying them
+  accounts_file: ""  # JSON or CSV file listing the accounts to scan instead of Organizations
+  global_region: ""  # Region for global scanners and the cost estimator; derived from the partition when empty
+  compress: true  # Gzip JSON output files and S3 objects
+  report_regions: ""  # Comma-separated list of regions to report findings from; global for global scanners, ! excludes a region
+  baseline: ""  # Previous scan's JSON output to compare each scanner's finding count and cost against
+  anomaly_threshold: 50.0  # Percent change from baseline in a scanner's finding count or cost that is flagged
+  emit_ignored: ""  # Write findings left out by the ignore rules and filters to this JSON file, with the rule that matched
+  output_concurrency: 10  # Number of accounts whose results are written or uploaded at once
+  emit_cloudwatch: false  # Publish finding counts and savings as CloudWatch custom metrics
+  session_duration: 1h  # How long each assumed-role session lasts before it is refreshed
+  profile_region_map: ""  # Comma-separated REGION=PROFILE pairs scanning regions with their own profile or role ARN
+  min_confidence: "low"  # Only report findings with at least this confidence: low, medium or high
 
   # Ignore list configuration
   # Resources matching any of these criteria will be excluded from scan results
@@ -77,7 +127,25 @@ scan:
     tags:
       # Environment: production
       # KeepAlive: true
-      # Project: critical-service`
+      # Project: critical-service
+
+    # Ignore rules scoped to specific scanners (by argument name or label) and account IDs, so
+    # one team's rules don't hide another team's findings
+    # rules:
+    #   - scanners: [ebs-volumes, ebs-snapshots]
+    #     tags:
+    #       team: storage
+    #   - scanners: [rds-instances]
+    #     accounts: ["123456789012"]
+    #     resource_names: [reporting-replica]
+
+# Named scan presets, run with: cloudsift scan --preset <name>
+# Each preset takes the same settings as the scan section; command line flags override them
+# scans:
+#   nightly:
+#     scanners: [ebs-volumes, ebs-snapshots]
+#     days_unused: 30
+#     output_format: json`
 
 // NewConfigCmd creates the config subcommand
 func NewConfigCmd() *cobra.Command {
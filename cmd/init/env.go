@@ -27,6 +27,10 @@ CLOUDSIFT_AWS_ORGANIZATION_ROLE=
 # Required when scanning organization accounts
 CLOUDSIFT_AWS_SCANNER_ROLE=
 
+# Comma-separated roles to assume in order before the organization role, e.g. to pass
+# through a security tooling account. Entries are role ARNs or role names in the previous hop's account
+CLOUDSIFT_AWS_ASSUME_ROLE_CHAIN=
+
 #######################
 # Application Settings
 #######################
@@ -43,6 +47,10 @@ CLOUDSIFT_APP_LOG_FORMAT=text
 # Default: INFO
 CLOUDSIFT_APP_LOG_LEVEL=INFO
 
+# Only log errors and the final scan summary, overriding the log level
+# Default: false
+CLOUDSIFT_APP_QUIET=false
+
 #######################
 # Scan Configuration
 #######################
@@ -62,11 +70,11 @@ CLOUDSIFT_SCAN_SCANNERS=
 # Example: 123456789012,098765432109
 CLOUDSIFT_SCAN_ACCOUNTS=
 
-# Output type (filesystem or s3)
+# Output type (filesystem, s3 or http)
 # Default: filesystem
 CLOUDSIFT_SCAN_OUTPUT=filesystem
 
-# Output format (json or html)
+# Output format (json, html or junit)
 # Default: html
 CLOUDSIFT_SCAN_OUTPUT_FORMAT=html
 
@@ -82,6 +90,219 @@ CLOUDSIFT_SCAN_BUCKET_REGION=
 # Default: 90
 CLOUDSIFT_SCAN_DAYS_UNUSED=90
 
+# Maximum time a single scanner may run in one account and region
+# Scanners exceeding this are cancelled and recorded as failed
+# Default: 3m
+CLOUDSIFT_SCAN_SCANNER_TIMEOUT=3m
+
+# Exit with an error when the estimated monthly cost of all findings exceeds this amount in USD
+# Useful as a CI gate; 0 disables the check
+# Default: 0
+CLOUDSIFT_SCAN_FAIL_OVER_COST=0
+
+# Base directory for filesystem output
+# Leave empty to write JSON to output/ and HTML or JUnit reports to reports/
+# Example: /var/lib/cloudsift/scan-1
+CLOUDSIFT_SCAN_OUTPUT_DIR=
+
+# File name for the HTML or JUnit report
+# Leave empty to use scan_report, with a timestamp appended when an output directory is set
+CLOUDSIFT_SCAN_REPORT_NAME=
+
+# Comma-separated list of AWS profiles to scan, each treated as a standalone account
+# Leave empty to scan only the account of the current profile
+# Example: dev,staging,prod
+CLOUDSIFT_SCAN_PROFILES=
+
+# Path to a YAML or JSON file of ignore rules, merged with the ignore lists below
+# Example: ./cloudsift-ignore.yaml
+CLOUDSIFT_SCAN_IGNORE_FILE=
+
+# Maximum scanner tasks running at once against a single account
+# Tasks over the cap wait while other accounts are scanned (0 disables)
+# Default: 0
+CLOUDSIFT_SCAN_MAX_TASKS_PER_ACCOUNT=0
+
+# Path to write suggested AWS CLI remediation commands to
+# Files ending in .json get a JSON action list, anything else a shell script
+CLOUDSIFT_SCAN_EMIT_REMEDIATION=
+
+# Leave destructive commands (deletes, terminations) uncommented in the remediation script
+# Default: false
+CLOUDSIFT_SCAN_REMEDIATION_UNCOMMENT=false
+
+# Show a live view of running scanners, task progress and savings found
+# Falls back to text progress when stdout is not a terminal
+# Default: false
+CLOUDSIFT_SCAN_TUI=false
+
+# ARN of the KMS key used to encrypt S3 output
+# Uses the AWS managed aws/s3 key when empty
+CLOUDSIFT_SCAN_S3_KMS_KEY_ID=
+
+# Only report resources carrying at least one of these tags
+# Format: comma-separated KEY=VALUE pairs (case-insensitive)
+# Example: team=data,owner=analytics
+CLOUDSIFT_SCAN_INCLUDE_TAGS=
+
+# Template for JSON output file names and S3 keys
+# Fields: {account_id}, {account_name}, {date}, {year}, {month}, {day}, {time}, {timestamp}, {format}
+# Example: {date}/{account_id}.json
+CLOUDSIFT_SCAN_FILENAME_TEMPLATE=
+
+# S3 key layout: flat (one JSON file per account) or partitioned
+# partitioned writes JSON lines under scan_date=YYYY-MM-DD/account_id=<id>/ for Athena
+# Default: flat
+CLOUDSIFT_SCAN_S3_LAYOUT=flat
+
+# Maximum scanner tasks running at once in a single region
+# Tasks over the cap wait while other regions are scanned (0 disables)
+# Default: 0
+CLOUDSIFT_SCAN_MAX_TASKS_PER_REGION=0
+
+# Write one JSON file containing every account's results, keyed by account ID
+# instead of one file per account
+# Default: false
+CLOUDSIFT_SCAN_COMBINED_OUTPUT=false
+
+# Comma-separated list of scanners to leave out
+# Applied after CLOUDSIFT_SCAN_SCANNERS, so the two can be combined
+CLOUDSIFT_SCAN_SKIP_SCANNERS=
+
+# Don't report resources created fewer than this many days ago
+# Resources without a known creation time are always reported (0 disables)
+# Default: 0
+CLOUDSIFT_SCAN_MIN_AGE_DAYS=0
+
+# Only scan accounts in these organizational units, including nested OUs
+# Requires organization and scanner roles
+# Example: ou-ab12-cdef3456,ou-ab12-78901234
+CLOUDSIFT_SCAN_ORGANIZATIONAL_UNITS=
+
+# URL each account's JSON results are POSTed to
+# Required when CLOUDSIFT_SCAN_OUTPUT=http
+CLOUDSIFT_SCAN_WEBHOOK_URL=
+
+# Bearer token sent in the Authorization header of webhook requests
+CLOUDSIFT_SCAN_WEBHOOK_TOKEN=
+
+# Fail if organization accounts can't be listed or the scanner role can't be
+# assumed in every account, instead of scanning the accounts that are reachable
+# Default: false
+CLOUDSIFT_SCAN_REQUIRE_ALL_ACCOUNTS=false
+
+# Title shown at the top of the HTML report (default: CloudSift Scan Report)
+CLOUDSIFT_SCAN_REPORT_TITLE=
+
+# Image file or http(s) URL shown beside the HTML report title; files are
+# embedded in the report
+CLOUDSIFT_SCAN_REPORT_LOGO=
+
+# Maximum findings kept per scanner in each account and region; the most
+# expensive are kept and the rest are counted as truncated (0 disables)
+# Default: 0
+CLOUDSIFT_SCAN_MAX_RESULTS_PER_SCANNER=0
+
+# File to periodically save completed scanner tasks and their findings to, so
+# a failed scan can be continued with --resume
+CLOUDSIFT_SCAN_CHECKPOINT=
+
+# ISO 4217 currency to report cost estimates in, e.g. EUR; estimates are
+# converted from USD with CLOUDSIFT_SCAN_EXCHANGE_RATE
+# Default: USD
+CLOUDSIFT_SCAN_CURRENCY=USD
+
+# Units of CLOUDSIFT_SCAN_CURRENCY one US dollar buys, e.g. 0.92 for EUR
+# Default: 0
+CLOUDSIFT_SCAN_EXCHANGE_RATE=0
+
+# Only write per-account output for accounts with findings or scanner errors
+# Default: false
+CLOUDSIFT_SCAN_ONLY_ACCOUNTS_WITH_FINDINGS=false
+
+# Recommend smaller instance types in the same family for running EC2 instances,
+# based on peak utilization over the days_unused window
+# Default: false
+CLOUDSIFT_SCAN_EC2_RIGHTSIZING=false
+
+# Look up each account's actual spend on the services behind its findings in
+# Cost Explorer and report it alongside the estimates
+# Default: false
+CLOUDSIFT_SCAN_ACTUAL_SPEND=false
+
+# Canned ACL applied to S3 output objects, e.g. bucket-owner-full-control
+CLOUDSIFT_SCAN_S3_ACL=
+
+# Storage class of S3 output objects, e.g. STANDARD_IA
+# Default: STANDARD
+CLOUDSIFT_SCAN_S3_STORAGE_CLASS=
+
+# Tag each flagged resource with the flag tag and the reason it was flagged
+# after the scan (requires tagging permissions)
+# Default: false
+CLOUDSIFT_SCAN_APPLY_TAGS=false
+
+# KEY=VALUE tag applied to flagged resources with apply tags; the reason is
+# tagged under KEY:reason
+# Default: cloudsift:flagged=true
+CLOUDSIFT_SCAN_FLAG_TAG=cloudsift:flagged=true
+
+# Log the tags apply tags would apply without applying them
+# Default: false
+CLOUDSIFT_SCAN_DRY_RUN=false
+
+# JSON or CSV file listing account IDs and names to scan instead of listing
+# them through Organizations
+CLOUDSIFT_SCAN_ACCOUNTS_FILE=
+
+# Region global scanners such as IAM and the cost estimator are called in;
+# derived from the partition when empty (us-gov-west-1 in GovCloud,
+# cn-north-1 in China)
+# Default: us-east-1
+CLOUDSIFT_SCAN_GLOBAL_REGION=
+
+# Gzip JSON output files and S3 objects, appending .gz to their names;
+# false writes plain JSON
+# Default: true
+CLOUDSIFT_SCAN_COMPRESS=true
+
+# Comma-separated list of regions to report findings from after the scan.
+# Global scanners report region global; prefix a region with ! to leave it out
+CLOUDSIFT_SCAN_REPORT_REGIONS=
+
+# Previous scan's JSON output (per-account or combined, optionally gzipped).
+# Scanners whose finding count or cost changed by more than CLOUDSIFT_SCAN_ANOMALY_THRESHOLD percent are flagged
+CLOUDSIFT_SCAN_BASELINE=
+
+# Percent change from CLOUDSIFT_SCAN_BASELINE in a scanner's finding count or monthly cost that is flagged
+# Default: 50
+CLOUDSIFT_SCAN_ANOMALY_THRESHOLD=50
+
+# Write findings left out by the ignore rules and filters to this JSON file, with the rule that matched
+CLOUDSIFT_SCAN_EMIT_IGNORED=
+
+# Number of accounts whose results are written, uploaded to S3 or posted to the webhook at once
+# Default: 10
+CLOUDSIFT_SCAN_OUTPUT_CONCURRENCY=10
+
+# Publish finding counts and estimated savings as CloudWatch custom metrics in
+# the CloudSift namespace at the end of the scan
+# Default: false
+CLOUDSIFT_SCAN_EMIT_CLOUDWATCH=false
+
+# How long each assumed-role session lasts (15m to 12h, up to the role's maximum session duration)
+# Default: 1h
+CLOUDSIFT_SCAN_SESSION_DURATION=1h
+
+# Comma-separated REGION=PROFILE pairs scanning regions with their own AWS profile or role ARN
+# Leave empty to scan every region with the default credentials
+# Example: us-gov-west-1=govcloud,eu-central-1=arn:aws:iam::123456789012:role/EuScanner
+CLOUDSIFT_SCAN_PROFILE_REGION_MAP=
+
+# Only report findings with at least this confidence: low, medium or high
+# Default: low
+CLOUDSIFT_SCAN_MIN_CONFIDENCE=low
+
 #######################
 # Ignore List Configuration
 #######################
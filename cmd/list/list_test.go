@@ -101,6 +101,10 @@ func (s *testScanner) Label() string {
 	return s.label
 }
 
+func (s *testScanner) IsGlobal() bool {
+	return false
+}
+
 func (s *testScanner) Scan(opts awspkg.ScanOptions) (awspkg.ScanResults, error) {
 	return awspkg.ScanResults{}, nil
 }
@@ -3,12 +3,14 @@ package cmd
 import (
 	"strings"
 
+	"cloudsift/cmd/diff"
 	initCmd "cloudsift/cmd/init"
 	"cloudsift/cmd/list"
 	"cloudsift/cmd/scan"
 	"cloudsift/cmd/version"
 	"cloudsift/internal/config"
 	"cloudsift/internal/logging"
+	internalVersion "cloudsift/internal/version"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,6 +25,7 @@ func Execute() error {
 		Short: "CloudSift - AWS resource management tool",
 		Long: `CloudSift is a command-line tool for managing and inspecting AWS resources.
 It provides a simple interface for common AWS tasks and operations.`,
+		Version: internalVersion.String(),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Skip config initialization for certain commands
 			if cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "completion" {
@@ -41,6 +44,9 @@ It provides a simple interface for common AWS tasks and operations.`,
 			if err := viper.BindPFlag("aws.scanner_role", cmd.Root().PersistentFlags().Lookup("scanner-role")); err != nil {
 				return err
 			}
+			if err := viper.BindPFlag("aws.assume_role_chain", cmd.Root().PersistentFlags().Lookup("assume-role-chain")); err != nil {
+				return err
+			}
 			if err := viper.BindPFlag("app.max_workers", cmd.Root().PersistentFlags().Lookup("max-workers")); err != nil {
 				return err
 			}
@@ -50,6 +56,9 @@ It provides a simple interface for common AWS tasks and operations.`,
 			if err := viper.BindPFlag("app.log_level", cmd.Root().PersistentFlags().Lookup("log-level")); err != nil {
 				return err
 			}
+			if err := viper.BindPFlag("app.quiet", cmd.Root().PersistentFlags().Lookup("quiet")); err != nil {
+				return err
+			}
 
 			// Set config file if specified
 			if configFile != "" {
@@ -65,7 +74,7 @@ It provides a simple interface for common AWS tasks and operations.`,
 
 			// Check if we should enable logging
 			shouldLog := false
-			if cmd.Name() == "scan" || cmd.Name() == "list" || (cmd.Parent() != nil && (cmd.Parent().Name() == "scan" || cmd.Parent().Name() == "list")) {
+			if cmd.Name() == "scan" || cmd.Name() == "list" || cmd.Name() == "preflight" || (cmd.Parent() != nil && (cmd.Parent().Name() == "scan" || cmd.Parent().Name() == "list")) {
 				shouldLog = true
 			}
 
@@ -73,20 +82,17 @@ It provides a simple interface for common AWS tasks and operations.`,
 			config.Config.Profile = viper.GetString("aws.profile")
 			config.Config.OrganizationRole = viper.GetString("aws.organization_role")
 			config.Config.ScannerRole = viper.GetString("aws.scanner_role")
+			config.Config.AssumeRoleChain = splitRoleChain(viper.GetStringSlice("aws.assume_role_chain"))
 			config.Config.MaxWorkers = viper.GetInt("app.max_workers")
 			config.Config.LogFormat = viper.GetString("app.log_format")
 			config.Config.LogLevel = viper.GetString("app.log_level")
+			config.Config.Quiet = viper.GetBool("app.quiet")
 
-			// Log configuration sources if logging is enabled
+			// Configure logging for scan, preflight and list commands
 			if shouldLog {
-				config.LogConfigurationSources(shouldLog, cmd)
-			}
-
-			// Configure logging for scan and list commands
-			if shouldLog {
-				logFormat := logging.Text
-				if config.Config.LogFormat == "json" {
-					logFormat = logging.JSON
+				logFormat, err := logging.ParseFormat(config.Config.LogFormat)
+				if err != nil {
+					return err
 				}
 
 				level := logging.INFO
@@ -105,29 +111,61 @@ It provides a simple interface for common AWS tasks and operations.`,
 				logging.Configure(logging.LogConfig{
 					Level:  level,
 					Format: logFormat,
+					Quiet:  config.Config.Quiet,
 				})
+
+				// Log configuration sources once the format and level are known
+				config.LogConfigurationSources(shouldLog, cmd)
 			}
 
 			return nil
 		},
 	}
 
+	// --version prints the same output as the version command
+	rootCmd.SetVersionTemplate("CloudSift {{ .Version }}\n")
+
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file")
 	rootCmd.PersistentFlags().StringVar(&config.Config.LogFormat, "log-format", "text", "Log output format (text or json)")
 	rootCmd.PersistentFlags().StringVar(&config.Config.LogLevel, "log-level", "INFO", "Set logging level (DEBUG, INFO, WARN, ERROR)")
+	rootCmd.PersistentFlags().BoolVarP(&config.Config.Quiet, "quiet", "q", false, "Only log errors and the final scan summary, overriding --log-level")
 	rootCmd.PersistentFlags().IntVar(&config.Config.MaxWorkers, "max-workers", 8, "Maximum number of concurrent workers")
 	rootCmd.PersistentFlags().StringVarP(&config.Config.Profile, "profile", "p", "default", "AWS profile to use (supports SSO profiles)")
 	rootCmd.PersistentFlags().StringVar(&config.Config.OrganizationRole, "organization-role", "", "Role name to assume for organization-wide operations")
 	rootCmd.PersistentFlags().StringVar(&config.Config.ScannerRole, "scanner-role", "", "Role name to assume for scanning operations")
+	rootCmd.PersistentFlags().StringSliceVar(&config.Config.AssumeRoleChain, "assume-role-chain", nil, "Comma-separated roles to assume in order before the organization role (names or ARNs)")
 
 	// Add commands
 	rootCmd.AddCommand(
 		scan.NewScanCmd(),
+		scan.NewPreflightCmd(),
+		scan.NewConfigCmd(),
 		list.NewListCmd(),
 		version.NewVersionCmd(),
 		initCmd.NewInitCmd(),
+		diff.NewDiffCmd(),
 	)
 
 	return rootCmd.Execute()
 }
+
+// ExitCode returns the process exit code for an error returned by Execute. Scans that produced a
+// report but weren't clean have their own codes; every other error exits with 1.
+func ExitCode(err error) int {
+	return scan.ExitCode(err)
+}
+
+// splitRoleChain normalizes a role chain read from a flag, config file list or comma-separated
+// environment variable into one role per entry
+func splitRoleChain(values []string) []string {
+	var roles []string
+	for _, value := range values {
+		for _, role := range strings.Split(value, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"cloudsift/internal/config"
+	"cloudsift/internal/logging"
 )
 
 func setupRootCmd() *cobra.Command {
@@ -291,3 +293,46 @@ func TestPersistentPreRunE(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitRoleChain(t *testing.T) {
+	// Flags and config lists give one role per entry, environment variables a single comma-separated value
+	assert.Equal(t, []string{"Bastion", "arn:aws:iam::222222222222:role/Tooling"},
+		splitRoleChain([]string{"Bastion", "arn:aws:iam::222222222222:role/Tooling"}))
+	assert.Equal(t, []string{"Bastion", "Tooling"}, splitRoleChain([]string{"Bastion, Tooling,"}))
+	assert.Empty(t, splitRoleChain(nil))
+}
+
+func TestQuietJSONLogging(t *testing.T) {
+	format, err := logging.ParseFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, logging.JSON, format)
+	_, err = logging.ParseFormat("xml")
+	assert.Error(t, err)
+
+	buf := new(bytes.Buffer)
+	previous := logging.SetOutput(buf)
+	t.Cleanup(func() {
+		logging.SetOutput(previous)
+		logging.Configure(logging.LogConfig{Level: logging.INFO, Format: logging.Text})
+	})
+
+	// Quiet mode keeps errors and the final summary, and drops everything else including progress
+	logging.Configure(logging.LogConfig{Level: logging.DEBUG, Format: logging.JSON, Quiet: true})
+	logging.Info("Starting scanner", map[string]interface{}{"scanner": "EBS Volumes"})
+	logging.Warn("Throttled")
+	logging.Progress("Pending Scanners")
+	logging.Error("Scanner failed", assert.AnError, map[string]interface{}{"region": "us-east-1"})
+	logging.ScanComplete(3)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, map[string]interface{}{"region": "us-east-1"}, entry["data"])
+
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Equal(t, "Scan operation complete", entry["message"])
+	assert.Equal(t, map[string]interface{}{"total_results": float64(3)}, entry["data"])
+}
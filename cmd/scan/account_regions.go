@@ -0,0 +1,155 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// maxRegionLookups is how many accounts have their enabled regions looked up at once
+const maxRegionLookups = 10
+
+// accountRegions is the set of regions to scan in each account, keyed by account ID
+type accountRegions map[string]map[string]bool
+
+// union returns every region scanned in at least one account, sorted
+func (r accountRegions) union() []string {
+	seen := make(map[string]bool)
+	for _, regions := range r {
+		for region := range regions {
+			seen[region] = true
+		}
+	}
+	union := make([]string, 0, len(seen))
+	for region := range seen {
+		union = append(union, region)
+	}
+	sort.Strings(union)
+	return union
+}
+
+// resolveAccountRegions looks up the regions enabled in each account, in parallel, since opt-in
+// regions such as ap-south-2 are enabled per account. Each account is scanned in its enabled
+// regions, limited to requested when regions were asked for. A requested region that no account
+// has enabled is an error, as is failing to look up any account's regions. An account whose
+// lookup fails is scanned in the regions found for the others.
+func resolveAccountRegions(accounts []awsinternal.Account, sessions map[string]*session.Session, requested []string) (accountRegions, error) {
+	enabled := make(map[string][]string, len(accounts))
+	lookupErrors := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxRegionLookups)
+
+	for _, account := range accounts {
+		account := account
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			regions, err := awsinternal.GetAvailableRegions(sessions[account.ID])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lookupErrors[account.ID] = err
+				return
+			}
+			enabled[account.ID] = regions
+		}()
+	}
+	wg.Wait()
+
+	if len(enabled) == 0 {
+		for _, account := range accounts {
+			if err := lookupErrors[account.ID]; err != nil {
+				return nil, fmt.Errorf("failed to get available regions: %w", err)
+			}
+		}
+		return nil, fmt.Errorf("failed to get available regions: no accounts to scan")
+	}
+
+	result := make(accountRegions, len(accounts))
+	for accountID, regions := range enabled {
+		result[accountID] = regionSet(regions, requested)
+	}
+
+	// Regions asked for must exist somewhere, which also catches typos
+	if len(requested) > 0 {
+		union := result.union()
+		scanned := make(map[string]bool, len(union))
+		for _, region := range union {
+			scanned[region] = true
+		}
+		var unavailable []string
+		for _, region := range requested {
+			if !scanned[region] {
+				unavailable = append(unavailable, region)
+			}
+		}
+		if len(unavailable) > 0 {
+			return nil, fmt.Errorf("invalid regions: %s not enabled in any account", strings.Join(unavailable, ", "))
+		}
+	}
+
+	// Accounts whose lookup failed are scanned in the regions found elsewhere rather than skipped
+	if len(lookupErrors) > 0 {
+		fallback := result.union()
+		for _, account := range accounts {
+			err, failed := lookupErrors[account.ID]
+			if !failed {
+				continue
+			}
+			logging.Warn("Failed to get enabled regions for account; scanning the regions enabled in other accounts", map[string]interface{}{
+				"account_id":   account.ID,
+				"account_name": account.Name,
+				"error":        err.Error(),
+			})
+			result[account.ID] = regionSet(fallback, nil)
+		}
+	}
+
+	// Say which accounts leave out regions others scan, so missing findings aren't a surprise
+	union := result.union()
+	for _, account := range accounts {
+		var skipped []string
+		for _, region := range union {
+			if !result[account.ID][region] {
+				skipped = append(skipped, region)
+			}
+		}
+		if len(skipped) > 0 {
+			logging.Info("Skipping regions not enabled in account", map[string]interface{}{
+				"account_id":   account.ID,
+				"account_name": account.Name,
+				"regions":      skipped,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// regionSet returns regions as a set, limited to requested when it isn't empty
+func regionSet(regions, requested []string) map[string]bool {
+	set := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		set[region] = true
+	}
+	if len(requested) == 0 {
+		return set
+	}
+	limited := make(map[string]bool, len(requested))
+	for _, region := range requested {
+		if set[region] {
+			limited[region] = true
+		}
+	}
+	return limited
+}
@@ -0,0 +1,93 @@
+package scan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+)
+
+// actualSpendDays is how many days of actual spend are looked up, about a month so it can be
+// compared with monthly estimates
+const actualSpendDays = 30
+
+// actualSpendLookup returns an account's spend on each of the given Cost Explorer services between
+// start (inclusive) and end (exclusive), keyed by service
+type actualSpendLookup func(accountID string, services []string, start, end time.Time) (map[string]float64, error)
+
+// reconcileActualSpend records what each account with findings was billed for the services its
+// findings are billed under, alongside their estimated monthly cost. Accounts whose spend can't be
+// looked up are logged and left without it.
+func reconcileActualSpend(accountResults map[string]*scanResult, lookup actualSpendLookup, now time.Time) {
+	end := now.UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -actualSpendDays)
+
+	var totalActual, totalEstimated float64
+	reconciled := 0
+	for _, accountID := range sortedAccountIDs(accountResults) {
+		accountResult := accountResults[accountID]
+
+		spends := make(map[string]*awsinternal.ActualSpend)
+		for label, results := range accountResult.Results {
+			service, ok := awsinternal.CostExplorerService(label)
+			if !ok || len(results) == 0 {
+				continue
+			}
+			spend, ok := spends[service]
+			if !ok {
+				spend = &awsinternal.ActualSpend{
+					Service: service,
+					Start:   start.Format("2006-01-02"),
+					End:     end.Format("2006-01-02"),
+				}
+				spends[service] = spend
+			}
+			spend.ResourceTypes = append(spend.ResourceTypes, label)
+			for _, result := range results {
+				if cost := awsinternal.USDCost(result.Cost); cost != nil {
+					spend.EstimatedMonthlyCostUSD += cost.MonthlyRate
+				}
+			}
+		}
+		if len(spends) == 0 {
+			continue
+		}
+
+		services := make([]string, 0, len(spends))
+		for service := range spends {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+
+		actual, err := lookup(accountID, services, start, end)
+		if err != nil {
+			logging.Error("Failed to look up actual spend", err, map[string]interface{}{
+				"account_id": accountID,
+			})
+			continue
+		}
+
+		accountResult.ActualSpend = make([]awsinternal.ActualSpend, 0, len(services))
+		for _, service := range services {
+			spend := spends[service]
+			sort.Strings(spend.ResourceTypes)
+			spend.ActualCostUSD = math.Round(actual[service]*100) / 100
+			spend.EstimatedMonthlyCostUSD = math.Round(spend.EstimatedMonthlyCostUSD*100) / 100
+			accountResult.ActualSpend = append(accountResult.ActualSpend, *spend)
+
+			totalActual += spend.ActualCostUSD
+			totalEstimated += spend.EstimatedMonthlyCostUSD
+		}
+		reconciled++
+	}
+
+	logging.Info("Looked up actual spend from Cost Explorer", map[string]interface{}{
+		"accounts":                   reconciled,
+		"start":                      start.Format("2006-01-02"),
+		"end":                        end.Format("2006-01-02"),
+		"actual_cost_usd":            math.Round(totalActual*100) / 100,
+		"estimated_monthly_cost_usd": math.Round(totalEstimated*100) / 100,
+	})
+}
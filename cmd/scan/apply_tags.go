@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+)
+
+// defaultFlagTag is the tag --apply-tags applies to flagged resources
+const defaultFlagTag = "cloudsift:flagged=true"
+
+// flagReasonSuffix is appended to the flag tag's key for the tag holding the finding's reason
+const flagReasonSuffix = ":reason"
+
+// resourceTagger applies tags to the resource with the given ARN in an account
+type resourceTagger func(accountID, resourceARN string, tags map[string]string) error
+
+// parseFlagTag splits a KEY=VALUE tag, rejecting keys and values AWS won't accept
+func parseFlagTag(tag string) (string, string, error) {
+	key, value, ok := strings.Cut(tag, "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid --flag-tag %q: must be KEY=VALUE", tag)
+	}
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		return "", "", fmt.Errorf("invalid --flag-tag %q: keys starting with aws: are reserved", tag)
+	}
+	// The reason tag's key is the flag tag's key with a suffix, so it has to fit too
+	if len(key+flagReasonSuffix) > awsinternal.TagKeyMaxLength {
+		return "", "", fmt.Errorf("invalid --flag-tag %q: key must be at most %d characters", tag, awsinternal.TagKeyMaxLength-len(flagReasonSuffix))
+	}
+	if value != awsinternal.SanitizeTagValue(value) {
+		return "", "", fmt.Errorf("invalid --flag-tag %q: value must be at most %d letters, digits, spaces or _.:/=+-@", tag, awsinternal.TagValueMaxLength)
+	}
+	return key, value, nil
+}
+
+// applyFindingTags tags each flagged resource with the flag tag and the reason it was flagged, so
+// owners can see it in the console. Resources without an ARN can't be tagged and are skipped, and
+// a resource flagged by more than one finding is tagged once with the first finding's reason. With
+// dryRun, the tags are only logged. It returns the number of resources tagged, skipped and failed.
+func applyFindingTags(accountResults map[string]*scanResult, key, value string, dryRun bool, tag resourceTagger) (int, int, int) {
+	tagged, skipped, failed := 0, 0, 0
+	seen := make(map[string]bool)
+	for _, result := range flattenResults(accountResults) {
+		if result.ARN == "" {
+			skipped++
+			logging.Debug("Skipping tagging of a finding without an ARN", map[string]interface{}{
+				"account_id":    result.AccountID,
+				"resource_type": result.ResourceType,
+				"resource_id":   result.ResourceID,
+			})
+			continue
+		}
+		if seen[result.ARN] {
+			continue
+		}
+		seen[result.ARN] = true
+
+		tags := map[string]string{key: value}
+		if reason := awsinternal.SanitizeTagValue(result.Reason); reason != "" {
+			tags[key+flagReasonSuffix] = reason
+		}
+		fields := map[string]interface{}{
+			"account_id":    result.AccountID,
+			"resource_type": result.ResourceType,
+			"arn":           result.ARN,
+			"tags":          tags,
+		}
+
+		if dryRun {
+			logging.Info("Would tag flagged resource", fields)
+			tagged++
+			continue
+		}
+		if err := tag(result.AccountID, result.ARN, tags); err != nil {
+			logging.Error("Failed to tag flagged resource", err, fields)
+			failed++
+			continue
+		}
+		logging.Info("Tagged flagged resource", fields)
+		tagged++
+	}
+	return tagged, skipped, failed
+}
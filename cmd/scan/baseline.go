@@ -0,0 +1,216 @@
+package scan
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	awsinternal "cloudsift/internal/aws"
+)
+
+// baselineAnomaly is a scanner whose finding count or monthly cost in an account changed from
+// the --baseline scan by more than --anomaly-threshold percent
+type baselineAnomaly struct {
+	Scanner       string   `json:"scanner"`
+	Metric        string   `json:"metric"` // "findings" or "monthly_cost"
+	Baseline      float64  `json:"baseline"`
+	Current       float64  `json:"current"`
+	ChangePercent *float64 `json:"change_percent"` // Null when the baseline was zero
+}
+
+// scannerTotals is the number of findings of one scanner in one account and their monthly cost
+type scannerTotals struct {
+	Findings    int
+	MonthlyCost float64
+}
+
+// baselineFile is either a single account's scan output or a --combined-output report
+type baselineFile struct {
+	AccountID string                     `json:"account_id"`
+	Results   map[string][]baselineEntry `json:"results"`
+	Accounts  map[string]baselineFile    `json:"accounts"`
+}
+
+// baselineEntry is the part of a finding decoded from a baseline scan that totals are taken from
+type baselineEntry struct {
+	Cost map[string]interface{} `json:"cost"`
+}
+
+// monthlyCost returns the finding's monthly cost in US dollars, so baselines reported in another
+// currency can still be compared
+func (e baselineEntry) monthlyCost() float64 {
+	total, ok := e.Cost["total_usd"].(map[string]interface{})
+	if !ok {
+		total, _ = e.Cost["total"].(map[string]interface{})
+	}
+	monthly, _ := total["monthly_rate"].(float64)
+	return monthly
+}
+
+// loadBaseline reads a previous scan's JSON output, which may be gzipped and may hold a single
+// account's result, a list of them or a combined report, and totals it by account and scanner
+func loadBaseline(path string) (map[string]map[string]scannerTotals, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline %s: %w", path, err)
+	}
+
+	// Scan output is gzipped; detect it by the gzip magic number rather than the file extension
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress baseline %s: %w", path, err)
+		}
+		defer gz.Close()
+
+		data, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress baseline %s: %w", path, err)
+		}
+	}
+
+	var files []baselineFile
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &files)
+	} else {
+		var file baselineFile
+		err = json.Unmarshal(trimmed, &file)
+		files = append(files, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	totals := make(map[string]map[string]scannerTotals)
+	var addFile func(file baselineFile)
+	addFile = func(file baselineFile) {
+		for _, account := range file.Accounts {
+			addFile(account)
+		}
+		if file.AccountID == "" {
+			return
+		}
+		accountTotals := make(map[string]scannerTotals)
+		for scannerLabel, entries := range file.Results {
+			scannerTotal := scannerTotals{Findings: len(entries)}
+			for _, entry := range entries {
+				scannerTotal.MonthlyCost += entry.monthlyCost()
+			}
+			accountTotals[scannerLabel] = scannerTotal
+		}
+		totals[file.AccountID] = accountTotals
+	}
+	for _, file := range files {
+		addFile(file)
+	}
+	if len(totals) == 0 {
+		return nil, fmt.Errorf("baseline %s holds no account results", path)
+	}
+	return totals, nil
+}
+
+// currentTotals totals an account's findings by scanner
+func currentTotals(results map[string]awsinternal.ScanResults) map[string]scannerTotals {
+	totals := make(map[string]scannerTotals, len(results))
+	for scannerLabel, scannerResults := range results {
+		scannerTotal := scannerTotals{Findings: len(scannerResults)}
+		for _, result := range scannerResults {
+			if total := awsinternal.USDCost(result.Cost); total != nil {
+				scannerTotal.MonthlyCost += total.MonthlyRate
+			}
+		}
+		totals[scannerLabel] = scannerTotal
+	}
+	return totals
+}
+
+// detectAnomalies compares an account's totals with its baseline for each scanner that ran,
+// and returns the finding counts and costs that changed by more than thresholdPercent. A
+// scanner that went from nothing to some findings is always flagged.
+func detectAnomalies(baseline, current map[string]scannerTotals, scannerLabels []string, thresholdPercent float64) []baselineAnomaly {
+	var anomalies []baselineAnomaly
+	check := func(scannerLabel, metric string, baselineValue, currentValue float64) {
+		if baselineValue == 0 {
+			if currentValue > 0 {
+				anomalies = append(anomalies, baselineAnomaly{Scanner: scannerLabel, Metric: metric, Current: currentValue})
+			}
+			return
+		}
+		change := (currentValue - baselineValue) / baselineValue * 100
+		if math.Abs(change) > thresholdPercent {
+			change = math.Round(change*100) / 100
+			anomalies = append(anomalies, baselineAnomaly{
+				Scanner:       scannerLabel,
+				Metric:        metric,
+				Baseline:      baselineValue,
+				Current:       currentValue,
+				ChangePercent: &change,
+			})
+		}
+	}
+
+	labels := append([]string(nil), scannerLabels...)
+	sort.Strings(labels)
+	for _, scannerLabel := range labels {
+		before, after := baseline[scannerLabel], current[scannerLabel]
+		check(scannerLabel, "findings", float64(before.Findings), float64(after.Findings))
+		check(scannerLabel, "monthly_cost", math.Round(before.MonthlyCost*100)/100, math.Round(after.MonthlyCost*100)/100)
+	}
+	return anomalies
+}
+
+// flagBaselineAnomalies records on each account the scanners whose findings changed from the
+// baseline by more than thresholdPercent, and returns a line describing each for the log.
+// Accounts missing from the baseline, and scanners that failed in an account, aren't compared.
+func flagBaselineAnomalies(accountResults map[string]*scanResult, baseline map[string]map[string]scannerTotals, scannerLabels []string, thresholdPercent float64) []string {
+	accountIDs := make([]string, 0, len(accountResults))
+	for accountID := range accountResults {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	var notes []string
+	for _, accountID := range accountIDs {
+		accountBaseline, ok := baseline[accountID]
+		if !ok {
+			continue
+		}
+		accountResult := accountResults[accountID]
+
+		failed := make(map[string]bool)
+		for _, scanErr := range accountResult.Errors {
+			failed[scanErr.Scanner] = true
+		}
+		var compared []string
+		for _, scannerLabel := range scannerLabels {
+			if !failed[scannerLabel] {
+				compared = append(compared, scannerLabel)
+			}
+		}
+
+		accountResult.Anomalies = detectAnomalies(accountBaseline, currentTotals(accountResult.Results), compared, thresholdPercent)
+		for _, anomaly := range accountResult.Anomalies {
+			notes = append(notes, fmt.Sprintf("%s in %s: %s", anomaly.Scanner, accountID, anomaly.describe()))
+		}
+	}
+	return notes
+}
+
+// describe returns the change as text, e.g. "findings 3 -> 30 (+900%)"
+func (a baselineAnomaly) describe() string {
+	format := "%s %.0f -> %.0f"
+	if a.Metric == "monthly_cost" {
+		format = "%s $%.2f -> $%.2f"
+	}
+	text := fmt.Sprintf(format, a.Metric, a.Baseline, a.Current)
+	if a.ChangePercent == nil {
+		return text + " (new)"
+	}
+	return text + fmt.Sprintf(" (%+.0f%%)", *a.ChangePercent)
+}
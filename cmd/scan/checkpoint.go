@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+	"cloudsift/internal/version"
+)
+
+// checkpointInterval is the minimum time between checkpoint writes while a scan is running
+const checkpointInterval = 30 * time.Second
+
+// checkpointTask is a scanner task that completed, with the findings it contributed to the report
+type checkpointTask struct {
+	AccountID string                        `json:"account_id"`
+	Region    string                        `json:"region"`  // "global" for global scanners
+	Scanner   string                        `json:"scanner"` // Scanner argument name
+	Results   awsinternal.ScanResults       `json:"results"`
+	Truncated *awsinternal.TruncatedResults `json:"truncated,omitempty"`
+}
+
+// scanCheckpoint records the scanner tasks of a scan that have completed so a scan that dies part
+// way through can be resumed with --resume. It is not safe for concurrent use; callers hold the
+// results mutex.
+type scanCheckpoint struct {
+	Version   string           `json:"cloudsift_version"`
+	StartedAt time.Time        `json:"started_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Tasks     []checkpointTask `json:"tasks"`
+
+	path      string         // File the checkpoint is written to, empty to keep it in memory only
+	index     map[string]int // Position of each task in Tasks, by checkpointKey
+	lastWrite time.Time
+}
+
+// checkpointKey identifies a scanner task by account, region and scanner
+func checkpointKey(accountID, region, scanner string) string {
+	return accountID + "/" + region + "/" + scanner
+}
+
+// newScanCheckpoint returns an empty checkpoint that is written to path
+func newScanCheckpoint(path string) *scanCheckpoint {
+	return &scanCheckpoint{
+		Version:   version.String(),
+		StartedAt: time.Now().UTC(),
+		Tasks:     []checkpointTask{},
+		path:      path,
+		index:     make(map[string]int),
+	}
+}
+
+// loadScanCheckpoint reads a checkpoint written by an earlier scan. Findings are decoded with their
+// total cost restored, so they can be reported as if the scanner had just returned them.
+func loadScanCheckpoint(path string) (*scanCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := newScanCheckpoint(path)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+
+	for i, task := range checkpoint.Tasks {
+		for j, result := range task.Results {
+			checkpoint.Tasks[i].Results[j].Region = result.SavedRegion()
+			total, ok := result.Cost["total"]
+			if !ok {
+				continue
+			}
+			encoded, err := json.Marshal(total)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+			}
+			var breakdown awsinternal.CostBreakdown
+			if err := json.Unmarshal(encoded, &breakdown); err != nil {
+				return nil, fmt.Errorf("failed to parse cost of %s in checkpoint %s: %w", result.ResourceID, path, err)
+			}
+			checkpoint.Tasks[i].Results[j].Cost["total"] = &breakdown
+		}
+		checkpoint.index[checkpointKey(task.AccountID, task.Region, task.Scanner)] = i
+	}
+	return checkpoint, nil
+}
+
+// completed returns the checkpointed task for an account, region and scanner, if it has completed
+func (c *scanCheckpoint) completed(accountID, region, scanner string) (checkpointTask, bool) {
+	i, ok := c.index[checkpointKey(accountID, region, scanner)]
+	if !ok {
+		return checkpointTask{}, false
+	}
+	return c.Tasks[i], true
+}
+
+// record marks a task as completed and writes the checkpoint if it hasn't been written within
+// checkpointInterval
+func (c *scanCheckpoint) record(task checkpointTask) {
+	key := checkpointKey(task.AccountID, task.Region, task.Scanner)
+	if i, ok := c.index[key]; ok {
+		c.Tasks[i] = task
+	} else {
+		c.index[key] = len(c.Tasks)
+		c.Tasks = append(c.Tasks, task)
+	}
+
+	if time.Since(c.lastWrite) < checkpointInterval {
+		return
+	}
+	if err := c.write(); err != nil {
+		logging.Error("Failed to write scan checkpoint", err, map[string]interface{}{
+			"checkpoint": c.path,
+		})
+	}
+}
+
+// write saves the checkpoint, replacing the file atomically so a scan killed mid-write leaves the
+// previous checkpoint intact
+func (c *scanCheckpoint) write() error {
+	if c.path == "" {
+		return nil
+	}
+	c.lastWrite = time.Now()
+	c.UpdatedAt = c.lastWrite.UTC()
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tempFile := c.path + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp checkpoint file: %w", err)
+	}
+	if err := os.Rename(tempFile, c.path); err != nil {
+		os.Remove(tempFile) // Clean up temp file if rename fails
+		return fmt.Errorf("failed to rename temp checkpoint file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/config"
+	"cloudsift/internal/logging"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// settingEnums lists the allowed values of config settings that only take a fixed set of values
+var settingEnums = map[string][]string{
+	"app.log_format":     {"text", "json"},
+	"list.format":        {"text", "json"},
+	"scan.output":        {"filesystem", "s3", "http"},
+	"scan.output_format": {"json", "html", "junit"},
+	"scan.s3_layout":     {"flat", "partitioned"},
+}
+
+// NewConfigCmd creates and returns the config command
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Validate the configuration or print its JSON schema",
+		Long: `Work with the CloudSift configuration, which is read from config.yaml (or --config),
+CLOUDSIFT_* environment variables and global flags.`,
+	}
+
+	cmd.AddCommand(newConfigValidateCmd(), newConfigSchemaCmd())
+	return cmd
+}
+
+// newConfigValidateCmd creates the config validate command
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration for problems without scanning",
+		Long: `Check the configuration against the settings CloudSift knows about, without calling AWS.
+
+Validate reports every problem it finds rather than stopping at the first:
+  - settings CloudSift doesn't recognize, such as misspelled keys
+  - values of the wrong type, such as days_unused: ninety
+  - scanner names in scan.scanners, scan.skip_scanners and ignore rules that don't exist
+  - output types, output formats, S3 layouts and the other scan options the scan command checks
+  - malformed role names, log formats and log levels
+  - every preset in the scans section, and the rules in scan.ignore.rules and scan.ignore_file
+
+The command exits with an error if any problem is found.`,
+		Example: `  # Validate ./config.yaml along with any CLOUDSIFT_* environment variables
+  cloudsift config validate
+
+  # Validate a specific config file
+  cloudsift config validate --config ./ci/cloudsift.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			problems := validateConfig()
+			out := cmd.OutOrStdout()
+			if len(problems) == 0 {
+				fmt.Fprintln(out, "Configuration is valid")
+				return nil
+			}
+
+			fmt.Fprintln(out, "Configuration problems:")
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  - %s\n", problem)
+			}
+			return fmt.Errorf("configuration is invalid: %d problem(s) found", len(problems))
+		},
+	}
+}
+
+// newConfigSchemaCmd creates the config schema command
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of the config file",
+		Long: `Print a JSON schema describing every setting of the config file, for editors and CI checks
+that validate config.yaml before CloudSift runs.`,
+		Example: `  # Save the schema for an editor's YAML language server
+  cloudsift config schema > cloudsift.schema.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeConfigSchema(cmd.OutOrStdout(), cmd.Root().PersistentFlags())
+		},
+	}
+}
+
+// validateConfig checks the loaded configuration and returns every problem found
+func validateConfig() []error {
+	var errs []error
+
+	for _, key := range config.UnknownKeys() {
+		errs = append(errs, fmt.Errorf("unknown setting %q", key))
+	}
+
+	// Global settings are already read into config.Config by the root command
+	if config.Config.MaxWorkers <= 0 {
+		errs = append(errs, fmt.Errorf("app.max_workers must be greater than 0"))
+	}
+	if _, err := logging.ParseFormat(config.Config.LogFormat); err != nil {
+		errs = append(errs, fmt.Errorf("app.log_format: %w", err))
+	}
+	switch strings.ToUpper(config.Config.LogLevel) {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+		// Valid levels
+	default:
+		errs = append(errs, fmt.Errorf("app.log_level: invalid log level %q (must be DEBUG, INFO, WARN or ERROR)", config.Config.LogLevel))
+	}
+	if config.Config.OrganizationRole != "" {
+		if err := config.ValidateRoleName(config.Config.OrganizationRole, false); err != nil {
+			errs = append(errs, fmt.Errorf("aws.organization_role: %w", err))
+		}
+	}
+	if config.Config.ScannerRole != "" {
+		if err := config.ValidateRoleName(config.Config.ScannerRole, false); err != nil {
+			errs = append(errs, fmt.Errorf("aws.scanner_role: %w", err))
+		}
+	}
+	for _, role := range config.Config.AssumeRoleChain {
+		if err := config.ValidateRoleName(role, true); err != nil {
+			errs = append(errs, fmt.Errorf("aws.assume_role_chain: %w", err))
+		}
+	}
+
+	rules, err := config.LoadScanIgnoreRules()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, validateScanSettings("scan", config.SectionFlagValues("scan"), rules)...)
+
+	// Presets are checked on their own, the way scan --preset applies them over the flag defaults
+	for _, name := range config.ScanPresetNames() {
+		preset, err := config.LoadScanPreset(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		errs = append(errs, validateScanSettings(fmt.Sprintf("scan preset %q", name), preset.Flags, preset.IgnoreRules)...)
+	}
+
+	return errs
+}
+
+// validateScanSettings applies scan flag values to a fresh set of scan options and returns every
+// problem with them, each prefixed with where the settings came from
+func validateScanSettings(source string, flagValues map[string]string, rules []config.IgnoreRule) []error {
+	var errs []error
+	addError := func(err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", source, err))
+	}
+
+	opts := &scanOptions{}
+	cmd := &cobra.Command{}
+	addScanFlags(cmd, opts)
+
+	// Flag names are sorted so problems are reported in a stable order
+	flagNames := make([]string, 0, len(flagValues))
+	for flagName := range flagValues {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+	for _, flagName := range flagNames {
+		if err := cmd.Flags().Set(flagName, flagValues[flagName]); err != nil {
+			addError(err)
+		}
+	}
+
+	// Roles come from the aws section rather than the scan section
+	opts.organizationRole = config.Config.OrganizationRole
+	opts.scannerRole = config.Config.ScannerRole
+
+	for _, err := range validateScanOptions(opts) {
+		addError(err)
+	}
+
+	if opts.scanners != "" {
+		if _, invalid, err := getScanners(opts.scanners); err != nil {
+			addError(err)
+		} else if len(invalid) > 0 {
+			addError(fmt.Errorf("unknown scanners in --scanners: %s", strings.Join(invalid, ", ")))
+		}
+	}
+	if _, invalid := skipScanners(nil, opts.skipScanners); len(invalid) > 0 {
+		addError(fmt.Errorf("unknown scanners in --skip-scanners: %s", strings.Join(invalid, ", ")))
+	}
+
+	if opts.ignoreFile != "" {
+		ignoreFile, err := config.LoadIgnoreFile(opts.ignoreFile)
+		if err != nil {
+			addError(err)
+		} else {
+			for i, rule := range ignoreFile.Rules {
+				for _, err := range rule.Validate(isScannerName) {
+					addError(fmt.Errorf("ignore file rule %d: %w", i+1, err))
+				}
+			}
+		}
+	}
+	for i, rule := range rules {
+		for _, err := range rule.Validate(isScannerName) {
+			addError(fmt.Errorf("ignore.rules rule %d: %w", i+1, err))
+		}
+	}
+
+	return errs
+}
+
+// isScannerName reports whether name is the argument name or label of a registered scanner,
+// ignoring case the way ignore rules match scanners
+func isScannerName(name string) bool {
+	for _, argumentName := range awsinternal.DefaultRegistry.ListScanners() {
+		scanner, err := awsinternal.DefaultRegistry.GetScanner(argumentName)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(name, scanner.ArgumentName()) || strings.EqualFold(name, scanner.Label()) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeConfigSchema writes a JSON schema of the config file. Setting types and descriptions come
+// from the flag that sets each key, so the schema follows the flags as they change.
+func writeConfigSchema(w io.Writer, globalFlags *pflag.FlagSet) error {
+	scanCmd := &cobra.Command{}
+	addScanFlags(scanCmd, &scanOptions{})
+
+	sections := make(map[string]map[string]interface{})
+	for _, key := range config.Keys() {
+		section, setting, _ := strings.Cut(key, ".")
+		if sections[section] == nil {
+			sections[section] = make(map[string]interface{})
+		}
+
+		var flag *pflag.Flag
+		if flagName, ok := config.FlagName(key); ok {
+			if section == "scan" {
+				flag = scanCmd.Flags().Lookup(flagName)
+			} else {
+				flag = globalFlags.Lookup(flagName)
+			}
+		}
+		sections[section][setting] = settingSchema(key, flag)
+	}
+
+	properties := make(map[string]interface{})
+	for section, settings := range sections {
+		if section == "scan" {
+			properties[section] = objectSchema(nestIgnoreSettings(settings))
+			continue
+		}
+		properties[section] = objectSchema(settings)
+	}
+	properties["scans"] = map[string]interface{}{
+		"type":                 "object",
+		"description":          "Named scan presets, run with scan --preset",
+		"additionalProperties": objectSchema(nestIgnoreSettings(sections["scan"])),
+	}
+
+	schema := objectSchema(properties)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "CloudSift configuration"
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
+
+// nestIgnoreSettings moves the ignore.* settings of the scan section under an ignore object
+func nestIgnoreSettings(settings map[string]interface{}) map[string]interface{} {
+	nested := make(map[string]interface{})
+	ignore := make(map[string]interface{})
+	for setting, schema := range settings {
+		if child, ok := strings.CutPrefix(setting, "ignore."); ok {
+			ignore[child] = schema
+			continue
+		}
+		nested[setting] = schema
+	}
+	nested["ignore"] = objectSchema(ignore)
+	return nested
+}
+
+// settingSchema describes one setting, based on the type and usage of the flag that sets it
+func settingSchema(key string, flag *pflag.Flag) map[string]interface{} {
+	schema := make(map[string]interface{})
+	if key == "scan.ignore.rules" {
+		schema["type"] = "array"
+		schema["description"] = "Ignore rules scoped to specific scanners or accounts"
+		schema["items"] = ignoreRuleSchema()
+	} else if flag == nil {
+		schema["type"] = "string"
+	} else {
+		schema["description"] = flag.Usage
+		switch flag.Value.Type() {
+		case "bool":
+			schema["type"] = "boolean"
+		case "int":
+			schema["type"] = "integer"
+		case "float64":
+			schema["type"] = "number"
+		case "duration":
+			schema["type"] = "string"
+			schema["pattern"] = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+		case "stringSlice":
+			schema["type"] = []string{"array", "string"}
+			schema["items"] = map[string]interface{}{"type": "string"}
+		default:
+			switch {
+			case strings.Contains(flag.Usage, "KEY=VALUE"):
+				// Tag lists are written as a map in the config file
+				schema["type"] = []string{"object", "string"}
+				schema["additionalProperties"] = map[string]interface{}{"type": "string"}
+			case strings.HasPrefix(flag.Usage, "Comma-separated"):
+				schema["type"] = []string{"array", "string"}
+				schema["items"] = map[string]interface{}{"type": "string"}
+			default:
+				schema["type"] = "string"
+			}
+		}
+	}
+	if values, ok := settingEnums[key]; ok {
+		schema["enum"] = values
+	}
+	return schema
+}
+
+// ignoreRuleSchema describes one scoped ignore rule
+func ignoreRuleSchema() map[string]interface{} {
+	stringList := map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}
+	return objectSchema(map[string]interface{}{
+		"resource_ids":   stringList,
+		"resource_names": stringList,
+		"tags": map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "string"},
+		},
+		"scanners": stringList,
+		"accounts": stringList,
+	})
+}
+
+// objectSchema describes an object that only allows the given properties
+func objectSchema(properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
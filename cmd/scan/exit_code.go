@@ -0,0 +1,96 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of the scan command, so schedulers can tell a degraded scan from a clean one
+const (
+	// ExitCodeClean means every scan task succeeded
+	ExitCodeClean = 0
+	// ExitCodeError means the scan couldn't run at all, for example because credentials, the
+	// organization role or the S3 bucket couldn't be used or the flags were invalid, or that none
+	// of its results could be written
+	ExitCodeError = 1
+	// ExitCodePartialFailure means a report was produced, but some scan tasks failed, some
+	// accounts were skipped or some accounts' results couldn't be written
+	ExitCodePartialFailure = 2
+	// ExitCodeCostThreshold means a report was produced, but its findings exceed --fail-over-cost
+	ExitCodeCostThreshold = 3
+)
+
+// ExitError is returned by the scan command when a report was produced but the scan wasn't
+// clean. Code is the exit code the process should end with.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements error interface
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned by the scan command, or any other command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeClean
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitCodeError
+}
+
+// partialFailureError reports scan tasks that failed and accounts that were skipped, or returns
+// nil when there were none
+func partialFailureError(failedTasks, totalTasks int64, skippedAccounts int) error {
+	switch {
+	case failedTasks > 0 && skippedAccounts > 0:
+		return &ExitError{
+			Code: ExitCodePartialFailure,
+			Err:  fmt.Errorf("%d of %d scan tasks failed and %d accounts were skipped", failedTasks, totalTasks, skippedAccounts),
+		}
+	case failedTasks > 0:
+		return &ExitError{
+			Code: ExitCodePartialFailure,
+			Err:  fmt.Errorf("%d of %d scan tasks failed", failedTasks, totalTasks),
+		}
+	case skippedAccounts > 0:
+		return &ExitError{
+			Code: ExitCodePartialFailure,
+			Err:  fmt.Errorf("%d accounts were skipped", skippedAccounts),
+		}
+	}
+	return nil
+}
+
+// writeFailureError reports output writes that failed, or returns nil when there were none. When
+// every write failed no report was produced, so the scan fails as if it couldn't run.
+func writeFailureError(failedWrites, totalWrites int) error {
+	switch {
+	case failedWrites == 0:
+		return nil
+	case failedWrites >= totalWrites && totalWrites == 1:
+		return &ExitError{
+			Code: ExitCodeError,
+			Err:  errors.New("scan results could not be written"),
+		}
+	case failedWrites >= totalWrites:
+		return &ExitError{
+			Code: ExitCodeError,
+			Err:  fmt.Errorf("results could not be written for any of the %d accounts", totalWrites),
+		}
+	}
+	return &ExitError{
+		Code: ExitCodePartialFailure,
+		Err:  fmt.Errorf("results could not be written for %d of %d accounts", failedWrites, totalWrites),
+	}
+}
@@ -0,0 +1,141 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/config"
+)
+
+// Filters that leave a finding out of the report, as recorded by --emit-ignored
+const (
+	ignoreFilterIncludeTags = "include_tags"   // The resource has none of --include-tags
+	ignoreFilterMinAge      = "min_age_days"   // The resource is newer than --min-age-days
+	ignoreFilterConfidence  = "min_confidence" // The finding's confidence is below --min-confidence
+	ignoreFilterIgnoreList  = "ignore_list"    // The resource is in --ignore-resource-ids, --ignore-resource-names or --ignore-tags
+	ignoreFilterScopedRule  = "scoped_rule"    // The resource matches an ignore rule scoped to scanners or accounts
+)
+
+// ignoredResource is a finding left out of the report and the filter that left it out
+type ignoredResource struct {
+	AccountID    string   `json:"account_id"`
+	AccountName  string   `json:"account_name"`
+	Scanner      string   `json:"scanner"`
+	Region       string   `json:"region"`
+	ResourceID   string   `json:"resource_id"`
+	ResourceName string   `json:"resource_name"`
+	ARN          string   `json:"arn,omitempty"`
+	Filter       string   `json:"filter"`                  // include_tags, min_age_days, ignore_list or scoped_rule
+	Match        string   `json:"match,omitempty"`         // The entry that matched, such as "tag: env=dev"
+	RuleScanners []string `json:"rule_scanners,omitempty"` // Scanners a scoped rule is limited to
+	RuleAccounts []string `json:"rule_accounts,omitempty"` // Accounts a scoped rule is limited to
+}
+
+// ignoredReport is the file written by --emit-ignored
+type ignoredReport struct {
+	ScannedAt time.Time         `json:"scanned_at"`
+	Ignored   []ignoredResource `json:"ignored"`
+}
+
+// filterResult returns why a finding should be left out of the report, or false if it should be
+// reported. Filters are checked in the order runScan has always applied them, so the first
+// filter that matches is the one recorded.
+func filterResult(result awsinternal.ScanResult, scanner awsinternal.Scanner, accountID string, now time.Time) (ignoredResource, bool) {
+	ignored := ignoredResource{
+		AccountID:    accountID,
+		Scanner:      scanner.Label(),
+		ResourceID:   result.ResourceID,
+		ResourceName: result.ResourceName,
+		ARN:          result.ARN,
+	}
+
+	// When include tags are set, only resources carrying one of them are reported
+	if len(config.Config.ScanIncludeTags) > 0 && !hasMatchingTag(result.Tags, config.Config.ScanIncludeTags) {
+		ignored.Filter = ignoreFilterIncludeTags
+		return ignored, true
+	}
+
+	// Resources younger than --min-age-days are usually still being set up
+	if isNewerThan(result, config.Config.ScanMinAgeDays, now) {
+		ignored.Filter = ignoreFilterMinAge
+		ignored.Match = "created_at: " + result.CreatedAt.Format(time.RFC3339) + ", min_age_days: " + strconv.Itoa(config.Config.ScanMinAgeDays)
+		return ignored, true
+	}
+
+	// Findings below --min-confidence are left out, e.g. to report only near-certain waste
+	if !result.MeetsConfidence(config.Config.ScanMinConfidence) {
+		ignored.Filter = ignoreFilterConfidence
+		ignored.Match = "confidence: " + result.ConfidenceLevel() + ", min_confidence: " + config.Config.ScanMinConfidence
+		return ignored, true
+	}
+
+	// The ignore lists apply to every scanner and account
+	ignoreList := config.IgnoreRule{
+		ResourceIDs:   config.Config.ScanIgnoreResourceIDs,
+		ResourceNames: config.Config.ScanIgnoreResourceNames,
+		Tags:          config.Config.ScanIgnoreTags,
+	}
+	if match, ok := ignoreList.Match(result.ResourceID, result.ResourceName, result.Tags); ok {
+		ignored.Filter = ignoreFilterIgnoreList
+		ignored.Match = match
+		return ignored, true
+	}
+
+	// Check ignore rules scoped to specific scanners or accounts
+	for _, rule := range config.Config.ScanIgnoreRules {
+		if !rule.AppliesTo(scanner.ArgumentName(), scanner.Label(), accountID) {
+			continue
+		}
+		if match, ok := rule.Match(result.ResourceID, result.ResourceName, result.Tags); ok {
+			ignored.Filter = ignoreFilterScopedRule
+			ignored.Match = match
+			ignored.RuleScanners = rule.Scanners
+			ignored.RuleAccounts = rule.Accounts
+			return ignored, true
+		}
+	}
+
+	return ignoredResource{}, false
+}
+
+// writeIgnored writes the findings left out of the report to path as JSON, ordered by account,
+// scanner, region and resource
+func writeIgnored(path string, ignored []ignoredResource, scannedAt time.Time) error {
+	sort.SliceStable(ignored, func(i, j int) bool {
+		a, b := ignored[i], ignored[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		if a.Scanner != b.Scanner {
+			return a.Scanner < b.Scanner
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ResourceID < b.ResourceID
+	})
+	if ignored == nil {
+		ignored = []ignoredResource{}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create ignored resources directory: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(ignoredReport{ScannedAt: scannedAt, Ignored: ignored}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ignored resources: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ignored resources: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	awsinternal "cloudsift/internal/aws"
+	"cloudsift/internal/config"
+	"cloudsift/internal/output"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/spf13/cobra"
+)
+
+type preflightOptions struct {
+	accounts            string // Comma-separated list of account IDs to check
+	organizationalUnits string // Comma-separated list of OU IDs whose accounts are checked
+	accountsFile        string // JSON or CSV file listing the accounts to check instead of Organizations
+	profiles            string // Comma-separated list of AWS profiles to check instead of an organization
+	bucket              string // S3 bucket the scan would write to
+	bucketRegion        string // Region of the S3 bucket
+	s3KMSKeyID          string // KMS key the scan would encrypt S3 uploads with
+	s3ACL               string // Canned ACL the scan would apply to S3 uploads
+	s3StorageClass      string // Storage class the scan would upload S3 objects with
+	outputFormat        string // Output format for the report (text or json)
+}
+
+// preflightCheck is the outcome of one access check
+type preflightCheck struct {
+	Check       string `json:"check"`
+	AccountID   string `json:"account_id,omitempty"`
+	AccountName string `json:"account_name,omitempty"`
+	Passed      bool   `json:"passed"`
+	Skipped     bool   `json:"skipped,omitempty"` // The scan would skip the account, so it wasn't checked
+	Detail      string `json:"detail,omitempty"`
+}
+
+// preflightReport is the full set of checks run by the preflight command
+type preflightReport struct {
+	Checks  []preflightCheck `json:"checks"`
+	Passed  int              `json:"passed"`
+	Failed  int              `json:"failed"`
+	Skipped int              `json:"skipped"`
+}
+
+// add records a check, using the error as the detail when it failed
+func (r *preflightReport) add(check preflightCheck, err error) {
+	check.Passed = err == nil
+	if err != nil {
+		check.Detail = err.Error()
+		r.Failed++
+	} else {
+		r.Passed++
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// skip records a check that wasn't run because the scan would skip it. Skipped checks don't fail
+// preflight.
+func (r *preflightReport) skip(check preflightCheck) {
+	check.Skipped = true
+	r.Skipped++
+	r.Checks = append(r.Checks, check)
+}
+
+// NewPreflightCmd creates and returns the preflight command
+func NewPreflightCmd() *cobra.Command {
+	opts := &preflightOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Check credentials, role assumptions and S3 access without scanning",
+		Long: `Check that a scan would be able to run, without scanning any resources.
+
+Preflight performs the same role assumptions as the scan command and reports a pass/fail
+result for each account:
+  - the base credentials of the selected profile
+  - each role in the chain (with --assume-role-chain)
+  - the organization role, and listing the organization's accounts (with --organization-role)
+  - the scanner role in every account (with --scanner-role, and --organization-role or
+    --accounts-file), narrowed by --organizational-units and --accounts the way a scan is.
+    Suspended and closing accounts are reported as skipped, since the scan skips them too
+  - each profile (with --profiles)
+  - writing a test object to the output bucket (with --bucket)
+
+The command exits with an error if any check fails.`,
+		Example: `  # Check the scanner role can be assumed in every account of the organization
+  cloudsift preflight --organization-role OrganizationAccountAccessRole --scanner-role SecurityAuditRole
+
+  # Also check the scan can write to its output bucket
+  cloudsift preflight --organization-role OrganizationAccountAccessRole --scanner-role SecurityAuditRole \
+    --bucket my-bucket --bucket-region us-west-2
+
+  # Check a set of profiles and output the results as JSON
+  cloudsift preflight --profiles dev,staging,prod --output-format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.outputFormat != "text" && opts.outputFormat != "json" {
+				return fmt.Errorf("invalid output format: %s (must be 'text' or 'json')", opts.outputFormat)
+			}
+			if opts.bucket != "" && opts.bucketRegion == "" {
+				return fmt.Errorf("--bucket-region is required when using --bucket")
+			}
+			if opts.s3KMSKeyID != "" {
+				if opts.bucket == "" {
+					return fmt.Errorf("--s3-kms-key-id requires --bucket")
+				}
+				if err := validateKMSKeyARN(opts.s3KMSKeyID); err != nil {
+					return err
+				}
+			}
+			if (opts.s3ACL != "" || opts.s3StorageClass != "") && opts.bucket == "" {
+				return fmt.Errorf("--s3-acl and --s3-storage-class require --bucket")
+			}
+			if err := output.ValidateS3ACL(opts.s3ACL); err != nil {
+				return err
+			}
+			if err := output.ValidateS3StorageClass(opts.s3StorageClass); err != nil {
+				return err
+			}
+			if opts.profiles != "" && config.Config.OrganizationRole != "" && config.Config.ScannerRole != "" {
+				return fmt.Errorf("--profiles cannot be combined with --organization-role and --scanner-role")
+			}
+			if opts.accountsFile != "" && opts.profiles != "" {
+				return fmt.Errorf("--accounts-file cannot be combined with --profiles")
+			}
+			if opts.accountsFile != "" && config.Config.ScannerRole == "" {
+				return fmt.Errorf("--accounts-file requires --scanner-role")
+			}
+
+			report := runPreflight(opts)
+			if err := writePreflightReport(cmd.OutOrStdout(), report, opts.outputFormat); err != nil {
+				return err
+			}
+			if report.Failed > 0 {
+				return fmt.Errorf("preflight failed: %d of %d checks failed", report.Failed, len(report.Checks))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.accounts, "accounts", "", "Comma-separated list of account IDs to check (default: all accounts)")
+	cmd.Flags().StringVar(&opts.organizationalUnits, "organizational-units", "", "Comma-separated list of organizational unit IDs; only accounts in these OUs, or in OUs nested below them, are checked")
+	cmd.Flags().StringVar(&opts.accountsFile, "accounts-file", "", "JSON or CSV file listing account IDs and names to check instead of listing them through Organizations")
+	cmd.Flags().StringVar(&opts.profiles, "profiles", "", "Comma-separated list of AWS profiles to check, each as a standalone account")
+	cmd.Flags().StringVar(&opts.bucket, "bucket", "", "S3 bucket to check write access to")
+	cmd.Flags().StringVar(&opts.bucketRegion, "bucket-region", "", "S3 bucket region")
+	cmd.Flags().StringVar(&opts.s3KMSKeyID, "s3-kms-key-id", "", "ARN of the KMS key to encrypt the S3 test object with")
+	cmd.Flags().StringVar(&opts.s3ACL, "s3-acl", "", "Canned ACL to apply to the S3 test object, e.g. bucket-owner-full-control")
+	cmd.Flags().StringVar(&opts.s3StorageClass, "s3-storage-class", "", "Storage class to upload the S3 test object with, e.g. STANDARD_IA")
+	cmd.Flags().StringVar(&opts.outputFormat, "output-format", "text", "Output format (text or json)")
+
+	return cmd
+}
+
+// runPreflight runs the access checks a scan depends on, resolving accounts the same way the scan
+// does. Checks that depend on an earlier one are skipped when it fails.
+func runPreflight(opts *preflightOptions) *preflightReport {
+	report := &preflightReport{}
+	orgRole := config.Config.OrganizationRole
+	scannerRole := config.Config.ScannerRole
+	partition := awsinternal.ProfilePartition()
+	// Accounts from a file are assumed into from the organization session when there is one and
+	// from the current credentials otherwise, as in a scan
+	assumeScannerRoles := scannerRole != "" && (orgRole != "" || opts.accountsFile != "")
+
+	// Base credentials are needed for everything except --profiles, which authenticates each profile itself
+	var baseSession *session.Session
+	if opts.profiles == "" {
+		var err error
+		baseSession, err = awsinternal.NewSession(config.Config.Profile, "")
+		var identity *sts.GetCallerIdentityOutput
+		if err == nil {
+			identity, err = sts.New(baseSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		}
+		check := preflightCheck{Check: "Base credentials"}
+		if err == nil {
+			check.AccountID = aws.StringValue(identity.Account)
+			check.Detail = aws.StringValue(identity.Arn)
+		} else {
+			err = fmt.Errorf("profile %s: %w", config.Config.Profile, err)
+		}
+		report.add(check, err)
+		if err != nil {
+			return report
+		}
+
+		if chain := config.Config.AssumeRoleChain; len(chain) > 0 {
+			baseSession, err = awsinternal.AssumeRoleChain(baseSession, chain)
+			if err == nil {
+				identity, err = sts.New(baseSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			}
+			check := preflightCheck{Check: "Role chain"}
+			if err == nil {
+				check.AccountID = aws.StringValue(identity.Account)
+				check.Detail = aws.StringValue(identity.Arn)
+			}
+			report.add(check, err)
+			if err != nil {
+				return report
+			}
+		}
+
+		if !assumeScannerRoles {
+			accounts, err := awsinternal.ListCurrentAccount(baseSession)
+			if err == nil {
+				accounts = filterPreflightAccounts(report, accounts, opts.accounts)
+				for _, account := range accounts {
+					report.add(preflightCheck{Check: "Current account", AccountID: account.ID, AccountName: account.Name}, nil)
+				}
+			} else {
+				report.add(preflightCheck{Check: "Current account"}, err)
+			}
+		}
+	}
+
+	if opts.profiles != "" {
+		requested := strings.Split(opts.profiles, ",")
+		accounts, _ := awsinternal.ListProfileAccounts(requested)
+		authenticated := make(map[string]awsinternal.Account)
+		for _, account := range accounts {
+			authenticated[account.Profile] = account
+		}
+		for _, profile := range requested {
+			profile = strings.TrimSpace(profile)
+			if profile == "" {
+				continue
+			}
+			if account, ok := authenticated[profile]; ok {
+				report.add(preflightCheck{Check: "Profile " + profile, AccountID: account.ID, AccountName: account.Name}, nil)
+			} else {
+				report.add(preflightCheck{Check: "Profile " + profile}, fmt.Errorf("profile could not be authenticated"))
+			}
+		}
+	}
+
+	if assumeScannerRoles {
+		checkScannerRoles(report, opts, baseSession, partition)
+	}
+
+	if opts.bucket != "" {
+		err := validateS3Access(opts.bucket, opts.bucketRegion, orgRole, opts.s3KMSKeyID, opts.s3ACL, opts.s3StorageClass)
+		report.add(preflightCheck{Check: "S3 write access", Detail: fmt.Sprintf("s3://%s", opts.bucket)}, err)
+	}
+
+	return report
+}
+
+// checkScannerRoles lists the accounts a scan would cover, from the accounts file or the
+// organization, narrows them by organizational unit and account ID, and checks the scanner role
+// can be assumed in each active account
+func checkScannerRoles(report *preflightReport, opts *preflightOptions, baseSession *session.Session, partition string) {
+	orgRole := config.Config.OrganizationRole
+	assumeSession := baseSession
+	if orgRole != "" {
+		orgSession, err := awsinternal.GetSessionChain(orgRole, "", "", awsinternal.OrganizationsRegion())
+		report.add(preflightCheck{Check: "Organization role", Detail: orgRole}, err)
+		if err != nil {
+			return
+		}
+		assumeSession = orgSession
+	}
+
+	var accounts []awsinternal.Account
+	var err error
+	check := preflightCheck{Check: "List organization accounts"}
+	if opts.accountsFile != "" {
+		check = preflightCheck{Check: "Load accounts file"}
+		accounts, err = awsinternal.LoadAccountsFile(opts.accountsFile)
+	} else {
+		accounts, err = awsinternal.ListAccountsWithSession(assumeSession)
+	}
+	if err == nil {
+		check.Detail = fmt.Sprintf("%d accounts", len(accounts))
+	}
+	report.add(check, err)
+	if err != nil {
+		return
+	}
+
+	if opts.organizationalUnits != "" {
+		accounts, err = filterOrganizationalUnits(assumeSession, accounts, opts.organizationalUnits)
+		check := preflightCheck{Check: "Organizational units", Detail: opts.organizationalUnits}
+		if err == nil {
+			check.Detail = fmt.Sprintf("%d accounts in %s", len(accounts), opts.organizationalUnits)
+		}
+		report.add(check, err)
+		if err != nil {
+			return
+		}
+	}
+
+	accounts, inactiveAccounts := splitInactiveAccounts(filterPreflightAccounts(report, accounts, opts.accounts))
+	for _, account := range inactiveAccounts {
+		report.skip(preflightCheck{
+			Check:       "Scanner role",
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			Detail:      fmt.Sprintf("skipped_inactive: account is %s", account.Status),
+		})
+	}
+	for _, account := range accounts {
+		scanSession, identityARN, err := assumeScannerRole(assumeSession, partition, account.ID, config.Config.ScannerRole)
+		if err == nil {
+			awsinternal.ForgetRoleSession(scanSession)
+		}
+		report.add(preflightCheck{
+			Check:       "Scanner role",
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			Detail:      identityARN,
+		}, err)
+	}
+}
+
+// filterPreflightAccounts narrows accounts to the requested account IDs, recording a failed check
+// for each requested account that was not found
+func filterPreflightAccounts(report *preflightReport, accounts []awsinternal.Account, requested string) []awsinternal.Account {
+	if requested == "" {
+		return accounts
+	}
+
+	byID := make(map[string]awsinternal.Account)
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	var filtered []awsinternal.Account
+	for _, accountID := range strings.Split(requested, ",") {
+		accountID = strings.TrimSpace(accountID)
+		if accountID == "" {
+			continue
+		}
+		account, ok := byID[accountID]
+		if !ok {
+			report.add(preflightCheck{Check: "Find account", AccountID: accountID}, fmt.Errorf("account not found"))
+			continue
+		}
+		filtered = append(filtered, account)
+	}
+	return filtered
+}
+
+// writePreflightReport writes the check results as a table or as JSON
+func writePreflightReport(w io.Writer, report *preflightReport, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tACCOUNT\tRESULT\tDETAIL")
+	for _, check := range report.Checks {
+		account := "-"
+		if check.AccountID != "" {
+			account = check.AccountID
+			if check.AccountName != "" && check.AccountName != check.AccountID {
+				account = fmt.Sprintf("%s (%s)", check.AccountName, check.AccountID)
+			}
+		}
+		result := "PASS"
+		switch {
+		case check.Skipped:
+			result = "SKIP"
+		case !check.Passed:
+			result = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", check.Check, account, result, check.Detail)
+	}
+	fmt.Fprintln(tw)
+	if report.Skipped > 0 {
+		fmt.Fprintf(tw, "%d passed, %d failed, %d skipped\n", report.Passed, report.Failed, report.Skipped)
+	} else {
+		fmt.Fprintf(tw, "%d passed, %d failed\n", report.Passed, report.Failed)
+	}
+
+	return tw.Flush()
+}
@@ -3,18 +3,22 @@ package scan
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/spf13/cobra"
@@ -25,23 +29,74 @@ import (
 	"cloudsift/internal/logging"
 	"cloudsift/internal/output"
 	"cloudsift/internal/output/html"
+	"cloudsift/internal/version"
 	"cloudsift/internal/worker"
 )
 
 type scanOptions struct {
-	regions             string
-	scanners            string
-	output              string // filesystem or s3
-	outputFormat        string // html or json
-	bucket              string
-	bucketRegion        string
-	organizationRole    string // Role to assume for listing organization accounts
-	scannerRole         string // Role to assume for scanning accounts
-	daysUnused          int    // Number of days a resource must be unused to be reported
-	ignoreResourceIDs   string
-	ignoreResourceNames string
-	ignoreTags          string
-	accounts            string // Comma-separated list of account IDs to scan
+	regions                  string
+	scanners                 string
+	output                   string // filesystem or s3
+	outputFormat             string // html, json or junit
+	bucket                   string
+	bucketRegion             string
+	organizationRole         string // Role to assume for listing organization accounts
+	scannerRole              string // Role to assume for scanning accounts
+	daysUnused               int    // Number of days a resource must be unused to be reported
+	ignoreResourceIDs        string
+	ignoreResourceNames      string
+	ignoreTags               string
+	accounts                 string        // Comma-separated list of account IDs to scan
+	scannerTimeout           time.Duration // Maximum time a single scanner task may run
+	failOverCost             float64       // Fail the scan when estimated monthly cost of findings exceeds this (0 disables)
+	outputDir                string        // Base directory for filesystem output (default: output/ for JSON, reports/ for HTML)
+	reportName               string        // File name for the HTML or JUnit report (default: timestamped when --output-dir is set)
+	profiles                 string        // Comma-separated list of AWS profiles, each scanned as a standalone account
+	ignoreFile               string        // Path to a YAML or JSON file of ignore rules
+	maxTasksPerAccount       int           // Maximum scanner tasks running at once against a single account (0 disables)
+	emitRemediation          string        // Path to write suggested remediation commands to (.json for an action list, otherwise a shell script)
+	remediationUncomment     bool          // Leave destructive remediation commands uncommented
+	tui                      bool          // Show a live progress view instead of periodic progress logs
+	s3KMSKeyID               string        // KMS key ARN used to encrypt S3 output
+	includeTags              string        // Only keep results carrying at least one of these KEY=VALUE tags
+	filenameTemplate         string        // Template for JSON output file names and S3 keys
+	s3Layout                 string        // S3 key layout: flat or partitioned
+	maxTasksPerRegion        int           // Maximum scanner tasks running at once in a single region (0 disables)
+	combinedOutput           bool          // Write all accounts to a single JSON file instead of one per account
+	preset                   string        // Named scan preset from the scans section of the config file
+	skipScanners             string        // Comma-separated list of scanners to leave out
+	minAgeDays               int           // Skip resources created fewer than this many days ago (0 disables)
+	organizationalUnits      string        // Comma-separated list of OU IDs whose accounts are scanned
+	webhookURL               string        // URL results are POSTed to with --output http
+	webhookToken             string        // Bearer token sent with webhook requests
+	requireAllAccounts       bool          // Fail instead of scanning a subset of the organization's accounts
+	reportTitle              string        // Heading of the HTML report
+	reportLogo               string        // Image file or http(s) URL shown beside the HTML report title
+	maxResultsPerScanner     int           // Maximum findings kept per scanner, account and region (0 disables)
+	checkpoint               string        // File completed scanner tasks are checkpointed to
+	resume                   string        // Checkpoint of an earlier scan whose completed tasks are skipped
+	currency                 string        // ISO 4217 currency cost estimates are reported in
+	exchangeRate             float64       // Units of the report currency one US dollar buys
+	onlyAccountsWithFindings bool          // Skip per-account output for accounts without findings
+	ec2Rightsizing           bool          // Recommend smaller instance types for running EC2 instances
+	actualSpend              bool          // Compare estimates with actual spend from Cost Explorer
+	s3ACL                    string        // Canned ACL applied to S3 output objects
+	s3StorageClass           string        // Storage class of S3 output objects
+	applyTags                bool          // Tag flagged resources after the scan
+	flagTag                  string        // KEY=VALUE tag applied to flagged resources with --apply-tags
+	dryRun                   bool          // Log the tags --apply-tags would apply without applying them
+	accountsFile             string        // JSON or CSV file listing the accounts to scan instead of Organizations
+	globalRegion             string        // Region global scanners and the cost estimator are called in
+	compress                 bool          // Gzip JSON output files and S3 objects
+	reportRegions            string        // Comma-separated list of regions to report findings from; ! excludes a region
+	baseline                 string        // Previous scan's JSON output to compare finding counts and costs against
+	anomalyThreshold         float64       // Percent change from --baseline that flags a scanner's finding count or cost
+	emitIgnored              string        // Path to write findings left out by the ignore rules and filters to
+	outputConcurrency        int           // Number of accounts whose results are written or uploaded at once
+	emitCloudWatch           bool          // Publish finding counts and savings as CloudWatch custom metrics
+	sessionDuration          time.Duration // How long each assumed-role session lasts before it is refreshed
+	profileRegionMap         string        // Comma-separated REGION=PROFILE pairs giving regions their own credentials
+	minConfidence            string        // Lowest finding confidence that is reported: low, medium or high
 }
 
 type scannerProgress struct {
@@ -54,13 +109,128 @@ type scannerProgress struct {
 
 type scannerProgressMap struct {
 	sync.RWMutex
-	progress map[string]*scannerProgress // key is accountID:region:scanner
+	progress      map[string]*scannerProgress // key is accountID:region:scanner
+	accountTotals map[string]int              // key is accountID, value is number of queued tasks
+	accountDone   map[string]int              // key is accountID, value is number of finished tasks
+	savings       float64                     // Estimated monthly cost of the findings reported so far, in USD
+	currency      string                      // Currency progress output reports savings in
+	rate          float64                     // Units of currency one US dollar buys
+}
+
+// scanProgressSummary is a point-in-time snapshot of overall scan completion
+type scanProgressSummary struct {
+	TotalTasks        int
+	FinishedTasks     int
+	TotalAccounts     int
+	CompletedAccounts int
 }
 
 func newScannerProgressMap() *scannerProgressMap {
 	return &scannerProgressMap{
-		progress: make(map[string]*scannerProgress),
+		progress:      make(map[string]*scannerProgress),
+		accountTotals: make(map[string]int),
+		accountDone:   make(map[string]int),
+		currency:      awsinternal.BaseCurrency,
+		rate:          1,
+	}
+}
+
+// setCurrency sets the currency progress output reports savings in, matching --currency
+func (s *scannerProgressMap) setCurrency(currency string, rate float64) {
+	s.Lock()
+	defer s.Unlock()
+	s.currency = currency
+	s.rate = rate
+}
+
+// addTask records that a task has been queued for the given account
+func (s *scannerProgressMap) addTask(accountID string) {
+	s.Lock()
+	defer s.Unlock()
+	s.accountTotals[accountID]++
+}
+
+// finishTask records that a queued task for the given account has finished, successfully or not
+func (s *scannerProgressMap) finishTask(accountID string) {
+	s.Lock()
+	defer s.Unlock()
+	s.accountDone[accountID]++
+}
+
+// summary returns the overall task and account completion counts
+func (s *scannerProgressMap) summary() scanProgressSummary {
+	s.RLock()
+	defer s.RUnlock()
+	var summary scanProgressSummary
+	for accountID, total := range s.accountTotals {
+		done := s.accountDone[accountID]
+		summary.TotalTasks += total
+		summary.FinishedTasks += done
+		summary.TotalAccounts++
+		if done >= total {
+			summary.CompletedAccounts++
+		}
+	}
+	return summary
+}
+
+// estimateRemaining estimates the time left for the scan based on the average task
+// execution time, the number of remaining tasks, and the number of workers running them
+func estimateRemaining(summary scanProgressSummary, avgExecutionMs int64, workers int) time.Duration {
+	remaining := summary.TotalTasks - summary.FinishedTasks
+	if remaining <= 0 || avgExecutionMs <= 0 {
+		return 0
+	}
+	if workers <= 0 {
+		workers = 1
 	}
+	// Remaining tasks are spread across the pool, so round the number of batches up
+	batches := (remaining + workers - 1) / workers
+	return time.Duration(int64(batches)*avgExecutionMs) * time.Millisecond
+}
+
+// formatETA formats an estimate from estimateRemaining, which is zero until a task has finished
+// and once every task has
+func formatETA(summary scanProgressSummary, eta time.Duration) string {
+	if summary.FinishedTasks >= summary.TotalTasks {
+		return "0s"
+	}
+	if eta <= 0 {
+		return "calculating"
+	}
+	return eta.Round(time.Second).String()
+}
+
+// formatProgressSummary formats overall task and account completion with an estimate of the
+// time left, for the periodic text progress output
+func formatProgressSummary(summary scanProgressSummary, eta time.Duration) string {
+	percent := 0.0
+	if summary.TotalTasks > 0 {
+		percent = float64(summary.FinishedTasks) / float64(summary.TotalTasks) * 100
+	}
+	return fmt.Sprintf("Tasks %d/%d (%.0f%%), accounts %d/%d complete, ETA %s",
+		summary.FinishedTasks, summary.TotalTasks, percent, summary.CompletedAccounts, summary.TotalAccounts, formatETA(summary, eta))
+}
+
+// addSavings adds the estimated monthly cost of newly reported findings to the running total
+func (s *scannerProgressMap) addSavings(monthly float64) {
+	s.Lock()
+	defer s.Unlock()
+	s.savings += monthly
+}
+
+// monthlySavings returns the estimated monthly cost of the findings reported so far, in USD
+func (s *scannerProgressMap) monthlySavings() float64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.savings
+}
+
+// formatSavings formats the savings found so far in the report currency, such as "€18.40"
+func (s *scannerProgressMap) formatSavings() string {
+	s.RLock()
+	defer s.RUnlock()
+	return fmt.Sprintf("%s%.2f", awsinternal.CurrencySymbol(s.currency), s.savings*s.rate)
 }
 
 func (s *scannerProgressMap) startScanner(accountID, accountName, region, scanner string) {
@@ -115,6 +285,7 @@ When no scanners or regions are specified, all available scanners will be run in
 When no organization-role is specified, only the current account will be scanned.
 When both organization-role and scanner-role are specified, all accounts in the organization will be scanned.
 When accounts is specified, only the specified accounts will be scanned. The accounts must exist in the organization.
+When profiles is specified, each profile is scanned as a standalone account and the results are merged into one report.
 
 Examples:
   # Scan all resources in all regions of current account
@@ -126,15 +297,66 @@ Examples:
   # Scan multiple resource types in multiple regions of all organization accounts
   cloudsift scan --scanners ebs-volumes,ebs-snapshots --regions us-west-2,us-east-1 --organization-role OrganizationAccessRole --scanner-role SecurityAuditRole
 
+  # Run every scanner except the IAM scanners
+  cloudsift scan --skip-scanners iam-roles,iam-users,iam-access-keys
+
   # Scan specific accounts in the organization
   cloudsift scan --accounts 123456789012,098765432109 --organization-role OrganizationAccessRole --scanner-role SecurityAuditRole
 
+  # Skip resources listed in an ignore file
+  cloudsift scan --ignore-file ./cloudsift-ignore.yaml
+
+  # Only report resources owned by one team
+  cloudsift scan --include-tags team=data
+
+  # Leave out resources created in the last two weeks
+  cloudsift scan --min-age-days 14
+
+  # Scan only the accounts in an organizational unit and the OUs below it
+  cloudsift scan --organization-role OrganizationAccountAccessRole --scanner-role SecurityAuditRole \
+    --organizational-units ou-ab12-cdef3456
+
+  # Watch a long scan in a live terminal view
+  cloudsift scan --tui
+
+  # Write suggested remediation commands for review, with deletes commented out
+  cloudsift scan --emit-remediation ./remediate.sh
+
+  # Scan several standalone accounts, one per profile, into a single report
+  cloudsift scan --profiles dev,staging,prod --output-format html
+
+  # Run the "nightly" preset from the scans section of the config file
+  cloudsift scan --preset nightly
+
+  # Write one JSON file covering every account instead of one per account
+  cloudsift scan --output-format json --combined-output
+
+  # Write findings as a JUnit XML report for CI, failing the build over $500/month
+  cloudsift scan --output-format junit --output-dir ./test-results --fail-over-cost 500
+
+  # Write the HTML report to a separate directory so parallel scans don't overwrite each other
+  cloudsift scan --output-dir ./scans/prod --report-name prod-weekly
+
   # Output HTML report to S3
   cloudsift scan --output s3 --output-format html --bucket my-bucket --bucket-region us-west-2
 
   # Output JSON results to S3
-  cloudsift scan --output s3 --output-format json --bucket my-bucket --bucket-region us-west-2`,
+  cloudsift scan --output s3 --output-format json --bucket my-bucket --bucket-region us-west-2
+
+  # Post JSON results to an ingestion API
+  cloudsift scan --output http --webhook-url https://ingest.example.com/cloudsift --webhook-token $TOKEN
+
+  # Encrypt S3 output with a specific KMS key
+  cloudsift scan --output s3 --bucket my-bucket --bucket-region us-west-2 \
+    --s3-kms-key-id arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// A preset fills in every flag that wasn't given on the command line
+			if opts.preset != "" {
+				if err := applyScanPreset(cmd, opts.preset); err != nil {
+					return err
+				}
+			}
+
 			// Command line flags should take precedence over config and env vars
 			if cmd.Flags().Changed("regions") {
 				config.Config.ScanRegions = opts.regions
@@ -170,18 +392,155 @@ Examples:
 				config.Config.ScanIgnoreResourceNames = strings.Split(opts.ignoreResourceNames, ",")
 			}
 			if cmd.Flags().Changed("ignore-tags") {
-				tags := make(map[string]string)
-				for _, tag := range strings.Split(opts.ignoreTags, ",") {
-					parts := strings.SplitN(tag, "=", 2)
-					if len(parts) == 2 {
-						tags[parts[0]] = parts[1]
-					}
-				}
-				config.Config.ScanIgnoreTags = tags
+				config.Config.ScanIgnoreTags = parseTagList(opts.ignoreTags)
 			}
 			if cmd.Flags().Changed("accounts") {
 				config.Config.ScanAccounts = strings.Split(opts.accounts, ",")
 			}
+			if cmd.Flags().Changed("scanner-timeout") {
+				config.Config.ScanScannerTimeout = opts.scannerTimeout
+			}
+			if cmd.Flags().Changed("fail-over-cost") {
+				config.Config.ScanFailOverCost = opts.failOverCost
+			}
+			if cmd.Flags().Changed("output-dir") {
+				config.Config.ScanOutputDir = opts.outputDir
+			}
+			if cmd.Flags().Changed("report-name") {
+				config.Config.ScanReportName = opts.reportName
+			}
+			if cmd.Flags().Changed("profiles") {
+				config.Config.ScanProfiles = strings.Split(opts.profiles, ",")
+			}
+			if cmd.Flags().Changed("ignore-file") {
+				config.Config.ScanIgnoreFile = opts.ignoreFile
+			}
+			if cmd.Flags().Changed("max-tasks-per-account") {
+				config.Config.ScanMaxTasksPerAccount = opts.maxTasksPerAccount
+			}
+			if cmd.Flags().Changed("emit-remediation") {
+				config.Config.ScanEmitRemediation = opts.emitRemediation
+			}
+			if cmd.Flags().Changed("remediation-uncomment") {
+				config.Config.ScanRemediationUncomment = opts.remediationUncomment
+			}
+			if cmd.Flags().Changed("tui") {
+				config.Config.ScanTUI = opts.tui
+			}
+			if cmd.Flags().Changed("s3-kms-key-id") {
+				config.Config.ScanS3KMSKeyID = opts.s3KMSKeyID
+			}
+			if cmd.Flags().Changed("include-tags") {
+				config.Config.ScanIncludeTags = parseTagList(opts.includeTags)
+			}
+			if cmd.Flags().Changed("filename-template") {
+				config.Config.ScanFilenameTemplate = opts.filenameTemplate
+			}
+			if cmd.Flags().Changed("s3-layout") {
+				config.Config.ScanS3Layout = opts.s3Layout
+			}
+			if cmd.Flags().Changed("max-tasks-per-region") {
+				config.Config.ScanMaxTasksPerRegion = opts.maxTasksPerRegion
+			}
+			if cmd.Flags().Changed("combined-output") {
+				config.Config.ScanCombinedOutput = opts.combinedOutput
+			}
+			if cmd.Flags().Changed("skip-scanners") {
+				config.Config.ScanSkipScanners = opts.skipScanners
+			}
+			if cmd.Flags().Changed("min-age-days") {
+				config.Config.ScanMinAgeDays = opts.minAgeDays
+			}
+			if cmd.Flags().Changed("organizational-units") {
+				config.Config.ScanOrganizationalUnits = strings.Split(opts.organizationalUnits, ",")
+			}
+			if cmd.Flags().Changed("webhook-url") {
+				config.Config.ScanWebhookURL = opts.webhookURL
+			}
+			if cmd.Flags().Changed("webhook-token") {
+				config.Config.ScanWebhookToken = opts.webhookToken
+			}
+			if cmd.Flags().Changed("require-all-accounts") {
+				config.Config.ScanRequireAllAccounts = opts.requireAllAccounts
+			}
+			if cmd.Flags().Changed("report-title") {
+				config.Config.ScanReportTitle = opts.reportTitle
+			}
+			if cmd.Flags().Changed("report-logo") {
+				config.Config.ScanReportLogo = opts.reportLogo
+			}
+			if cmd.Flags().Changed("max-results-per-scanner") {
+				config.Config.ScanMaxResultsPerScanner = opts.maxResultsPerScanner
+			}
+			if cmd.Flags().Changed("checkpoint") {
+				config.Config.ScanCheckpoint = opts.checkpoint
+			}
+			if cmd.Flags().Changed("currency") {
+				config.Config.ScanCurrency = opts.currency
+			}
+			if cmd.Flags().Changed("exchange-rate") {
+				config.Config.ScanExchangeRate = opts.exchangeRate
+			}
+			if cmd.Flags().Changed("only-accounts-with-findings") {
+				config.Config.ScanOnlyAccountsWithFindings = opts.onlyAccountsWithFindings
+			}
+			if cmd.Flags().Changed("ec2-rightsizing") {
+				config.Config.ScanEC2Rightsizing = opts.ec2Rightsizing
+			}
+			if cmd.Flags().Changed("actual-spend") {
+				config.Config.ScanActualSpend = opts.actualSpend
+			}
+			if cmd.Flags().Changed("s3-acl") {
+				config.Config.ScanS3ACL = opts.s3ACL
+			}
+			if cmd.Flags().Changed("s3-storage-class") {
+				config.Config.ScanS3StorageClass = opts.s3StorageClass
+			}
+			if cmd.Flags().Changed("apply-tags") {
+				config.Config.ScanApplyTags = opts.applyTags
+			}
+			if cmd.Flags().Changed("flag-tag") {
+				config.Config.ScanFlagTag = opts.flagTag
+			}
+			if cmd.Flags().Changed("dry-run") {
+				config.Config.ScanDryRun = opts.dryRun
+			}
+			if cmd.Flags().Changed("accounts-file") {
+				config.Config.ScanAccountsFile = opts.accountsFile
+			}
+			if cmd.Flags().Changed("global-region") {
+				config.Config.ScanGlobalRegion = opts.globalRegion
+			}
+			if cmd.Flags().Changed("compress") {
+				config.Config.ScanCompress = opts.compress
+			}
+			if cmd.Flags().Changed("report-regions") {
+				config.Config.ScanReportRegions = opts.reportRegions
+			}
+			if cmd.Flags().Changed("baseline") {
+				config.Config.ScanBaseline = opts.baseline
+			}
+			if cmd.Flags().Changed("anomaly-threshold") {
+				config.Config.ScanAnomalyThreshold = opts.anomalyThreshold
+			}
+			if cmd.Flags().Changed("emit-ignored") {
+				config.Config.ScanEmitIgnored = opts.emitIgnored
+			}
+			if cmd.Flags().Changed("output-concurrency") {
+				config.Config.ScanOutputConcurrency = opts.outputConcurrency
+			}
+			if cmd.Flags().Changed("emit-cloudwatch") {
+				config.Config.ScanEmitCloudWatch = opts.emitCloudWatch
+			}
+			if cmd.Flags().Changed("session-duration") {
+				config.Config.ScanSessionDuration = opts.sessionDuration
+			}
+			if cmd.Flags().Changed("profile-region-map") {
+				config.Config.ScanProfileRegionMap, _ = parseProfileRegionMap(opts.profileRegionMap)
+			}
+			if cmd.Flags().Changed("min-confidence") {
+				config.Config.ScanMinConfidence = opts.minConfidence
+			}
 
 			// Bind scan-specific flags to viper
 			if err := viper.BindPFlag("scan.regions", cmd.Flags().Lookup("regions")); err != nil {
@@ -217,44 +576,190 @@ Examples:
 			if err := viper.BindPFlag("scan.accounts", cmd.Flags().Lookup("accounts")); err != nil {
 				return err
 			}
+			if err := viper.BindPFlag("scan.scanner_timeout", cmd.Flags().Lookup("scanner-timeout")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.fail_over_cost", cmd.Flags().Lookup("fail-over-cost")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.output_dir", cmd.Flags().Lookup("output-dir")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.report_name", cmd.Flags().Lookup("report-name")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.profiles", cmd.Flags().Lookup("profiles")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.ignore_file", cmd.Flags().Lookup("ignore-file")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.max_tasks_per_account", cmd.Flags().Lookup("max-tasks-per-account")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.emit_remediation", cmd.Flags().Lookup("emit-remediation")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.remediation_uncomment", cmd.Flags().Lookup("remediation-uncomment")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.tui", cmd.Flags().Lookup("tui")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.s3_kms_key_id", cmd.Flags().Lookup("s3-kms-key-id")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.include_tags", cmd.Flags().Lookup("include-tags")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.filename_template", cmd.Flags().Lookup("filename-template")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.s3_layout", cmd.Flags().Lookup("s3-layout")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.max_tasks_per_region", cmd.Flags().Lookup("max-tasks-per-region")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.combined_output", cmd.Flags().Lookup("combined-output")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.skip_scanners", cmd.Flags().Lookup("skip-scanners")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.min_age_days", cmd.Flags().Lookup("min-age-days")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.organizational_units", cmd.Flags().Lookup("organizational-units")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.webhook_url", cmd.Flags().Lookup("webhook-url")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.webhook_token", cmd.Flags().Lookup("webhook-token")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.require_all_accounts", cmd.Flags().Lookup("require-all-accounts")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.report_title", cmd.Flags().Lookup("report-title")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.report_logo", cmd.Flags().Lookup("report-logo")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.max_results_per_scanner", cmd.Flags().Lookup("max-results-per-scanner")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.checkpoint", cmd.Flags().Lookup("checkpoint")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.currency", cmd.Flags().Lookup("currency")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.exchange_rate", cmd.Flags().Lookup("exchange-rate")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.only_accounts_with_findings", cmd.Flags().Lookup("only-accounts-with-findings")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.ec2_rightsizing", cmd.Flags().Lookup("ec2-rightsizing")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.actual_spend", cmd.Flags().Lookup("actual-spend")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.s3_acl", cmd.Flags().Lookup("s3-acl")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.s3_storage_class", cmd.Flags().Lookup("s3-storage-class")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.apply_tags", cmd.Flags().Lookup("apply-tags")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.flag_tag", cmd.Flags().Lookup("flag-tag")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.dry_run", cmd.Flags().Lookup("dry-run")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.accounts_file", cmd.Flags().Lookup("accounts-file")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.global_region", cmd.Flags().Lookup("global-region")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.compress", cmd.Flags().Lookup("compress")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.report_regions", cmd.Flags().Lookup("report-regions")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.baseline", cmd.Flags().Lookup("baseline")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.anomaly_threshold", cmd.Flags().Lookup("anomaly-threshold")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.emit_ignored", cmd.Flags().Lookup("emit-ignored")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.output_concurrency", cmd.Flags().Lookup("output-concurrency")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.emit_cloudwatch", cmd.Flags().Lookup("emit-cloudwatch")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.session_duration", cmd.Flags().Lookup("session-duration")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.profile_region_map", cmd.Flags().Lookup("profile-region-map")); err != nil {
+				return err
+			}
+			if err := viper.BindPFlag("scan.min_confidence", cmd.Flags().Lookup("min-confidence")); err != nil {
+				return err
+			}
 
 			// Log configuration sources after binding all flags
 			config.LogConfigurationSources(true, cmd)
 
-			// Validate output format
-			switch opts.outputFormat {
-			case "json", "html":
-				// Valid formats
-			default:
-				return fmt.Errorf("invalid output format: %s", opts.outputFormat)
+			// Report the first problem; config validate reports them all
+			if errs := validateScanOptions(opts); len(errs) > 0 {
+				return errs[0]
 			}
 
-			// Validate output type
-			switch opts.output {
-			case "filesystem", "s3":
-				// Valid output types
-			default:
-				return fmt.Errorf("invalid output type: %s", opts.output)
+			// Merge ignore rules from the ignore file into the configured ignore lists
+			if opts.ignoreFile != "" {
+				ignoreFile, err := config.LoadIgnoreFile(opts.ignoreFile)
+				if err != nil {
+					return err
+				}
+				ignoreFile.MergeInto(config.Config)
 			}
 
-			// Validate S3 parameters
-			if opts.output == "s3" {
-				if opts.bucket == "" {
-					return fmt.Errorf("--bucket is required when --output=s3")
-				}
-				if opts.bucketRegion == "" {
-					return fmt.Errorf("--bucket-region is required when --output=s3")
-				}
+			// Scoped rules in the config file apply alongside those of the ignore file and preset
+			rules, err := config.LoadScanIgnoreRules()
+			if err != nil {
+				return err
 			}
+			config.Config.ScanIgnoreRules = append(config.Config.ScanIgnoreRules, rules...)
 
 			return runScan(cmd, opts)
 		},
 	}
 
+	addScanFlags(cmd, opts)
+
+	return cmd
+}
+
+// addScanFlags registers the scan command's flags on cmd, bound to opts
+func addScanFlags(cmd *cobra.Command, opts *scanOptions) {
 	cmd.Flags().StringVar(&opts.regions, "regions", "", "Comma-separated list of regions to scan (default: all available regions)")
 	cmd.Flags().StringVar(&opts.scanners, "scanners", "", "Comma-separated list of scanners to run (default: all available scanners)")
-	cmd.Flags().StringVar(&opts.output, "output", "filesystem", "Output type (filesystem, s3)")
-	cmd.Flags().StringVarP(&opts.outputFormat, "output-format", "o", "html", "Output format (json, html)")
+	cmd.Flags().StringVar(&opts.output, "output", "filesystem", "Output type (filesystem, s3, http)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output-format", "o", "html", "Output format (json, html, junit)")
 	cmd.Flags().StringVar(&opts.bucket, "bucket", "", "S3 bucket name (required when --output=s3)")
 	cmd.Flags().StringVar(&opts.bucketRegion, "bucket-region", "", "S3 bucket region (required when --output=s3)")
 	cmd.Flags().StringVar(&opts.organizationRole, "organization-role", "", "Role to assume for listing organization accounts")
@@ -264,69 +769,429 @@ Examples:
 	cmd.Flags().StringVar(&opts.ignoreResourceNames, "ignore-resource-names", "", "Comma-separated list of resource names to ignore (case-insensitive)")
 	cmd.Flags().StringVar(&opts.ignoreTags, "ignore-tags", "", "Comma-separated list of tags to ignore in KEY=VALUE format (case-insensitive)")
 	cmd.Flags().StringVar(&opts.accounts, "accounts", "", "Comma-separated list of account IDs to scan (default: all accounts in organization)")
-
-	return cmd
+	cmd.Flags().DurationVar(&opts.scannerTimeout, "scanner-timeout", worker.DefaultTaskTimeout, "Maximum time a single scanner may run in one account and region before it is cancelled")
+	cmd.Flags().Float64Var(&opts.failOverCost, "fail-over-cost", 0, "Exit with an error when the estimated monthly cost of all findings exceeds this amount in USD (0 disables)")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Base directory for filesystem output (default: output/ for JSON and reports/ for HTML)")
+	cmd.Flags().StringVar(&opts.reportName, "report-name", "", "File name for the HTML or JUnit report (default: scan_report, timestamped when --output-dir is set)")
+	cmd.Flags().StringVar(&opts.profiles, "profiles", "", "Comma-separated list of AWS profiles to scan, each treated as a standalone account")
+	cmd.Flags().StringVar(&opts.ignoreFile, "ignore-file", "", "Path to a YAML or JSON file of resource IDs, names and tags to ignore, optionally scoped per scanner and account")
+	cmd.Flags().IntVar(&opts.maxTasksPerAccount, "max-tasks-per-account", 0, "Maximum scanner tasks to run at once against a single account, so one account is not throttled (0 disables)")
+	cmd.Flags().StringVar(&opts.emitRemediation, "emit-remediation", "", "Write suggested AWS CLI remediation commands to this path: a JSON action list for .json files, otherwise a shell script")
+	cmd.Flags().BoolVar(&opts.remediationUncomment, "remediation-uncomment", false, "Leave destructive commands (deletes, terminations) uncommented in the remediation script")
+	cmd.Flags().BoolVar(&opts.tui, "tui", false, "Show a live view of running scanners, task progress and savings found (falls back to text progress when stdout is not a terminal)")
+	cmd.Flags().StringVar(&opts.s3KMSKeyID, "s3-kms-key-id", "", "ARN of the KMS key used to encrypt S3 output (default: the bucket's AWS managed key)")
+	cmd.Flags().StringVar(&opts.includeTags, "include-tags", "", "Comma-separated list of tags in KEY=VALUE format; only resources carrying at least one of them are reported (case-insensitive)")
+	cmd.Flags().StringVar(&opts.filenameTemplate, "filename-template", "", "Template for JSON output file names and S3 keys using {account_id}, {account_name}, {date}, {year}, {month}, {day}, {time}, {timestamp} and {format}, e.g. \"{date}/{account_id}.json\" (default: YYYY/MM/DD/<account_id>/HH-MM-SS.json.gz)")
+	cmd.Flags().StringVar(&opts.s3Layout, "s3-layout", "flat", "S3 key layout: flat (one JSON file per account under YYYY/MM/DD/<account_id>/) or partitioned (JSON lines under scan_date=YYYY-MM-DD/account_id=<id>/ for Athena)")
+	cmd.Flags().IntVar(&opts.maxTasksPerRegion, "max-tasks-per-region", 0, "Maximum scanner tasks to run at once in a single region, so a slow or throttled region can't take over the pool (0 disables)")
+	cmd.Flags().BoolVar(&opts.combinedOutput, "combined-output", false, "Write one JSON file containing every account's results, keyed by account ID, instead of one file per account")
+	cmd.Flags().StringVar(&opts.preset, "preset", "", "Name of a scan preset from the scans section of the config file; flags given on the command line override it")
+	cmd.Flags().StringVar(&opts.skipScanners, "skip-scanners", "", "Comma-separated list of scanners to leave out, applied after --scanners (e.g. iam-roles,iam-users)")
+	cmd.Flags().IntVar(&opts.minAgeDays, "min-age-days", 0, "Don't report resources created fewer than this many days ago; resources without a known creation time are always reported (0 disables)")
+	cmd.Flags().StringVar(&opts.organizationalUnits, "organizational-units", "", "Comma-separated list of organizational unit IDs (e.g. ou-ab12-cdef3456); only accounts in these OUs, or in OUs nested below them, are scanned (requires --organization-role and --scanner-role)")
+	cmd.Flags().StringVar(&opts.webhookURL, "webhook-url", "", "URL to POST each account's JSON results to (required when --output=http)")
+	cmd.Flags().StringVar(&opts.webhookToken, "webhook-token", "", "Bearer token to send with webhook requests (prefer CLOUDSIFT_SCAN_WEBHOOK_TOKEN, which keeps it out of shell history)")
+	cmd.Flags().BoolVar(&opts.requireAllAccounts, "require-all-accounts", false, "Fail if organization accounts can't be listed or the scanner role can't be assumed in every account, instead of scanning the accounts that are reachable")
+	cmd.Flags().StringVar(&opts.reportTitle, "report-title", "", "Title shown at the top of the HTML report (default: CloudSift Scan Report)")
+	cmd.Flags().StringVar(&opts.reportLogo, "report-logo", "", "Image file or http(s) URL shown beside the HTML report title; files are embedded in the report")
+	cmd.Flags().IntVar(&opts.maxResultsPerScanner, "max-results-per-scanner", 0, "Maximum findings kept per scanner in each account and region, keeping the most expensive; the rest are counted as truncated (0 disables)")
+	cmd.Flags().StringVar(&opts.checkpoint, "checkpoint", "", "Periodically save completed scanner tasks and their findings to this file so a failed scan can be continued with --resume")
+	cmd.Flags().StringVar(&opts.resume, "resume", "", "Continue a failed scan from a checkpoint written with --checkpoint, skipping the scanner tasks it completed")
+	cmd.Flags().StringVar(&opts.currency, "currency", awsinternal.BaseCurrency, "ISO 4217 currency to report cost estimates in, e.g. EUR; estimates are converted from USD with --exchange-rate")
+	cmd.Flags().Float64Var(&opts.exchangeRate, "exchange-rate", 0, "Units of --currency one US dollar buys, e.g. 0.92 for EUR")
+	cmd.Flags().BoolVar(&opts.onlyAccountsWithFindings, "only-accounts-with-findings", false, "Only write JSON output for accounts with findings or scanner errors; other accounts are still counted in the summary")
+	cmd.Flags().BoolVar(&opts.ec2Rightsizing, "ec2-rightsizing", false, "Recommend smaller instance types in the same family for running EC2 instances, based on peak utilization over --days-unused days")
+	cmd.Flags().BoolVar(&opts.actualSpend, "actual-spend", false, "Look up each account's actual spend over the last 30 days on the services behind its findings in Cost Explorer and report it alongside the estimates")
+	cmd.Flags().StringVar(&opts.s3ACL, "s3-acl", "", "Canned ACL applied to S3 output objects, e.g. bucket-owner-full-control for buckets in another account (default: none)")
+	cmd.Flags().StringVar(&opts.s3StorageClass, "s3-storage-class", "", "Storage class of S3 output objects, e.g. STANDARD_IA (default: STANDARD)")
+	cmd.Flags().BoolVar(&opts.applyTags, "apply-tags", false, "After the scan, tag each flagged resource with --flag-tag and the reason it was flagged (requires tagging permissions)")
+	cmd.Flags().StringVar(&opts.flagTag, "flag-tag", defaultFlagTag, "KEY=VALUE tag applied to flagged resources with --apply-tags; the reason is tagged under KEY:reason")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "With --apply-tags, log the tags that would be applied without applying them")
+	cmd.Flags().StringVar(&opts.accountsFile, "accounts-file", "", "JSON or CSV file listing account IDs and names to scan instead of listing them through Organizations")
+	cmd.Flags().StringVar(&opts.globalRegion, "global-region", "", "Region global scanners such as IAM and the cost estimator are called in (default: us-east-1, or the partition's global region in GovCloud and China)")
+	cmd.Flags().BoolVar(&opts.compress, "compress", true, "Gzip JSON output files and S3 objects, appending .gz to their names; --compress=false writes plain JSON")
+	cmd.Flags().StringVar(&opts.reportRegions, "report-regions", "", "Comma-separated list of regions to report findings from, including global for global scanners; prefix a region with ! to leave it out instead (e.g. us-east-1,global or !global)")
+	cmd.Flags().StringVar(&opts.baseline, "baseline", "", "Previous scan's JSON output (per-account or combined, optionally gzipped) to compare each scanner's finding count and cost against")
+	cmd.Flags().Float64Var(&opts.anomalyThreshold, "anomaly-threshold", 50, "Flag scanners whose finding count or monthly cost changed by more than this percent from --baseline")
+	cmd.Flags().StringVar(&opts.emitIgnored, "emit-ignored", "", "Write every finding left out by --include-tags, --min-age-days, --min-confidence or the ignore rules to this JSON file, with the rule that matched")
+	cmd.Flags().IntVar(&opts.outputConcurrency, "output-concurrency", 10, "Number of accounts whose results are written, uploaded to S3 or posted to the webhook at once")
+	cmd.Flags().BoolVar(&opts.emitCloudWatch, "emit-cloudwatch", false, "Publish UnusedResources and EstimatedMonthlySavings CloudWatch metrics in the CloudSift namespace, by account, region and scanner, at the end of the scan")
+	cmd.Flags().DurationVar(&opts.sessionDuration, "session-duration", awsinternal.DefaultSessionDuration, "How long each assumed-role session lasts (15m to 12h, up to the role's maximum session duration); credentials are refreshed automatically before they expire")
+	cmd.Flags().StringVar(&opts.profileRegionMap, "profile-region-map", "", "Comma-separated REGION=PROFILE pairs scanning regions with their own AWS profile or role ARN instead of the default credentials (e.g. us-gov-west-1=govcloud)")
+	cmd.Flags().StringVar(&opts.minConfidence, "min-confidence", awsinternal.ConfidenceLow, "Only report findings with at least this confidence: low, medium or high")
 }
 
-type scanResult struct {
-	AccountID   string                             `json:"account_id"`
-	AccountName string                             `json:"account_name"`
-	Results     map[string]awsinternal.ScanResults `json:"results"` // Map of scanner name to results
-}
+// validateScanOptions checks scan options that can be verified without calling AWS and returns
+// every problem found rather than stopping at the first
+func validateScanOptions(opts *scanOptions) []error {
+	var errs []error
+
+	// Validate output format
+	switch opts.outputFormat {
+	case "json", "html", "junit":
+		// Valid formats
+	default:
+		errs = append(errs, fmt.Errorf("invalid output format: %s", opts.outputFormat))
+	}
 
-// isIAMScanner returns true if the scanner is for IAM resources
-func isIAMScanner(scanner awsinternal.Scanner) bool {
-	return scanner.Label() == "IAM Roles" || scanner.Label() == "IAM Users"
-}
+	// Validate output type
+	switch opts.output {
+	case "filesystem", "s3", "http":
+		// Valid output types
+	default:
+		errs = append(errs, fmt.Errorf("invalid output type: %s", opts.output))
+	}
 
-func getScanners(scannerList string) ([]awsinternal.Scanner, []string, error) {
-	var scanners []awsinternal.Scanner
-	var invalidScanners []string
+	// Validate scanner timeout
+	if opts.scannerTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--scanner-timeout must be greater than 0"))
+	}
 
-	// If no scanners specified, get all available scanners
-	if scannerList == "" {
-		// If no scanners specified, get all available scanners
-		names := awsinternal.DefaultRegistry.ListScanners()
-		if len(names) == 0 {
-			return nil, nil, fmt.Errorf("no scanners available in registry")
-		}
+	// Validate cost threshold
+	if opts.failOverCost < 0 {
+		errs = append(errs, fmt.Errorf("--fail-over-cost must not be negative"))
+	}
 
-		for _, name := range names {
-			scanner, err := awsinternal.DefaultRegistry.GetScanner(name)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to get scanner '%s': %w", name, err)
-			}
-			scanners = append(scanners, scanner)
-		}
-		return scanners, invalidScanners, nil
+	// Profiles are standalone accounts and can't be combined with organization scanning
+	if opts.profiles != "" && opts.organizationRole != "" && opts.scannerRole != "" {
+		errs = append(errs, fmt.Errorf("--profiles cannot be combined with --organization-role and --scanner-role"))
 	}
 
-	// Parse comma-separated list of scanners
-	names := strings.Split(scannerList, ",")
-	for _, name := range names {
-		scanner, err := awsinternal.DefaultRegistry.GetScanner(name)
-		if err != nil {
-			// Track invalid scanner but continue processing
-			invalidScanners = append(invalidScanners, name)
-			continue
+	// Validate per-account task limit
+	if opts.maxTasksPerAccount < 0 {
+		errs = append(errs, fmt.Errorf("--max-tasks-per-account must not be negative"))
+	}
+
+	// Validate the KMS key before the scan so a bad key fails fast
+	if opts.s3KMSKeyID != "" {
+		if err := validateKMSKeyARN(opts.s3KMSKeyID); err != nil {
+			errs = append(errs, err)
 		}
-		scanners = append(scanners, scanner)
 	}
 
-	return scanners, invalidScanners, nil
+	// Validate the output file name template. Each account gets its own file unless the output is
+	// combined or the partitioned S3 layout already puts the account in the key.
+	perAccount := !opts.combinedOutput && !(opts.output == "s3" && output.Layout(opts.s3Layout) == output.PartitionedLayout)
+	if err := output.ValidateFilenameTemplate(opts.filenameTemplate, perAccount); err != nil {
+		errs = append(errs, fmt.Errorf("invalid --filename-template: %w", err))
+	}
+
+	// Validate S3 key layout
+	switch output.Layout(opts.s3Layout) {
+	case output.FlatLayout, output.PartitionedLayout:
+		// Valid layouts
+	default:
+		errs = append(errs, fmt.Errorf("invalid --s3-layout: %s (must be flat or partitioned)", opts.s3Layout))
+	}
+
+	// Validate per-region task limit
+	if opts.maxTasksPerRegion < 0 {
+		errs = append(errs, fmt.Errorf("--max-tasks-per-region must not be negative"))
+	}
+
+	// Combined output is a single JSON document, so it needs the flat JSON layout
+	if opts.combinedOutput {
+		if opts.outputFormat != "json" && opts.output == "filesystem" {
+			errs = append(errs, fmt.Errorf("--combined-output requires --output-format json"))
+		}
+		if output.Layout(opts.s3Layout) == output.PartitionedLayout {
+			errs = append(errs, fmt.Errorf("--combined-output cannot be used with --s3-layout partitioned"))
+		}
+	}
+
+	// Validate minimum resource age
+	if opts.minAgeDays < 0 {
+		errs = append(errs, fmt.Errorf("--min-age-days must not be negative"))
+	}
+
+	// Validate organizational units
+	if opts.organizationalUnits != "" {
+		if opts.organizationRole == "" || opts.scannerRole == "" {
+			errs = append(errs, fmt.Errorf("--organizational-units requires --organization-role and --scanner-role"))
+		}
+		for _, ouID := range strings.Split(opts.organizationalUnits, ",") {
+			if !organizationalUnitPattern.MatchString(strings.TrimSpace(ouID)) {
+				errs = append(errs, fmt.Errorf("invalid organizational unit ID %q: expected an OU ID such as ou-ab12-cdef3456 or a root ID such as r-ab12", strings.TrimSpace(ouID)))
+			}
+		}
+	}
+
+	// Validate webhook parameters
+	if opts.output == "http" {
+		if opts.webhookURL == "" {
+			errs = append(errs, fmt.Errorf("--webhook-url is required when --output=http"))
+		} else if err := validateWebhookURL(opts.webhookURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Read the logo up front so a bad path fails before the scan rather than after it
+	if opts.reportLogo != "" {
+		if _, err := html.LogoURL(opts.reportLogo); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --report-logo: %w", err))
+		}
+	}
+
+	// Validate per-scanner result limit
+	if opts.maxResultsPerScanner < 0 {
+		errs = append(errs, fmt.Errorf("--max-results-per-scanner must not be negative"))
+	}
+
+	// Validate output concurrency
+	if opts.outputConcurrency < 1 {
+		errs = append(errs, fmt.Errorf("--output-concurrency must be at least 1"))
+	}
+
+	// Validate assumed-role session duration, which STS accepts between 15 minutes and 12 hours
+	if opts.sessionDuration < 15*time.Minute || opts.sessionDuration > 12*time.Hour {
+		errs = append(errs, fmt.Errorf("--session-duration must be between 15m and 12h"))
+	}
+
+	// Validate currency conversion
+	if opts.currency != "" {
+		currency, err := awsinternal.NormalizeCurrency(opts.currency)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid --currency: %w", err))
+		} else if currency != awsinternal.BaseCurrency && opts.exchangeRate == 0 && awsinternal.DefaultExchangeRateProvider == nil {
+			errs = append(errs, fmt.Errorf("--exchange-rate is required when --currency=%s", currency))
+		}
+	}
+	if opts.exchangeRate < 0 {
+		errs = append(errs, fmt.Errorf("--exchange-rate must not be negative"))
+	}
+
+	// Validate S3 object settings before the scan so a rejected upload doesn't lose results
+	if err := output.ValidateS3ACL(opts.s3ACL); err != nil {
+		errs = append(errs, err)
+	}
+	if err := output.ValidateS3StorageClass(opts.s3StorageClass); err != nil {
+		errs = append(errs, err)
+	}
+
+	if opts.applyTags {
+		if _, _, err := parseFlagTag(opts.flagTag); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Accounts from a file are reached by assuming the scanner role in each of them
+	if opts.accountsFile != "" && opts.profiles != "" {
+		errs = append(errs, fmt.Errorf("--accounts-file cannot be combined with --profiles"))
+	}
+	if opts.accountsFile != "" && opts.scannerRole == "" {
+		errs = append(errs, fmt.Errorf("--accounts-file requires --scanner-role"))
+	}
+
+	// Global findings are filtered as region "global", so any non-empty name is accepted
+	if _, _, err := parseRegionFilter(opts.reportRegions); err != nil {
+		errs = append(errs, err)
+	}
+
+	if opts.baseline != "" && opts.anomalyThreshold <= 0 {
+		errs = append(errs, fmt.Errorf("--anomaly-threshold must be greater than 0"))
+	}
+
+	// Validate region to profile mappings; each profile is its own account, so they can't be remapped
+	if opts.profileRegionMap != "" {
+		if _, err := parseProfileRegionMap(opts.profileRegionMap); err != nil {
+			errs = append(errs, err)
+		}
+		if opts.profiles != "" {
+			errs = append(errs, fmt.Errorf("--profile-region-map cannot be combined with --profiles"))
+		}
+	}
+
+	// Validate minimum confidence
+	if opts.minConfidence != "" && !awsinternal.ValidConfidence(opts.minConfidence) {
+		errs = append(errs, fmt.Errorf("invalid --min-confidence %q: must be low, medium or high", opts.minConfidence))
+	}
+
+	// Validate S3 parameters
+	if opts.output == "s3" {
+		if opts.bucket == "" {
+			errs = append(errs, fmt.Errorf("--bucket is required when --output=s3"))
+		}
+		if opts.bucketRegion == "" {
+			errs = append(errs, fmt.Errorf("--bucket-region is required when --output=s3"))
+		}
+	}
+
+	return errs
+}
+
+// applyScanPreset sets every scan flag the named preset defines, unless the flag was given on the
+// command line, and adds the preset's scoped ignore rules
+func applyScanPreset(cmd *cobra.Command, name string) error {
+	preset, err := config.LoadScanPreset(name)
+	if err != nil {
+		return err
+	}
+
+	for flagName, value := range preset.Flags {
+		if cmd.Flags().Changed(flagName) {
+			continue
+		}
+		if err := cmd.Flags().Set(flagName, value); err != nil {
+			return fmt.Errorf("invalid %s in scan preset %q: %w", flagName, name, err)
+		}
+	}
+	config.Config.ScanIgnoreRules = append(config.Config.ScanIgnoreRules, preset.IgnoreRules...)
+
+	logging.Info("Applied scan preset", map[string]interface{}{
+		"preset":   name,
+		"settings": len(preset.Flags),
+	})
+	return nil
+}
+
+type scanResult struct {
+	AccountID   string                             `json:"account_id"`
+	AccountName string                             `json:"account_name"`
+	Profile     string                             `json:"profile,omitempty"`         // AWS profile the account was scanned through
+	Version     string                             `json:"cloudsift_version"`         // CloudSift build that produced the results
+	Results     map[string]awsinternal.ScanResults `json:"results"`                   // Map of scanner name to results
+	Errors      []awsinternal.ScanError            `json:"errors"`                    // Scanner tasks that failed for this account
+	Truncated   []awsinternal.TruncatedResults     `json:"truncated,omitempty"`       // Scanner tasks that hit --max-results-per-scanner
+	ActualSpend []awsinternal.ActualSpend          `json:"actual_spend,omitempty"`    // Cost Explorer spend on the services behind the findings, with --actual-spend
+	Anomalies   []baselineAnomaly                  `json:"anomalies,omitempty"`       // Scanners whose finding count or cost changed beyond --anomaly-threshold from --baseline
+	Timings     []scannerTiming                    `json:"scanner_timings,omitempty"` // Time each scanner's tasks took in this account, slowest first
+}
+
+// combinedScanResult holds every account's results for --combined-output
+type combinedScanResult struct {
+	Version   string                 `json:"cloudsift_version"` // CloudSift build that produced the results
+	ScannedAt time.Time              `json:"scanned_at"`
+	Accounts  map[string]*scanResult `json:"accounts"`                  // Map of account ID to results
+	Timings   []scannerTiming        `json:"scanner_timings,omitempty"` // Time each scanner's tasks took, slowest first
+}
+
+func getScanners(scannerList string) ([]awsinternal.Scanner, []string, error) {
+	var scanners []awsinternal.Scanner
+	var invalidScanners []string
+
+	// If no scanners specified, get all available scanners
+	if scannerList == "" {
+		// If no scanners specified, get all available scanners
+		names := awsinternal.DefaultRegistry.ListScanners()
+		if len(names) == 0 {
+			return nil, nil, fmt.Errorf("no scanners available in registry")
+		}
+
+		for _, name := range names {
+			scanner, err := awsinternal.DefaultRegistry.GetScanner(name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get scanner '%s': %w", name, err)
+			}
+			// Grouping scanners repeat other scanners' findings, so they only run when asked for
+			if _, ok := scanner.(awsinternal.GroupingScanner); ok {
+				continue
+			}
+			scanners = append(scanners, scanner)
+		}
+		return scanners, invalidScanners, nil
+	}
+
+	// Parse comma-separated list of scanners
+	names := strings.Split(scannerList, ",")
+	for _, name := range names {
+		scanner, err := awsinternal.DefaultRegistry.GetScanner(name)
+		if err != nil {
+			// Track invalid scanner but continue processing
+			invalidScanners = append(invalidScanners, name)
+			continue
+		}
+		scanners = append(scanners, scanner)
+	}
+
+	return dropCoveredScanners(scanners), invalidScanners, nil
+}
+
+// dropCoveredScanners removes scanners whose findings a selected grouping scanner already reports,
+// so they aren't counted twice in totals and cost checks
+func dropCoveredScanners(scanners []awsinternal.Scanner) []awsinternal.Scanner {
+	coveredBy := make(map[string]string)
+	for _, scanner := range scanners {
+		if grouping, ok := scanner.(awsinternal.GroupingScanner); ok {
+			for _, name := range grouping.Covers() {
+				coveredBy[name] = grouping.ArgumentName()
+			}
+		}
+	}
+	if len(coveredBy) == 0 {
+		return scanners
+	}
+
+	remaining := make([]awsinternal.Scanner, 0, len(scanners))
+	for _, scanner := range scanners {
+		if grouping, ok := coveredBy[scanner.ArgumentName()]; ok {
+			logging.Warn("Skipping scanner whose findings are already reported by another selected scanner", map[string]interface{}{
+				"scanner":     scanner.ArgumentName(),
+				"reported_by": grouping,
+			})
+			continue
+		}
+		remaining = append(remaining, scanner)
+	}
+	return remaining
+}
+
+// skipScanners removes the scanners named in a comma-separated list, returning the remaining
+// scanners and any names that are not valid scanners
+func skipScanners(scanners []awsinternal.Scanner, skipList string) ([]awsinternal.Scanner, []string) {
+	skip := make(map[string]bool)
+	var invalidScanners []string
+	for _, name := range strings.Split(skipList, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		scanner, err := awsinternal.DefaultRegistry.GetScanner(name)
+		if err != nil {
+			invalidScanners = append(invalidScanners, name)
+			continue
+		}
+		// Deprecated names resolve to the renamed scanner
+		skip[scanner.ArgumentName()] = true
+	}
+
+	var remaining []awsinternal.Scanner
+	for _, scanner := range scanners {
+		if !skip[scanner.ArgumentName()] {
+			remaining = append(remaining, scanner)
+		}
+	}
+	return remaining, invalidScanners
 }
 
 func runScan(cmd *cobra.Command, opts *scanOptions) error {
+	// The flags are valid by now, so errors from here on are about the scan rather than its usage
+	cmd.SilenceUsage = true
+
 	// Validate S3 access first if using S3 output
 	if opts.output == "s3" {
 		if opts.bucket == "" {
 			return fmt.Errorf("S3 bucket not specified. Use --bucket flag to specify the S3 bucket")
 		}
-		if err := validateS3Access(opts.bucket, opts.bucketRegion, opts.organizationRole); err != nil {
+		if err := validateS3Access(opts.bucket, opts.bucketRegion, opts.organizationRole, opts.s3KMSKeyID, opts.s3ACL, opts.s3StorageClass); err != nil {
 			return fmt.Errorf("S3 bucket validation failed: %w", err)
 		}
 	}
 
+	// Look up the exchange rate before scanning so a missing rate doesn't waste a whole scan
+	currency, rate, err := exchangeRate(opts.currency, opts.exchangeRate)
+	if err != nil {
+		return err
+	}
+
+	// Read the baseline before scanning so an unreadable file doesn't waste a whole scan
+	var baseline map[string]map[string]scannerTotals
+	if opts.baseline != "" {
+		baseline, err = loadBaseline(opts.baseline)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get and validate scanners
 	scanners, invalidScanners, err := getScanners(opts.scanners)
 	if err != nil {
@@ -342,6 +1207,21 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		})
 	}
 
+	// Remove skipped scanners from the included list
+	if opts.skipScanners != "" {
+		selected := len(scanners)
+		var invalidSkipped []string
+		scanners, invalidSkipped = skipScanners(scanners, opts.skipScanners)
+		if len(invalidSkipped) > 0 {
+			logging.Warn("Invalid scanners specified in --skip-scanners", map[string]interface{}{
+				"invalid_scanners": invalidSkipped,
+			})
+		}
+		if selected > 0 && len(scanners) == 0 {
+			return fmt.Errorf("--skip-scanners excludes every selected scanner")
+		}
+	}
+
 	if len(scanners) == 0 {
 		if len(invalidScanners) > 0 {
 			// Exit immediately if no valid scanners and at least one invalid scanner
@@ -353,36 +1233,63 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	// Create base session and get accounts
 	var baseSession *session.Session
 	var accounts []awsinternal.Account
+	var profileSessions map[string]*session.Session // Sessions for accounts reached through --profiles
+
+	// Global services and role ARNs depend on the partition (aws, aws-cn or aws-us-gov)
+	partition := awsinternal.ProfilePartition()
+	globalRegion := awsinternal.GlobalRegion(partition)
+	if opts.globalRegion != "" {
+		globalRegion = opts.globalRegion
+		if regionPartition := awsinternal.PartitionForRegion(globalRegion); regionPartition != partition {
+			logging.Warn("--global-region is outside the profile's partition", map[string]interface{}{
+				"global_region":     globalRegion,
+				"region_partition":  regionPartition,
+				"profile_partition": partition,
+			})
+		}
+	}
+	logging.Debug("Using global region", map[string]interface{}{
+		"partition":     partition,
+		"global_region": globalRegion,
+	})
+
+	// Regions with their own credentials are authenticated up front so a bad mapping fails fast
+	if opts.profileRegionMap != "" {
+		profileRegionMap, err := parseProfileRegionMap(opts.profileRegionMap)
+		if err != nil {
+			return err
+		}
+		if err := awsinternal.SetRegionProfiles(profileRegionMap); err != nil {
+			return err
+		}
+	}
 
 	// Create a session with organization role for cost estimator
 	var costEstimatorSession *session.Session
 	var costErr error
 	if opts.organizationRole != "" {
-		costEstimatorSession, costErr = awsinternal.GetSessionChain(opts.organizationRole, "", "", "us-east-1")
+		costEstimatorSession, costErr = awsinternal.GetSessionChain(opts.organizationRole, "", "", globalRegion)
 		if costErr != nil {
 			logging.Error("Failed to create cost estimator session with org role", costErr, map[string]interface{}{
 				"organization_role": opts.organizationRole,
 			})
 			// Fall back to root profile
 			logging.Info("Falling back to root profile for cost estimator")
-			costEstimatorSession, costErr = awsinternal.NewSession(config.Config.Profile, "us-east-1")
+			costEstimatorSession, costErr = awsinternal.NewBaseSession(globalRegion)
 			if costErr != nil {
-				logging.Error("Failed to create cost estimator session", costErr, nil)
-				return nil // Return nil to continue without failing
+				return fmt.Errorf("failed to create cost estimator session: %w", costErr)
 			}
 		}
 	} else {
-		costEstimatorSession, costErr = awsinternal.NewSession(config.Config.Profile, "us-east-1")
+		costEstimatorSession, costErr = awsinternal.NewBaseSession(globalRegion)
 		if costErr != nil {
-			logging.Error("Failed to create cost estimator session", costErr, nil)
-			return nil // Return nil to continue without failing
+			return fmt.Errorf("failed to create cost estimator session: %w", costErr)
 		}
 	}
 
 	// Initialize cost estimator with the session
 	if err := awsinternal.InitializeDefaultCostEstimator(costEstimatorSession); err != nil {
-		logging.Error("Failed to initialize cost estimator", err, nil)
-		return nil // Return nil to continue without failing
+		return fmt.Errorf("failed to initialize cost estimator: %w", err)
 	}
 
 	if opts.organizationRole != "" && opts.scannerRole != "" {
@@ -391,50 +1298,86 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 			"scanner_role":      opts.scannerRole,
 		})
 		// Create org role session for listing accounts
-		baseSession, err = awsinternal.GetSessionChain(opts.organizationRole, "", "", "us-west-2")
+		baseSession, err = awsinternal.GetSessionChain(opts.organizationRole, "", "", awsinternal.OrganizationsRegion())
 		if err != nil {
 			logging.Error("Failed to create organization session", err, map[string]interface{}{
 				"organization_role": opts.organizationRole,
 			})
 			// Fall back to current session
 			logging.Info("Falling back to current session")
-			baseSession, err = awsinternal.NewSession(config.Config.Profile, "")
+			baseSession, err = awsinternal.NewBaseSession("")
 			if err != nil {
-				logging.Error("Failed to create base session", err, nil)
-				return nil // Return nil to continue without failing
+				return fmt.Errorf("failed to create base session: %w", err)
 			}
 		}
 	} else {
 		logging.Debug("Using current session", nil)
 		// Use current session with profile
-		baseSession, err = awsinternal.NewSession(config.Config.Profile, "")
+		baseSession, err = awsinternal.NewBaseSession("")
 		if err != nil {
-			logging.Error("Failed to create base session", err, nil)
-			return nil // Return nil to continue without failing
+			return fmt.Errorf("failed to create base session: %w", err)
 		}
 	}
 
 	// Get accounts
-	if opts.organizationRole != "" && opts.scannerRole != "" {
+	if opts.accountsFile != "" {
+		// An inventory kept outside Organizations replaces account listing entirely
+		accounts, err = awsinternal.LoadAccountsFile(opts.accountsFile)
+		if err != nil {
+			return err
+		}
+		logging.Info("Loaded accounts from file", map[string]interface{}{
+			"accounts_file": opts.accountsFile,
+			"account_count": len(accounts),
+		})
+	} else if opts.organizationRole != "" && opts.scannerRole != "" {
 		accounts, err = awsinternal.ListAccountsWithSession(baseSession)
 		if err != nil {
 			logging.Error("Failed to list organization accounts", err, map[string]interface{}{
 				"organization_role": opts.organizationRole,
 			})
+			if opts.requireAllAccounts {
+				return fmt.Errorf("failed to list organization accounts with role %s (--require-all-accounts is set): %w", opts.organizationRole, err)
+			}
 			// Fall back to current account
-			logging.Info("Falling back to current account")
+			logging.Warn("Organization accounts could not be listed; ONLY the current account will be scanned. Use --require-all-accounts to fail instead", map[string]interface{}{
+				"organization_role": opts.organizationRole,
+			})
 			accounts, err = awsinternal.ListCurrentAccount(baseSession)
 			if err != nil {
-				logging.Error("Failed to get current account", err, nil)
-				return nil // Return nil to continue without failing
+				return fmt.Errorf("failed to get current account: %w", err)
 			}
 		}
+	} else if opts.profiles != "" {
+		// Each profile is scanned as a standalone account with its own session
+		accounts, profileSessions = awsinternal.ListProfileAccounts(strings.Split(opts.profiles, ","))
+		if len(accounts) == 0 {
+			return fmt.Errorf("none of the profiles %s could be authenticated", opts.profiles)
+		}
 	} else {
 		// Get current account only
 		accounts, err = awsinternal.ListCurrentAccount(baseSession)
 		if err != nil {
-			logging.Error("Failed to get current account", err, nil)
-			return nil // Return nil to continue without failing
+			return fmt.Errorf("failed to get current account: %w", err)
+		}
+	}
+
+	// Filter accounts to the specified organizational units
+	if opts.organizationalUnits != "" {
+		accounts, err = filterOrganizationalUnits(baseSession, accounts, opts.organizationalUnits)
+		if err != nil {
+			logging.Error("Failed to list organizational unit accounts", err, map[string]interface{}{
+				"organizational_units": opts.organizationalUnits,
+			})
+			return err
+		}
+
+		logging.Info("Filtered accounts to organizational units", map[string]interface{}{
+			"organizational_units": opts.organizationalUnits,
+			"account_count":        len(accounts),
+		})
+		if len(accounts) == 0 {
+			return fmt.Errorf("no accounts found in the specified organizational units")
 		}
 	}
 
@@ -478,45 +1421,60 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		}
 	}
 
+	// Suspended and closing accounts are still listed by Organizations, but the scanner role can't be
+	// assumed in them, so they are left out rather than reported as assumption failures
+	var inactiveAccounts []awsinternal.Account
+	accounts, inactiveAccounts = splitInactiveAccounts(accounts)
+	for _, account := range inactiveAccounts {
+		logging.Info("Skipping account that is not active in the organization", map[string]interface{}{
+			"account_id":   account.ID,
+			"account_name": account.Name,
+			"status":       account.Status,
+		})
+	}
+	if len(accounts) == 0 {
+		return fmt.Errorf("none of the %d accounts are active in the organization", len(inactiveAccounts))
+	}
+
 	// Create sessions for each account
 	accountSessions := make(map[string]*session.Session)
+	// Role sessions are only tracked for as long as this scan uses them
+	defer func() {
+		for _, sess := range accountSessions {
+			awsinternal.ForgetRoleSession(sess)
+		}
+	}()
 	var authenticatedAccounts []awsinternal.Account // Track accounts that successfully authenticated
+	var skippedAccounts []awsinternal.Account       // Accounts the scanner role couldn't be assumed in
+	// Accounts from a file are assumed into from the organization session when there is one and
+	// from the current credentials otherwise
+	assumeScannerRoles := opts.scannerRole != "" && (opts.organizationRole != "" || opts.accountsFile != "")
 	for _, account := range accounts {
-		if opts.organizationRole != "" && opts.scannerRole != "" {
+		if assumeScannerRoles {
 			// Assume scanner role in target account using org session
-			scannerRoleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", account.ID, opts.scannerRole)
-			scannerCreds := stscreds.NewCredentials(baseSession, scannerRoleARN)
-			scanSession, err := session.NewSession(aws.NewConfig().WithCredentials(scannerCreds))
+			scanSession, identityARN, err := assumeScannerRole(baseSession, partition, account.ID, opts.scannerRole)
 			if err != nil {
 				logging.Warn("Failed to assume scanner role", map[string]interface{}{
 					"error":        err.Error(),
 					"account_id":   account.ID,
 					"account_name": account.Name,
-					"role_arn":     scannerRoleARN,
-				})
-				continue // Skip this account
-			}
-
-			// Verify scanner role assumption
-			stsSvc := sts.New(scanSession)
-			identity, err := stsSvc.GetCallerIdentity(&sts.GetCallerIdentityInput{})
-			if err != nil {
-				logging.Warn("Failed to verify scanner role assumption", map[string]interface{}{
-					"error":        err.Error(),
-					"account_id":   account.ID,
-					"account_name": account.Name,
-					"role_arn":     scannerRoleARN,
+					"role_arn":     awsinternal.RoleARN(partition, account.ID, opts.scannerRole),
 				})
+				skippedAccounts = append(skippedAccounts, account)
 				continue // Skip this account
 			}
 			logging.Info("Successfully assumed scanner role", map[string]interface{}{
 				"account_id":   account.ID,
 				"account_name": account.Name,
-				"role_arn":     *identity.Arn,
+				"role_arn":     identityARN,
 			})
 
 			accountSessions[account.ID] = scanSession
 			authenticatedAccounts = append(authenticatedAccounts, account)
+		} else if profileSession, ok := profileSessions[account.ID]; ok {
+			// Use the session of the profile the account was reached through
+			accountSessions[account.ID] = profileSession
+			authenticatedAccounts = append(authenticatedAccounts, account)
 		} else {
 			// Use base session for current account
 			accountSessions[account.ID] = baseSession
@@ -524,32 +1482,31 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		}
 	}
 
+	if len(skippedAccounts) > 0 && opts.requireAllAccounts {
+		return fmt.Errorf("failed to assume scanner role in %d of %d accounts (--require-all-accounts is set): %s",
+			len(skippedAccounts), len(accounts), strings.Join(accountLabels(skippedAccounts), ", "))
+	}
+
 	if len(accountSessions) == 0 {
-		logging.Warn("No valid sessions created for any accounts, scan will be skipped", nil)
-		return nil
+		return fmt.Errorf("no valid sessions could be created for any account")
 	}
 
 	// Use only authenticated accounts from here on
 	accounts = authenticatedAccounts
 
-	// Get and validate regions
-	var regions []string
-	if opts.regions == "" {
-		// If no regions specified, get all available regions
-		regions, err = awsinternal.GetAvailableRegions(accountSessions[accounts[0].ID])
-		if err != nil {
-			logging.Error("Failed to get available regions", err, nil)
-			return nil // Return nil to continue without failing
-		}
-	} else {
-		// Parse and validate comma-separated list of regions
-		regions = strings.Split(opts.regions, ",")
-		if err := awsinternal.ValidateRegions(accountSessions[accounts[0].ID], regions); err != nil {
-			logging.Error("Invalid regions", err, map[string]interface{}{
-				"regions": opts.regions,
-			})
-			return nil // Return nil to continue without failing
-		}
+	// Get and validate regions. Opt-in regions are enabled per account, so each account is only
+	// scanned in the regions it has enabled.
+	var requestedRegions []string
+	if opts.regions != "" {
+		requestedRegions = strings.Split(opts.regions, ",")
+	}
+	enabledRegions, err := resolveAccountRegions(accounts, accountSessions, requestedRegions)
+	if err != nil {
+		return err
+	}
+	regions := requestedRegions
+	if len(regions) == 0 {
+		regions = enabledRegions.union()
 	}
 
 	// Initialize results map
@@ -558,15 +1515,56 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		accountResults[account.ID] = &scanResult{
 			AccountID:   account.ID,
 			AccountName: account.Name,
+			Profile:     account.Profile,
+			Version:     version.String(),
 			Results:     make(map[string]awsinternal.ScanResults),
+			Errors:      []awsinternal.ScanError{},
 		}
 	}
 
 	// Create tasks for each scanner+region+account combination
-	var tasks []worker.Task
+	var tasks []worker.KeyedTask
+	regionTasks := make(map[string]int)
 	var resultsMutex sync.Mutex
+	var scanErrors []awsinternal.ScanError
+	recordScanError := func(account awsinternal.Account, region string, scanner awsinternal.Scanner, err error) {
+		scanError := awsinternal.ScanError{
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			Region:      region,
+			Scanner:     scanner.Label(),
+			Error:       err.Error(),
+		}
+		resultsMutex.Lock()
+		scanErrors = append(scanErrors, scanError)
+		accountResults[account.ID].Errors = append(accountResults[account.ID].Errors, scanError)
+		resultsMutex.Unlock()
+	}
+	var truncations []awsinternal.TruncatedResults
+	var ignoredResources []ignoredResource // Findings left out of the report, kept for --emit-ignored
 	progressMap := newScannerProgressMap()
-	actualTasks := 0
+	progressMap.setCurrency(currency, rate)
+	scannerTimings := newScannerTimingMap()
+	accountTimings := make(map[string]*scannerTimingMap, len(accounts)) // Read-only once built, so tasks share it without a lock
+	for _, account := range accounts {
+		accountTimings[account.ID] = newScannerTimingMap()
+	}
+
+	// Completed tasks are checkpointed so a scan that dies part way through can be resumed
+	var checkpoint *scanCheckpoint
+	if opts.resume != "" {
+		loaded, err := loadScanCheckpoint(opts.resume)
+		if err != nil {
+			return err
+		}
+		checkpoint = loaded
+		if opts.checkpoint != "" {
+			checkpoint.path = opts.checkpoint
+		}
+	} else if opts.checkpoint != "" {
+		checkpoint = newScanCheckpoint(opts.checkpoint)
+	}
+	resumedTasks := 0
 
 	// Initialize shared worker pool
 	if err := worker.InitSharedPool(config.Config.MaxWorkers); err != nil {
@@ -574,6 +1572,17 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}
 	workerPool := worker.GetSharedPool()
 
+	// Throttled AWS requests feed the pool's adaptive concurrency
+	awsinternal.SetThrottleHandler(workerPool.ReportThrottle)
+
+	// CloudWatch responses are shared between scanners for this scan only
+	awsinternal.DefaultMetricCache.Reset()
+
+	// Make sure the pool does not cancel scanner tasks before the scanner timeout does
+	if opts.scannerTimeout > workerPool.TaskTimeout() {
+		workerPool.SetTaskTimeout(opts.scannerTimeout)
+	}
+
 	// Log scan start with configuration
 	var scannerNames []string
 	for _, s := range scanners {
@@ -592,10 +1601,26 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	startTime := time.Now()
 	logging.ScanStart(scannerNames, accountInfo, regions)
 
+	// Show the live view when asked for and stdout can display it, otherwise log progress periodically
+	useTUI := opts.tui && isTerminal(os.Stdout)
+	if opts.tui && !useTUI {
+		logging.Info("Stdout is not a terminal; using text progress instead of --tui", nil)
+	}
+	var tui *scanTUI
+	if useTUI {
+		tui = newScanTUI(os.Stdout, progressMap, workerPool, config.Config.MaxWorkers)
+		tui.Start()
+		defer tui.Stop()
+	}
+
 	// Start progress logger
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go func() {
+		if useTUI {
+			return
+		}
+
 		tickDuration := 30 * time.Second
 		ticker := time.NewTicker(tickDuration)
 		defer ticker.Stop()
@@ -617,6 +1642,11 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 						freeWorkers := maxWorkers - activeWorkers
 						utilization := float64(activeWorkers) / float64(maxWorkers) * 100
 
+						// Log overall completion and the time left before the per-scanner detail
+						summary := progressMap.summary()
+						eta := estimateRemaining(summary, metrics.AverageExecutionMs, config.Config.MaxWorkers)
+						logging.Progress(formatProgressSummary(summary, eta), nil)
+
 						// Log header with detailed worker stats
 						logging.Progress(fmt.Sprintf("Pending Scanners (Workers: %d active (%d%% utilized), %d idle of %d total):",
 							activeWorkers, int(utilization), freeWorkers, maxWorkers), nil)
@@ -631,16 +1661,11 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 
 						// Log each scanner on its own line
 						for _, prog := range running {
-							region := prog.Region
-							if region == "us-east-1" && (prog.Scanner == "IAM Roles" || prog.Scanner == "IAM Users") {
-								region = "global"
-							}
-
 							logging.Progress(fmt.Sprintf("  %s: %s (%s) in %s - %d results found",
 								prog.Scanner,
 								prog.AccountName,
 								prog.AccountID,
-								region,
+								prog.Region,
 								prog.ResultCount,
 							), nil)
 						}
@@ -656,6 +1681,9 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 								float64(avgExecMs)/1000.0,
 							), nil)
 						}
+
+						// Log the estimated savings found so far
+						logging.Progress(fmt.Sprintf("  Estimated savings so far: %s/month", progressMap.formatSavings()), nil)
 					}
 				}
 			}
@@ -663,23 +1691,53 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 	}()
 
 	for _, scanner := range scanners {
-		// For IAM scanners, we only need to scan us-east-1 since IAM is global
+		// Global scanners (e.g. IAM) only need to run once per account, from the global region
 		scanRegions := regions
-		if isIAMScanner(scanner) {
-			scanRegions = []string{"us-east-1"}
+		if scanner.IsGlobal() {
+			scanRegions = []string{globalRegion}
 		}
 
 		for _, region := range scanRegions {
 			for _, account := range accounts {
-				actualTasks++
+				if !scanner.IsGlobal() && !enabledRegions[account.ID][region] {
+					continue
+				}
+
+				// Tasks completed by the scan being resumed contribute their saved findings instead
+				if checkpoint != nil {
+					if task, ok := checkpoint.completed(account.ID, region, scanner.ArgumentName()); ok {
+						accountResult := accountResults[account.ID]
+						accountResult.Results[scanner.Label()] = append(accountResult.Results[scanner.Label()], task.Results...)
+						if task.Truncated != nil {
+							truncations = append(truncations, *task.Truncated)
+							accountResult.Truncated = append(accountResult.Truncated, *task.Truncated)
+						}
+						resumedTasks++
+						continue
+					}
+				}
+
+				progressMap.addTask(account.ID)
 				scanner := scanner // Create new variable for closure
 				region := region
 				account := account
 
-				tasks = append(tasks, worker.Task(func(ctx context.Context) error {
-					// For IAM scanners, always log region as "global"
+				regionTasks[region]++
+
+				tasks = append(tasks, worker.KeyedTask{Key: account.ID, Group: region, Task: func(ctx context.Context) (err error) {
+					defer progressMap.finishTask(account.ID)
+
+					// Time every run, failed or not, so slow scanners show up in the metrics
+					taskStart := time.Now()
+					defer func() {
+						elapsed := time.Since(taskStart)
+						scannerTimings.record(scanner.Label(), elapsed, err != nil)
+						accountTimings[account.ID].record(scanner.Label(), elapsed, err != nil)
+					}()
+
+					// For global scanners, always log region as "global"
 					logRegion := region
-					if isIAMScanner(scanner) {
+					if scanner.IsGlobal() {
 						logRegion = "global"
 					}
 					logging.ScannerStart(scanner.Label(), account.ID, account.Name, logRegion)
@@ -693,84 +1751,69 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 					regionSession, err := awsinternal.GetSessionInRegion(scanSession, region)
 					if err != nil {
 						logging.ScannerError(scanner.Label(), account.ID, account.Name, logRegion, err)
-						return fmt.Errorf("failed to create regional session for account %s: %w", account.ID, err)
+						err = fmt.Errorf("failed to create regional session for account %s: %w", account.ID, err)
+						recordScanError(account, logRegion, scanner, err)
+						return err
 					}
 					logging.Debug("Created regional session", map[string]interface{}{
 						"region": region,
 					})
 
-					results, err := scanner.Scan(awsinternal.ScanOptions{
-						Region:     region,
-						DaysUnused: opts.daysUnused,
-						Session:    regionSession,
-					})
+					results, err := runScannerWithTimeout(ctx, scanner, awsinternal.ScanOptions{
+						AccountID:      account.ID,
+						Region:         region,
+						DaysUnused:     opts.daysUnused,
+						Session:        regionSession,
+						EC2Rightsizing: opts.ec2Rightsizing,
+					}, opts.scannerTimeout)
 					if err != nil {
+						if errors.Is(err, context.DeadlineExceeded) {
+							logging.Error("Scanner timed out", err, map[string]interface{}{
+								"scanner":      scanner.Label(),
+								"account_id":   account.ID,
+								"account_name": account.Name,
+								"region":       logRegion,
+								"timeout":      opts.scannerTimeout.String(),
+							})
+							recordScanError(account, logRegion, scanner, err)
+							return err
+						}
 						logging.ScannerError(scanner.Label(), account.ID, account.Name, logRegion, err)
+						recordScanError(account, logRegion, scanner, err)
 						return err
 					}
 
-					// Filter results based on ignore list
+					// Filter results based on include tags, --min-age-days, --min-confidence and the ignore lists
 					var filteredResults awsinternal.ScanResults
 					for _, result := range results {
-						// Check if resource ID is in ignore list
-						shouldIgnore := false
-						for _, ignoreID := range config.Config.ScanIgnoreResourceIDs {
-							if strings.EqualFold(result.ResourceID, ignoreID) {
-								logging.Debug("Ignoring resource by ID", map[string]interface{}{
-									"resource_id": result.ResourceID,
-									"scanner":     scanner.Label(),
-									"account_id":  account.ID,
-									"region":      logRegion,
-								})
-								shouldIgnore = true
-								break
-							}
+						ignored, ok := filterResult(result, scanner, account.ID, time.Now())
+						if !ok {
+							filteredResults = append(filteredResults, result)
+							continue
 						}
-
-						// Check if resource name is in ignore list
-						if !shouldIgnore {
-							for _, ignoreName := range config.Config.ScanIgnoreResourceNames {
-								if strings.EqualFold(result.ResourceName, ignoreName) {
-									logging.Debug("Ignoring resource by name", map[string]interface{}{
-										"resource_name": result.ResourceName,
-										"scanner":       scanner.Label(),
-										"account_id":    account.ID,
-										"region":        logRegion,
-									})
-									shouldIgnore = true
-									break
-								}
-							}
+						logging.Debug("Ignoring resource", map[string]interface{}{
+							"resource_id":   result.ResourceID,
+							"resource_name": result.ResourceName,
+							"scanner":       scanner.Label(),
+							"account_id":    account.ID,
+							"region":        logRegion,
+							"filter":        ignored.Filter,
+							"match":         ignored.Match,
+						})
+						if opts.emitIgnored != "" {
+							ignored.AccountName = account.Name
+							ignored.Region = logRegion
+							resultsMutex.Lock()
+							ignoredResources = append(ignoredResources, ignored)
+							resultsMutex.Unlock()
 						}
+					}
 
-						// Check if any resource tags match ignore list
-						if !shouldIgnore && len(result.Tags) > 0 {
-							for ignoreKey, ignoreValue := range config.Config.ScanIgnoreTags {
-								// Convert tag key and value to lowercase for case-insensitive comparison
-								for tagKey, tagValue := range result.Tags {
-									if strings.EqualFold(tagKey, ignoreKey) && strings.EqualFold(tagValue, ignoreValue) {
-										logging.Debug("Ignoring resource by tag", map[string]interface{}{
-											"resource_id": result.ResourceID,
-											"tag_key":     ignoreKey,
-											"tag_value":   ignoreValue,
-											"scanner":     scanner.Label(),
-											"account_id":  account.ID,
-											"region":      logRegion,
-										})
-										shouldIgnore = true
-										break
-									}
-								}
-								if shouldIgnore {
-									break
-								}
-							}
-						}
-
-						if !shouldIgnore {
-							filteredResults = append(filteredResults, result)
-						}
-					}
+					// Keep only the most expensive findings so a huge account can't exhaust memory
+					var omitted int
+					if opts.maxResultsPerScanner > 0 {
+						filteredResults, omitted = truncateResults(filteredResults, opts.maxResultsPerScanner)
+					}
 
 					// Update result count with filtered results
 					progressMap.updateResultCount(account.ID, logRegion, scanner.Label(), len(filteredResults))
@@ -782,13 +1825,22 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 						}
 						filteredResults[i].AccountID = account.ID
 						filteredResults[i].AccountName = account.Name
-						// For IAM scanners, set region as "global", otherwise use actual region
-						if isIAMScanner(scanner) {
-							filteredResults[i].Details["region"] = "global"
-						} else {
-							filteredResults[i].Details["region"] = region
+						if account.Profile != "" {
+							filteredResults[i].Details["profile"] = account.Profile
+						}
+						// Global scanners report region "global"; details keep the region for older consumers
+						filteredResults[i].Region = logRegion
+						filteredResults[i].Details["region"] = logRegion
+					}
+
+					// Track the running savings total for progress output
+					var monthly float64
+					for _, result := range filteredResults {
+						if total, ok := result.Cost["total"].(*awsinternal.CostBreakdown); ok && total != nil {
+							monthly += total.MonthlyRate
 						}
 					}
+					progressMap.addSavings(monthly)
 
 					// Safely append results
 					resultsMutex.Lock()
@@ -797,6 +1849,28 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 					} else {
 						accountResults[account.ID].Results[scanner.Label()] = append(accountResults[account.ID].Results[scanner.Label()], filteredResults...)
 					}
+					var truncation *awsinternal.TruncatedResults
+					if omitted > 0 {
+						truncation = &awsinternal.TruncatedResults{
+							AccountID:   account.ID,
+							AccountName: account.Name,
+							Region:      logRegion,
+							Scanner:     scanner.Label(),
+							Kept:        len(filteredResults),
+							Omitted:     omitted,
+						}
+						truncations = append(truncations, *truncation)
+						accountResults[account.ID].Truncated = append(accountResults[account.ID].Truncated, *truncation)
+					}
+					if checkpoint != nil {
+						checkpoint.record(checkpointTask{
+							AccountID: account.ID,
+							Region:    region,
+							Scanner:   scanner.ArgumentName(),
+							Results:   filteredResults,
+							Truncated: truncation,
+						})
+					}
 					resultsMutex.Unlock()
 
 					// Log completion with results
@@ -807,59 +1881,268 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 					logging.ScannerComplete(scanner.Label(), account.ID, account.Name, logRegion, resultInterfaces)
 
 					return nil
-				}))
+				}})
 			}
 		}
 	}
 
+	if resumedTasks > 0 {
+		logging.Info("Resuming scan from checkpoint", map[string]interface{}{
+			"checkpoint":      opts.resume,
+			"completed_tasks": resumedTasks,
+			"remaining_tasks": len(tasks),
+		})
+	}
+
 	// Execute tasks using the worker pool
-	workerPool.ExecuteTasks(tasks)
+	// Per-account limits spread API pressure so no single account gets throttled, and tasks are
+	// interleaved across regions so a slow region can't hold every worker
+	workerPool.ExecuteKeyedTasks(tasks, opts.maxTasksPerAccount, opts.maxTasksPerRegion)
+	if tui != nil {
+		tui.Stop()
+	}
+
+	// Save every completed task so a resumed scan only reruns the ones that failed
+	if checkpoint != nil {
+		if err := checkpoint.write(); err != nil {
+			logging.Error("Failed to write scan checkpoint", err, map[string]interface{}{
+				"checkpoint": checkpoint.path,
+			})
+		}
+	}
 
 	// Verify task count matches expected scans
 	metrics := workerPool.GetMetrics()
 
+	cacheHits, cacheMisses := awsinternal.DefaultMetricCache.Stats()
+
 	// Get worker pool metrics
 	logging.Info("Worker pool metrics", map[string]interface{}{
-		"total_tasks":        metrics.TotalTasks,
-		"completed_tasks":    metrics.CompletedTasks,
-		"failed_tasks":       metrics.FailedTasks,
-		"peak_workers":       metrics.PeakWorkers,
-		"avg_execution_ms":   metrics.AverageExecutionMs,
-		"tasks_per_second":   float64(metrics.CompletedTasks) / float64(metrics.AverageExecutionMs) * 1000,
-		"worker_utilization": float64(metrics.PeakWorkers) / float64(config.Config.MaxWorkers) * 100,
+		"total_tasks":         metrics.TotalTasks,
+		"completed_tasks":     metrics.CompletedTasks,
+		"failed_tasks":        metrics.FailedTasks,
+		"peak_workers":        metrics.PeakWorkers,
+		"avg_execution_ms":    metrics.AverageExecutionMs,
+		"tasks_per_second":    float64(metrics.CompletedTasks) / float64(metrics.AverageExecutionMs) * 1000,
+		"worker_utilization":  float64(metrics.PeakWorkers) / float64(config.Config.MaxWorkers) * 100,
+		"throttle_events":     metrics.ThrottleEvents,
+		"throttle_reductions": metrics.ThrottleReductions,
+		"concurrency_limit":   metrics.ConcurrencyLimit,
+		"metric_cache_hits":   cacheHits,
+		"metric_cache_misses": cacheMisses,
+		"region_tasks":        regionTasks,
 	})
 
-	// Output results
+	// Break the time down by scanner, slowest first, to show which scanners dominate the run
+	timings := scannerTimings.sorted()
+	if len(timings) > 0 {
+		logging.Info("Scanner timings", map[string]interface{}{
+			"scanner_timings": formatScannerTimings(timings),
+		})
+	}
+	for accountID, accountResult := range accountResults {
+		if accountTiming, ok := accountTimings[accountID]; ok {
+			accountResult.Timings = accountTiming.sorted()
+		}
+	}
+
+	// Order errors so the report lists them consistently between runs
+	sortScanErrors(scanErrors)
+	for _, accountResult := range accountResults {
+		sortScanErrors(accountResult.Errors)
+	}
+	if len(scanErrors) > 0 {
+		logging.Warn("Some scanner tasks failed; see the errors section of the report", map[string]interface{}{
+			"failed_tasks": len(scanErrors),
+		})
+	}
+
+	if len(truncations) > 0 {
+		sortTruncations(truncations)
+		for _, accountResult := range accountResults {
+			sortTruncations(accountResult.Truncated)
+		}
+		notes := make([]string, len(truncations))
+		for i, truncation := range truncations {
+			notes[i] = fmt.Sprintf("%s in %s/%s: truncated, %d more", truncation.Scanner, truncation.AccountID, truncation.Region, truncation.Omitted)
+		}
+		logging.Warn("Some scanners reported more findings than --max-results-per-scanner; the least expensive were left out", map[string]interface{}{
+			"truncated": notes,
+		})
+	}
+
+	// Collapse findings that were reported more than once, e.g. a global resource scanned twice, and
+	// order what's left so reports from separate runs can be diffed
+	duplicates := 0
+	for _, accountResult := range accountResults {
+		for scannerLabel, scannerResults := range accountResult.Results {
+			deduped, removed := dedupeResults(scannerResults)
+			sortResults(deduped)
+			accountResult.Results[scannerLabel] = deduped
+			duplicates += removed
+		}
+	}
+	if duplicates > 0 {
+		logging.Info("Removed duplicate findings", map[string]interface{}{
+			"duplicates": duplicates,
+		})
+	}
+
+	// Leave out findings from regions that weren't asked for, so later steps only see reported findings
+	if opts.reportRegions != "" {
+		include, exclude, err := parseRegionFilter(opts.reportRegions)
+		if err != nil {
+			return err
+		}
+		if removed := filterResultsByRegion(accountResults, include, exclude); removed > 0 {
+			logging.Info("Left out findings from regions not in --report-regions", map[string]interface{}{
+				"removed":        removed,
+				"report_regions": opts.reportRegions,
+			})
+		}
+	}
+
+	// Call out scanners whose findings jumped or dropped since the baseline scan, so a sudden
+	// spike is noticed rather than read as just another number
+	if baseline != nil {
+		scannerLabels := make([]string, len(scanners))
+		for i, scanner := range scanners {
+			scannerLabels[i] = scanner.Label()
+		}
+		if notes := flagBaselineAnomalies(accountResults, baseline, scannerLabels, opts.anomalyThreshold); len(notes) > 0 {
+			logging.Warn("Some scanners' findings changed from the baseline by more than --anomaly-threshold", map[string]interface{}{
+				"anomalies": notes,
+				"baseline":  opts.baseline,
+			})
+		}
+	}
+
+	// Compare the estimates with what the accounts were actually billed
+	if opts.actualSpend {
+		reconcileActualSpend(accountResults, func(accountID string, services []string, start, end time.Time) (map[string]float64, error) {
+			return awsinternal.DefaultCostEstimator.GetActualSpend(ctx, accountID, services, start, end)
+		}, time.Now())
+	}
+
+	// Tag flagged resources so their owners see them in the console
+	if opts.applyTags {
+		key, value, err := parseFlagTag(opts.flagTag)
+		if err != nil {
+			return err
+		}
+		tagged, skipped, failed := applyFindingTags(accountResults, key, value, opts.dryRun, func(accountID, resourceARN string, tags map[string]string) error {
+			sess, ok := accountSessions[accountID]
+			if !ok {
+				return fmt.Errorf("no session for account %s", accountID)
+			}
+			return awsinternal.TagResource(ctx, sess, resourceARN, tags)
+		})
+		logging.Info("Tagged flagged resources", map[string]interface{}{
+			"tagged":         tagged,
+			"skipped_no_arn": skipped,
+			"failed":         failed,
+			"dry_run":        opts.dryRun,
+		})
+	}
+
+	// Report costs in the requested currency, keeping the US dollar estimates for auditing
+	if currency != awsinternal.BaseCurrency {
+		for _, accountResult := range accountResults {
+			for _, scannerResults := range accountResult.Results {
+				awsinternal.ConvertCosts(scannerResults, currency, rate)
+			}
+		}
+		logging.Info("Converted cost estimates", map[string]interface{}{
+			"currency":      currency,
+			"exchange_rate": rate,
+		})
+	}
+
+	// Write suggested remediation commands alongside the report; nothing is run against AWS
+	if opts.emitRemediation != "" {
+		allResults := flattenResults(accountResults)
+
+		if err := output.WriteRemediation(allResults, opts.emitRemediation, opts.remediationUncomment); err != nil {
+			logging.Error("Error writing remediation commands", err, map[string]interface{}{
+				"output_path": opts.emitRemediation,
+			})
+		} else {
+			fmt.Printf("Remediation commands written to %s\n", opts.emitRemediation)
+		}
+	}
+
+	// Record what the filters left out, so a missing finding can be told apart from one never found
+	if opts.emitIgnored != "" {
+		if err := writeIgnored(opts.emitIgnored, ignoredResources, startTime); err != nil {
+			logging.Error("Error writing ignored resources", err, map[string]interface{}{
+				"output_path": opts.emitIgnored,
+			})
+		} else {
+			fmt.Printf("Ignored resources written to %s\n", opts.emitIgnored)
+		}
+	}
+
+	// Accounts without findings still count towards the summary, but can be left out of the JSON
+	// output so reports only list accounts worth looking at
+	outputAccountIDs := accountsToWrite(accountResults, opts.onlyAccountsWithFindings)
+	if skipped := len(accountResults) - len(outputAccountIDs); skipped > 0 {
+		logging.Info("Skipping output for accounts without findings", map[string]interface{}{
+			"accounts": skipped,
+		})
+	}
+	outputAccounts := make(map[string]*scanResult, len(outputAccountIDs))
+	for _, accountID := range outputAccountIDs {
+		outputAccounts[accountID] = accountResults[accountID]
+	}
+
+	// Output results, counting writes that fail so a scan whose report never arrived doesn't pass
+	// for a clean one
+	var failedWrites, totalWrites int
 	switch opts.output {
 	case "filesystem":
 		switch opts.outputFormat {
 		case "json":
 			// Use writer for JSON filesystem output
+			outputDir := opts.outputDir
+			if outputDir == "" {
+				outputDir = "output"
+			}
 			writer := output.NewWriter(output.Config{
-				Type:      output.FileSystem,
-				OutputDir: "output",
+				Type:             output.FileSystem,
+				OutputDir:        outputDir,
+				FilenameTemplate: opts.filenameTemplate,
+				Uncompressed:     !opts.compress,
 			})
 
-			for accountID, result := range accountResults {
-				if err := writer.Write(accountID, result); err != nil {
+			if opts.combinedOutput {
+				if err := writer.WriteCombined(combinedScanResult{
+					Version:   version.String(),
+					ScannedAt: startTime,
+					Accounts:  outputAccounts,
+					Timings:   timings,
+				}); err != nil {
+					logging.Error("Error writing combined results", err, nil)
+					failedWrites = 1
+				}
+				totalWrites = 1
+				break
+			}
+
+			failed := writeAccounts(outputAccountIDs, opts.outputConcurrency, func(accountID string) error {
+				result := accountResults[accountID]
+				err := writer.Write(accountID, result.AccountName, result)
+				if err != nil {
 					logging.Error("Error writing results for account", err, map[string]interface{}{
 						"account_id": accountID,
 					})
 				}
-			}
+				return err
+			})
+			logFailedWrites(failed, len(outputAccountIDs))
+			failedWrites, totalWrites = len(failed), len(outputAccountIDs)
 		case "html":
-			// Create reports directory if it doesn't exist
-			if err := os.MkdirAll("reports", 0755); err != nil {
-				logging.Error("Error creating reports directory", err, nil)
-			}
-
-			// Collect all results
-			var allResults []awsinternal.ScanResult
-			for _, accountResult := range accountResults {
-				for _, scannerResults := range accountResult.Results {
-					allResults = append(allResults, scannerResults...)
-				}
-			}
+			// Collect all results in report order
+			allResults := flattenResults(accountResults)
 
 			// Calculate scan metrics
 			duration := time.Since(startTime).Seconds()
@@ -874,63 +2157,659 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 				WorkerUtilization:  float64(metrics.PeakWorkers) / float64(config.Config.MaxWorkers) * 100,
 				AvgExecutionTimeMs: metrics.AverageExecutionMs,
 				TasksPerSecond:     float64(metrics.CompletedTasks) / float64(metrics.AverageExecutionMs) * 1000,
+				ThrottleEvents:     metrics.ThrottleEvents,
+				ThrottleReductions: metrics.ThrottleReductions,
+				MetricCacheHits:    cacheHits,
+				MetricCacheMisses:  cacheMisses,
+				Version:            version.String(),
+				ReportTitle:        opts.reportTitle,
+				ReportLogo:         opts.reportLogo,
+				Truncated:          truncations,
+				Currency:           currency,
 			}
 
-			outputPath := "reports/scan_report.html"
-			if err := html.WriteHTML(allResults, outputPath, metrics); err != nil {
+			outputPath := htmlReportPath(opts.outputDir, opts.reportName, startTime)
+			totalWrites = 1
+			if err := html.WriteHTML(allResults, outputPath, metrics, scanErrors); err != nil {
 				logging.Error("Error writing HTML output", err, map[string]interface{}{
 					"output_path": outputPath,
 				})
+				failedWrites = 1
+			} else {
+				fmt.Printf("HTML report written to %s\n", outputPath)
+			}
+		case "junit":
+			// Each finding becomes a failing test case, so CI test report views list them
+			allResults := flattenResults(accountResults)
+
+			outputPath := junitReportPath(opts.outputDir, opts.reportName, startTime)
+			totalWrites = 1
+			if err := output.WriteJUnit(allResults, outputPath, scanErrors, time.Since(startTime).Seconds()); err != nil {
+				logging.Error("Error writing JUnit output", err, map[string]interface{}{
+					"output_path": outputPath,
+				})
+				failedWrites = 1
+			} else {
+				fmt.Printf("JUnit report written to %s\n", outputPath)
 			}
-			fmt.Printf("HTML report written to %s\n", outputPath)
 		}
 	case "s3":
 		writer := output.NewWriter(output.Config{
 			Type:             output.S3,
 			S3Bucket:         opts.bucket,
 			S3Region:         opts.bucketRegion,
+			S3KMSKeyID:       opts.s3KMSKeyID,
+			S3ACL:            opts.s3ACL,
+			S3StorageClass:   opts.s3StorageClass,
+			Uncompressed:     !opts.compress,
 			OrganizationRole: opts.organizationRole,
+			FilenameTemplate: opts.filenameTemplate,
+			S3Layout:         output.Layout(opts.s3Layout),
 		})
 
-		// Write results for each account
-		for accountID, result := range accountResults {
-			outputData := scanResult{
-				AccountID:   accountID,
-				AccountName: accounts[0].Name,
-				Results:     result.Results,
+		if opts.combinedOutput {
+			if err := writer.WriteCombined(combinedScanResult{
+				Version:   version.String(),
+				ScannedAt: startTime,
+				Accounts:  outputAccounts,
+				Timings:   timings,
+			}); err != nil {
+				logging.Error("Error writing combined scan results to S3", err, map[string]interface{}{
+					"bucket": opts.bucket,
+				})
+				failedWrites = 1
+			} else {
+				logging.Info("Successfully wrote combined scan results to S3", map[string]interface{}{
+					"accounts": len(outputAccounts),
+					"bucket":   opts.bucket,
+				})
 			}
+			totalWrites = 1
+			break
+		}
 
-			data, err := json.Marshal(outputData)
-			if err != nil {
-				logging.Error("Error marshaling scan results", err, map[string]interface{}{
-					"account_id": accountID,
+		// Write results for each account
+		failed := writeAccounts(outputAccountIDs, opts.outputConcurrency, func(accountID string) error {
+			result := accountResults[accountID]
+			var err error
+			if output.Layout(opts.s3Layout) == output.PartitionedLayout {
+				// The partitioned layout holds one finding per line, in a stable order
+				var findings []awsinternal.ScanResult
+				for _, scannerResults := range result.Results {
+					findings = append(findings, scannerResults...)
+				}
+				sort.SliceStable(findings, func(i, j int) bool {
+					if findings[i].ResourceType != findings[j].ResourceType {
+						return findings[i].ResourceType < findings[j].ResourceType
+					}
+					return findings[i].ResourceID < findings[j].ResourceID
 				})
-				continue
+				err = writer.WriteRecords(accountID, result.AccountName, findings)
+			} else {
+				err = writer.Write(accountID, result.AccountName, result)
 			}
-
-			if err := writer.Write(accountID, data); err != nil {
+			if err != nil {
 				logging.Error("Error writing scan results to S3", err, map[string]interface{}{
 					"account_id": accountID,
 					"bucket":     opts.bucket,
 				})
-				continue
+				return err
 			}
 
 			logging.Info("Successfully wrote scan results to S3", map[string]interface{}{
 				"account_id": accountID,
 				"bucket":     opts.bucket,
 			})
+			return nil
+		})
+		logFailedWrites(failed, len(outputAccountIDs))
+		failedWrites, totalWrites = len(failed), len(outputAccountIDs)
+	case "http":
+		writer := output.NewWriter(output.Config{
+			Type:         output.HTTP,
+			WebhookURL:   opts.webhookURL,
+			WebhookToken: opts.webhookToken,
+		})
+
+		if opts.combinedOutput {
+			if err := writer.WriteCombined(combinedScanResult{
+				Version:   version.String(),
+				ScannedAt: startTime,
+				Accounts:  outputAccounts,
+				Timings:   timings,
+			}); err != nil {
+				logging.Error("Error posting combined scan results to webhook", err, nil)
+				failedWrites = 1
+			} else {
+				logging.Info("Successfully posted combined scan results to webhook", map[string]interface{}{
+					"accounts": len(outputAccounts),
+				})
+			}
+			totalWrites = 1
+			break
+		}
+
+		// Post results for each account
+		failed := writeAccounts(outputAccountIDs, opts.outputConcurrency, func(accountID string) error {
+			result := accountResults[accountID]
+			if err := writer.Write(accountID, result.AccountName, result); err != nil {
+				logging.Error("Error posting scan results to webhook", err, map[string]interface{}{
+					"account_id": accountID,
+				})
+				return err
+			}
+
+			logging.Info("Successfully posted scan results to webhook", map[string]interface{}{
+				"account_id": accountID,
+			})
+			return nil
+		})
+		logFailedWrites(failed, len(outputAccountIDs))
+		failedWrites, totalWrites = len(failed), len(outputAccountIDs)
+	}
+
+	// CloudWatch metrics are published alongside whichever output was chosen, from the base
+	// session in the global region, so alarms on every account live in one place
+	if opts.emitCloudWatch {
+		sink := output.NewCloudWatchSink(cloudwatch.New(baseSession, aws.NewConfig().WithRegion(globalRegion)), output.CloudWatchNamespace)
+		if err := sink.Publish(ctx, flattenResults(accountResults), sortedAccountIDs(accountResults), startTime); err != nil {
+			logging.Error("Error publishing CloudWatch metrics", err, map[string]interface{}{
+				"region": globalRegion,
+			})
+		} else {
+			logging.Info("Successfully published CloudWatch metrics", map[string]interface{}{
+				"namespace": output.CloudWatchNamespace,
+				"region":    globalRegion,
+			})
 		}
 	}
 
 	logging.ScanComplete(len(accountResults))
-	return nil
+
+	// Inactive accounts are expected to be skipped, so they are noted without failing the scan
+	if len(inactiveAccounts) > 0 {
+		logging.Info("Some accounts were skipped because they are not active in the organization", map[string]interface{}{
+			"skipped_inactive":       accountLabels(inactiveAccounts),
+			"skipped_inactive_count": len(inactiveAccounts),
+		})
+	}
+
+	// Accounts that were never scanned are called out last so a partial scan doesn't pass for a full one
+	if len(skippedAccounts) > 0 {
+		logging.Error("Some accounts were skipped because the scanner role couldn't be assumed", nil, map[string]interface{}{
+			"skipped_accounts": accountLabels(skippedAccounts),
+			"skipped_count":    len(skippedAccounts),
+			"scanned_count":    len(accountResults),
+		})
+	}
+
+	// Without any written output the scan produced no report, which fails it outright
+	writeErr := writeFailureError(failedWrites, totalWrites)
+	if ExitCode(writeErr) == ExitCodeError {
+		return writeErr
+	}
+
+	// Fail the scan if findings exceed the configured monthly cost budget
+	if opts.failOverCost > 0 {
+		if err := checkCostThreshold(monthlyCostByScanner(accountResults), opts.failOverCost); err != nil {
+			return &ExitError{Code: ExitCodeCostThreshold, Err: err}
+		}
+	}
+
+	// The report is complete, but failed tasks, skipped accounts and accounts whose results couldn't
+	// be written still fail the scan so schedulers can tell it from a clean one
+	if err := partialFailureError(metrics.FailedTasks, metrics.TotalTasks, len(skippedAccounts)); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// monthlyCostByScanner sums the estimated monthly cost of all findings, keyed by scanner label
+func monthlyCostByScanner(accountResults map[string]*scanResult) map[string]float64 {
+	costs := make(map[string]float64)
+	for _, accountResult := range accountResults {
+		for scannerLabel, scannerResults := range accountResult.Results {
+			for _, result := range scannerResults {
+				if total := awsinternal.USDCost(result.Cost); total != nil {
+					costs[scannerLabel] += total.MonthlyRate
+				}
+			}
+		}
+	}
+	return costs
+}
+
+// checkCostThreshold returns an error listing the largest contributors when the summed
+// monthly cost exceeds threshold
+func checkCostThreshold(costs map[string]float64, threshold float64) error {
+	var total float64
+	labels := make([]string, 0, len(costs))
+	for label, cost := range costs {
+		total += cost
+		labels = append(labels, label)
+	}
+	if total <= threshold {
+		return nil
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if costs[labels[i]] != costs[labels[j]] {
+			return costs[labels[i]] > costs[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	const maxContributors = 3
+	var contributors []string
+	for i, label := range labels {
+		if i == maxContributors {
+			break
+		}
+		contributors = append(contributors, fmt.Sprintf("%s ($%.2f)", label, costs[label]))
+	}
+
+	return fmt.Errorf("estimated monthly cost of findings $%.2f exceeds --fail-over-cost $%.2f; top contributors: %s",
+		total, threshold, strings.Join(contributors, ", "))
+}
+
+// htmlReportPath returns where the HTML report should be written. Without an output directory
+// or report name this is reports/scan_report.html; when an output directory is given and no
+// report name, the scan start time is added to the file name so repeated scans don't overwrite
+// each other.
+func htmlReportPath(outputDir, reportName string, startTime time.Time) string {
+	return reportPath(outputDir, reportName, ".html", startTime)
+}
+
+// junitReportPath returns where the JUnit XML report should be written, named like the HTML report
+func junitReportPath(outputDir, reportName string, startTime time.Time) string {
+	return reportPath(outputDir, strings.TrimSuffix(reportName, ".html"), ".xml", startTime)
+}
+
+// reportPath returns the path of a single-file report with the given extension
+func reportPath(outputDir, reportName, extension string, startTime time.Time) string {
+	dir := outputDir
+	if dir == "" {
+		dir = "reports"
+	}
+
+	name := reportName
+	if name == "" {
+		name = "scan_report"
+		if outputDir != "" {
+			name = fmt.Sprintf("scan_report-%s", startTime.Format("20060102-150405"))
+		}
+	}
+	if !strings.HasSuffix(name, extension) {
+		name += extension
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// accountLabels formats accounts as "ID (name)" for messages
+func accountLabels(accounts []awsinternal.Account) []string {
+	labels := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		labels = append(labels, fmt.Sprintf("%s (%s)", account.ID, account.Name))
+	}
+	return labels
+}
+
+// filterOrganizationalUnits narrows accounts to those in a comma-separated list of organizational
+// units, or in OUs nested below them
+func filterOrganizationalUnits(sess *session.Session, accounts []awsinternal.Account, organizationalUnits string) ([]awsinternal.Account, error) {
+	var ouIDs []string
+	for _, ouID := range strings.Split(organizationalUnits, ",") {
+		ouIDs = append(ouIDs, strings.TrimSpace(ouID))
+	}
+	ouAccountIDs, err := awsinternal.ListOrganizationalUnitAccountIDs(sess, ouIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts in organizational units: %w", err)
+	}
+
+	var ouAccounts []awsinternal.Account
+	for _, account := range accounts {
+		if ouAccountIDs[account.ID] {
+			ouAccounts = append(ouAccounts, account)
+		}
+	}
+	return ouAccounts, nil
+}
+
+// splitInactiveAccounts separates accounts that are active, or have no known status, from those
+// Organizations reports as suspended or pending closure
+func splitInactiveAccounts(accounts []awsinternal.Account) (active, inactive []awsinternal.Account) {
+	for _, account := range accounts {
+		if account.IsActive() {
+			active = append(active, account)
+		} else {
+			inactive = append(inactive, account)
+		}
+	}
+	return active, inactive
+}
+
+// sortScanErrors orders scan errors by account, region and scanner
+func sortScanErrors(scanErrors []awsinternal.ScanError) {
+	sort.Slice(scanErrors, func(i, j int) bool {
+		if scanErrors[i].AccountID != scanErrors[j].AccountID {
+			return scanErrors[i].AccountID < scanErrors[j].AccountID
+		}
+		if scanErrors[i].Region != scanErrors[j].Region {
+			return scanErrors[i].Region < scanErrors[j].Region
+		}
+		return scanErrors[i].Scanner < scanErrors[j].Scanner
+	})
+}
+
+// exchangeRate returns the currency to report costs in and the number of units of it one US dollar
+// buys, from the static rate when one is set and otherwise from the default rate provider
+func exchangeRate(currency string, staticRate float64) (string, float64, error) {
+	if currency == "" {
+		return awsinternal.BaseCurrency, 1, nil
+	}
+	code, err := awsinternal.NormalizeCurrency(currency)
+	if err != nil {
+		return "", 0, err
+	}
+	if code == awsinternal.BaseCurrency {
+		return code, 1, nil
+	}
+
+	var provider awsinternal.ExchangeRateProvider = awsinternal.StaticExchangeRate(staticRate)
+	if staticRate == 0 && awsinternal.DefaultExchangeRateProvider != nil {
+		provider = awsinternal.DefaultExchangeRateProvider
+	}
+	rate, err := provider.ExchangeRate(code)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get exchange rate for %s: %w", code, err)
+	}
+	return code, rate, nil
+}
+
+// truncateResults keeps the limit most expensive results, in their original order, and returns them
+// with the number left out
+func truncateResults(results awsinternal.ScanResults, limit int) (awsinternal.ScanResults, int) {
+	if len(results) <= limit {
+		return results, 0
+	}
+
+	monthly := func(result awsinternal.ScanResult) float64 {
+		if total, ok := result.Cost["total"].(*awsinternal.CostBreakdown); ok && total != nil {
+			return total.MonthlyRate
+		}
+		return 0
+	}
+
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return monthly(results[order[i]]) > monthly(results[order[j]])
+	})
+	kept := order[:limit]
+	sort.Ints(kept)
+
+	truncated := make(awsinternal.ScanResults, 0, limit)
+	for _, i := range kept {
+		truncated = append(truncated, results[i])
+	}
+	return truncated, len(results) - limit
+}
+
+// sortTruncations orders truncation notes by account, region and scanner
+func sortTruncations(truncations []awsinternal.TruncatedResults) {
+	sort.Slice(truncations, func(i, j int) bool {
+		if truncations[i].AccountID != truncations[j].AccountID {
+			return truncations[i].AccountID < truncations[j].AccountID
+		}
+		if truncations[i].Region != truncations[j].Region {
+			return truncations[i].Region < truncations[j].Region
+		}
+		return truncations[i].Scanner < truncations[j].Scanner
+	})
+}
+
+// sortResults orders a scanner's findings by resource ID. Scanners run once per region in
+// parallel, so findings otherwise arrive in whatever order the regions finish.
+func sortResults(results awsinternal.ScanResults) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].ResourceID != results[j].ResourceID {
+			return results[i].ResourceID < results[j].ResourceID
+		}
+		return results[i].Region < results[j].Region
+	})
+}
+
+// sortedAccountIDs returns the scanned account IDs in ascending order
+func sortedAccountIDs(accountResults map[string]*scanResult) []string {
+	accountIDs := make([]string, 0, len(accountResults))
+	for accountID := range accountResults {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+	return accountIDs
+}
+
+// accountsToWrite returns the IDs of the accounts to write per-account output for, in ascending
+// order. When onlyWithFindings is set, accounts with no findings are left out unless a scanner failed
+// for them, so a failed scan isn't mistaken for a clean account.
+func accountsToWrite(accountResults map[string]*scanResult, onlyWithFindings bool) []string {
+	accountIDs := sortedAccountIDs(accountResults)
+	if !onlyWithFindings {
+		return accountIDs
+	}
+
+	withFindings := make([]string, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		result := accountResults[accountID]
+		if len(result.Errors) > 0 {
+			withFindings = append(withFindings, accountID)
+			continue
+		}
+		for _, scannerResults := range result.Results {
+			if len(scannerResults) > 0 {
+				withFindings = append(withFindings, accountID)
+				break
+			}
+		}
+	}
+	return withFindings
+}
+
+// writeAccounts calls write for each account on a pool of concurrency workers, so writing or
+// uploading hundreds of accounts' results doesn't happen one at a time. It returns the errors of
+// the accounts whose write failed, keyed by account ID.
+func writeAccounts(accountIDs []string, concurrency int, write func(accountID string) error) map[string]error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	pool := worker.NewPool(concurrency)
+	pool.Start()
+	defer pool.Stop()
+
+	failed := make(map[string]error)
+	var failedMutex sync.Mutex
+	tasks := make([]worker.Task, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		accountID := accountID
+		tasks = append(tasks, func(ctx context.Context) error {
+			err := write(accountID)
+			if err != nil {
+				failedMutex.Lock()
+				failed[accountID] = err
+				failedMutex.Unlock()
+			}
+			return err
+		})
+	}
+	pool.ExecuteTasks(tasks)
+	return failed
+}
+
+// logFailedWrites logs how many accounts' results couldn't be written, after each was logged
+// on its own, so failures among hundreds of accounts aren't lost in the output
+func logFailedWrites(failed map[string]error, total int) {
+	if len(failed) == 0 {
+		return
+	}
+	accountIDs := make([]string, 0, len(failed))
+	for accountID := range failed {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+	logging.Warn("Failed to write results for some accounts", map[string]interface{}{
+		"failed_accounts": accountIDs,
+		"failed_count":    len(failed),
+		"total_count":     total,
+	})
+}
+
+// flattenResults collects every finding into a single list ordered by account ID, then scanner
+// label, then the order of each scanner's results
+func flattenResults(accountResults map[string]*scanResult) []awsinternal.ScanResult {
+	var allResults []awsinternal.ScanResult
+	for _, accountID := range sortedAccountIDs(accountResults) {
+		results := accountResults[accountID].Results
+		labels := make([]string, 0, len(results))
+		for label := range results {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			allResults = append(allResults, results[label]...)
+		}
+	}
+	return allResults
+}
+
+// parseRegionFilter splits a --report-regions list into the regions to report findings from and
+// the regions, prefixed with !, to leave out. Global findings are in region "global".
+func parseRegionFilter(filter string) (map[string]bool, map[string]bool, error) {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+	if strings.TrimSpace(filter) == "" {
+		return include, exclude, nil
+	}
+	for _, entry := range strings.Split(filter, ",") {
+		region, excluded := strings.CutPrefix(strings.TrimSpace(entry), "!")
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" {
+			return nil, nil, fmt.Errorf("invalid --report-regions %q: empty region", filter)
+		}
+		if excluded {
+			exclude[region] = true
+		} else {
+			include[region] = true
+		}
+	}
+	return include, exclude, nil
+}
+
+// filterResultsByRegion drops findings outside the included regions, when any are given, and
+// findings in excluded regions. It returns the number of findings removed.
+func filterResultsByRegion(accountResults map[string]*scanResult, include, exclude map[string]bool) int {
+	removed := 0
+	for _, accountResult := range accountResults {
+		for scannerLabel, scannerResults := range accountResult.Results {
+			kept := scannerResults[:0]
+			for _, result := range scannerResults {
+				if (len(include) > 0 && !include[result.Region]) || exclude[result.Region] {
+					removed++
+					continue
+				}
+				kept = append(kept, result)
+			}
+			accountResult.Results[scannerLabel] = kept
+		}
+	}
+	return removed
+}
+
+// dedupeResults collapses findings for the same resource into a single entry and records how
+// many times each was reported. Results passed in are expected to share a scanner and account;
+// the region is part of the key since some resource IDs (DynamoDB tables, classic load balancers)
+// are only unique within a region, while global resources are all reported as region "global".
+// It returns the deduplicated results and the number of duplicates removed.
+func dedupeResults(results awsinternal.ScanResults) (awsinternal.ScanResults, int) {
+	type resultKey struct {
+		region     string
+		resourceID string
+	}
+
+	deduped := make(awsinternal.ScanResults, 0, len(results))
+	index := make(map[resultKey]int, len(results))
+	for _, result := range results {
+		key := resultKey{region: result.Region, resourceID: result.ResourceID}
+		if i, ok := index[key]; ok {
+			deduped[i].Occurrences++
+			continue
+		}
+		result.Occurrences = 1
+		index[key] = len(deduped)
+		deduped = append(deduped, result)
+	}
+
+	return deduped, len(results) - len(deduped)
+}
+
+// runScannerWithTimeout runs a scanner with a deadline derived from the worker task context.
+// If the deadline passes before the scanner returns, its results are discarded and an error
+// wrapping context.DeadlineExceeded is returned so the task is recorded as failed.
+func runScannerWithTimeout(ctx context.Context, scanner awsinternal.Scanner, scanOpts awsinternal.ScanOptions, timeout time.Duration) (awsinternal.ScanResults, error) {
+	scanCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	scanOpts.Ctx = scanCtx
+
+	type scanOutcome struct {
+		results awsinternal.ScanResults
+		err     error
+	}
+	done := make(chan scanOutcome, 1) // Buffered so an abandoned scanner can still finish
+	go func() {
+		results, err := scanner.Scan(scanOpts)
+		done <- scanOutcome{results: results, err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		if outcome.err != nil && errors.Is(scanCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("scanner %s exceeded timeout of %s: %w", scanner.Label(), timeout, context.DeadlineExceeded)
+		}
+		return outcome.results, outcome.err
+	case <-scanCtx.Done():
+		return nil, fmt.Errorf("scanner %s exceeded timeout of %s: %w", scanner.Label(), timeout, scanCtx.Err())
+	}
+}
+
+// assumeScannerRole assumes the scanner role in an account from the organization session and
+// verifies the assumption with GetCallerIdentity, returning the session and the assumed identity ARN.
+// The credentials are refreshed automatically for as long as the scan runs. The session is registered
+// as a role session, so callers must pass it to ForgetRoleSession once they are done with it.
+func assumeScannerRole(baseSession *session.Session, partition, accountID, scannerRole string) (*session.Session, string, error) {
+	scannerRoleARN := awsinternal.RoleARN(partition, accountID, scannerRole)
+	chained := config.Config.OrganizationRole != "" || len(config.Config.AssumeRoleChain) > 0
+	scannerCreds := awsinternal.AssumeRoleCredentials(baseSession, scannerRoleARN, chained)
+	scanSession, err := session.NewSession(aws.NewConfig().WithCredentials(scannerCreds))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create session for %s: %w", scannerRoleARN, err)
+	}
+	identity, err := sts.New(scanSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to verify scanner role assumption for %s: %w", scannerRoleARN, err)
+	}
+	// Regions with their own credentials assume the scanner role from those instead
+	awsinternal.RegisterRoleSession(scanSession, scannerRoleARN)
+
+	return scanSession, aws.StringValue(identity.Arn), nil
 }
 
 // getRoleARN returns the full ARN for a role. If the input is already an ARN, returns it as is.
 func getRoleARN(sess *session.Session, roleName string) (string, error) {
 	// If it's already an ARN, return it
-	if strings.HasPrefix(roleName, "arn:aws:iam::") {
+	if arn.IsARN(roleName) {
 		return roleName, nil
 	}
 
@@ -941,8 +2820,8 @@ func getRoleARN(sess *session.Session, roleName string) (string, error) {
 		return "", fmt.Errorf("failed to get account ID: %w", err)
 	}
 
-	// Construct the role ARN
-	return fmt.Sprintf("arn:aws:iam::%s:role/%s", *result.Account, roleName), nil
+	// Construct the role ARN in the caller's partition
+	return awsinternal.RoleARN(awsinternal.PartitionFromARN(aws.StringValue(result.Arn)), *result.Account, roleName), nil
 }
 
 // getSessionWithOrgRole creates an AWS session and assumes the organization role if specified
@@ -966,6 +2845,7 @@ func getSessionWithOrgRole(region, orgRole string) (*session.Session, error) {
 		input := &sts.AssumeRoleInput{
 			RoleArn:         aws.String(roleARN),
 			RoleSessionName: aws.String(roleSessionName),
+			DurationSeconds: aws.Int64(int64(awsinternal.SessionDuration(len(config.Config.AssumeRoleChain) > 0).Seconds())),
 		}
 
 		// Assume the role
@@ -995,8 +2875,88 @@ func getSessionWithOrgRole(region, orgRole string) (*session.Session, error) {
 	return sess, nil
 }
 
-// validateS3Access validates that we can write to the specified S3 bucket
-func validateS3Access(bucket, region string, orgRole string) error {
+// parseTagList parses a comma-separated list of KEY=VALUE tags, skipping entries without a value
+func parseTagList(list string) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(list, ",") {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		}
+	}
+	return tags
+}
+
+// parseProfileRegionMap parses a comma-separated list of REGION=PROFILE pairs, where the profile
+// may also be a role ARN. Each region may only be mapped once.
+func parseProfileRegionMap(list string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		region, profile, ok := strings.Cut(entry, "=")
+		region, profile = strings.TrimSpace(region), strings.TrimSpace(profile)
+		if !ok || region == "" || profile == "" {
+			return nil, fmt.Errorf("invalid --profile-region-map entry %q: expected REGION=PROFILE", entry)
+		}
+		if _, exists := mapping[region]; exists {
+			return nil, fmt.Errorf("region %s is mapped more than once in --profile-region-map", region)
+		}
+		mapping[region] = profile
+	}
+	return mapping, nil
+}
+
+// hasMatchingTag reports whether any of a resource's tags matches one of the rules. Keys and values
+// are compared case-insensitively.
+func hasMatchingTag(tags map[string]string, rules map[string]string) bool {
+	for ruleKey, ruleValue := range rules {
+		for tagKey, tagValue := range tags {
+			if strings.EqualFold(tagKey, ruleKey) && strings.EqualFold(tagValue, ruleValue) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNewerThan reports whether a result's resource was created fewer than minAgeDays days before now.
+// Results without a known creation time are never considered new, and a minAgeDays of 0 disables the check.
+func isNewerThan(result awsinternal.ScanResult, minAgeDays int, now time.Time) bool {
+	if minAgeDays <= 0 || result.CreatedAt == nil || result.CreatedAt.IsZero() {
+		return false
+	}
+	return now.Sub(*result.CreatedAt) < time.Duration(minAgeDays)*24*time.Hour
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid --webhook-url %q: expected an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// organizationalUnitPattern matches organizational unit and organization root IDs
+var organizationalUnitPattern = regexp.MustCompile(`^(ou-[0-9a-z]{4,32}-[0-9a-z]{8,32}|r-[0-9a-z]{4,32})$`)
+
+// kmsKeyARNPattern matches KMS key and alias ARNs in any partition
+var kmsKeyARNPattern = regexp.MustCompile(`^arn:aws(-[a-z-]+)?:kms:[a-z0-9-]+:[0-9]{12}:(key/[A-Za-z0-9-]+|alias/[A-Za-z0-9/_-]+)$`)
+
+// validateKMSKeyARN checks that a KMS key ARN is well formed
+func validateKMSKeyARN(keyARN string) error {
+	if !kmsKeyARNPattern.MatchString(keyARN) {
+		return fmt.Errorf("invalid --s3-kms-key-id %q: expected a KMS key ARN such as arn:aws:kms:us-west-2:111122223333:key/<key-id>", keyARN)
+	}
+	return nil
+}
+
+// validateS3Access validates that we can write to the specified S3 bucket, encrypting the test
+// object with kmsKeyID when one is given
+func validateS3Access(bucket, region string, orgRole string, kmsKeyID string, acl string, storageClass string) error {
 	logging.Info("Starting S3 bucket access validation", map[string]interface{}{
 		"bucket": bucket,
 		"region": region,
@@ -1018,12 +2978,23 @@ func validateS3Access(bucket, region string, orgRole string) error {
 	testKey := ".cloudsift_validation"
 
 	// Try to upload a test file with required encryption
-	_, err = s3Client.PutObject(&s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket:               aws.String(bucket),
 		Key:                  aws.String(testKey),
 		Body:                 bytes.NewReader([]byte("test")),
 		ServerSideEncryption: aws.String("aws:kms"),
-	})
+	}
+	if kmsKeyID != "" {
+		input.SSEKMSKeyId = aws.String(kmsKeyID)
+	}
+	// Bucket policies can require an ACL or storage class, so the test object uses the same ones
+	if acl != "" {
+		input.ACL = aws.String(acl)
+	}
+	if storageClass != "" {
+		input.StorageClass = aws.String(storageClass)
+	}
+	_, err = s3Client.PutObject(input)
 	if err != nil {
 		logging.Error("Failed to write test file to S3", err, map[string]interface{}{
 			"bucket": bucket,
@@ -2,10 +2,19 @@ package scan
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,11 +22,15 @@ import (
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -26,6 +39,9 @@ import (
 
 	awsinternal "cloudsift/internal/aws"
 	"cloudsift/internal/config"
+	"cloudsift/internal/output"
+	"cloudsift/internal/output/html"
+	"cloudsift/pkg/scanner"
 )
 
 // Mock AWS services
@@ -73,6 +89,7 @@ func (m *mockOrganizationsAPI) ListAccounts(input *organizations.ListAccountsInp
 type testScanner struct {
 	argumentName string
 	label        string
+	global       bool
 	scanFunc     func(opts awsinternal.ScanOptions) (awsinternal.ScanResults, error)
 }
 
@@ -84,6 +101,10 @@ func (s *testScanner) Label() string {
 	return s.label
 }
 
+func (s *testScanner) IsGlobal() bool {
+	return s.global
+}
+
 func (s *testScanner) Scan(opts awsinternal.ScanOptions) (awsinternal.ScanResults, error) {
 	if s.scanFunc != nil {
 		return s.scanFunc(opts)
@@ -161,6 +182,207 @@ func TestNewScanCmd(t *testing.T) {
 	daysUnusedFlag := flags.Lookup("days-unused")
 	assert.NotNil(t, daysUnusedFlag)
 	assert.Equal(t, "int", daysUnusedFlag.Value.Type())
+
+	scannerTimeoutFlag := flags.Lookup("scanner-timeout")
+	assert.NotNil(t, scannerTimeoutFlag)
+	assert.Equal(t, "duration", scannerTimeoutFlag.Value.Type())
+
+	failOverCostFlag := flags.Lookup("fail-over-cost")
+	assert.NotNil(t, failOverCostFlag)
+	assert.Equal(t, "float64", failOverCostFlag.Value.Type())
+
+	outputDirFlag := flags.Lookup("output-dir")
+	assert.NotNil(t, outputDirFlag)
+	assert.Equal(t, "string", outputDirFlag.Value.Type())
+
+	reportNameFlag := flags.Lookup("report-name")
+	assert.NotNil(t, reportNameFlag)
+	assert.Equal(t, "string", reportNameFlag.Value.Type())
+
+	profilesFlag := flags.Lookup("profiles")
+	assert.NotNil(t, profilesFlag)
+	assert.Equal(t, "string", profilesFlag.Value.Type())
+
+	ignoreFileFlag := flags.Lookup("ignore-file")
+	assert.NotNil(t, ignoreFileFlag)
+	assert.Equal(t, "string", ignoreFileFlag.Value.Type())
+
+	maxTasksPerAccountFlag := flags.Lookup("max-tasks-per-account")
+	assert.NotNil(t, maxTasksPerAccountFlag)
+	assert.Equal(t, "int", maxTasksPerAccountFlag.Value.Type())
+
+	emitRemediationFlag := flags.Lookup("emit-remediation")
+	assert.NotNil(t, emitRemediationFlag)
+	assert.Equal(t, "string", emitRemediationFlag.Value.Type())
+
+	remediationUncommentFlag := flags.Lookup("remediation-uncomment")
+	assert.NotNil(t, remediationUncommentFlag)
+	assert.Equal(t, "bool", remediationUncommentFlag.Value.Type())
+
+	tuiFlag := flags.Lookup("tui")
+	assert.NotNil(t, tuiFlag)
+	assert.Equal(t, "bool", tuiFlag.Value.Type())
+
+	s3KMSKeyID := flags.Lookup("s3-kms-key-id")
+	assert.NotNil(t, s3KMSKeyID)
+	assert.Equal(t, "string", s3KMSKeyID.Value.Type())
+
+	includeTags := flags.Lookup("include-tags")
+	assert.NotNil(t, includeTags)
+	assert.Equal(t, "string", includeTags.Value.Type())
+
+	filenameTemplate := flags.Lookup("filename-template")
+	assert.NotNil(t, filenameTemplate)
+	assert.Equal(t, "string", filenameTemplate.Value.Type())
+
+	s3Layout := flags.Lookup("s3-layout")
+	assert.NotNil(t, s3Layout)
+	assert.Equal(t, "string", s3Layout.Value.Type())
+	assert.Equal(t, "flat", s3Layout.DefValue)
+
+	maxTasksPerRegion := flags.Lookup("max-tasks-per-region")
+	assert.NotNil(t, maxTasksPerRegion)
+	assert.Equal(t, "int", maxTasksPerRegion.Value.Type())
+
+	combinedOutput := flags.Lookup("combined-output")
+	assert.NotNil(t, combinedOutput)
+	assert.Equal(t, "bool", combinedOutput.Value.Type())
+
+	preset := flags.Lookup("preset")
+	assert.NotNil(t, preset)
+	assert.Equal(t, "string", preset.Value.Type())
+
+	skipScanners := flags.Lookup("skip-scanners")
+	assert.NotNil(t, skipScanners)
+	assert.Equal(t, "string", skipScanners.Value.Type())
+
+	minAgeDaysFlag := flags.Lookup("min-age-days")
+	assert.NotNil(t, minAgeDaysFlag)
+	assert.Equal(t, "int", minAgeDaysFlag.Value.Type())
+
+	organizationalUnitsFlag := flags.Lookup("organizational-units")
+	assert.NotNil(t, organizationalUnitsFlag)
+	assert.Equal(t, "string", organizationalUnitsFlag.Value.Type())
+
+	webhookURLFlag := flags.Lookup("webhook-url")
+	assert.NotNil(t, webhookURLFlag)
+	assert.Equal(t, "string", webhookURLFlag.Value.Type())
+
+	webhookTokenFlag := flags.Lookup("webhook-token")
+	assert.NotNil(t, webhookTokenFlag)
+	assert.Equal(t, "string", webhookTokenFlag.Value.Type())
+
+	requireAllAccounts := flags.Lookup("require-all-accounts")
+	assert.NotNil(t, requireAllAccounts)
+	assert.Equal(t, "bool", requireAllAccounts.Value.Type())
+
+	reportTitle := flags.Lookup("report-title")
+	assert.NotNil(t, reportTitle)
+	assert.Equal(t, "string", reportTitle.Value.Type())
+
+	reportLogo := flags.Lookup("report-logo")
+	assert.NotNil(t, reportLogo)
+	assert.Equal(t, "string", reportLogo.Value.Type())
+
+	maxResultsPerScanner := flags.Lookup("max-results-per-scanner")
+	assert.NotNil(t, maxResultsPerScanner)
+	assert.Equal(t, "int", maxResultsPerScanner.Value.Type())
+
+	checkpoint := flags.Lookup("checkpoint")
+	assert.NotNil(t, checkpoint)
+	assert.Equal(t, "string", checkpoint.Value.Type())
+
+	resume := flags.Lookup("resume")
+	assert.NotNil(t, resume)
+	assert.Equal(t, "string", resume.Value.Type())
+
+	currency := flags.Lookup("currency")
+	assert.NotNil(t, currency)
+	assert.Equal(t, "string", currency.Value.Type())
+
+	exchangeRate := flags.Lookup("exchange-rate")
+	assert.NotNil(t, exchangeRate)
+	assert.Equal(t, "float64", exchangeRate.Value.Type())
+
+	onlyAccountsWithFindings := flags.Lookup("only-accounts-with-findings")
+	assert.NotNil(t, onlyAccountsWithFindings)
+	assert.Equal(t, "bool", onlyAccountsWithFindings.Value.Type())
+
+	ec2Rightsizing := flags.Lookup("ec2-rightsizing")
+	assert.NotNil(t, ec2Rightsizing)
+	assert.Equal(t, "bool", ec2Rightsizing.Value.Type())
+
+	actualSpend := flags.Lookup("actual-spend")
+	assert.NotNil(t, actualSpend)
+	assert.Equal(t, "bool", actualSpend.Value.Type())
+
+	s3ACL := flags.Lookup("s3-acl")
+	assert.NotNil(t, s3ACL)
+	assert.Equal(t, "string", s3ACL.Value.Type())
+
+	s3StorageClass := flags.Lookup("s3-storage-class")
+	assert.NotNil(t, s3StorageClass)
+	assert.Equal(t, "string", s3StorageClass.Value.Type())
+
+	applyTags := flags.Lookup("apply-tags")
+	assert.NotNil(t, applyTags)
+	assert.Equal(t, "bool", applyTags.Value.Type())
+
+	flagTag := flags.Lookup("flag-tag")
+	assert.NotNil(t, flagTag)
+	assert.Equal(t, "string", flagTag.Value.Type())
+
+	dryRun := flags.Lookup("dry-run")
+	assert.NotNil(t, dryRun)
+	assert.Equal(t, "bool", dryRun.Value.Type())
+
+	accountsFile := flags.Lookup("accounts-file")
+	assert.NotNil(t, accountsFile)
+	assert.Equal(t, "string", accountsFile.Value.Type())
+
+	globalRegion := flags.Lookup("global-region")
+	assert.NotNil(t, globalRegion)
+	assert.Equal(t, "string", globalRegion.Value.Type())
+
+	compress := flags.Lookup("compress")
+	assert.NotNil(t, compress)
+	assert.Equal(t, "bool", compress.Value.Type())
+
+	reportRegionsFlag := flags.Lookup("report-regions")
+	assert.NotNil(t, reportRegionsFlag)
+	assert.Equal(t, "string", reportRegionsFlag.Value.Type())
+
+	baselineFlag := flags.Lookup("baseline")
+	assert.NotNil(t, baselineFlag)
+	assert.Equal(t, "string", baselineFlag.Value.Type())
+
+	anomalyThresholdFlag := flags.Lookup("anomaly-threshold")
+	assert.NotNil(t, anomalyThresholdFlag)
+	assert.Equal(t, "float64", anomalyThresholdFlag.Value.Type())
+
+	emitIgnoredFlag := flags.Lookup("emit-ignored")
+	assert.NotNil(t, emitIgnoredFlag)
+	assert.Equal(t, "string", emitIgnoredFlag.Value.Type())
+
+	outputConcurrencyFlag := flags.Lookup("output-concurrency")
+	assert.NotNil(t, outputConcurrencyFlag)
+	assert.Equal(t, "int", outputConcurrencyFlag.Value.Type())
+
+	emitCloudWatchFlag := flags.Lookup("emit-cloudwatch")
+	assert.NotNil(t, emitCloudWatchFlag)
+	assert.Equal(t, "bool", emitCloudWatchFlag.Value.Type())
+
+	sessionDurationFlag := flags.Lookup("session-duration")
+	assert.NotNil(t, sessionDurationFlag)
+	assert.Equal(t, "duration", sessionDurationFlag.Value.Type())
+
+	profileRegionMapFlag := flags.Lookup("profile-region-map")
+	assert.NotNil(t, profileRegionMapFlag)
+	assert.Equal(t, "string", profileRegionMapFlag.Value.Type())
+
+	minConfidenceFlag := flags.Lookup("min-confidence")
+	assert.NotNil(t, minConfidenceFlag)
+	assert.Equal(t, "string", minConfidenceFlag.Value.Type())
 }
 
 // TestGetScanners tests the getScanners function
@@ -320,6 +542,152 @@ func TestGetScanners(t *testing.T) {
 	}
 }
 
+// TestRegisterCustomScanner tests that scanners registered through the public API are picked up
+func TestRegisterCustomScanner(t *testing.T) {
+	originalRegistry := awsinternal.DefaultRegistry
+	defer func() {
+		awsinternal.DefaultRegistry = originalRegistry
+	}()
+	awsinternal.DefaultRegistry = awsinternal.NewScannerRegistry()
+	awsinternal.DefaultRegistry.RegisterScanner(&testScanner{argumentName: "builtin", label: "Built-in"})
+
+	custom := func() scanner.Scanner {
+		return &testScanner{argumentName: "custom-widgets", label: "Custom Widgets"}
+	}
+	require.NoError(t, scanner.Register("custom-widgets", custom))
+	assert.Equal(t, []string{"builtin", "custom-widgets"}, scanner.Names())
+
+	scanners, invalid, err := getScanners("")
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	require.Len(t, scanners, 2)
+	assert.Equal(t, "custom-widgets", scanners[1].ArgumentName())
+
+	scanners, invalid, err = getScanners("custom-widgets")
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	require.Len(t, scanners, 1)
+	assert.Equal(t, "Custom Widgets", scanners[0].Label())
+
+	// Names can't be reused, mismatched or used to break --scanners lists
+	assert.ErrorContains(t, scanner.Register("builtin", func() scanner.Scanner {
+		return &testScanner{argumentName: "builtin", label: "Replacement"}
+	}), "already registered")
+	assert.ErrorContains(t, scanner.Register("other-name", custom), "has argument name custom-widgets")
+	assert.ErrorContains(t, scanner.Register("a,b", custom), "invalid scanner name")
+	assert.ErrorContains(t, scanner.Register("no-factory", nil), "has no factory")
+	assert.Equal(t, []string{"builtin", "custom-widgets"}, scanner.Names())
+}
+
+// TestSkipScanners tests removing scanners with --skip-scanners
+func TestSkipScanners(t *testing.T) {
+	originalRegistry := awsinternal.DefaultRegistry
+	defer func() {
+		awsinternal.DefaultRegistry = originalRegistry
+	}()
+
+	testRegistry := awsinternal.NewScannerRegistry()
+	awsinternal.DefaultRegistry = testRegistry
+	for _, name := range []string{"scanner1", "scanner2", "scanner3"} {
+		testRegistry.RegisterScanner(&testScanner{argumentName: name, label: name})
+	}
+
+	// Skips apply after the include list
+	scanners, _, err := getScanners("scanner1,scanner2")
+	require.NoError(t, err)
+	remaining, invalid := skipScanners(scanners, "scanner2, scanner3,unknown")
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "scanner1", remaining[0].ArgumentName())
+	assert.Equal(t, []string{"unknown"}, invalid)
+
+	// Skipping from the full registry
+	scanners, _, err = getScanners("")
+	require.NoError(t, err)
+	remaining, invalid = skipScanners(scanners, "scanner1")
+	assert.Len(t, remaining, 2)
+	assert.Empty(t, invalid)
+}
+
+// TestDeprecatedScannerNames tests that a renamed scanner can still be selected and skipped by its
+// old name, without the old name being listed or registered again
+func TestDeprecatedScannerNames(t *testing.T) {
+	originalRegistry := awsinternal.DefaultRegistry
+	defer func() {
+		awsinternal.DefaultRegistry = originalRegistry
+	}()
+
+	testRegistry := awsinternal.NewScannerRegistry()
+	awsinternal.DefaultRegistry = testRegistry
+	testRegistry.RegisterScanner(&testScanner{argumentName: "scanner1-resources", label: "Scanner 1"})
+	testRegistry.RegisterScanner(&testScanner{argumentName: "scanner2", label: "Scanner 2"})
+	testRegistry.RegisterAlias("scanner1", "scanner1-resources")
+
+	assert.Equal(t, []string{"scanner1-resources", "scanner2"}, testRegistry.ListScanners())
+
+	scanners, invalid, err := getScanners("scanner1")
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	require.Len(t, scanners, 1)
+	assert.Equal(t, "scanner1-resources", scanners[0].ArgumentName())
+
+	scanners, _, err = getScanners("")
+	require.NoError(t, err)
+	remaining, invalid := skipScanners(scanners, "scanner1")
+	assert.Empty(t, invalid)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "scanner2", remaining[0].ArgumentName())
+
+	err = testRegistry.RegisterScannerFactory("scanner1", func() awsinternal.Scanner {
+		return &testScanner{argumentName: "scanner1", label: "Other Scanner 1"}
+	})
+	assert.Error(t, err)
+}
+
+// groupingTestScanner is a test scanner that reports the findings of the scanners it covers
+type groupingTestScanner struct {
+	testScanner
+	covers []string
+}
+
+func (s *groupingTestScanner) Covers() []string {
+	return s.covers
+}
+
+// TestGroupingScanners tests that grouping scanners only run when selected, and replace the
+// scanners they cover so no finding is reported twice
+func TestGroupingScanners(t *testing.T) {
+	originalRegistry := awsinternal.DefaultRegistry
+	defer func() {
+		awsinternal.DefaultRegistry = originalRegistry
+	}()
+
+	testRegistry := awsinternal.NewScannerRegistry()
+	awsinternal.DefaultRegistry = testRegistry
+	for _, name := range []string{"scanner1", "scanner2", "scanner3"} {
+		testRegistry.RegisterScanner(&testScanner{argumentName: name, label: name})
+	}
+	testRegistry.RegisterScanner(&groupingTestScanner{
+		testScanner: testScanner{argumentName: "grouped", label: "Grouped"},
+		covers:      []string{"scanner1", "scanner2"},
+	})
+
+	argumentNames := func(scanners []awsinternal.Scanner) []string {
+		var names []string
+		for _, scanner := range scanners {
+			names = append(names, scanner.ArgumentName())
+		}
+		return names
+	}
+
+	scanners, _, err := getScanners("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scanner1", "scanner2", "scanner3"}, argumentNames(scanners))
+
+	scanners, _, err = getScanners("scanner1,grouped,scanner3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"grouped", "scanner3"}, argumentNames(scanners))
+}
+
 // TestGetRoleARN tests the getRoleARN function
 func TestGetRoleARN(t *testing.T) {
 	// Create mock STS client
@@ -384,6 +752,30 @@ func TestGetRoleARN(t *testing.T) {
 			expected:  "arn:aws:iam::123456789012:role/TestRole",
 			expectErr: false,
 		},
+		{
+			name:     "already a GovCloud ARN",
+			roleName: "arn:aws-us-gov:iam::123456789012:role/TestRole",
+			setupMocks: func() {
+				// No mocks needed, as the function should return early
+			},
+			expected:  "arn:aws-us-gov:iam::123456789012:role/TestRole",
+			expectErr: false,
+		},
+		{
+			name:      "role name only - GovCloud caller",
+			roleName:  "TestRole",
+			accountID: "123456789012",
+			setupMocks: func() {
+				mockSTS.ExpectedCalls = nil
+				mockSTS.On("GetCallerIdentity", &sts.GetCallerIdentityInput{}).Return(
+					&sts.GetCallerIdentityOutput{
+						Account: aws.String("123456789012"),
+						Arn:     aws.String("arn:aws-us-gov:iam::123456789012:user/testuser"),
+					}, nil)
+			},
+			expected:  "arn:aws-us-gov:iam::123456789012:role/TestRole",
+			expectErr: false,
+		},
 		{
 			name:     "role name only - error",
 			roleName: "TestRole",
@@ -463,6 +855,7 @@ func TestValidateS3Access(t *testing.T) {
 		bucket     string
 		region     string
 		orgRole    string
+		kmsKeyID   string
 		setupMocks func()
 		expectErr  bool
 	}{
@@ -514,6 +907,22 @@ func TestValidateS3Access(t *testing.T) {
 			},
 			expectErr: false, // Should not error, delete failure is just logged as a warning
 		},
+		{
+			name:     "put object with KMS key",
+			bucket:   "testbucket",
+			region:   "us-west-2",
+			orgRole:  "MyRole",
+			kmsKeyID: "arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			setupMocks: func() {
+				// PutObject must carry the requested key
+				mockS3Client.ExpectedCalls = nil
+				mockS3Client.On("PutObject", mock.MatchedBy(func(input *s3.PutObjectInput) bool {
+					return aws.StringValue(input.SSEKMSKeyId) == "arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+				})).Return(&s3.PutObjectOutput{}, nil)
+				mockS3Client.On("DeleteObject", mock.Anything).Return(&s3.DeleteObjectOutput{}, nil)
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -522,7 +931,7 @@ func TestValidateS3Access(t *testing.T) {
 			tt.setupMocks()
 
 			// Call function
-			err := validateS3Access(tt.bucket, tt.region, tt.orgRole)
+			err := validateS3Access(tt.bucket, tt.region, tt.orgRole, tt.kmsKeyID, "", "")
 
 			// Check expectations
 			if tt.expectErr {
@@ -534,6 +943,29 @@ func TestValidateS3Access(t *testing.T) {
 	}
 }
 
+// TestValidateKMSKeyARN tests that only well-formed KMS key and alias ARNs are accepted
+func TestValidateKMSKeyARN(t *testing.T) {
+	valid := []string{
+		"arn:aws:kms:us-west-2:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		"arn:aws:kms:us-east-1:111122223333:alias/cloudsift-output",
+		"arn:aws-us-gov:kms:us-gov-west-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+	}
+	for _, keyARN := range valid {
+		assert.NoError(t, validateKMSKeyARN(keyARN), keyARN)
+	}
+
+	invalid := []string{
+		"1234abcd-12ab-34cd-56ef-1234567890ab",
+		"alias/cloudsift-output",
+		"arn:aws:s3:::my-bucket",
+		"arn:aws:kms:us-west-2:1111:key/1234abcd",
+		"arn:aws:kms:us-west-2:111122223333:key/",
+	}
+	for _, keyARN := range invalid {
+		assert.Error(t, validateKMSKeyARN(keyARN), keyARN)
+	}
+}
+
 // TestGetSessionWithOrgRole tests the getSessionWithOrgRole function
 func TestGetSessionWithOrgRole(t *testing.T) {
 	// Create mock STS client
@@ -851,7 +1283,7 @@ func TestRunScan(t *testing.T) {
 	defer safeUnpatch(newCostEstimatorPatch)
 
 	// Patch validateS3Access for the error case
-	validateS3Patch, err := mpatch.PatchMethod(validateS3Access, func(bucket, region string, orgRole string) error {
+	validateS3Patch, err := mpatch.PatchMethod(validateS3Access, func(bucket, region string, orgRole string, kmsKeyID string, acl string, storageClass string) error {
 		if bucket == "error-bucket" {
 			return fmt.Errorf("S3 bucket access validation failed")
 		}
@@ -1282,7 +1714,7 @@ func TestScanIntegration(t *testing.T) {
 	defer safeUnpatch(getSessionWithOrgRolePatch)
 
 	// Patch validateS3Access
-	validateS3AccessPatch, err := mpatch.PatchMethod(validateS3Access, func(bucket, region, orgRole string) error {
+	validateS3AccessPatch, err := mpatch.PatchMethod(validateS3Access, func(bucket, region, orgRole, kmsKeyID, acl, storageClass string) error {
 		if bucket == "error-bucket" {
 			return fmt.Errorf("S3 validation error")
 		}
@@ -1428,3 +1860,1571 @@ func TestScanIntegration(t *testing.T) {
 		})
 	}
 }
+
+// TestScannerProgressSummary tests task and account completion tracking
+func TestScannerProgressSummary(t *testing.T) {
+	progressMap := newScannerProgressMap()
+	progressMap.addTask("111111111111")
+	progressMap.addTask("111111111111")
+	progressMap.addTask("222222222222")
+
+	summary := progressMap.summary()
+	assert.Equal(t, 3, summary.TotalTasks)
+	assert.Equal(t, 0, summary.FinishedTasks)
+	assert.Equal(t, 2, summary.TotalAccounts)
+	assert.Equal(t, 0, summary.CompletedAccounts)
+
+	progressMap.finishTask("222222222222")
+	progressMap.finishTask("111111111111")
+
+	summary = progressMap.summary()
+	assert.Equal(t, 2, summary.FinishedTasks)
+	assert.Equal(t, 1, summary.CompletedAccounts)
+}
+
+// TestEstimateRemaining tests the ETA calculation used by the progress logger
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name           string
+		summary        scanProgressSummary
+		avgExecutionMs int64
+		workers        int
+		expected       time.Duration
+	}{
+		{
+			name:           "no remaining tasks",
+			summary:        scanProgressSummary{TotalTasks: 10, FinishedTasks: 10},
+			avgExecutionMs: 1000,
+			workers:        4,
+			expected:       0,
+		},
+		{
+			name:           "no timing data yet",
+			summary:        scanProgressSummary{TotalTasks: 10, FinishedTasks: 0},
+			avgExecutionMs: 0,
+			workers:        4,
+			expected:       0,
+		},
+		{
+			name:           "remaining tasks spread across workers",
+			summary:        scanProgressSummary{TotalTasks: 10, FinishedTasks: 1},
+			avgExecutionMs: 2000,
+			workers:        4,
+			expected:       6 * time.Second,
+		},
+		{
+			name:           "zero workers treated as one",
+			summary:        scanProgressSummary{TotalTasks: 3, FinishedTasks: 1},
+			avgExecutionMs: 500,
+			workers:        0,
+			expected:       time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, estimateRemaining(tt.summary, tt.avgExecutionMs, tt.workers))
+		})
+	}
+}
+
+// TestFormatProgressSummary tests the overall progress line of the text progress output
+func TestFormatProgressSummary(t *testing.T) {
+	summary := scanProgressSummary{TotalTasks: 10, FinishedTasks: 1, TotalAccounts: 2, CompletedAccounts: 1}
+	assert.Equal(t, "Tasks 1/10 (10%), accounts 1/2 complete, ETA 6s",
+		formatProgressSummary(summary, estimateRemaining(summary, 2000, 4)))
+	assert.Equal(t, "Tasks 1/10 (10%), accounts 1/2 complete, ETA calculating", formatProgressSummary(summary, 0))
+
+	done := scanProgressSummary{TotalTasks: 4, FinishedTasks: 4, TotalAccounts: 1, CompletedAccounts: 1}
+	assert.Equal(t, "Tasks 4/4 (100%), accounts 1/1 complete, ETA 0s", formatProgressSummary(done, 0))
+}
+
+// TestRunScannerWithTimeout tests that slow scanners are cancelled and reported as timed out
+func TestRunScannerWithTimeout(t *testing.T) {
+	fastScanner := &testScanner{
+		argumentName: "fast",
+		label:        "Fast Scanner",
+	}
+	slowScanner := &testScanner{
+		argumentName: "slow",
+		label:        "Slow Scanner",
+		scanFunc: func(opts awsinternal.ScanOptions) (awsinternal.ScanResults, error) {
+			<-opts.Context().Done()
+			return nil, opts.Context().Err()
+		},
+	}
+
+	results, err := runScannerWithTimeout(context.Background(), fastScanner, awsinternal.ScanOptions{}, time.Second)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = runScannerWithTimeout(context.Background(), slowScanner, awsinternal.ScanOptions{}, 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Nil(t, results)
+	assert.Contains(t, err.Error(), "Slow Scanner")
+}
+
+// TestDedupeResults tests that repeated findings collapse into one with an occurrence count
+func TestDedupeResults(t *testing.T) {
+	results := awsinternal.ScanResults{
+		{ResourceID: "arn:aws:iam::123456789012:role/unused", Region: "global"},
+		{ResourceID: "table-a", Region: "us-east-1"},
+		{ResourceID: "arn:aws:iam::123456789012:role/unused", Region: "global"},
+		{ResourceID: "table-a", Region: "us-west-2"},
+		{ResourceID: "arn:aws:iam::123456789012:role/unused", Region: "global"},
+	}
+
+	deduped, removed := dedupeResults(results)
+	require.Len(t, deduped, 3)
+	assert.Equal(t, 2, removed)
+
+	assert.Equal(t, "arn:aws:iam::123456789012:role/unused", deduped[0].ResourceID)
+	assert.Equal(t, 3, deduped[0].Occurrences)
+	assert.Equal(t, 1, deduped[1].Occurrences)
+	assert.Equal(t, "us-west-2", deduped[2].Region)
+	assert.Equal(t, 1, deduped[2].Occurrences)
+
+	deduped, removed = dedupeResults(nil)
+	assert.Empty(t, deduped)
+	assert.Equal(t, 0, removed)
+}
+
+// TestTruncateResults tests that --max-results-per-scanner keeps the most expensive findings
+func TestTruncateResults(t *testing.T) {
+	withCost := func(id string, monthly float64) awsinternal.ScanResult {
+		return awsinternal.ScanResult{
+			ResourceID: id,
+			Cost:       map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: monthly}},
+		}
+	}
+	results := awsinternal.ScanResults{
+		withCost("snap-a", 1),
+		{ResourceID: "sg-b"},
+		withCost("snap-c", 30),
+		withCost("snap-d", 5),
+		withCost("snap-e", 12),
+	}
+
+	truncated, omitted := truncateResults(results, 2)
+	require.Len(t, truncated, 2)
+	assert.Equal(t, 3, omitted)
+	// Kept findings stay in the order the scanner reported them
+	assert.Equal(t, "snap-c", truncated[0].ResourceID)
+	assert.Equal(t, "snap-e", truncated[1].ResourceID)
+
+	truncated, omitted = truncateResults(results, 5)
+	assert.Len(t, truncated, 5)
+	assert.Equal(t, 0, omitted)
+}
+
+// TestCheckCostThreshold tests the --fail-over-cost budget check
+func TestCheckCostThreshold(t *testing.T) {
+	accountResults := map[string]*scanResult{
+		"111111111111": {
+			Results: map[string]awsinternal.ScanResults{
+				"EBS Volumes": {
+					{Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 40}}},
+					{Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 10}}},
+				},
+				"Elastic IPs": {
+					{Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 3.6}}},
+				},
+			},
+		},
+		"222222222222": {
+			Results: map[string]awsinternal.ScanResults{
+				"NAT Gateways": {
+					{Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 32.4}}},
+				},
+				"Security Groups": {
+					{Cost: nil},
+				},
+				"VPCs": {
+					{Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 1}}},
+				},
+			},
+		},
+	}
+
+	costs := monthlyCostByScanner(accountResults)
+	assert.InDelta(t, 50.0, costs["EBS Volumes"], 0.001)
+	assert.InDelta(t, 32.4, costs["NAT Gateways"], 0.001)
+	assert.NotContains(t, costs, "Security Groups")
+
+	assert.NoError(t, checkCostThreshold(costs, 100))
+
+	err := checkCostThreshold(costs, 50)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "$87.00")
+	assert.Contains(t, err.Error(), "EBS Volumes ($50.00), NAT Gateways ($32.40), Elastic IPs ($3.60)")
+	assert.NotContains(t, err.Error(), "VPCs")
+
+	assert.NoError(t, checkCostThreshold(map[string]float64{}, 0.5))
+}
+
+// TestAccountLabels tests how skipped accounts are listed in the --require-all-accounts error and scan summary
+func TestAccountLabels(t *testing.T) {
+	accounts := []awsinternal.Account{
+		{ID: "111111111111", Name: "production"},
+		{ID: "222222222222", Name: "staging"},
+	}
+	assert.Equal(t, []string{"111111111111 (production)", "222222222222 (staging)"}, accountLabels(accounts))
+	assert.Empty(t, accountLabels(nil))
+}
+
+// TestAccountsToWrite tests leaving accounts without findings out of per-account output
+func TestAccountsToWrite(t *testing.T) {
+	accountResults := map[string]*scanResult{
+		"333333333333": {Results: map[string]awsinternal.ScanResults{
+			"EBS Volumes": {{ResourceID: "vol-1"}},
+		}},
+		"111111111111": {Results: map[string]awsinternal.ScanResults{
+			"EBS Volumes": {},
+		}},
+		"222222222222": {
+			Results: map[string]awsinternal.ScanResults{},
+			Errors:  []awsinternal.ScanError{{Scanner: "EBS Volumes", Region: "us-east-1"}},
+		},
+		"444444444444": {Results: map[string]awsinternal.ScanResults{}},
+	}
+
+	assert.Equal(t, []string{"111111111111", "222222222222", "333333333333", "444444444444"}, accountsToWrite(accountResults, false))
+	assert.Equal(t, []string{"222222222222", "333333333333"}, accountsToWrite(accountResults, true))
+	assert.Empty(t, accountsToWrite(map[string]*scanResult{}, true))
+}
+
+// TestReconcileActualSpend tests comparing findings' estimates with Cost Explorer spend per service
+func TestReconcileActualSpend(t *testing.T) {
+	accountResults := map[string]*scanResult{
+		"111111111111": {Results: map[string]awsinternal.ScanResults{
+			"EBS Volumes": {
+				{ResourceID: "vol-1", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 8}}},
+			},
+			"NAT Gateways": {
+				{ResourceID: "nat-1", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 32.85}}},
+			},
+			"EC2 Instances": {
+				{ResourceID: "i-1", Cost: map[string]interface{}{
+					"total":     &awsinternal.CostBreakdown{MonthlyRate: 90, Currency: "EUR"},
+					"total_usd": &awsinternal.CostBreakdown{MonthlyRate: 100},
+				}},
+			},
+			"IAM Users": {{ResourceID: "user"}},
+		}},
+		"222222222222": {Results: map[string]awsinternal.ScanResults{
+			"EBS Volumes": {},
+		}},
+		"333333333333": {Results: map[string]awsinternal.ScanResults{
+			"SQS Queues": {{ResourceID: "queue"}},
+		}},
+	}
+
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	var lookedUp []string
+	reconcileActualSpend(accountResults, func(accountID string, services []string, start, end time.Time) (map[string]float64, error) {
+		lookedUp = append(lookedUp, accountID)
+		assert.Equal(t, time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), end)
+		if accountID == "333333333333" {
+			return nil, errors.New("access denied")
+		}
+		assert.Equal(t, []string{"Amazon Elastic Compute Cloud - Compute", "EC2 - Other"}, services)
+		return map[string]float64{
+			"Amazon Elastic Compute Cloud - Compute": 412.456,
+			"EC2 - Other":                            55.5,
+		}, nil
+	}, now)
+
+	// Accounts without billable findings aren't looked up
+	assert.Equal(t, []string{"111111111111", "333333333333"}, lookedUp)
+	assert.Equal(t, []awsinternal.ActualSpend{
+		{
+			Service:                 "Amazon Elastic Compute Cloud - Compute",
+			ResourceTypes:           []string{"EC2 Instances"},
+			Start:                   "2024-02-14",
+			End:                     "2024-03-15",
+			ActualCostUSD:           412.46,
+			EstimatedMonthlyCostUSD: 100,
+		},
+		{
+			Service:                 "EC2 - Other",
+			ResourceTypes:           []string{"EBS Volumes", "NAT Gateways"},
+			Start:                   "2024-02-14",
+			End:                     "2024-03-15",
+			ActualCostUSD:           55.5,
+			EstimatedMonthlyCostUSD: 40.85,
+		},
+	}, accountResults["111111111111"].ActualSpend)
+	assert.Empty(t, accountResults["222222222222"].ActualSpend)
+	assert.Empty(t, accountResults["333333333333"].ActualSpend)
+}
+
+// TestApplyFindingTags tests tagging flagged resources with the flag tag and their reason
+func TestApplyFindingTags(t *testing.T) {
+	key, value, err := parseFlagTag(defaultFlagTag)
+	require.NoError(t, err)
+	assert.Equal(t, "cloudsift:flagged", key)
+	assert.Equal(t, "true", value)
+	for _, tag := range []string{"flagged", "=true", "aws:flagged=true", "flagged=it's unused", strings.Repeat("k", 125) + "=true"} {
+		_, _, err := parseFlagTag(tag)
+		assert.Error(t, err, tag)
+	}
+
+	accountResults := map[string]*scanResult{
+		"111111111111": {Results: map[string]awsinternal.ScanResults{
+			"EBS Volumes": {
+				{AccountID: "111111111111", ResourceID: "vol-1", ARN: "arn:aws:ec2:us-east-1:111111111111:volume/vol-1",
+					Reason: "Volume is unattached (available), cost: $8.00/month"},
+				{AccountID: "111111111111", ResourceID: "vol-2"},
+			},
+			"EC2 Instances": {
+				{AccountID: "111111111111", ResourceID: "i-1", ARN: "arn:aws:ec2:us-east-1:111111111111:instance/i-1", Reason: "Idle"},
+				{AccountID: "111111111111", ResourceID: "i-1", ARN: "arn:aws:ec2:us-east-1:111111111111:instance/i-1", Reason: "Duplicate"},
+			},
+		}},
+		"222222222222": {Results: map[string]awsinternal.ScanResults{
+			"IAM Roles": {{AccountID: "222222222222", ResourceID: "role", ARN: "arn:aws:iam::222222222222:role/role", Reason: "Unused"}},
+		}},
+	}
+
+	applied := make(map[string]map[string]string)
+	tagger := func(accountID, resourceARN string, tags map[string]string) error {
+		if accountID == "222222222222" {
+			return errors.New("access denied")
+		}
+		applied[resourceARN] = tags
+		return nil
+	}
+
+	tagged, skipped, failed := applyFindingTags(accountResults, "cloudsift:flagged", "true", true, tagger)
+	assert.Equal(t, []int{3, 1, 0}, []int{tagged, skipped, failed})
+	assert.Empty(t, applied, "dry run must not tag anything")
+
+	tagged, skipped, failed = applyFindingTags(accountResults, "cloudsift:flagged", "true", false, tagger)
+	assert.Equal(t, []int{2, 1, 1}, []int{tagged, skipped, failed})
+	assert.Equal(t, map[string]map[string]string{
+		"arn:aws:ec2:us-east-1:111111111111:volume/vol-1": {
+			"cloudsift:flagged":        "true",
+			"cloudsift:flagged:reason": "Volume is unattached available cost: 8.00/month",
+		},
+		"arn:aws:ec2:us-east-1:111111111111:instance/i-1": {
+			"cloudsift:flagged":        "true",
+			"cloudsift:flagged:reason": "Idle",
+		},
+	}, applied)
+}
+
+// TestSortScanErrors tests that scan errors are ordered by account, region and scanner
+func TestSortScanErrors(t *testing.T) {
+	scanErrors := []awsinternal.ScanError{
+		{AccountID: "222222222222", Region: "us-east-1", Scanner: "EBS Volumes"},
+		{AccountID: "111111111111", Region: "us-west-2", Scanner: "AMIs"},
+		{AccountID: "111111111111", Region: "global", Scanner: "IAM Users"},
+		{AccountID: "111111111111", Region: "global", Scanner: "IAM Roles"},
+	}
+
+	sortScanErrors(scanErrors)
+
+	expected := []string{
+		"111111111111/global/IAM Roles",
+		"111111111111/global/IAM Users",
+		"111111111111/us-west-2/AMIs",
+		"222222222222/us-east-1/EBS Volumes",
+	}
+	for i, scanError := range scanErrors {
+		assert.Equal(t, expected[i], scanError.AccountID+"/"+scanError.Region+"/"+scanError.Scanner)
+	}
+}
+
+// TestFlattenResults tests that findings are ordered by account, scanner and resource ID
+func TestFlattenResults(t *testing.T) {
+	accountResults := map[string]*scanResult{
+		"222222222222": {
+			Results: map[string]awsinternal.ScanResults{
+				"EBS Volumes": {
+					{ResourceID: "vol-b", Region: "us-east-1"},
+					{ResourceID: "vol-a", Region: "us-west-2"},
+					{ResourceID: "vol-a", Region: "eu-west-1"},
+				},
+			},
+		},
+		"111111111111": {
+			Results: map[string]awsinternal.ScanResults{
+				"NAT Gateways": {{ResourceID: "nat-1"}},
+				"Elastic IPs":  {{ResourceID: "eipalloc-2"}, {ResourceID: "eipalloc-1"}},
+			},
+		},
+	}
+	for _, accountResult := range accountResults {
+		for _, results := range accountResult.Results {
+			sortResults(results)
+		}
+	}
+
+	assert.Equal(t, []string{"111111111111", "222222222222"}, sortedAccountIDs(accountResults))
+
+	var order []string
+	for _, result := range flattenResults(accountResults) {
+		order = append(order, result.ResourceID+"/"+result.Region)
+	}
+	assert.Equal(t, []string{
+		"eipalloc-1/",
+		"eipalloc-2/",
+		"nat-1/",
+		"vol-a/eu-west-1",
+		"vol-a/us-west-2",
+		"vol-b/us-east-1",
+	}, order)
+}
+
+// TestHTMLReportPath tests how --output-dir and --report-name determine the HTML report path
+func TestHTMLReportPath(t *testing.T) {
+	startTime := time.Date(2024, 3, 5, 14, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		outputDir  string
+		reportName string
+		expected   string
+	}{
+		{
+			name:     "defaults",
+			expected: filepath.Join("reports", "scan_report.html"),
+		},
+		{
+			name:      "output dir adds timestamp",
+			outputDir: "/tmp/scans",
+			expected:  filepath.Join("/tmp/scans", "scan_report-20240305-143015.html"),
+		},
+		{
+			name:       "report name with output dir",
+			outputDir:  "/tmp/scans",
+			reportName: "nightly",
+			expected:   filepath.Join("/tmp/scans", "nightly.html"),
+		},
+		{
+			name:       "report name with extension",
+			reportName: "nightly.html",
+			expected:   filepath.Join("reports", "nightly.html"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, htmlReportPath(tt.outputDir, tt.reportName, startTime))
+		})
+	}
+}
+
+// TestJUnitReport tests the JUnit report path and its contents
+func TestJUnitReport(t *testing.T) {
+	startTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, filepath.Join("reports", "scan_report.xml"), junitReportPath("", "", startTime))
+	assert.Equal(t, filepath.Join("results", "cloudsift.xml"), junitReportPath("results", "cloudsift.html", startTime))
+
+	results := []awsinternal.ScanResult{
+		{
+			ResourceType: "EBS Volumes",
+			ResourceName: "data",
+			ResourceID:   "vol-123",
+			Reason:       "Volume is unattached",
+			Details:      map[string]interface{}{"account_id": "123456789012", "region": "us-west-2"},
+			Region:       "us-west-2",
+			Cost:         map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 12.5}},
+		},
+	}
+	scanErrors := []awsinternal.ScanError{
+		{AccountID: "123456789012", Region: "us-east-1", Scanner: "EC2 Instances", Error: "access denied"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, output.WriteJUnitReport(&buf, results, scanErrors, 1.5))
+	report := buf.String()
+	assert.Contains(t, report, `<testsuites name="cloudsift" tests="2" failures="1" errors="1" time="1.5">`)
+	assert.Contains(t, report, `<testsuite name="EBS Volumes" tests="1" failures="1" errors="0">`)
+	assert.Contains(t, report, `<testcase name="data (vol-123)" classname="123456789012.us-west-2">`)
+	assert.Contains(t, report, `message="Volume is unattached (estimated $12.50/month)"`)
+	assert.Contains(t, report, `<error message="access denied" type="ScanError">`)
+}
+
+// TestHTMLReportBranding tests the --report-title and --report-logo options and the savings leaderboard
+func TestHTMLReportBranding(t *testing.T) {
+	dir := t.TempDir()
+	logoPath := filepath.Join(dir, "logo.png")
+	require.NoError(t, os.WriteFile(logoPath, []byte("\x89PNG\r\n\x1a\n"), 0644))
+
+	logoURL, err := html.LogoURL(logoPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(logoURL), "data:image/png;base64,"))
+	logoURL, err = html.LogoURL("https://example.com/logo.svg")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/logo.svg", string(logoURL))
+	_, err = html.LogoURL(filepath.Join(dir, "missing.png"))
+	assert.Error(t, err)
+	notImage := filepath.Join(dir, "logo.txt")
+	require.NoError(t, os.WriteFile(notImage, []byte("not an image"), 0644))
+	_, err = html.LogoURL(notImage)
+	assert.Error(t, err)
+
+	var results []awsinternal.ScanResult
+	for i := 1; i <= 12; i++ {
+		results = append(results, awsinternal.ScanResult{
+			ResourceType: "EBS Volumes",
+			ResourceID:   fmt.Sprintf("vol-%02d", i),
+			Details:      map[string]interface{}{"region": "us-east-1"},
+			Cost:         map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: float64(i)}},
+		})
+	}
+
+	outputPath := filepath.Join(dir, "report.html")
+	require.NoError(t, html.WriteHTML(results, outputPath, html.ScanMetrics{
+		ReportTitle: "Acme Cloud Cost Review",
+		ReportLogo:  logoPath,
+	}, nil))
+	report, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "<title>Acme Cloud Cost Review</title>")
+	assert.Contains(t, string(report), `class="report-logo" src="data:image/png;base64,`)
+
+	// Only the ten most expensive findings are ranked, most expensive first
+	leaderboard := string(report)[strings.Index(string(report), `id="leaderboard"`):]
+	leaderboard = leaderboard[:strings.Index(leaderboard, "</table>")]
+	assert.Less(t, strings.Index(leaderboard, "vol-12"), strings.Index(leaderboard, "vol-11"))
+	assert.Contains(t, leaderboard, "vol-03")
+	assert.NotContains(t, leaderboard, "vol-02")
+	assert.NotContains(t, leaderboard, "vol-01")
+
+	require.NoError(t, html.WriteHTML(nil, outputPath, html.ScanMetrics{}, nil))
+	report, err = os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "<title>"+html.DefaultReportTitle+"</title>")
+	assert.Contains(t, string(report), "No findings have an estimated cost.")
+}
+
+// TestHTMLReportFilters tests that findings carry the attributes the report's filter controls use
+func TestHTMLReportFilters(t *testing.T) {
+	results := []awsinternal.ScanResult{
+		{
+			ResourceType: "EBS Volumes",
+			ResourceID:   "vol-01",
+			AccountID:    "111111111111",
+			AccountName:  "prod",
+			Region:       "us-west-2",
+			Confidence:   awsinternal.ConfidenceHigh,
+			Tags:         map[string]string{"team": "web"},
+			Cost:         map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 12.5}},
+		},
+		{
+			ResourceType: "IAM Users",
+			ResourceID:   "user-01",
+			AccountID:    "111111111111",
+			AccountName:  "prod",
+			Region:       "us-east-1",
+		},
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	require.NoError(t, html.WriteHTML(results, outputPath, html.ScanMetrics{}, nil))
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	report := string(data)
+
+	for _, id := range []string{"filter-scanner", "filter-account", "filter-region", "filter-confidence", "filter-tag-key", "filter-tag-value", "filter-min-cost"} {
+		assert.Contains(t, report, `id="`+id+`"`)
+	}
+	assert.Contains(t, report, `<option value="team">team</option>`)
+	assert.Contains(t, report, `<option value="us-west-2">us-west-2</option>`)
+	assert.Contains(t, report, `<option value="111111111111">prod (111111111111)</option>`)
+	assert.Contains(t, report, `data-scanner="EBS Volumes" data-account="111111111111" data-region="us-west-2" data-cost="12.5" data-tags="{&#34;team&#34;:&#34;web&#34;}" data-confidence="high"`)
+	// Findings without a cost estimate have no cost to filter on, and those without a confidence count as medium
+	assert.Contains(t, report, `data-scanner="IAM Users" data-account="111111111111" data-region="us-east-1" data-cost="" data-tags="{}" data-confidence="medium"`)
+	assert.Contains(t, report, `<td data-sort-value="3"><span class="confidence-badge confidence-high">high</span></td>`)
+}
+
+// TestLogCapture tests that captured log output keeps only the most recent complete lines
+func TestLogCapture(t *testing.T) {
+	capture := &logCapture{}
+	for i := 1; i <= tuiLogLines+2; i++ {
+		_, err := fmt.Fprintf(capture, "line %d", i)
+		require.NoError(t, err)
+		_, err = capture.Write([]byte("\n"))
+		require.NoError(t, err)
+	}
+	_, err := capture.Write([]byte("partial"))
+	require.NoError(t, err)
+
+	recent := capture.recentLines()
+	require.Len(t, recent, tuiLogLines)
+	assert.Equal(t, "line 3", recent[0])
+	assert.Equal(t, fmt.Sprintf("line %d", tuiLogLines+2), recent[len(recent)-1])
+	assert.True(t, strings.HasSuffix(capture.buf.String(), "partial"))
+}
+
+// TestTruncateVisible tests that lines are cut to the terminal width without breaking color codes
+func TestTruncateVisible(t *testing.T) {
+	assert.Equal(t, "abc", truncateVisible("abcdef", 3))
+	assert.Equal(t, "\x1b[32mab\x1b[0m", truncateVisible("\x1b[32mabcd\x1b[0m", 2))
+	assert.Equal(t, "short", truncateVisible("short", 10))
+}
+
+// TestScannerProgressSavings tests the running savings total shown in progress output
+func TestScannerProgressSavings(t *testing.T) {
+	progressMap := newScannerProgressMap()
+	progressMap.addSavings(12.5)
+	progressMap.addSavings(7.5)
+	assert.InDelta(t, 20.0, progressMap.monthlySavings(), 0.001)
+	assert.Equal(t, "$20.00", progressMap.formatSavings())
+	progressMap.setCurrency("EUR", 0.9)
+	assert.Equal(t, "€18.00", progressMap.formatSavings(), "progress output matches the report's --currency")
+
+	// Scanner tasks add their findings concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			progressMap.addSavings(1)
+		}()
+	}
+	wg.Wait()
+	assert.InDelta(t, 70.0, progressMap.monthlySavings(), 0.001)
+}
+
+// TestHasMatchingTag tests case-insensitive tag matching used by include and ignore tags
+func TestHasMatchingTag(t *testing.T) {
+	rules := parseTagList("team=data,Owner=Analytics,malformed")
+	assert.Equal(t, map[string]string{"team": "data", "Owner": "Analytics"}, rules)
+
+	assert.True(t, hasMatchingTag(map[string]string{"Team": "DATA"}, rules))
+	assert.True(t, hasMatchingTag(map[string]string{"env": "prod", "owner": "analytics"}, rules))
+	assert.False(t, hasMatchingTag(map[string]string{"team": "platform"}, rules))
+	assert.False(t, hasMatchingTag(nil, rules))
+	assert.False(t, hasMatchingTag(map[string]string{"team": "data"}, nil))
+}
+
+// TestIsNewerThan tests filtering of results by resource creation time
+func TestIsNewerThan(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-3 * 24 * time.Hour)
+	old := now.Add(-30 * 24 * time.Hour)
+
+	assert.True(t, isNewerThan(awsinternal.ScanResult{CreatedAt: &recent}, 7, now))
+	assert.False(t, isNewerThan(awsinternal.ScanResult{CreatedAt: &old}, 7, now))
+	assert.False(t, isNewerThan(awsinternal.ScanResult{CreatedAt: &recent}, 0, now))
+	assert.False(t, isNewerThan(awsinternal.ScanResult{}, 7, now))
+}
+
+// TestOrganizationalUnitPattern tests which IDs --organizational-units accepts
+func TestOrganizationalUnitPattern(t *testing.T) {
+	assert.True(t, organizationalUnitPattern.MatchString("ou-ab12-cdef3456"))
+	assert.True(t, organizationalUnitPattern.MatchString("r-ab12"))
+	assert.False(t, organizationalUnitPattern.MatchString("Sandbox"))
+	assert.False(t, organizationalUnitPattern.MatchString("ou-ab12"))
+	assert.False(t, organizationalUnitPattern.MatchString("123456789012"))
+}
+
+// TestValidateWebhookURL tests which URLs --webhook-url accepts
+func TestValidateWebhookURL(t *testing.T) {
+	assert.NoError(t, validateWebhookURL("https://ingest.example.com/cloudsift"))
+	assert.NoError(t, validateWebhookURL("http://localhost:8080/results"))
+	assert.Error(t, validateWebhookURL("ingest.example.com/cloudsift"))
+	assert.Error(t, validateWebhookURL("ftp://ingest.example.com"))
+}
+
+// TestWebhookOutput tests posting results to a webhook, including retries and rejected requests
+func TestWebhookOutput(t *testing.T) {
+	var requests int
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "123456789012", r.Header.Get("X-Cloudsift-Account-Id"))
+
+		// Fail the first attempt so the writer has to retry
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer server.Close()
+
+	writer := output.NewWriter(output.Config{
+		Type:         output.HTTP,
+		WebhookURL:   server.URL,
+		WebhookToken: "secret",
+		Retry:        &output.RetryConfig{MaxRetries: 3, RetryDelay: time.Millisecond},
+	})
+	require.NoError(t, writer.Write("123456789012", "prod", scanResult{AccountID: "123456789012", AccountName: "prod"}))
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, "123456789012", body["account_id"])
+
+	// Client errors are not retried
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "bad payload", http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	requests = 0
+	writer = output.NewWriter(output.Config{
+		Type:       output.HTTP,
+		WebhookURL: rejecting.URL,
+		Retry:      &output.RetryConfig{MaxRetries: 3, RetryDelay: time.Millisecond},
+	})
+	err := writer.Write("123456789012", "prod", scanResult{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad payload")
+	assert.Equal(t, 1, requests)
+}
+
+// TestWriterCompression tests that JSON output is gzipped by default and written as plain JSON when
+// compression is turned off
+func TestWriterCompression(t *testing.T) {
+	for _, uncompressed := range []bool{false, true} {
+		outputDir := t.TempDir()
+		writer := output.NewWriter(output.Config{
+			Type:             output.FileSystem,
+			OutputDir:        outputDir,
+			FilenameTemplate: "{account_id}.json",
+			Uncompressed:     uncompressed,
+		})
+		require.NoError(t, writer.Write("123456789012", "prod", scanResult{AccountID: "123456789012"}))
+
+		name := "123456789012.json.gz"
+		if uncompressed {
+			name = "123456789012.json"
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, name))
+		require.NoError(t, err)
+
+		var reader io.Reader = bytes.NewReader(data)
+		if !uncompressed {
+			gz, err := gzip.NewReader(reader)
+			require.NoError(t, err)
+			reader = gz
+		}
+		var decoded scanResult
+		require.NoError(t, json.NewDecoder(reader).Decode(&decoded))
+		assert.Equal(t, "123456789012", decoded.AccountID)
+	}
+}
+
+// TestFilenameTemplateValidation tests that only known placeholders are accepted, and that
+// per-account output names the account
+func TestFilenameTemplateValidation(t *testing.T) {
+	assert.NoError(t, output.ValidateFilenameTemplate("", true))
+	assert.NoError(t, output.ValidateFilenameTemplate("{date}/{account_id}-{account_name}.{format}", true))
+	assert.NoError(t, output.ValidateFilenameTemplate("{date}/{account_name}.json", true))
+
+	err := output.ValidateFilenameTemplate("{account}-{date}.json", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "{account}")
+
+	assert.Error(t, output.ValidateFilenameTemplate("../{account_id}.json", true))
+
+	// Without the account every account would write the same file
+	err = output.ValidateFilenameTemplate("{date}.json", true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "{account_id} or {account_name}")
+	assert.NoError(t, output.ValidateFilenameTemplate("{date}.json", false))
+}
+
+// TestApplyScanPreset tests that presets fill in unset flags and leave command line flags alone
+func TestApplyScanPreset(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	originalRules := config.Config.ScanIgnoreRules
+	t.Cleanup(func() { config.Config.ScanIgnoreRules = originalRules })
+
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+scans:
+  nightly:
+    scanners: [ebs-volumes, ec2-instances]
+    regions: us-east-1,us-west-2
+    days_unused: 30
+    output_format: json
+    ignore:
+      tags:
+        keep: "true"
+      rules:
+        - scanners: [ebs-volumes]
+          resource_ids: [vol-123]
+  broken:
+    not_a_setting: 1
+`)))
+
+	cmd := NewScanCmd()
+	require.NoError(t, cmd.Flags().Set("days-unused", "7"))
+	require.NoError(t, applyScanPreset(cmd, "nightly"))
+
+	flags := cmd.Flags()
+	assert.Equal(t, "ebs-volumes,ec2-instances", flags.Lookup("scanners").Value.String())
+	assert.Equal(t, "us-east-1,us-west-2", flags.Lookup("regions").Value.String())
+	assert.Equal(t, "json", flags.Lookup("output-format").Value.String())
+	assert.Equal(t, "keep=true", flags.Lookup("ignore-tags").Value.String())
+	assert.Equal(t, "7", flags.Lookup("days-unused").Value.String(), "command line flags override the preset")
+	require.NotEmpty(t, config.Config.ScanIgnoreRules)
+	assert.Equal(t, []string{"vol-123"}, config.Config.ScanIgnoreRules[len(config.Config.ScanIgnoreRules)-1].ResourceIDs)
+
+	err := applyScanPreset(NewScanCmd(), "weekly")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken, nightly")
+
+	err = applyScanPreset(NewScanCmd(), "broken")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not_a_setting")
+}
+
+// TestNewPreflightCmd tests the flags of the preflight command
+func TestNewPreflightCmd(t *testing.T) {
+	cmd := NewPreflightCmd()
+	assert.Equal(t, "preflight", cmd.Use)
+
+	for _, name := range []string{"accounts", "organizational-units", "accounts-file", "profiles", "bucket", "bucket-region", "s3-kms-key-id", "s3-acl", "s3-storage-class", "output-format"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), "missing flag %s", name)
+	}
+	assert.Equal(t, "text", cmd.Flags().Lookup("output-format").DefValue)
+
+	cmd.SetArgs([]string{"--bucket", "my-bucket"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--bucket-region is required")
+}
+
+// TestWritePreflightReport tests the preflight report output
+func TestWritePreflightReport(t *testing.T) {
+	report := &preflightReport{}
+	report.add(preflightCheck{Check: "Organization role", Detail: "OrgRole"}, nil)
+	report.add(preflightCheck{Check: "Scanner role", AccountID: "123456789012", AccountName: "prod"}, fmt.Errorf("access denied"))
+	filtered := filterPreflightAccounts(report, []awsinternal.Account{{ID: "111111111111", Name: "dev"}}, "111111111111, 999999999999")
+
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, 1, report.Passed)
+
+	var text bytes.Buffer
+	require.NoError(t, writePreflightReport(&text, report, "text"))
+	assert.Contains(t, text.String(), "CHECK")
+	assert.Regexp(t, `Scanner role\s+prod \(123456789012\)\s+FAIL\s+access denied`, text.String())
+	assert.Regexp(t, `Find account\s+999999999999\s+FAIL`, text.String())
+	assert.Contains(t, text.String(), "1 passed, 2 failed")
+
+	var out bytes.Buffer
+	require.NoError(t, writePreflightReport(&out, report, "json"))
+	var decoded preflightReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded.Checks, 3)
+	assert.True(t, decoded.Checks[0].Passed)
+	assert.Equal(t, "access denied", decoded.Checks[1].Detail)
+}
+
+// TestPreflightReportSkipped tests that accounts the scan would skip are reported without failing preflight
+func TestPreflightReportSkipped(t *testing.T) {
+	report := &preflightReport{}
+	active, inactive := splitInactiveAccounts([]awsinternal.Account{
+		{ID: "111111111111", Name: "dev", Status: "ACTIVE"},
+		{ID: "222222222222", Name: "old", Status: "SUSPENDED"},
+	})
+	require.Len(t, active, 1)
+	for _, account := range inactive {
+		report.skip(preflightCheck{Check: "Scanner role", AccountID: account.ID, AccountName: account.Name, Detail: fmt.Sprintf("skipped_inactive: account is %s", account.Status)})
+	}
+	report.add(preflightCheck{Check: "Scanner role", AccountID: active[0].ID, AccountName: active[0].Name}, nil)
+
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 0, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+
+	var text bytes.Buffer
+	require.NoError(t, writePreflightReport(&text, report, "text"))
+	assert.Regexp(t, `Scanner role\s+old \(222222222222\)\s+SKIP\s+skipped_inactive: account is SUSPENDED`, text.String())
+	assert.Contains(t, text.String(), "1 passed, 0 failed, 1 skipped")
+}
+
+// TestValidateScanOptions tests that every problem with the scan options is reported
+func TestValidateScanOptions(t *testing.T) {
+	valid := &scanOptions{output: "filesystem", outputFormat: "html", scannerTimeout: time.Minute, s3Layout: "flat", outputConcurrency: 1, sessionDuration: time.Hour}
+	assert.Empty(t, validateScanOptions(valid))
+
+	errs := validateScanOptions(&scanOptions{output: "s3", outputFormat: "pdf", s3Layout: "flat", minAgeDays: -1, maxResultsPerScanner: -1,
+		s3ACL: "public", s3StorageClass: "standard_ia", applyTags: true, flagTag: "aws:flagged=true",
+		accountsFile: "accounts.csv", minConfidence: "certain"})
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		"invalid output format: pdf",
+		"--scanner-timeout must be greater than 0",
+		"--min-age-days must not be negative",
+		"--max-results-per-scanner must not be negative",
+		"--output-concurrency must be at least 1",
+		"--session-duration must be between 15m and 12h",
+		"invalid --s3-acl \"public\": must be one of private, public-read, public-read-write, authenticated-read, aws-exec-read, bucket-owner-read, bucket-owner-full-control",
+		"invalid --s3-storage-class \"standard_ia\": must be one of STANDARD, REDUCED_REDUNDANCY, STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING, GLACIER, DEEP_ARCHIVE, OUTPOSTS, GLACIER_IR",
+		"invalid --flag-tag \"aws:flagged=true\": keys starting with aws: are reserved",
+		"--accounts-file requires --scanner-role",
+		"invalid --min-confidence \"certain\": must be low, medium or high",
+		"--bucket is required when --output=s3",
+		"--bucket-region is required when --output=s3",
+	}, messages)
+}
+
+// TestValidateConfig tests that config validate reports every problem in the config and its presets
+func TestValidateConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	originalConfig := *config.Config
+	t.Cleanup(func() { *config.Config = originalConfig })
+	originalRegistry := awsinternal.DefaultRegistry
+	t.Cleanup(func() { awsinternal.DefaultRegistry = originalRegistry })
+
+	testRegistry := awsinternal.NewScannerRegistry()
+	awsinternal.DefaultRegistry = testRegistry
+	testRegistry.RegisterScanner(&testScanner{argumentName: "ebs-volumes", label: "EBS Volumes"})
+
+	config.Config.MaxWorkers = 8
+	config.Config.LogFormat = "text"
+	config.Config.LogLevel = "info"
+	config.Config.ScannerRole = "Scanner Role"
+
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+scan:
+  scanners: [ebs-volumes, ebs-volume]
+  days_unused: ninety
+  output: s4
+  regoins: us-east-1
+scans:
+  nightly:
+    output_format: json
+    ignore:
+      rules:
+        - scanners: [ebs volumes, nope]
+          accounts: ["123"]
+          resource_ids: [vol-123]
+`)))
+
+	var messages []string
+	for _, err := range validateConfig() {
+		messages = append(messages, err.Error())
+	}
+	assert.Len(t, messages, 7)
+	assert.Contains(t, messages, `unknown setting "scan.regoins"`)
+	assert.Contains(t, strings.Join(messages, "\n"), `aws.scanner_role: invalid role name "Scanner Role"`)
+	assert.Contains(t, strings.Join(messages, "\n"), `scan: invalid argument "ninety" for "--days-unused"`)
+	assert.Contains(t, messages, "scan: invalid output type: s4")
+	assert.Contains(t, messages, "scan: unknown scanners in --scanners: ebs-volume")
+	assert.Contains(t, messages, `scan preset "nightly": ignore.rules rule 1: unknown scanner "nope"`)
+	assert.Contains(t, messages, `scan preset "nightly": ignore.rules rule 1: invalid account ID "123": expected 12 digits`)
+
+	cmd := NewConfigCmd()
+	cmd.SetArgs([]string{"validate"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "7 problem(s)")
+	assert.Contains(t, out.String(), "  - scan: invalid output type: s4")
+}
+
+// TestWriteConfigSchema tests the JSON schema of the config file
+func TestWriteConfigSchema(t *testing.T) {
+	globalFlags := pflag.NewFlagSet("global", pflag.ContinueOnError)
+	globalFlags.Int("max-workers", 8, "Maximum number of concurrent workers")
+
+	var out bytes.Buffer
+	require.NoError(t, writeConfigSchema(&out, globalFlags))
+
+	var schema struct {
+		Properties map[string]struct {
+			Properties           map[string]map[string]interface{} `json:"properties"`
+			AdditionalProperties interface{}                       `json:"additionalProperties"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+
+	assert.Equal(t, "integer", schema.Properties["app"].Properties["max_workers"]["type"])
+	scan := schema.Properties["scan"].Properties
+	assert.Equal(t, "integer", scan["days_unused"]["type"])
+	assert.Equal(t, "boolean", scan["combined_output"]["type"])
+	assert.Equal(t, []interface{}{"json", "html", "junit"}, scan["output_format"]["enum"])
+	assert.Equal(t, []interface{}{"array", "string"}, scan["scanners"]["type"])
+	assert.Contains(t, scan["ignore"]["properties"], "tags")
+	assert.Contains(t, scan["ignore"]["properties"], "rules")
+
+	preset := schema.Properties["scans"].AdditionalProperties.(map[string]interface{})
+	presetIgnore := preset["properties"].(map[string]interface{})["ignore"].(map[string]interface{})
+	assert.Contains(t, presetIgnore["properties"], "rules")
+}
+
+// TestLoadScanIgnoreRules tests that scoped ignore rules in the scan section only apply to their scanners
+func TestLoadScanIgnoreRules(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	rules, err := config.LoadScanIgnoreRules()
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+scan:
+  ignore:
+    rules:
+      - scanners: [ebs-volumes]
+        resource_ids: [vol-123]
+      - scanners: [RDS Instances]
+        tags:
+          team: databases
+`)))
+
+	rules, err = config.LoadScanIgnoreRules()
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	ebsRule, rdsRule := rules[0], rules[1]
+	assert.True(t, ebsRule.AppliesTo("ebs-volumes", "EBS Volumes", "123456789012"))
+	assert.False(t, ebsRule.AppliesTo("rds-instances", "RDS Instances", "123456789012"), "one team's rules don't hide another's findings")
+	assert.True(t, ebsRule.Matches("vol-123", "", nil))
+	assert.True(t, rdsRule.AppliesTo("rds-instances", "RDS Instances", "123456789012"))
+	assert.True(t, rdsRule.Matches("db-1", "db-1", map[string]string{"Team": "Databases"}))
+}
+
+// TestScanCheckpoint tests that completed tasks survive a round trip through a checkpoint file
+func TestScanCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints", "scan.json")
+
+	checkpoint := newScanCheckpoint(path)
+	checkpoint.record(checkpointTask{
+		AccountID: "123456789012",
+		Region:    "us-east-1",
+		Scanner:   "ebs-volumes",
+		Results: awsinternal.ScanResults{{
+			ResourceID: "vol-123",
+			Details:    map[string]interface{}{"region": "us-east-1"},
+			Cost:       map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 8}},
+		}},
+		Truncated: &awsinternal.TruncatedResults{AccountID: "123456789012", Region: "us-east-1", Scanner: "EBS Volumes", Kept: 1, Omitted: 4},
+	})
+	// The first task is written straight away; later ones wait for the interval or a final write
+	checkpoint.record(checkpointTask{AccountID: "123456789012", Region: "us-west-2", Scanner: "ebs-volumes"})
+	require.NoError(t, checkpoint.write())
+
+	loaded, err := loadScanCheckpoint(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Tasks, 2)
+
+	task, ok := loaded.completed("123456789012", "us-east-1", "ebs-volumes")
+	require.True(t, ok)
+	require.Len(t, task.Results, 1)
+	assert.Equal(t, "us-east-1", task.Results[0].Region, "region is filled in from details")
+	total, ok := task.Results[0].Cost["total"].(*awsinternal.CostBreakdown)
+	require.True(t, ok, "costs are restored as cost breakdowns")
+	assert.Equal(t, 8.0, total.MonthlyRate)
+	require.NotNil(t, task.Truncated)
+	assert.Equal(t, 4, task.Truncated.Omitted)
+
+	_, ok = loaded.completed("123456789012", "us-west-2", "ebs-volumes")
+	assert.True(t, ok)
+	_, ok = loaded.completed("123456789012", "eu-west-1", "ebs-volumes")
+	assert.False(t, ok)
+
+	_, err = loadScanCheckpoint(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+// TestCurrencyConversion tests that --currency converts cost estimates and keeps the USD estimates
+func TestCurrencyConversion(t *testing.T) {
+	currency, rate, err := exchangeRate("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", currency)
+	assert.Equal(t, 1.0, rate)
+
+	currency, rate, err = exchangeRate("eur", 0.5)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", currency)
+	assert.Equal(t, 0.5, rate)
+
+	_, _, err = exchangeRate("EUR", 0)
+	assert.Error(t, err, "a currency other than USD needs a rate")
+	_, _, err = exchangeRate("euro", 0.5)
+	assert.Error(t, err)
+
+	errs := validateScanOptions(&scanOptions{output: "filesystem", outputFormat: "json", scannerTimeout: time.Minute, s3Layout: "flat", currency: "GBP", outputConcurrency: 1, sessionDuration: time.Hour})
+	require.Len(t, errs, 1)
+	assert.Equal(t, "--exchange-rate is required when --currency=GBP", errs[0].Error())
+
+	lifetime := 100.0
+	results := awsinternal.ScanResults{
+		{ResourceID: "vol-1", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 10, Lifetime: &lifetime}}},
+		{ResourceID: "sg-1"},
+	}
+	awsinternal.ConvertCosts(results, "EUR", 0.5)
+
+	total := results[0].Cost["total"].(*awsinternal.CostBreakdown)
+	assert.Equal(t, 5.0, total.MonthlyRate)
+	assert.Equal(t, 50.0, *total.Lifetime)
+	assert.Equal(t, "EUR", total.Currency)
+	assert.Equal(t, "€", total.CurrencySymbol())
+	assert.Equal(t, 10.0, awsinternal.USDCost(results[0].Cost).MonthlyRate)
+	assert.Nil(t, results[1].Cost)
+
+	// The budget check stays in US dollars
+	costs := monthlyCostByScanner(map[string]*scanResult{
+		"123456789012": {Results: map[string]awsinternal.ScanResults{"EBS Volumes": results}},
+	})
+	assert.Equal(t, 10.0, costs["EBS Volumes"])
+
+	outputPath := filepath.Join(t.TempDir(), "report.html")
+	require.NoError(t, html.WriteHTML(results, outputPath, html.ScanMetrics{Currency: "EUR"}, nil))
+	report, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), `data-currency-symbol="€"`)
+	assert.Contains(t, string(report), "<strong>€5.00</strong>")
+}
+
+// TestExitCode tests the exit codes of clean, degraded and failed scans
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeClean, ExitCode(nil))
+	assert.Equal(t, ExitCodeError, ExitCode(errors.New("failed to get current account")))
+	assert.Equal(t, ExitCodeCostThreshold, ExitCode(fmt.Errorf("scan: %w", &ExitError{Code: ExitCodeCostThreshold, Err: errors.New("over budget")})))
+
+	assert.NoError(t, partialFailureError(0, 12, 0))
+
+	err := partialFailureError(3, 12, 0)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodePartialFailure, ExitCode(err))
+	assert.Equal(t, "3 of 12 scan tasks failed", err.Error())
+
+	err = partialFailureError(0, 12, 2)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodePartialFailure, ExitCode(err))
+	assert.Equal(t, "2 accounts were skipped", err.Error())
+
+	err = partialFailureError(1, 12, 2)
+	require.Error(t, err)
+	assert.Equal(t, "1 of 12 scan tasks failed and 2 accounts were skipped", err.Error())
+
+	assert.NoError(t, writeFailureError(0, 12))
+	assert.NoError(t, writeFailureError(0, 0))
+
+	err = writeFailureError(3, 12)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodePartialFailure, ExitCode(err))
+	assert.Equal(t, "results could not be written for 3 of 12 accounts", err.Error())
+
+	err = writeFailureError(12, 12)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeError, ExitCode(err))
+	assert.Equal(t, "results could not be written for any of the 12 accounts", err.Error())
+
+	err = writeFailureError(1, 1)
+	require.Error(t, err)
+	assert.Equal(t, ExitCodeError, ExitCode(err))
+	assert.Equal(t, "scan results could not be written", err.Error())
+}
+
+// TestFilterResultsByRegion tests the --report-regions filter, including global findings
+func TestFilterResultsByRegion(t *testing.T) {
+	newResults := func() map[string]*scanResult {
+		return map[string]*scanResult{
+			"111111111111": {Results: map[string]awsinternal.ScanResults{
+				"IAM Roles":   {{ResourceID: "role-a", Region: "global"}},
+				"EBS Volumes": {{ResourceID: "vol-a", Region: "us-east-1"}, {ResourceID: "vol-b", Region: "eu-west-1"}},
+			}},
+		}
+	}
+	resourceIDs := func(accountResults map[string]*scanResult) []string {
+		var ids []string
+		for _, result := range flattenResults(accountResults) {
+			ids = append(ids, result.ResourceID)
+		}
+		return ids
+	}
+
+	include, exclude, err := parseRegionFilter(" US-East-1 , global ")
+	require.NoError(t, err)
+	accountResults := newResults()
+	assert.Equal(t, 1, filterResultsByRegion(accountResults, include, exclude))
+	assert.Equal(t, []string{"vol-a", "role-a"}, resourceIDs(accountResults))
+
+	include, exclude, err = parseRegionFilter("!global")
+	require.NoError(t, err)
+	accountResults = newResults()
+	assert.Equal(t, 1, filterResultsByRegion(accountResults, include, exclude))
+	assert.Equal(t, []string{"vol-a", "vol-b"}, resourceIDs(accountResults))
+
+	include, exclude, err = parseRegionFilter("")
+	require.NoError(t, err)
+	accountResults = newResults()
+	assert.Equal(t, 0, filterResultsByRegion(accountResults, include, exclude))
+
+	_, _, err = parseRegionFilter("us-east-1,,global")
+	assert.Error(t, err)
+	_, _, err = parseRegionFilter("!")
+	assert.Error(t, err)
+}
+
+// TestScannerTimingMap tests per-scanner timings recorded by concurrent tasks
+func TestScannerTimingMap(t *testing.T) {
+	timings := newScannerTimingMap()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			timings.record("EC2 Instances", time.Duration(i+1)*100*time.Millisecond, i == 0)
+		}(i)
+	}
+	wg.Wait()
+	timings.record("SNS Topics", 200*time.Millisecond, false)
+	timings.record("IAM Roles", 200*time.Millisecond, false)
+
+	sorted := timings.sorted()
+	require.Len(t, sorted, 3)
+	assert.Equal(t, scannerTiming{Scanner: "EC2 Instances", Tasks: 10, FailedTasks: 1, TotalMs: 5500, AverageMs: 550, MaxMs: 1000}, sorted[0])
+	assert.Equal(t, "IAM Roles", sorted[1].Scanner, "ties are ordered by label")
+	assert.Equal(t, "SNS Topics", sorted[2].Scanner)
+
+	assert.Equal(t, "EC2 Instances: 10 tasks (1 failed), 5.5s total, 550ms avg, 1s max", formatScannerTimings(sorted)[0])
+}
+
+// TestBaselineAnomalies tests comparing a scan's findings with a combined baseline report
+func TestBaselineAnomalies(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, os.WriteFile(baselinePath, []byte(`{
+  "cloudsift_version": "1.0.0",
+  "accounts": {
+    "111111111111": {
+      "account_id": "111111111111",
+      "results": {
+        "EBS Volumes": [
+          {"resource_id": "vol-a", "cost": {"total": {"monthly_rate": 10}}},
+          {"resource_id": "vol-b", "cost": {"total": {"monthly_rate": 10}}}
+        ],
+        "SNS Topics": [
+          {"resource_id": "topic-a", "cost": {"total": {"monthly_rate": 1}}}
+        ]
+      }
+    }
+  }
+}`), 0600))
+
+	baseline, err := loadBaseline(baselinePath)
+	require.NoError(t, err)
+	assert.Equal(t, scannerTotals{Findings: 2, MonthlyCost: 20}, baseline["111111111111"]["EBS Volumes"])
+
+	volumes := awsinternal.ScanResults{}
+	for i := 0; i < 20; i++ {
+		volumes = append(volumes, awsinternal.ScanResult{
+			ResourceID: fmt.Sprintf("vol-%d", i),
+			Cost:       map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 1.1}},
+		})
+	}
+	accountResults := map[string]*scanResult{
+		"111111111111": {
+			Results: map[string]awsinternal.ScanResults{
+				"EBS Volumes": volumes,
+				"SNS Topics":  {{ResourceID: "topic-a", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 1}}}},
+				"IAM Roles":   {{ResourceID: "role-a"}},
+			},
+		},
+		"222222222222": {
+			Results: map[string]awsinternal.ScanResults{"EBS Volumes": volumes},
+		},
+	}
+
+	notes := flagBaselineAnomalies(accountResults, baseline, []string{"EBS Volumes", "SNS Topics", "IAM Roles"}, 50)
+	assert.Equal(t, []string{
+		"EBS Volumes in 111111111111: findings 2 -> 20 (+900%)",
+		"IAM Roles in 111111111111: findings 0 -> 1 (new)",
+	}, notes, "a 10% cost change and unchanged scanners stay under the threshold")
+	assert.Nil(t, accountResults["222222222222"].Anomalies, "accounts missing from the baseline aren't compared")
+
+	// Scanners that failed in an account aren't compared, since their findings are incomplete
+	accountResults["111111111111"].Errors = []awsinternal.ScanError{{Scanner: "EBS Volumes"}}
+	notes = flagBaselineAnomalies(accountResults, baseline, []string{"EBS Volumes", "SNS Topics", "IAM Roles"}, 50)
+	assert.Equal(t, []string{"IAM Roles in 111111111111: findings 0 -> 1 (new)"}, notes)
+
+	_, err = loadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+// TestFilterResult tests which filter is recorded for findings left out of the report
+func TestFilterResult(t *testing.T) {
+	originalConfig := *config.Config
+	t.Cleanup(func() { *config.Config = originalConfig })
+
+	config.Config.ScanIncludeTags = nil
+	config.Config.ScanMinAgeDays = 7
+	config.Config.ScanMinConfidence = awsinternal.ConfidenceMedium
+	config.Config.ScanIgnoreResourceIDs = []string{"VOL-IGNORED"}
+	config.Config.ScanIgnoreResourceNames = nil
+	config.Config.ScanIgnoreTags = map[string]string{"env": "sandbox"}
+	config.Config.ScanIgnoreRules = []config.IgnoreRule{
+		{Tags: map[string]string{"team": "data"}, Scanners: []string{"ebs-volumes"}, Accounts: []string{"111111111111"}},
+	}
+
+	scanner := &testScanner{argumentName: "ebs-volumes", label: "EBS Volumes"}
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		name       string
+		result     awsinternal.ScanResult
+		accountID  string
+		wantFilter string
+		wantMatch  string
+	}{
+		{
+			name:       "resource ID in ignore list",
+			result:     awsinternal.ScanResult{ResourceID: "vol-ignored"},
+			accountID:  "111111111111",
+			wantFilter: ignoreFilterIgnoreList,
+			wantMatch:  "resource_id: vol-ignored",
+		},
+		{
+			name:       "tag in ignore list",
+			result:     awsinternal.ScanResult{ResourceID: "vol-1", Tags: map[string]string{"Env": "Sandbox"}},
+			accountID:  "111111111111",
+			wantFilter: ignoreFilterIgnoreList,
+			wantMatch:  "tag: Env=Sandbox",
+		},
+		{
+			name:       "newer than min age",
+			result:     awsinternal.ScanResult{ResourceID: "vol-ignored", CreatedAt: &created},
+			accountID:  "111111111111",
+			wantFilter: ignoreFilterMinAge,
+			wantMatch:  "created_at: 2024-03-13T00:00:00Z, min_age_days: 7",
+		},
+		{
+			name:       "below min confidence",
+			result:     awsinternal.ScanResult{ResourceID: "vol-3", Confidence: awsinternal.ConfidenceLow},
+			accountID:  "111111111111",
+			wantFilter: ignoreFilterConfidence,
+			wantMatch:  "confidence: low, min_confidence: medium",
+		},
+		{
+			name:      "meets min confidence",
+			result:    awsinternal.ScanResult{ResourceID: "vol-3", Confidence: awsinternal.ConfidenceHigh},
+			accountID: "111111111111",
+		},
+		{
+			name:       "scoped rule",
+			result:     awsinternal.ScanResult{ResourceID: "vol-2", Tags: map[string]string{"team": "data"}},
+			accountID:  "111111111111",
+			wantFilter: ignoreFilterScopedRule,
+			wantMatch:  "tag: team=data",
+		},
+		{
+			name:      "scoped rule for another account",
+			result:    awsinternal.ScanResult{ResourceID: "vol-2", Tags: map[string]string{"team": "data"}},
+			accountID: "222222222222",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignored, ok := filterResult(tt.result, scanner, tt.accountID, now)
+			assert.Equal(t, tt.wantFilter != "", ok)
+			assert.Equal(t, tt.wantFilter, ignored.Filter)
+			assert.Equal(t, tt.wantMatch, ignored.Match)
+		})
+	}
+
+	ignored, _ := filterResult(awsinternal.ScanResult{ResourceID: "vol-2", Tags: map[string]string{"team": "data"}}, scanner, "111111111111", now)
+	assert.Equal(t, []string{"ebs-volumes"}, ignored.RuleScanners)
+	assert.Equal(t, []string{"111111111111"}, ignored.RuleAccounts)
+
+	// The report lists ignored resources in a stable order
+	path := filepath.Join(t.TempDir(), "audit", "ignored.json")
+	require.NoError(t, writeIgnored(path, []ignoredResource{
+		{AccountID: "222222222222", Scanner: "EBS Volumes", ResourceID: "vol-b", Filter: ignoreFilterIgnoreList},
+		{AccountID: "111111111111", Scanner: "EBS Volumes", ResourceID: "vol-a", Filter: ignoreFilterScopedRule},
+	}, now))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var report ignoredReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Len(t, report.Ignored, 2)
+	assert.Equal(t, "vol-a", report.Ignored[0].ResourceID)
+	assert.Equal(t, now, report.ScannedAt)
+}
+
+// TestResolveAccountRegions tests regions resolved per account, where opt-in regions differ
+func TestResolveAccountRegions(t *testing.T) {
+	optedIn, standard, broken := &session.Session{}, &session.Session{}, &session.Session{}
+	enabled := map[*session.Session][]string{
+		optedIn:  {"us-east-1", "ap-south-2"},
+		standard: {"us-east-1"},
+	}
+	regionsPatch, err := mpatch.PatchMethod(awsinternal.GetAvailableRegions, func(sess *session.Session) ([]string, error) {
+		if regions, ok := enabled[sess]; ok {
+			return regions, nil
+		}
+		return nil, fmt.Errorf("access denied")
+	})
+	require.NoError(t, err)
+	defer safeUnpatch(regionsPatch)
+
+	accounts := []awsinternal.Account{{ID: "111111111111"}, {ID: "222222222222"}}
+	sessions := map[string]*session.Session{"111111111111": optedIn, "222222222222": standard}
+
+	resolved, err := resolveAccountRegions(accounts, sessions, nil)
+	require.NoError(t, err)
+	assert.True(t, resolved["111111111111"]["ap-south-2"])
+	assert.False(t, resolved["222222222222"]["ap-south-2"])
+	assert.Equal(t, []string{"ap-south-2", "us-east-1"}, resolved.union())
+
+	resolved, err = resolveAccountRegions(accounts, sessions, []string{"ap-south-2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"ap-south-2": true}, resolved["111111111111"])
+	assert.Empty(t, resolved["222222222222"])
+
+	_, err = resolveAccountRegions(accounts, sessions, []string{"us-east-1", "eu-nowhere-1"})
+	assert.ErrorContains(t, err, "invalid regions: eu-nowhere-1")
+
+	// An account whose regions can't be looked up is scanned in the regions found for the others
+	sessions["333333333333"] = broken
+	resolved, err = resolveAccountRegions(append(accounts, awsinternal.Account{ID: "333333333333"}), sessions, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"us-east-1": true, "ap-south-2": true}, resolved["333333333333"])
+
+	_, err = resolveAccountRegions([]awsinternal.Account{{ID: "333333333333"}}, sessions, nil)
+	assert.ErrorContains(t, err, "failed to get available regions")
+}
+
+// TestSplitInactiveAccounts tests that suspended and closing accounts are set aside
+func TestSplitInactiveAccounts(t *testing.T) {
+	accounts := []awsinternal.Account{
+		{ID: "111111111111", Name: "prod", Status: "ACTIVE"},
+		{ID: "222222222222", Name: "decommissioned", Status: "SUSPENDED"},
+		{ID: "333333333333", Name: "closing", Status: "PENDING_CLOSURE"},
+		{ID: "444444444444", Name: "from-file"},
+	}
+
+	active, inactive := splitInactiveAccounts(accounts)
+	assert.Equal(t, []string{"111111111111 (prod)", "444444444444 (from-file)"}, accountLabels(active))
+	assert.Equal(t, []string{"222222222222 (decommissioned)", "333333333333 (closing)"}, accountLabels(inactive))
+}
+
+// TestParseProfileRegionMap tests parsing and validation of --profile-region-map
+func TestParseProfileRegionMap(t *testing.T) {
+	mapping, err := parseProfileRegionMap("us-gov-west-1=govcloud, eu-central-1=arn:aws:iam::123456789012:role/EuScanner")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"us-gov-west-1": "govcloud",
+		"eu-central-1":  "arn:aws:iam::123456789012:role/EuScanner",
+	}, mapping)
+
+	_, err = parseProfileRegionMap("us-east-1")
+	assert.ErrorContains(t, err, "expected REGION=PROFILE")
+	_, err = parseProfileRegionMap("us-east-1=")
+	assert.ErrorContains(t, err, "expected REGION=PROFILE")
+	_, err = parseProfileRegionMap("us-east-1=a,us-east-1=b")
+	assert.ErrorContains(t, err, "mapped more than once")
+
+	errs := validateScanOptions(&scanOptions{
+		outputFormat:      "json",
+		output:            "filesystem",
+		scannerTimeout:    time.Minute,
+		outputConcurrency: 1,
+		sessionDuration:   time.Hour,
+		profiles:          "dev,prod",
+		profileRegionMap:  "us-east-1=dev",
+	})
+	assert.Contains(t, fmt.Sprint(errs), "--profile-region-map cannot be combined with --profiles")
+}
+
+// TestWriteAccounts tests that per-account output is written concurrently and failures collected
+func TestWriteAccounts(t *testing.T) {
+	accountIDs := []string{"111111111111", "222222222222", "333333333333", "444444444444"}
+
+	var mu sync.Mutex
+	var running, peak int
+	written := make(map[string]bool)
+	failed := writeAccounts(accountIDs, 2, func(accountID string) error {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		running--
+		written[accountID] = true
+		if accountID == "333333333333" {
+			return fmt.Errorf("upload failed")
+		}
+		return nil
+	})
+
+	assert.Len(t, written, len(accountIDs))
+	assert.LessOrEqual(t, peak, 2)
+	require.Len(t, failed, 1)
+	assert.EqualError(t, failed["333333333333"], "upload failed")
+
+	assert.Empty(t, writeAccounts(nil, 0, func(string) error { return nil }))
+}
+
+// fakeCloudWatch records the metrics published through PutMetricData
+type fakeCloudWatch struct {
+	cloudwatchiface.CloudWatchAPI
+	inputs []*cloudwatch.PutMetricDataInput
+}
+
+func (f *fakeCloudWatch) PutMetricDataWithContext(ctx aws.Context, input *cloudwatch.PutMetricDataInput, opts ...request.Option) (*cloudwatch.PutMetricDataOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+// TestCloudWatchSink tests the metrics published for findings by account, region and scanner
+func TestCloudWatchSink(t *testing.T) {
+	results := []awsinternal.ScanResult{
+		{AccountID: "111111111111", Region: "us-east-1", ResourceType: "EBS Volumes", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 10}}},
+		{AccountID: "111111111111", Region: "us-east-1", ResourceType: "EBS Volumes", Cost: map[string]interface{}{"total": &awsinternal.CostBreakdown{MonthlyRate: 5}}},
+		{AccountID: "111111111111", Region: "global", ResourceType: "IAM Roles"},
+	}
+	scannedAt := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	client := &fakeCloudWatch{}
+	sink := output.NewCloudWatchSink(client, "")
+	require.NoError(t, sink.Publish(context.Background(), results, []string{"111111111111", "222222222222"}, scannedAt))
+
+	values := make(map[string]float64)
+	for _, input := range client.inputs {
+		assert.Equal(t, output.CloudWatchNamespace, aws.StringValue(input.Namespace))
+		assert.LessOrEqual(t, len(input.MetricData), 20)
+		for _, datum := range input.MetricData {
+			var dimensions []string
+			for _, dimension := range datum.Dimensions {
+				dimensions = append(dimensions, aws.StringValue(dimension.Value))
+			}
+			assert.Equal(t, scannedAt, aws.TimeValue(datum.Timestamp))
+			values[aws.StringValue(datum.MetricName)+" "+strings.Join(dimensions, "/")] = aws.Float64Value(datum.Value)
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"UnusedResources 111111111111":                               3,
+		"EstimatedMonthlySavings 111111111111":                       15,
+		"UnusedResources 222222222222":                               0,
+		"EstimatedMonthlySavings 222222222222":                       0,
+		"UnusedResources 111111111111/us-east-1/EBS Volumes":         2,
+		"EstimatedMonthlySavings 111111111111/us-east-1/EBS Volumes": 15,
+		"UnusedResources 111111111111/global/IAM Roles":              1,
+		"EstimatedMonthlySavings 111111111111/global/IAM Roles":      0,
+	}, values)
+}
@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// scannerTiming is the time a scanner's tasks spent running, summed over accounts and regions
+type scannerTiming struct {
+	Scanner     string `json:"scanner"`
+	Tasks       int    `json:"tasks"`
+	FailedTasks int    `json:"failed_tasks"`
+	TotalMs     int64  `json:"total_ms"`
+	AverageMs   int64  `json:"average_ms"`
+	MaxMs       int64  `json:"max_ms"`
+}
+
+// scannerTimingMap collects scanner timings from tasks running concurrently
+type scannerTimingMap struct {
+	sync.Mutex
+	timings map[string]*scannerTiming // key is scanner label
+}
+
+func newScannerTimingMap() *scannerTimingMap {
+	return &scannerTimingMap{timings: make(map[string]*scannerTiming)}
+}
+
+// record adds a finished task's execution time to its scanner's timing
+func (m *scannerTimingMap) record(scanner string, elapsed time.Duration, failed bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	timing, ok := m.timings[scanner]
+	if !ok {
+		timing = &scannerTiming{Scanner: scanner}
+		m.timings[scanner] = timing
+	}
+	ms := elapsed.Milliseconds()
+	timing.Tasks++
+	timing.TotalMs += ms
+	timing.AverageMs = timing.TotalMs / int64(timing.Tasks)
+	if ms > timing.MaxMs {
+		timing.MaxMs = ms
+	}
+	if failed {
+		timing.FailedTasks++
+	}
+}
+
+// sorted returns the scanner timings, slowest scanner first
+func (m *scannerTimingMap) sorted() []scannerTiming {
+	m.Lock()
+	defer m.Unlock()
+
+	timings := make([]scannerTiming, 0, len(m.timings))
+	for _, timing := range m.timings {
+		timings = append(timings, *timing)
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].TotalMs != timings[j].TotalMs {
+			return timings[i].TotalMs > timings[j].TotalMs
+		}
+		return timings[i].Scanner < timings[j].Scanner
+	})
+	return timings
+}
+
+// formatScannerTimings renders scanner timings as one line per scanner for the metrics log
+func formatScannerTimings(timings []scannerTiming) []string {
+	lines := make([]string, len(timings))
+	for i, timing := range timings {
+		lines[i] = fmt.Sprintf("%s: %d tasks (%d failed), %s total, %s avg, %s max", timing.Scanner, timing.Tasks, timing.FailedTasks,
+			time.Duration(timing.TotalMs)*time.Millisecond, time.Duration(timing.AverageMs)*time.Millisecond, time.Duration(timing.MaxMs)*time.Millisecond)
+	}
+	return lines
+}
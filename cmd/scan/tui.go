@@ -0,0 +1,264 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"cloudsift/internal/logging"
+	"cloudsift/internal/worker"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+const (
+	tuiRefreshInterval = 500 * time.Millisecond
+	tuiLogLines        = 5 // Recent log lines shown below the scanner table
+
+	// ANSI sequences used to draw the view in the terminal's alternate screen
+	ansiAltScreenOn  = "\x1b[?1049h"
+	ansiAltScreenOff = "\x1b[?1049l"
+	ansiHideCursor   = "\x1b[?25l"
+	ansiShowCursor   = "\x1b[?25h"
+	ansiHome         = "\x1b[H"
+	ansiClearLine    = "\x1b[K"
+	ansiClearBelow   = "\x1b[J"
+)
+
+// logCapture holds log output while the TUI owns the terminal so it can be shown in the view
+// and replayed once the scan finishes
+type logCapture struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	partial string
+	recent  []string
+}
+
+// Write implements io.Writer
+func (c *logCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.buf.Write(p)
+
+	// Log lines may arrive in pieces, so only complete lines are added to the recent list
+	text := c.partial + string(p)
+	lines := strings.Split(text, "\n")
+	c.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		c.recent = append(c.recent, line)
+	}
+	if len(c.recent) > tuiLogLines {
+		c.recent = c.recent[len(c.recent)-tuiLogLines:]
+	}
+
+	return len(p), nil
+}
+
+// recentLines returns the most recent complete log lines
+func (c *logCapture) recentLines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.recent...)
+}
+
+// scanTUI renders a live view of running scanners, task completion and estimated savings
+type scanTUI struct {
+	out         *os.File
+	progressMap *scannerProgressMap
+	workerPool  *worker.Pool
+	maxWorkers  int
+	startTime   time.Time
+	logs        *logCapture
+	previousLog io.Writer
+	done        chan struct{}
+	stopped     chan struct{}
+	stopOnce    sync.Once
+	signals     chan os.Signal
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// newScanTUI creates a TUI that draws to out
+func newScanTUI(out *os.File, progressMap *scannerProgressMap, workerPool *worker.Pool, maxWorkers int) *scanTUI {
+	return &scanTUI{
+		out:         out,
+		progressMap: progressMap,
+		workerPool:  workerPool,
+		maxWorkers:  maxWorkers,
+		startTime:   time.Now(),
+		logs:        &logCapture{},
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+		signals:     make(chan os.Signal, 1),
+	}
+}
+
+// Start takes over the terminal and begins redrawing the view
+func (t *scanTUI) Start() {
+	t.previousLog = logging.SetOutput(t.logs)
+	fmt.Fprint(t.out, ansiAltScreenOn+ansiHideCursor)
+
+	// Give the terminal back if the scan is interrupted
+	signal.Notify(t.signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer close(t.stopped)
+		ticker := time.NewTicker(tuiRefreshInterval)
+		defer ticker.Stop()
+
+		t.render()
+		for {
+			select {
+			case <-t.done:
+				return
+			case sig := <-t.signals:
+				t.restore()
+				fmt.Fprintf(t.out, "Scan interrupted (%s)\n", sig)
+				os.Exit(130)
+			case <-ticker.C:
+				t.render()
+			}
+		}
+	}()
+}
+
+// Stop restores the terminal and replays the log output captured while the view was shown
+func (t *scanTUI) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+		<-t.stopped
+		t.restore()
+	})
+}
+
+// restore leaves the alternate screen and sends logging back to its original writer
+func (t *scanTUI) restore() {
+	signal.Stop(t.signals)
+	fmt.Fprint(t.out, ansiShowCursor+ansiAltScreenOff)
+
+	logging.SetOutput(t.previousLog)
+	t.logs.mu.Lock()
+	_, _ = t.previousLog.Write(t.logs.buf.Bytes())
+	t.logs.buf.Reset()
+	t.logs.mu.Unlock()
+}
+
+// render redraws the whole view
+func (t *scanTUI) render() {
+	width, height, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 120, 40
+	}
+
+	summary := t.progressMap.summary()
+	metrics := t.workerPool.GetMetrics()
+	running := t.progressMap.getRunning()
+	sort.Slice(running, func(i, j int) bool {
+		if running[i].AccountID != running[j].AccountID {
+			return running[i].AccountID < running[j].AccountID
+		}
+		if running[i].Region != running[j].Region {
+			return running[i].Region < running[j].Region
+		}
+		return running[i].Scanner < running[j].Scanner
+	})
+
+	var lines []string
+	elapsed := time.Since(t.startTime).Round(time.Second)
+	lines = append(lines, color.New(color.Bold).Sprintf("CloudSift scan - %s elapsed", elapsed))
+
+	percent := 0.0
+	if summary.TotalTasks > 0 {
+		percent = float64(summary.FinishedTasks) / float64(summary.TotalTasks) * 100
+	}
+	eta := estimateRemaining(summary, metrics.AverageExecutionMs, t.maxWorkers)
+	lines = append(lines, fmt.Sprintf("Tasks:    %d/%d (%.0f%%), %d failed   Accounts: %d/%d complete   ETA: %s",
+		summary.FinishedTasks, summary.TotalTasks, percent, metrics.FailedTasks, summary.CompletedAccounts, summary.TotalAccounts, formatETA(summary, eta)))
+	lines = append(lines, fmt.Sprintf("Workers:  %d active of %d (limit %d)   Throttled requests: %d",
+		metrics.CurrentWorkers, t.maxWorkers, metrics.ConcurrencyLimit, metrics.ThrottleEvents))
+	lines = append(lines, fmt.Sprintf("Savings:  %s/month found so far",
+		color.GreenString(t.progressMap.formatSavings())))
+	lines = append(lines, "")
+
+	header := fmt.Sprintf("%-24s %-32s %-16s %8s", "SCANNER", "ACCOUNT", "REGION", "RESULTS")
+	lines = append(lines, color.New(color.Bold).Sprint(header))
+
+	// Leave room for the header above, the log panel below, and a spare line so the screen never scrolls
+	tableRows := height - len(lines) - tuiLogLines - 4
+	if tableRows < 1 {
+		tableRows = 1
+	}
+	for i, prog := range running {
+		if i == tableRows {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(running)-tableRows))
+			break
+		}
+		account := fmt.Sprintf("%s (%s)", prog.AccountName, prog.AccountID)
+		lines = append(lines, fmt.Sprintf("%-24s %-32s %-16s %8d",
+			truncate(prog.Scanner, 24), truncate(account, 32), truncate(prog.Region, 16), prog.ResultCount))
+	}
+	if len(running) == 0 {
+		lines = append(lines, "Waiting for scanners to start...")
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, color.New(color.Bold).Sprint("Recent log output"))
+	lines = append(lines, t.logs.recentLines()...)
+
+	var b strings.Builder
+	b.WriteString(ansiHome)
+	for _, line := range lines {
+		b.WriteString(truncateVisible(line, width))
+		b.WriteString(ansiClearLine + "\n")
+	}
+	b.WriteString(ansiClearBelow)
+	fmt.Fprint(t.out, b.String())
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 3 {
+		return s[:n]
+	}
+	return s[:n-3] + "..."
+}
+
+// truncateVisible shortens a line that may contain ANSI color codes to n visible characters,
+// keeping the escape codes so colors are still reset
+func truncateVisible(s string, n int) string {
+	var b strings.Builder
+	visible := 0
+	inEscape := false
+	for _, r := range s {
+		if r == '\x1b' {
+			inEscape = true
+		}
+		if inEscape {
+			b.WriteRune(r)
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				inEscape = false
+			}
+			continue
+		}
+		if visible < n {
+			b.WriteRune(r)
+			visible++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"cloudsift/cmd"
+	"cloudsift/pkg/scanner"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// StoppedInstanceScanner is an example out-of-tree scanner that reports EC2 instances tagged
+// Environment=sandbox that have been stopped for longer than the scan's --days-unused
+type StoppedInstanceScanner struct{}
+
+// ArgumentName implements Scanner interface
+func (s *StoppedInstanceScanner) ArgumentName() string {
+	return "stopped-sandbox-instances"
+}
+
+// Label implements Scanner interface
+func (s *StoppedInstanceScanner) Label() string {
+	return "Stopped Sandbox Instances"
+}
+
+// IsGlobal implements Scanner interface
+func (s *StoppedInstanceScanner) IsGlobal() bool {
+	return false
+}
+
+// Scan implements Scanner interface
+func (s *StoppedInstanceScanner) Scan(opts scanner.ScanOptions) (scanner.ScanResults, error) {
+	client := ec2.New(opts.Session)
+	cutoff := time.Now().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	var results scanner.ScanResults
+	err := client.DescribeInstancesPagesWithContext(opts.Context(), &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("instance-state-name"), Values: []*string{aws.String(ec2.InstanceStateNameStopped)}},
+			{Name: aws.String("tag:Environment"), Values: []*string{aws.String("sandbox")}},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if aws.TimeValue(instance.LaunchTime).After(cutoff) {
+					continue
+				}
+				tags := make(map[string]string)
+				for _, tag := range instance.Tags {
+					tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				results = append(results, scanner.ScanResult{
+					ResourceType: s.Label(),
+					ResourceName: tags["Name"],
+					ResourceID:   aws.StringValue(instance.InstanceId),
+					CreatedAt:    instance.LaunchTime,
+					Reason:       fmt.Sprintf("Sandbox instance has been stopped for over %d days", opts.DaysUnused),
+					Details: map[string]interface{}{
+						"account_id":    opts.AccountID,
+						"region":        opts.Region,
+						"instance_type": aws.StringValue(instance.InstanceType),
+					},
+					Tags: tags,
+				})
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instances: %w", err)
+	}
+	return results, nil
+}
+
+func main() {
+	if err := scanner.Register("stopped-sandbox-instances", func() scanner.Scanner {
+		return &StoppedInstanceScanner{}
+	}); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -185,7 +185,7 @@ func main() {
 	}
 
 	// Write the HTML report
-	if err := html.WriteHTML(results, outputPath, metrics); err != nil {
+	if err := html.WriteHTML(results, outputPath, metrics, nil); err != nil {
 		log.Fatalf("Error generating HTML report: %v", err)
 	}
 
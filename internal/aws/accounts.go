@@ -1,9 +1,18 @@
 package aws
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -13,13 +22,30 @@ import (
 
 const (
 	// Organizations API requires a specific region
-	organizationsRegion = "us-west-2"
+	defaultOrganizationsRegion = "us-west-2"
 )
 
+// OrganizationsRegion returns the region used for Organizations and account listing calls. Outside the
+// commercial partition this is the partition's global region, e.g. us-gov-west-1 for GovCloud.
+func OrganizationsRegion() string {
+	if partition := ProfilePartition(); partition != endpoints.AwsPartitionID {
+		return GlobalRegion(partition)
+	}
+	return defaultOrganizationsRegion
+}
+
 // Account represents an AWS account
 type Account struct {
-	ID   string
-	Name string
+	ID      string
+	Name    string
+	Profile string // AWS profile the account was reached through, when scanning with --profiles
+	Status  string // Organizations status such as ACTIVE or SUSPENDED; empty when not listed from Organizations
+}
+
+// IsActive reports whether an account can be scanned. Accounts not listed from Organizations have
+// no status and are assumed active.
+func (a Account) IsActive() bool {
+	return a.Status == "" || a.Status == organizations.AccountStatusActive
 }
 
 // ListAccounts attempts to list all accounts in the organization, falling back to current account if not in an org
@@ -45,7 +71,7 @@ func ListAccounts(organizationRole string) ([]Account, error) {
 	}
 
 	logging.Info("No organization role provided, falling back to current account")
-	sess, err := GetSessionChain("", "", "", organizationsRegion)
+	sess, err := GetSessionChain("", "", "", OrganizationsRegion())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
@@ -54,7 +80,7 @@ func ListAccounts(organizationRole string) ([]Account, error) {
 
 // tryListOrganizationAccounts attempts to list all accounts in the organization
 func tryListOrganizationAccounts(organizationRole string) ([]Account, error) {
-	sess, err := GetSessionChain(organizationRole, "", "", organizationsRegion)
+	sess, err := GetSessionChain(organizationRole, "", "", OrganizationsRegion())
 	if err != nil {
 		return nil, err
 	}
@@ -70,8 +96,9 @@ func ListAccountsWithSession(sess *session.Session) ([]Account, error) {
 	err := svc.ListAccountsPages(input, func(page *organizations.ListAccountsOutput, lastPage bool) bool {
 		for _, account := range page.Accounts {
 			accounts = append(accounts, Account{
-				ID:   aws.StringValue(account.Id),
-				Name: aws.StringValue(account.Name),
+				ID:     aws.StringValue(account.Id),
+				Name:   aws.StringValue(account.Name),
+				Status: aws.StringValue(account.Status),
 			})
 		}
 		return !lastPage
@@ -87,6 +114,56 @@ func ListAccountsWithSession(sess *session.Session) ([]Account, error) {
 	return accounts, nil
 }
 
+// ListOrganizationalUnitAccountIDs returns the IDs of the accounts in the given organizational units,
+// including accounts in every OU nested below them. Organization roots are accepted as well.
+func ListOrganizationalUnitAccountIDs(sess *session.Session, ouIDs []string) (map[string]bool, error) {
+	svc := organizations.New(sess)
+	accountIDs := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	// Walk the OU tree breadth first from the requested OUs
+	queue := append([]string(nil), ouIDs...)
+	for len(queue) > 0 {
+		parentID := queue[0]
+		queue = queue[1:]
+		if visited[parentID] {
+			continue
+		}
+		visited[parentID] = true
+
+		err := svc.ListAccountsForParentPages(&organizations.ListAccountsForParentInput{
+			ParentId: aws.String(parentID),
+		}, func(page *organizations.ListAccountsForParentOutput, lastPage bool) bool {
+			for _, account := range page.Accounts {
+				accountIDs[aws.StringValue(account.Id)] = true
+			}
+			return !lastPage
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts in %s: %w", parentID, err)
+		}
+
+		err = svc.ListOrganizationalUnitsForParentPages(&organizations.ListOrganizationalUnitsForParentInput{
+			ParentId: aws.String(parentID),
+		}, func(page *organizations.ListOrganizationalUnitsForParentOutput, lastPage bool) bool {
+			for _, ou := range page.OrganizationalUnits {
+				queue = append(queue, aws.StringValue(ou.Id))
+			}
+			return !lastPage
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organizational units in %s: %w", parentID, err)
+		}
+	}
+
+	logging.Debug("Listed organizational unit accounts", map[string]interface{}{
+		"organizational_units": ouIDs,
+		"ou_count":             len(visited),
+		"account_count":        len(accountIDs),
+	})
+	return accountIDs, nil
+}
+
 // getCurrentAccountID gets the current account ID using STS
 func getCurrentAccountID(sess *session.Session) (string, error) {
 	stsSvc := sts.New(sess)
@@ -139,3 +216,127 @@ func ListCurrentAccount(sess *session.Session) ([]Account, error) {
 		},
 	}, nil
 }
+
+// ListProfileAccounts resolves the account behind each AWS profile and returns the accounts along
+// with the session to scan each one with, keyed by account ID. Profiles that fail to authenticate
+// or that resolve to an account already seen are skipped with a warning.
+func ListProfileAccounts(profiles []string) ([]Account, map[string]*session.Session) {
+	var accounts []Account
+	sessions := make(map[string]*session.Session)
+
+	for _, profile := range profiles {
+		profile = strings.TrimSpace(profile)
+		if profile == "" {
+			continue
+		}
+
+		sess, err := NewSession(profile, "")
+		if err != nil {
+			logging.Warn("Failed to create session for profile, skipping", map[string]interface{}{
+				"profile": profile,
+				"error":   err.Error(),
+			})
+			continue
+		}
+
+		profileAccounts, err := ListCurrentAccount(sess)
+		if err != nil {
+			logging.Warn("Failed to get account for profile, skipping", map[string]interface{}{
+				"profile": profile,
+				"error":   err.Error(),
+			})
+			continue
+		}
+
+		account := profileAccounts[0]
+		if _, exists := sessions[account.ID]; exists {
+			logging.Warn("Profile resolves to an account that is already being scanned, skipping", map[string]interface{}{
+				"profile":    profile,
+				"account_id": account.ID,
+			})
+			continue
+		}
+
+		// Standalone accounts usually can't read their name from Organizations, so fall back to the profile
+		if account.Name == account.ID {
+			account.Name = profile
+		}
+		account.Profile = profile
+
+		accounts = append(accounts, account)
+		sessions[account.ID] = sess
+	}
+
+	return accounts, sessions
+}
+
+// accountIDPattern matches a 12-digit AWS account ID
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// accountsFileEntry is an account in a JSON accounts file
+type accountsFileEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// LoadAccountsFile reads the accounts to scan from a file instead of listing them through
+// Organizations. Files ending in .json hold an array of {"id": ..., "name": ...} objects; anything
+// else is read as CSV with an account ID and an optional name per row, and an optional header row.
+// Accounts without a name are named after their ID.
+func LoadAccountsFile(path string) ([]Account, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts file: %w", err)
+	}
+
+	var entries []accountsFileEntry
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse accounts file %s: %w", path, err)
+		}
+	} else {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse accounts file %s: %w", path, err)
+		}
+		for i, record := range records {
+			id := strings.TrimSpace(record[0])
+			// Skip a header row such as "id,name"; account IDs never contain letters
+			if i == 0 && strings.ContainsFunc(id, unicode.IsLetter) {
+				continue
+			}
+			entry := accountsFileEntry{ID: id}
+			if len(record) > 1 {
+				entry.Name = record[1]
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	var accounts []Account
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		id := strings.TrimSpace(entry.ID)
+		if !accountIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid account ID %q in accounts file %s (entry %d)", entry.ID, path, i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate account ID %s in accounts file %s", id, path)
+		}
+		seen[id] = true
+
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			name = id
+		}
+		accounts = append(accounts, Account{ID: id, Name: name})
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("accounts file %s lists no accounts", path)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// globalRegions maps each partition to the region its global services (IAM, Route53, region
+// discovery) are called in
+var globalRegions = map[string]string{
+	endpoints.AwsPartitionID:      "us-east-1",
+	endpoints.AwsCnPartitionID:    "cn-north-1",
+	endpoints.AwsUsGovPartitionID: "us-gov-west-1",
+}
+
+// PartitionForRegion returns the partition a region belongs to, such as "aws-us-gov" for
+// us-gov-west-1. Empty or unknown regions are treated as the commercial "aws" partition.
+func PartitionForRegion(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// PartitionFromARN returns the partition segment of an ARN, or "aws" if it can't be parsed
+func PartitionFromARN(value string) string {
+	parsed, err := arn.Parse(value)
+	if err != nil || parsed.Partition == "" {
+		return endpoints.AwsPartitionID
+	}
+	return parsed.Partition
+}
+
+// SessionPartition returns the partition of a session's configured region, falling back to the
+// active profile's partition for sessions created without a region
+func SessionPartition(sess *session.Session) string {
+	if region := aws.StringValue(sess.Config.Region); region != "" {
+		return PartitionForRegion(region)
+	}
+	return ProfilePartition()
+}
+
+// GlobalRegion returns the region global services are called in for a partition
+func GlobalRegion(partition string) string {
+	if region, ok := globalRegions[partition]; ok {
+		return region
+	}
+	return globalRegions[endpoints.AwsPartitionID]
+}
+
+// RoleARN builds the ARN of an IAM role in the given partition
+func RoleARN(partition, accountID, roleName string) string {
+	return arn.ARN{
+		Partition: partition,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  "role/" + roleName,
+	}.String()
+}
+
+// ResourceARN builds the ARN for a regional resource. The partition is taken from the region.
+func ResourceARN(service, region, accountID, resource string) string {
+	return arn.ARN{
+		Partition: PartitionForRegion(region),
+		Service:   service,
+		Region:    region,
+		AccountID: accountID,
+		Resource:  resource,
+	}.String()
+}
+
+// GlobalResourceARN builds the ARN for a global resource, which has no region segment. The
+// partition is taken from the region the resource was scanned from.
+func GlobalResourceARN(service, scanRegion, accountID, resource string) string {
+	return arn.ARN{
+		Partition: PartitionForRegion(scanRegion),
+		Service:   service,
+		AccountID: accountID,
+		Resource:  resource,
+	}.String()
+}
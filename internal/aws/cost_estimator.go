@@ -16,6 +16,8 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/lightsail"
 	"github.com/aws/aws-sdk-go/service/pricing"
 )
 
@@ -27,21 +29,32 @@ type CostBreakdown struct {
 	YearlyRate   float64  `json:"yearly_rate"`
 	HoursRunning *float64 `json:"hours_running,omitempty"`
 	Lifetime     *float64 `json:"lifetime,omitempty"`
+	Currency     string   `json:"currency,omitempty"` // Currency of the rates when converted out of US dollars
 }
 
 // ResourceCostConfig holds configuration for resource cost calculation
 type ResourceCostConfig struct {
 	ResourceType  string
-	ResourceSize  interface{} // Can be int64 for storage sizes or string for instance types
+	ResourceSize  interface{} // Can be int64 for storage sizes, float64 GB for ECR images and S3 objects or string for instance types
 	Region        string
 	CreationTime  time.Time
 	VolumeType    string  // Volume type for EBS (e.g., "gp2", "gp3", "io1")
 	LBType        string  // Load balancer type (e.g., "application", "network")
 	ProcessedGB   float64 // Processed GB for load balancers
-	InstanceCount int64   // Instance count for OpenSearch
-	StorageSize   int64   // Storage size for OpenSearch
+	InstanceCount int64   // Instance count for OpenSearch, DocumentDB and Neptune, unused instances for capacity reservations, nodes for Redshift, tasks for Fargate, attachment count for Transit Gateways, shard count for Kinesis, vCPUs for Batch, brokers for MSK, HSMs for CloudHSM, broker instances for Amazon MQ, provisioned instances for App Runner
+	StorageSize   int64   // Storage size in GB for OpenSearch, AWS Backup and MSK (across all brokers)
 	MultiAZ       bool    // Multi-AZ for RDS
 	Engine        string  // Database engine for RDS
+	// ProvisionedThroughput is the provisioned throughput in MiB/s for EFS
+	ProvisionedThroughput float64
+	// CapacityUnits is the number of Aurora capacity units (ACUs) for Aurora Serverless
+	CapacityUnits float64
+	// VCPUs and MemoryGB are the vCPUs and memory of each Fargate task or App Runner instance;
+	// InstanceCount is the task or instance count
+	VCPUs    float64
+	MemoryGB float64
+	// GBSecondsPerHour is the Lambda compute used per hour in GB-seconds; ResourceSize is the architecture
+	GBSecondsPerHour float64
 }
 
 // AWS region to location name mapping for pricing API
@@ -159,14 +172,82 @@ var regionToLocation = map[string]string{
 	"au-southeast-2": "New Zealand (Auckland)",
 }
 
+// apiGatewayCacheRates are the us-east-1 hourly prices of API Gateway cache sizes, in GB, used when
+// the Pricing API is unavailable
+var apiGatewayCacheRates = map[string]float64{
+	"0.5":  0.02,
+	"1.6":  0.038,
+	"6.1":  0.20,
+	"13.5": 0.25,
+	"28.4": 0.50,
+	"58.2": 1.00,
+	"118":  1.90,
+	"237":  3.80,
+}
+
+// auroraServerlessACURates are the us-east-1 prices per ACU-hour of Aurora Serverless v1 and v2, used
+// when the Pricing API is unavailable
+var auroraServerlessACURates = map[string]float64{
+	"v1": 0.06,
+	"v2": 0.12,
+}
+
+// awsBackupStorageRates are the us-east-1 prices per GB-month of AWS Backup warm storage by protected
+// resource type, used when the Pricing API is unavailable
+var awsBackupStorageRates = map[string]float64{
+	"EBS":        0.05,
+	"EC2":        0.05,
+	"RDS":        0.095,
+	"Aurora":     0.021,
+	"DocumentDB": 0.021,
+	"Neptune":    0.021,
+	"DynamoDB":   0.10,
+	"EFS":        0.05,
+	"FSx":        0.05,
+	"S3":         0.05,
+}
+
+// awsBackupColdStorageRate is the us-east-1 price per GB-month of AWS Backup cold storage
+const awsBackupColdStorageRate = 0.01
+
+// openSearchVolumeTypes maps EBS volume types to the volumeType the Pricing API lists OpenSearch
+// storage under
+var openSearchVolumeTypes = map[string]string{
+	"gp2":      "General Purpose",
+	"gp3":      "GP3",
+	"io1":      "Provisioned IOPS",
+	"standard": "Magnetic",
+}
+
+// mskBrokerRates are the us-east-1 hourly prices of MSK broker instance types, used when the Pricing
+// API is unavailable
+var mskBrokerRates = map[string]float64{
+	"kafka.t3.small":    0.0456,
+	"kafka.m5.large":    0.21,
+	"kafka.m5.xlarge":   0.42,
+	"kafka.m5.2xlarge":  0.84,
+	"kafka.m5.4xlarge":  1.68,
+	"kafka.m5.8xlarge":  3.36,
+	"kafka.m5.12xlarge": 5.04,
+	"kafka.m5.16xlarge": 6.72,
+	"kafka.m5.24xlarge": 10.08,
+	"kafka.m7g.large":   0.204,
+	"kafka.m7g.xlarge":  0.408,
+	"kafka.m7g.2xlarge": 0.816,
+	"kafka.m7g.4xlarge": 1.632,
+	"kafka.m7g.8xlarge": 3.264,
+}
+
 // CostEstimator handles AWS resource cost calculations with caching
 type CostEstimator struct {
-	pricingClient *pricing.Pricing
-	cacheFile     string
-	priceCache    map[string]float64
-	cacheLock     sync.RWMutex
-	saveLock      sync.Mutex
-	rateLimiter   *RateLimiter
+	pricingClient      *pricing.Pricing
+	costExplorerClient *costexplorer.CostExplorer // Looks up actual spend to compare with estimates
+	session            *session.Session           // Looks up Lightsail bundle prices, which are listed by Lightsail rather than the Pricing API
+	cacheFile          string
+	priceCache         map[string]float64
+	cacheLock          sync.RWMutex
+	saveLock           sync.Mutex
+	rateLimiter        *RateLimiter
 }
 
 // DefaultCostEstimator is the default cost estimator instance
@@ -200,10 +281,12 @@ func NewCostEstimator(sess *session.Session, cacheFile string) (*CostEstimator,
 	// Create pricing client with explicit config to ensure region is set to us-east-1 (required for pricing API)
 	cfg := aws.NewConfig().WithRegion("us-east-1")
 	ce := &CostEstimator{
-		pricingClient: pricing.New(sess, cfg),
-		cacheFile:     cacheFile,
-		priceCache:    make(map[string]float64),
-		rateLimiter:   NewRateLimiter(&config.DefaultRateLimitConfig), // Use default rate limit config
+		pricingClient:      pricing.New(sess, cfg),
+		costExplorerClient: costexplorer.New(sess, cfg), // Cost Explorer is also only served from us-east-1
+		session:            sess,
+		cacheFile:          cacheFile,
+		priceCache:         make(map[string]float64),
+		rateLimiter:        NewRateLimiter(&config.DefaultRateLimitConfig), // Use default rate limit config
 	}
 
 	if err := ce.loadCache(); err != nil {
@@ -289,7 +372,7 @@ func (ce *CostEstimator) saveCache() error {
 
 func (ce *CostEstimator) getAWSPrice(resourceType, region string, config ResourceCostConfig) (float64, error) {
 	var resourceSizeStr string
-	if resourceType == "EBSVolumes" || resourceType == "EBSSnapshots" {
+	if resourceType == "EBSVolumes" || resourceType == "EBSSnapshots" || resourceType == "EBSProvisionedIOPS" {
 		resourceSizeStr = config.VolumeType
 	} else {
 		resourceSizeStr = fmt.Sprintf("%v", config.ResourceSize)
@@ -385,6 +468,74 @@ func (ce *CostEstimator) getAWSPrice(resourceType, region string, config Resourc
 				Value: aws.String(location),
 			},
 		}
+	case "DocumentDB", "Neptune":
+		// DocumentDB and Neptune clusters are billed per instance-hour like RDS
+		instanceClass, ok := config.ResourceSize.(string)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for %s: %T", resourceType, config.ResourceSize)
+		}
+		serviceCode := "AmazonDocDB"
+		if resourceType == "Neptune" {
+			serviceCode = "AmazonNeptune"
+		}
+		filters = []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String(serviceCode),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Database Instance"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(instanceClass),
+			},
+		}
+
+		// Get instance price per hour
+		return ce.getCachedPrice(cacheKey, filters)
+	case "EBSProvisionedIOPS":
+		// Provisioned IOPS are billed per IOPS-month on top of the volume's storage
+		_, ok := config.ResourceSize.(int64)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for %s: %T", resourceType, config.ResourceSize)
+		}
+		filters = []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonEC2"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("System Operation"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("group"),
+				Value: aws.String("EBS IOPS"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("volumeApiName"),
+				Value: aws.String(config.VolumeType),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+		}
 	case "EBSSnapshots":
 		_, ok := config.ResourceSize.(int64)
 		if !ok {
@@ -588,8 +739,7 @@ func (ce *CostEstimator) getAWSPrice(resourceType, region string, config Resourc
 
 		return totalCost, nil
 	case "OpenSearch":
-		// For OpenSearch we need to calculate both instance and storage costs
-		// First get instance price
+		// Domains are billed per instance-hour plus per GB-month of EBS storage across all data nodes
 		instanceType, ok := config.ResourceSize.(string)
 		if !ok {
 			return 0, fmt.Errorf("invalid resource size type for OpenSearch: %T", config.ResourceSize)
@@ -611,59 +761,48 @@ func (ce *CostEstimator) getAWSPrice(resourceType, region string, config Resourc
 				Field: aws.String("instanceType"),
 				Value: aws.String(instanceType),
 			},
-			{
-				Type:  aws.String("TERM_MATCH"),
-				Field: aws.String("usagetype"),
-				Value: aws.String(fmt.Sprintf("%s-BoxUsage:%s", strings.ToUpper(strings.Split(region, "-")[0]), instanceType)),
-			},
 		}
 
 		// Get instance price per hour
-		instancePrice, err := ce.getPriceFromAPI(instanceFilters)
+		instancePrice, err := ce.getCachedPrice(fmt.Sprintf("OpenSearch:instance:%s:%s", instanceType, region), instanceFilters)
 		if err != nil {
 			return 0, fmt.Errorf("failed to get OpenSearch instance price: %w", err)
 		}
 
-		// Get storage price
-		storageFilters := []*pricing.Filter{
-			{
-				Type:  aws.String("TERM_MATCH"),
-				Field: aws.String("servicecode"),
-				Value: aws.String("AmazonES"),
-			},
-			{
-				Type:  aws.String("TERM_MATCH"),
-				Field: aws.String("location"),
-				Value: aws.String(location),
-			},
-			{
-				Type:  aws.String("TERM_MATCH"),
-				Field: aws.String("volumeType"),
-				Value: aws.String(config.VolumeType),
-			},
-			{
-				Type:  aws.String("TERM_MATCH"),
-				Field: aws.String("productFamily"),
-				Value: aws.String("Storage"),
-			},
-		}
+		// Domains without EBS storage, such as those on instance storage, only pay for instances
+		var storagePrice float64
+		if config.StorageSize > 0 {
+			volumeType, ok := openSearchVolumeTypes[config.VolumeType]
+			if !ok {
+				return 0, fmt.Errorf("unknown OpenSearch volume type: %s", config.VolumeType)
+			}
+			storageFilters := []*pricing.Filter{
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("servicecode"),
+					Value: aws.String("AmazonES"),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("location"),
+					Value: aws.String(location),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("volumeType"),
+					Value: aws.String(volumeType),
+				},
+			}
 
-		// Get storage price per GB per month
-		storagePrice, err := ce.getPriceFromAPI(storageFilters)
-		if err != nil {
-			return 0, fmt.Errorf("failed to get OpenSearch storage price: %w", err)
+			// Get storage price per GB per month
+			storagePrice, err = ce.getCachedPrice(fmt.Sprintf("OpenSearch:storage:%s:%s", config.VolumeType, region), storageFilters)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get OpenSearch storage price: %w", err)
+			}
 		}
 
-		// Calculate total cost
-		// Storage price is per GB per month, convert to per hour
-		storagePricePerHour := (storagePrice * float64(config.StorageSize)) / (24 * 30) // Approximate month to 30 days
-		totalCost := (instancePrice * float64(config.InstanceCount)) + storagePricePerHour
-
-		ce.cacheLock.Lock()
-		ce.priceCache[cacheKey] = totalCost
-		ce.cacheLock.Unlock()
-
-		return totalCost, nil
+		// Convert monthly storage cost to hourly (730 hours in a month)
+		return instancePrice*float64(config.InstanceCount) + storagePrice*float64(config.StorageSize)/730, nil
 	case "RDS":
 		// Extract instance class from resource size string
 		instanceClass, ok := config.ResourceSize.(string)
@@ -851,72 +990,898 @@ func (ce *CostEstimator) getAWSPrice(resourceType, region string, config Resourc
 		}
 
 		return hourlyRate, nil
-	default:
-		cacheKey = fmt.Sprintf("%s:%s", resourceType, region)
-	}
-
-	input := &pricing.GetProductsInput{
-		ServiceCode: aws.String("AmazonEC2"),
-		Filters:     filters,
-	}
-
-	logging.Debug("Fetching pricing from AWS", map[string]interface{}{
-		"resource_type": resourceType,
-		"region":        region,
-		"filters":       filters,
-	})
-
-	result, err := ce.pricingClient.GetProducts(input)
-	if err != nil {
-		logging.Error("Failed to get pricing from AWS", err, map[string]interface{}{
-			"resource_type": resourceType,
-			"region":        region,
-			"filters":       filters,
-		})
-		return 0, fmt.Errorf("failed to get pricing: %w", err)
-	}
+	case "TransitGateway":
+		// Transit Gateways are billed per attachment-hour, independent of traffic
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonVPC"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("usagetype"),
+				Value: aws.String("TransitGateway-Hours"),
+			},
+		}
 
-	if len(result.PriceList) == 0 {
-		logging.Error("No pricing information found", fmt.Errorf("no pricing data"), map[string]interface{}{
-			"resource_type": resourceType,
-			"region":        region,
-			"filters":       filters,
-		})
-		return 0, fmt.Errorf("no pricing information found")
-	}
+		// Get Transit Gateway attachment hourly price
+		hourlyRate, err := ce.getPriceFromAPI(filters)
+		if err != nil {
+			logging.Error("Failed to get Transit Gateway attachment price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			// Default hourly rate per attachment if pricing API fails
+			hourlyRate = 0.05 // $0.05 per attachment-hour
+		}
 
-	logging.Debug("Got pricing results from AWS", map[string]interface{}{
-		"resource_type":    resourceType,
-		"region":           region,
-		"price_list_count": len(result.PriceList),
-	})
+		return hourlyRate, nil
+	case "VPCEndpoint":
+		// Interface endpoints are billed per endpoint-hour in each Availability Zone they're deployed in
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonVPC"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("usagetype"),
+				Value: aws.String("VpcEndpoint-Hours"),
+			},
+		}
 
-	// Parse the price from the response
-	var priceFloat float64
-	for _, priceData := range result.PriceList {
-		// Convert to JSON for easier parsing
-		jsonBytes, err := json.Marshal(priceData)
+		// Get VPC endpoint hourly price
+		hourlyRate, err := ce.getPriceFromAPI(filters)
 		if err != nil {
-			continue
+			logging.Error("Failed to get VPC endpoint price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			// Default hourly rate per Availability Zone if pricing API fails
+			hourlyRate = 0.01 // $0.01 per endpoint-hour
 		}
 
-		var data map[string]interface{}
-		if err := json.Unmarshal(jsonBytes, &data); err != nil {
-			continue
+		return hourlyRate, nil
+	case "VPNConnection":
+		// Site-to-Site VPN connections are billed per connection-hour, whether or not the tunnels are up
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonVPC"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("usagetype"),
+				Value: aws.String("VPN-Usage-Hours:ipsec.1"),
+			},
 		}
 
-		terms, ok := data["terms"].(map[string]interface{})
-		if !ok {
-			continue
+		// Get VPN connection hourly price
+		hourlyRate, err := ce.getPriceFromAPI(filters)
+		if err != nil {
+			logging.Error("Failed to get VPN connection price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			// Default hourly rate if pricing API fails
+			hourlyRate = 0.05 // $0.05 per connection-hour
 		}
 
-		onDemand, ok := terms["OnDemand"].(map[string]interface{})
-		if !ok {
-			continue
+		return hourlyRate, nil
+	case "CloudHSM":
+		// CloudHSM is billed per HSM-hour for every HSM in a cluster, whether or not clients use it
+		hsmType, _ := config.ResourceSize.(string)
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("CloudHSM"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(hsmType),
+			},
 		}
 
-		// Get the first price dimension
-		for _, term := range onDemand {
+		// Get HSM hourly price
+		hourlyRate, err := ce.getPriceFromAPI(filters)
+		if err != nil {
+			logging.Error("Failed to get CloudHSM price, using default", err, map[string]interface{}{
+				"region":   region,
+				"hsm_type": hsmType,
+				"filters":  filters,
+			})
+			// Default hourly rate if pricing API fails
+			hourlyRate = 1.45 // $1.45 per HSM-hour for hsm1.medium
+		}
+
+		return hourlyRate, nil
+	case "AmazonMQ":
+		// Amazon MQ brokers are billed per broker instance-hour; deployments with a standby or cluster
+		// nodes run more than one instance
+		instanceType, _ := config.ResourceSize.(string)
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonMQ"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(instanceType),
+			},
+		}
+
+		// Get broker instance hourly price
+		hourlyRate, err := ce.getPriceFromAPI(filters)
+		if err != nil {
+			logging.Error("Failed to get Amazon MQ broker price, using default", err, map[string]interface{}{
+				"region":        region,
+				"instance_type": instanceType,
+				"filters":       filters,
+			})
+			// Default hourly rate if pricing API fails
+			hourlyRate = 0.288 // $0.288 per instance-hour for mq.m5.large
+		}
+
+		return hourlyRate, nil
+	case "Kinesis":
+		// Provisioned streams are billed per shard-hour; on-demand streams per stream-hour plus data volume
+		capacityMode, _ := config.ResourceSize.(string)
+		group, defaultRate := "Provisioned shard hour", 0.015
+		if capacityMode == "ON_DEMAND" {
+			group, defaultRate = "On-demand Stream Hour", 0.04
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonKinesis"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("group"),
+				Value: aws.String(group),
+			},
+		}
+
+		// Get shard-hour or stream-hour price
+		hourlyRate, err := ce.getCachedPrice(fmt.Sprintf("Kinesis:%s:%s", capacityMode, region), filters)
+		if err != nil {
+			logging.Error("Failed to get Kinesis price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			hourlyRate = defaultRate
+		}
+
+		return hourlyRate, nil
+	case "APIGatewayCache":
+		// API Gateway caches are billed per hour by provisioned cache size, independent of traffic
+		cacheSize, _ := config.ResourceSize.(string)
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonApiGateway"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Amazon API Gateway Cache"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("cacheMemorySizeGb"),
+				Value: aws.String(cacheSize),
+			},
+		}
+
+		// Get cache price per hour
+		hourlyRate, err := ce.getCachedPrice(fmt.Sprintf("APIGatewayCache:%s:%s", cacheSize, region), filters)
+		if err != nil {
+			logging.Error("Failed to get API Gateway cache price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			defaultRate, ok := apiGatewayCacheRates[cacheSize]
+			if !ok {
+				return 0, fmt.Errorf("unknown API Gateway cache size: %s", cacheSize)
+			}
+			hourlyRate = defaultRate
+		}
+
+		return hourlyRate, nil
+	case "BatchvCPU":
+		// Batch compute environments choose instance types themselves, so vCPUs are priced at the
+		// per-vCPU rate of a general purpose m5.large
+		instancePrice, err := ce.getAWSPrice("EC2", region, ResourceCostConfig{ResourceSize: "m5.large", Region: region})
+		if err != nil {
+			logging.Error("Failed to get Batch vCPU price, using default", err, map[string]interface{}{
+				"region": region,
+			})
+			return 0.048, nil // m5.large in us-east-1, per vCPU
+		}
+		return instancePrice / 2, nil
+	case "AuroraServerless":
+		// Aurora Serverless is billed per ACU-hour; v1 and v2 are separate product families
+		version, _ := config.ResourceSize.(string)
+		productFamily := "Serverless"
+		if version == "v2" {
+			productFamily = "ServerlessV2"
+		}
+		databaseEngine := "Aurora MySQL"
+		if strings.Contains(config.Engine, "postgresql") {
+			databaseEngine = "Aurora PostgreSQL"
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonRDS"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String(productFamily),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("databaseEngine"),
+				Value: aws.String(databaseEngine),
+			},
+		}
+
+		// Get price per ACU-hour
+		acuRate, err := ce.getCachedPrice(fmt.Sprintf("AuroraServerless:%s:%s:%s", version, databaseEngine, region), filters)
+		if err != nil {
+			logging.Error("Failed to get Aurora Serverless price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			defaultRate, ok := auroraServerlessACURates[version]
+			if !ok {
+				return 0, fmt.Errorf("unknown Aurora Serverless version: %s", version)
+			}
+			acuRate = defaultRate
+		}
+
+		return acuRate, nil
+	case "AWSBackup":
+		// Recovery points are billed per GB-month, at a rate that depends on the protected resource
+		// type and on whether the lifecycle has moved the recovery point to cold storage
+		backupResourceType, _ := config.ResourceSize.(string)
+		storageClass := "Warm"
+		if config.VolumeType == "cold" {
+			storageClass = "Cold"
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AWSBackup"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("AWS Backup Storage"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("resourceType"),
+				Value: aws.String(backupResourceType),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("storageClass"),
+				Value: aws.String(storageClass),
+			},
+		}
+
+		// Get storage price per GB per month
+		storagePrice, err := ce.getCachedPrice(fmt.Sprintf("AWSBackup:%s:%s:%s", backupResourceType, storageClass, region), filters)
+		if err != nil {
+			logging.Error("Failed to get AWS Backup storage price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			defaultRate, ok := awsBackupStorageRates[backupResourceType]
+			if !ok {
+				defaultRate = 0.05 // $0.05 per GB-month
+			}
+			if storageClass == "Cold" {
+				defaultRate = awsBackupColdStorageRate
+			}
+			storagePrice = defaultRate
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return storagePrice * float64(config.StorageSize) / 730, nil
+	case "MSK":
+		// Provisioned MSK clusters are billed per broker-hour plus per GB-month of broker storage
+		instanceType, _ := config.ResourceSize.(string)
+		brokerFilters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonMSK"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(instanceType),
+			},
+		}
+
+		// Get broker price per hour
+		brokerRate, err := ce.getCachedPrice(fmt.Sprintf("MSK:broker:%s:%s", instanceType, region), brokerFilters)
+		if err != nil {
+			logging.Error("Failed to get MSK broker price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": brokerFilters,
+			})
+			defaultRate, ok := mskBrokerRates[instanceType]
+			if !ok {
+				return 0, fmt.Errorf("unknown MSK broker instance type: %s", instanceType)
+			}
+			brokerRate = defaultRate
+		}
+
+		storageFilters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonMSK"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Storage"),
+			},
+		}
+
+		// Get storage price per GB per month
+		storagePrice, err := ce.getCachedPrice(fmt.Sprintf("MSK:storage:%s", region), storageFilters)
+		if err != nil {
+			logging.Error("Failed to get MSK storage price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": storageFilters,
+			})
+			storagePrice = 0.10 // $0.10 per GB-month
+		}
+
+		// Convert monthly storage cost to hourly (730 hours in a month)
+		return brokerRate*float64(config.InstanceCount) + storagePrice*float64(config.StorageSize)/730, nil
+	case "ECR":
+		// Images are billed per GB-month of storage
+		sizeGB, ok := config.ResourceSize.(float64)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for ECR cost calculation: %T", config.ResourceSize)
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonECR"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("EC2 Container Registry"),
+			},
+		}
+
+		// Get storage price per GB per month
+		storagePrice, err := ce.getCachedPrice(fmt.Sprintf("ECR:%s", region), filters)
+		if err != nil {
+			logging.Error("Failed to get ECR storage price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			storagePrice = 0.10 // $0.10 per GB-month
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return storagePrice * sizeGB / 730, nil
+	case "S3Storage":
+		// Objects in the S3 Standard storage class are billed per GB-month
+		sizeGB, ok := config.ResourceSize.(float64)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for S3 storage cost calculation: %T", config.ResourceSize)
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonS3"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("storageClass"),
+				Value: aws.String("General Purpose"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("volumeType"),
+				Value: aws.String("Standard"),
+			},
+		}
+
+		// Get storage price per GB per month
+		storagePrice, err := ce.getCachedPrice(fmt.Sprintf("S3Storage:%s", region), filters)
+		if err != nil {
+			logging.Error("Failed to get S3 storage price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			storagePrice = 0.023 // $0.023 per GB-month
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return storagePrice * sizeGB / 730, nil
+	case "Fargate":
+		// Fargate tasks are billed per vCPU-hour and per GB-hour of memory
+		resourceFilters := func(field, value string) []*pricing.Filter {
+			return []*pricing.Filter{
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("servicecode"),
+					Value: aws.String("AmazonECS"),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("location"),
+					Value: aws.String(location),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("productFamily"),
+					Value: aws.String("Compute"),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String(field),
+					Value: aws.String(value),
+				},
+			}
+		}
+
+		vcpuFilters := resourceFilters("cputype", "perCPU")
+		vcpuRate, err := ce.getCachedPrice(fmt.Sprintf("Fargate:vCPU:%s", region), vcpuFilters)
+		if err != nil {
+			logging.Error("Failed to get Fargate vCPU price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": vcpuFilters,
+			})
+			vcpuRate = 0.04048 // $0.04048 per vCPU-hour
+		}
+
+		memoryFilters := resourceFilters("memorytype", "perGB")
+		memoryRate, err := ce.getCachedPrice(fmt.Sprintf("Fargate:memory:%s", region), memoryFilters)
+		if err != nil {
+			logging.Error("Failed to get Fargate memory price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": memoryFilters,
+			})
+			memoryRate = 0.004445 // $0.004445 per GB-hour
+		}
+
+		// Price per task-hour
+		return vcpuRate*config.VCPUs + memoryRate*config.MemoryGB, nil
+	case "AppRunner":
+		// App Runner bills provisioned instances for their memory, and for vCPU only while they
+		// are processing requests. The Pricing API doesn't break these out by region, so the
+		// published US East rates are used.
+		vcpuRate := 0.064   // $0.064 per vCPU-hour
+		memoryRate := 0.007 // $0.007 per GB-hour
+
+		// Price per instance-hour
+		return vcpuRate*config.VCPUs + memoryRate*config.MemoryGB, nil
+	case "EC2CapacityReservation":
+		// Unused reserved capacity is billed at the On-Demand rate of the instance type it holds
+		instanceType, ok := config.ResourceSize.(string)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for %s: %T", resourceType, config.ResourceSize)
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonEC2"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Compute Instance"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(instanceType),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("operatingSystem"),
+				Value: aws.String("Linux"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("tenancy"),
+				Value: aws.String("Shared"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("preInstalledSw"),
+				Value: aws.String("NA"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("capacityStatus"),
+				Value: aws.String("UnusedCapacityReservation"),
+			},
+		}
+
+		// Get price per unused instance-hour
+		return ce.getCachedPrice(cacheKey, filters)
+	case "RedshiftNode":
+		// Provisioned Redshift clusters are billed per node-hour of their node type
+		nodeType, ok := config.ResourceSize.(string)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for %s: %T", resourceType, config.ResourceSize)
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonRedshift"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Compute Instance"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("instanceType"),
+				Value: aws.String(nodeType),
+			},
+		}
+
+		// Get On-Demand price per node-hour
+		return ce.getCachedPrice(cacheKey, filters)
+	case "SecretsManager":
+		// Secrets are billed a flat monthly fee per secret, with each replica billed as its own secret
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AWSSecretsManager"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Secret"),
+			},
+		}
+
+		// Get secret price per month
+		monthlyRate, err := ce.getCachedPrice(fmt.Sprintf("SecretsManager:%s", region), filters)
+		if err != nil {
+			logging.Error("Failed to get Secrets Manager price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			monthlyRate = 0.40 // $0.40 per secret per month
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return monthlyRate / 730, nil
+	case "Route53HostedZone":
+		// Hosted zones are billed a flat monthly fee per zone, priced globally
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonRoute53"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("DNS Zone"),
+			},
+		}
+
+		// Get hosted zone price per month
+		monthlyRate, err := ce.getCachedPrice("Route53HostedZone", filters)
+		if err != nil {
+			logging.Error("Failed to get Route53 hosted zone price, using default", err, map[string]interface{}{
+				"filters": filters,
+			})
+			monthlyRate = 0.50 // $0.50 per hosted zone per month
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return monthlyRate / 730, nil
+	case "LambdaGBSecond":
+		// Lambda compute is billed per GB-second, at a lower rate on Arm
+		group := "AWS-Lambda-Duration"
+		defaultRate := 0.0000166667 // $0.0000166667 per GB-second on x86
+		if config.ResourceSize == "arm64" {
+			group = "AWS-Lambda-Duration-ARM"
+			defaultRate = 0.0000133334 // $0.0000133334 per GB-second on Arm
+		}
+		filters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AWSLambda"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("group"),
+				Value: aws.String(group),
+			},
+		}
+
+		// Get price per GB-second
+		price, err := ce.getCachedPrice(fmt.Sprintf("LambdaGBSecond:%s:%s", region, group), filters)
+		if err != nil {
+			logging.Error("Failed to get Lambda duration price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": filters,
+			})
+			price = defaultRate
+		}
+		return price, nil
+	case "Lightsail":
+		// Lightsail instances are billed a flat monthly price for their bundle
+		bundleID, ok := config.ResourceSize.(string)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for Lightsail cost calculation: %T", config.ResourceSize)
+		}
+		monthlyRate, err := ce.getLightsailBundlePrice(region, bundleID)
+		if err != nil {
+			return 0, err
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return monthlyRate / 730, nil
+	case "LightsailDisk":
+		// Lightsail block storage is billed a flat rate per GB-month
+		sizeGB, ok := config.ResourceSize.(int64)
+		if !ok {
+			return 0, fmt.Errorf("invalid resource size type for Lightsail disk cost calculation: %T", config.ResourceSize)
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return 0.10 * float64(sizeGB) / 730, nil // $0.10 per GB-month
+	case "EFS":
+		// EFS is billed per GB-month stored plus per MiB/s-month of provisioned throughput
+		storageFilters := []*pricing.Filter{
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("servicecode"),
+				Value: aws.String("AmazonEFS"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("location"),
+				Value: aws.String(location),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("productFamily"),
+				Value: aws.String("Storage"),
+			},
+			{
+				Type:  aws.String("TERM_MATCH"),
+				Field: aws.String("storageClass"),
+				Value: aws.String("General Purpose"),
+			},
+		}
+
+		// Get storage price per GB per month
+		storagePrice, err := ce.getCachedPrice(fmt.Sprintf("EFS:storage:%s", region), storageFilters)
+		if err != nil {
+			logging.Error("Failed to get EFS storage price, using default", err, map[string]interface{}{
+				"region":  region,
+				"filters": storageFilters,
+			})
+			storagePrice = 0.30 // $0.30 per GB-month
+		}
+
+		monthlyCost := storagePrice * float64(config.StorageSize)
+
+		if config.ProvisionedThroughput > 0 {
+			throughputFilters := []*pricing.Filter{
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("servicecode"),
+					Value: aws.String("AmazonEFS"),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("location"),
+					Value: aws.String(location),
+				},
+				{
+					Type:  aws.String("TERM_MATCH"),
+					Field: aws.String("productFamily"),
+					Value: aws.String("Provisioned Throughput"),
+				},
+			}
+
+			// Get provisioned throughput price per MiB/s per month
+			throughputPrice, err := ce.getCachedPrice(fmt.Sprintf("EFS:throughput:%s", region), throughputFilters)
+			if err != nil {
+				logging.Error("Failed to get EFS provisioned throughput price, using default", err, map[string]interface{}{
+					"region":  region,
+					"filters": throughputFilters,
+				})
+				throughputPrice = 6.00 // $6.00 per MiB/s-month
+			}
+
+			monthlyCost += throughputPrice * config.ProvisionedThroughput
+		}
+
+		// Convert monthly cost to hourly (730 hours in a month)
+		return monthlyCost / 730, nil
+	default:
+		cacheKey = fmt.Sprintf("%s:%s", resourceType, region)
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters:     filters,
+	}
+
+	logging.Debug("Fetching pricing from AWS", map[string]interface{}{
+		"resource_type": resourceType,
+		"region":        region,
+		"filters":       filters,
+	})
+
+	result, err := ce.pricingClient.GetProducts(input)
+	if err != nil {
+		logging.Error("Failed to get pricing from AWS", err, map[string]interface{}{
+			"resource_type": resourceType,
+			"region":        region,
+			"filters":       filters,
+		})
+		return 0, fmt.Errorf("failed to get pricing: %w", err)
+	}
+
+	if len(result.PriceList) == 0 {
+		logging.Error("No pricing information found", fmt.Errorf("no pricing data"), map[string]interface{}{
+			"resource_type": resourceType,
+			"region":        region,
+			"filters":       filters,
+		})
+		return 0, fmt.Errorf("no pricing information found")
+	}
+
+	logging.Debug("Got pricing results from AWS", map[string]interface{}{
+		"resource_type":    resourceType,
+		"region":           region,
+		"price_list_count": len(result.PriceList),
+	})
+
+	// Parse the price from the response
+	var priceFloat float64
+	for _, priceData := range result.PriceList {
+		// Convert to JSON for easier parsing
+		jsonBytes, err := json.Marshal(priceData)
+		if err != nil {
+			continue
+		}
+
+		var data map[string]interface{}
+		if err := json.Unmarshal(jsonBytes, &data); err != nil {
+			continue
+		}
+
+		terms, ok := data["terms"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		onDemand, ok := terms["OnDemand"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Get the first price dimension
+		for _, term := range onDemand {
 			termData, ok := term.(map[string]interface{})
 			if !ok {
 				continue
@@ -1072,6 +2037,71 @@ func (ce *CostEstimator) getPriceFromAPI(filters []*pricing.Filter) (float64, er
 	return 0, fmt.Errorf("could not find valid price in response")
 }
 
+// getCachedPrice returns the price for the given filters, consulting the price cache first
+func (ce *CostEstimator) getCachedPrice(cacheKey string, filters []*pricing.Filter) (float64, error) {
+	ce.cacheLock.RLock()
+	if price, ok := ce.priceCache[cacheKey]; ok {
+		ce.cacheLock.RUnlock()
+		return price, nil
+	}
+	ce.cacheLock.RUnlock()
+
+	price, err := ce.getPriceFromAPI(filters)
+	if err != nil {
+		return 0, err
+	}
+
+	ce.cacheLock.Lock()
+	ce.priceCache[cacheKey] = price
+	ce.cacheLock.Unlock()
+
+	return price, nil
+}
+
+// getLightsailBundlePrice returns the monthly price of a Lightsail bundle in a region. Bundles
+// aren't listed by the Pricing API, so every bundle's price is fetched from Lightsail at once and
+// cached, including inactive bundles that existing instances may still run on.
+func (ce *CostEstimator) getLightsailBundlePrice(region, bundleID string) (float64, error) {
+	cacheKey := fmt.Sprintf("LightsailBundle:%s:%s", region, bundleID)
+	ce.cacheLock.RLock()
+	if price, ok := ce.priceCache[cacheKey]; ok {
+		ce.cacheLock.RUnlock()
+		return price, nil
+	}
+	ce.cacheLock.RUnlock()
+
+	client := lightsail.New(ce.session, aws.NewConfig().WithRegion(region))
+	prices := make(map[string]float64)
+	input := &lightsail.GetBundlesInput{
+		IncludeInactive: aws.Bool(true),
+	}
+	for {
+		output, err := client.GetBundles(input)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get Lightsail bundles: %w", err)
+		}
+		for _, bundle := range output.Bundles {
+			prices[aws.StringValue(bundle.BundleId)] = aws.Float64Value(bundle.Price)
+		}
+		if aws.StringValue(output.NextPageToken) == "" {
+			break
+		}
+		input.PageToken = output.NextPageToken
+	}
+
+	ce.cacheLock.Lock()
+	for id, price := range prices {
+		ce.priceCache[fmt.Sprintf("LightsailBundle:%s:%s", region, id)] = price
+	}
+	ce.cacheLock.Unlock()
+
+	price, ok := prices[bundleID]
+	if !ok {
+		return 0, fmt.Errorf("no price found for Lightsail bundle %s", bundleID)
+	}
+	return price, nil
+}
+
 // roundCost rounds a cost value to 4 decimal places
 func roundCost(cost float64) float64 {
 	return math.Round(cost*10000) / 10000
@@ -1100,8 +2130,8 @@ func (ce *CostEstimator) CalculateCost(config ResourceCostConfig) (*CostBreakdow
 	case "EC2":
 		// For EC2, price is already per hour
 		hourlyPrice = pricePerUnit
-	case "EBSVolumes", "EBSSnapshots":
-		// For storage resources, we only care about size and rates
+	case "EBSVolumes", "EBSSnapshots", "EBSProvisionedIOPS":
+		// For storage resources, we only care about size and rates (IOPS count for provisioned IOPS)
 		size, ok := config.ResourceSize.(int64)
 		if !ok {
 			return nil, fmt.Errorf("invalid resource size type for cost calculation: %T", config.ResourceSize)
@@ -1137,6 +2167,22 @@ func (ce *CostEstimator) CalculateCost(config ResourceCostConfig) (*CostBreakdow
 			HoursRunning: nil,
 			Lifetime:     nil,
 		}, nil
+	case "Route53HostedZone":
+		// For hosted zones, price is already converted to hourly
+		hourlyPrice = pricePerUnit
+		dailyPrice := hourlyPrice * 24
+		monthlyPrice := dailyPrice * 30 // Approximate
+		yearlyPrice := dailyPrice * 365
+
+		// Route53 does not report when a hosted zone was created, so lifetime is unknown
+		return &CostBreakdown{
+			HourlyRate:   roundCost(hourlyPrice),
+			DailyRate:    roundCost(dailyPrice),
+			MonthlyRate:  roundCost(monthlyPrice),
+			YearlyRate:   roundCost(yearlyPrice),
+			HoursRunning: nil,
+			Lifetime:     nil,
+		}, nil
 	case "elb":
 		// For ELB, price is already per hour
 		hourlyPrice = pricePerUnit
@@ -1208,6 +2254,72 @@ func (ce *CostEstimator) CalculateCost(config ResourceCostConfig) (*CostBreakdow
 			HoursRunning: &hours,
 			Lifetime:     &lifetime,
 		}, nil
+	case "EFS":
+		// For EFS, storage and throughput are already converted to an hourly price
+		hourlyPrice = pricePerUnit
+	case "TransitGateway":
+		// For Transit Gateways, price is per attachment-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "VPCEndpoint":
+		// For VPC endpoints, price is per endpoint-hour in each Availability Zone
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "VPNConnection":
+		// For VPN connections, price is already per connection-hour
+		hourlyPrice = pricePerUnit
+	case "CloudHSM":
+		// For CloudHSM, price is per HSM-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "AmazonMQ":
+		// For Amazon MQ, price is per broker instance-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "Lightsail", "LightsailDisk":
+		// For Lightsail, the flat monthly price is already converted to hourly
+		hourlyPrice = pricePerUnit
+	case "LambdaGBSecond":
+		// For Lambda, price is per GB-second of compute
+		hourlyPrice = pricePerUnit * config.GBSecondsPerHour
+	case "DocumentDB", "Neptune":
+		// For DocumentDB and Neptune, price is per instance-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "Kinesis":
+		// For provisioned streams, price is per shard-hour; on-demand streams pass a count of 1
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "APIGatewayCache":
+		// For API Gateway caches, price is already per hour
+		hourlyPrice = pricePerUnit
+	case "BatchvCPU":
+		// For Batch, price is per vCPU-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "AuroraServerless":
+		// For Aurora Serverless, price is per ACU-hour
+		hourlyPrice = pricePerUnit * config.CapacityUnits
+	case "AWSBackup":
+		// For AWS Backup, storage is already converted to an hourly price
+		hourlyPrice = pricePerUnit
+	case "MSK":
+		// For MSK, brokers and storage are already converted to an hourly price
+		hourlyPrice = pricePerUnit
+	case "ECR":
+		// For ECR, storage is already converted to an hourly price
+		hourlyPrice = pricePerUnit
+	case "S3Storage":
+		// For S3, storage is already converted to an hourly price
+		hourlyPrice = pricePerUnit
+	case "Fargate":
+		// For Fargate, price is per task-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "AppRunner":
+		// For App Runner, price is per provisioned instance-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "EC2CapacityReservation":
+		// For capacity reservations, price is per hour for each unused instance
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "RedshiftNode":
+		// For Redshift, price is per node-hour
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
+	case "SecretsManager":
+		// For secrets, price is converted to hourly per secret; the count includes replicas
+		hourlyPrice = pricePerUnit * float64(config.InstanceCount)
 	case "NATGateway":
 		// For NAT Gateway, price is already per hour
 		hourlyPrice = pricePerUnit
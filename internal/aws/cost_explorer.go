@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+)
+
+// costExplorerDateFormat is the date format Cost Explorer time periods use
+const costExplorerDateFormat = "2006-01-02"
+
+// costExplorerServices maps scanner labels to the Cost Explorer service their resources are billed
+// under. Scanners of resources that cost nothing, such as IAM users and security groups, are left
+// out, as are Network Waste and RI and Savings Plans, whose findings are billed under several services.
+var costExplorerServices = map[string]string{
+	"Amazon MQ Brokers":              "Amazon MQ",
+	"AMIs":                           "EC2 - Other",
+	"API Gateway APIs":               "Amazon API Gateway",
+	"App Runner Services":            "AWS App Runner",
+	"AWS Backup Recovery Points":     "AWS Backup",
+	"Aurora Serverless Clusters":     "Amazon Relational Database Service",
+	"Batch Compute Environments":     "Amazon Elastic Compute Cloud - Compute",
+	"Beanstalk Application Versions": "Amazon Simple Storage Service",
+	"Capacity Reservations":          "Amazon Elastic Compute Cloud - Compute",
+	"CloudHSM Clusters":              "AWS CloudHSM",
+	"DocumentDB Clusters":            "Amazon DocumentDB (with MongoDB compatibility)",
+	"DynamoDB Tables":                "Amazon DynamoDB",
+	"EBS Snapshots":                  "EC2 - Other",
+	"EBS Volumes":                    "EC2 - Other",
+	"EC2 Instances":                  "Amazon Elastic Compute Cloud - Compute",
+	"ECR Images":                     "Amazon EC2 Container Registry (ECR)",
+	"ECS Services":                   "Amazon Elastic Container Service",
+	"EFS File Systems":               "Amazon Elastic File System",
+	"Elastic IPs":                    "Amazon Virtual Private Cloud",
+	"Kinesis Streams":                "Amazon Kinesis",
+	"Lambda Memory":                  "AWS Lambda",
+	"Lightsail Resources":            "Amazon Lightsail",
+	"Load Balancers":                 "Amazon Elastic Load Balancing",
+	"MSK Clusters":                   "Amazon Managed Streaming for Apache Kafka",
+	"NAT Gateways":                   "EC2 - Other",
+	"Neptune Clusters":               "Amazon Neptune",
+	"OpenSearch Domains":             "Amazon OpenSearch Service",
+	"RDS Instances":                  "Amazon Relational Database Service",
+	"Redshift":                       "Amazon Redshift",
+	"Route53 Hosted Zones":           "Amazon Route 53",
+	"SNS Topics":                     "Amazon Simple Notification Service",
+	"SQS Queues":                     "Amazon Simple Queue Service",
+	"Secrets Manager Secrets":        "AWS Secrets Manager",
+	"Shared Snapshots":               "EC2 - Other",
+	"Step Functions State Machines":  "AWS Step Functions",
+	"Transit Gateways":               "Amazon Virtual Private Cloud",
+	"VPN Connections":                "Amazon Virtual Private Cloud",
+}
+
+// ActualSpend compares what an account was billed for a service, according to Cost Explorer, with
+// the estimated cost of the findings billed under it
+type ActualSpend struct {
+	Service                 string   `json:"service"`        // Cost Explorer service name
+	ResourceTypes           []string `json:"resource_types"` // Labels of the scanners whose findings are billed under the service
+	Start                   string   `json:"start"`          // First day of the period, inclusive
+	End                     string   `json:"end"`            // Last day of the period, exclusive
+	ActualCostUSD           float64  `json:"actual_cost_usd"`
+	EstimatedMonthlyCostUSD float64  `json:"estimated_monthly_cost_usd"` // Monthly estimate of the findings only
+}
+
+// CostExplorerService returns the Cost Explorer service a scanner's resources are billed under, or
+// false if they aren't billed
+func CostExplorerService(resourceType string) (string, bool) {
+	service, ok := costExplorerServices[resourceType]
+	return service, ok
+}
+
+// GetActualSpend returns the unblended cost an account was billed for each of the given Cost
+// Explorer services between start (inclusive) and end (exclusive), keyed by service. The cost
+// estimator's session must be able to see the account's billing data, i.e. belong to the account
+// itself or to its organization's management account.
+func (ce *CostEstimator) GetActualSpend(ctx context.Context, accountID string, services []string, start, end time.Time) (map[string]float64, error) {
+	spend := make(map[string]float64)
+	if len(services) == 0 {
+		return spend, nil
+	}
+
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.UTC().Format(costExplorerDateFormat)),
+			End:   aws.String(end.UTC().Format(costExplorerDateFormat)),
+		},
+		Granularity: aws.String(costexplorer.GranularityMonthly),
+		Metrics:     []*string{aws.String(costexplorer.MetricUnblendedCost)},
+		Filter: &costexplorer.Expression{
+			And: []*costexplorer.Expression{
+				{
+					Dimensions: &costexplorer.DimensionValues{
+						Key:    aws.String(costexplorer.DimensionLinkedAccount),
+						Values: []*string{aws.String(accountID)},
+					},
+				},
+				{
+					Dimensions: &costexplorer.DimensionValues{
+						Key:    aws.String(costexplorer.DimensionService),
+						Values: aws.StringSlice(services),
+					},
+				},
+			},
+		},
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionService),
+			},
+		},
+	}
+
+	for {
+		if err := ce.rateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limiter interrupted: %w", err)
+		}
+		output, err := ce.costExplorerClient.GetCostAndUsageWithContext(ctx, input)
+		if err != nil {
+			ce.rateLimiter.OnFailure()
+			return nil, fmt.Errorf("failed to get cost and usage for account %s: %w", accountID, err)
+		}
+		ce.rateLimiter.OnSuccess()
+
+		// A period spanning more than one month is returned as one result per month
+		for _, period := range output.ResultsByTime {
+			for _, group := range period.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				metric, ok := group.Metrics[costexplorer.MetricUnblendedCost]
+				if !ok {
+					continue
+				}
+				amount, err := strconv.ParseFloat(aws.StringValue(metric.Amount), 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid cost amount %q for %s: %w", aws.StringValue(metric.Amount), aws.StringValue(group.Keys[0]), err)
+				}
+				spend[aws.StringValue(group.Keys[0])] += amount
+			}
+		}
+
+		if output.NextPageToken == nil {
+			return spend, nil
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+}
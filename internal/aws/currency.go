@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BaseCurrency is the currency AWS prices, and so every cost estimate, are in
+const BaseCurrency = "USD"
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// currencySymbols are the symbols shown before amounts in common currencies. Other currencies are
+// shown with their code.
+var currencySymbols = map[string]string{
+	"AUD": "A$",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"CHF": "CHF ",
+	"CNY": "CN¥",
+	"DKK": "kr ",
+	"EUR": "€",
+	"GBP": "£",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"NOK": "kr ",
+	"NZD": "NZ$",
+	"SEK": "kr ",
+	"SGD": "S$",
+	"USD": "$",
+}
+
+// ExchangeRateProvider looks up exchange rates for converting cost estimates out of US dollars
+type ExchangeRateProvider interface {
+	// ExchangeRate returns the number of units of currency one US dollar buys
+	ExchangeRate(currency string) (float64, error)
+}
+
+// StaticExchangeRate is an ExchangeRateProvider with a fixed rate for every currency, such as one
+// set with --exchange-rate
+type StaticExchangeRate float64
+
+// ExchangeRate implements ExchangeRateProvider interface
+func (r StaticExchangeRate) ExchangeRate(currency string) (float64, error) {
+	if r <= 0 {
+		return 0, fmt.Errorf("exchange rate for %s must be greater than 0", currency)
+	}
+	return float64(r), nil
+}
+
+// DefaultExchangeRateProvider is used when a currency is requested without a static exchange rate.
+// It is nil unless a build of CloudSift registers one, e.g. backed by a finance team's rate feed.
+var DefaultExchangeRateProvider ExchangeRateProvider
+
+// NormalizeCurrency returns a currency code in upper case, or an error if it isn't an ISO 4217 code
+func NormalizeCurrency(currency string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(currency))
+	if !currencyPattern.MatchString(code) {
+		return "", fmt.Errorf("invalid currency %q: expected a three-letter ISO 4217 code such as EUR", currency)
+	}
+	return code, nil
+}
+
+// CurrencySymbol returns the symbol shown before amounts in a currency
+func CurrencySymbol(currency string) string {
+	if symbol, ok := currencySymbols[currency]; ok {
+		return symbol
+	}
+	return currency + " "
+}
+
+// CurrencySymbol returns the symbol shown before the cost breakdown's amounts
+func (c *CostBreakdown) CurrencySymbol() string {
+	if c.Currency == "" {
+		return CurrencySymbol(BaseCurrency)
+	}
+	return CurrencySymbol(c.Currency)
+}
+
+// Convert returns a copy of the cost breakdown in another currency, given the number of units of
+// that currency one US dollar buys
+func (c *CostBreakdown) Convert(currency string, rate float64) *CostBreakdown {
+	converted := &CostBreakdown{
+		HourlyRate:   c.HourlyRate * rate,
+		DailyRate:    c.DailyRate * rate,
+		MonthlyRate:  c.MonthlyRate * rate,
+		YearlyRate:   c.YearlyRate * rate,
+		HoursRunning: c.HoursRunning,
+		Currency:     currency,
+	}
+	if c.Lifetime != nil {
+		lifetime := *c.Lifetime * rate
+		converted.Lifetime = &lifetime
+	}
+	return converted
+}
+
+// ConvertCosts converts the total cost of each result to another currency. The US dollar estimate
+// is kept under "total_usd" so reports can still be audited against AWS prices.
+func ConvertCosts(results ScanResults, currency string, rate float64) {
+	for _, result := range results {
+		total, ok := result.Cost["total"].(*CostBreakdown)
+		if !ok || total == nil {
+			continue
+		}
+		result.Cost["total_usd"] = total
+		result.Cost["total"] = total.Convert(currency, rate)
+	}
+}
+
+// USDCost returns a result's total cost in US dollars, whether or not it has been converted to
+// another currency
+func USDCost(cost map[string]interface{}) *CostBreakdown {
+	if total, ok := cost["total_usd"].(*CostBreakdown); ok {
+		return total
+	}
+	total, _ := cost["total"].(*CostBreakdown)
+	return total
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// metricCacheEntry holds one cached CloudWatch response. ready is closed once the response is
+// stored, so concurrent callers asking for the same metric wait for a single request.
+type metricCacheEntry struct {
+	ready  chan struct{}
+	output interface{}
+	err    error
+}
+
+// MetricCache shares CloudWatch metric responses between scanners for the duration of a scan.
+// Entries are scoped to the account and region of the client that fetched them.
+type MetricCache struct {
+	mu      sync.Mutex
+	entries map[string]*metricCacheEntry
+	hits    int64
+	misses  int64
+}
+
+// DefaultMetricCache is the cache used by GetMetricStatistics and GetMetricData
+var DefaultMetricCache = NewMetricCache()
+
+// NewMetricCache creates an empty metric cache
+func NewMetricCache() *MetricCache {
+	return &MetricCache{
+		entries: make(map[string]*metricCacheEntry),
+	}
+}
+
+// Reset drops all cached responses and clears the hit counters
+func (c *MetricCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*metricCacheEntry)
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+}
+
+// Stats returns the number of requests served from the cache and sent to CloudWatch
+func (c *MetricCache) Stats() (hits, misses int64) {
+	return atomic.LoadInt64(&c.hits), atomic.LoadInt64(&c.misses)
+}
+
+// metricCacheKey builds the cache key for a request. Sessions for different accounts never share
+// a credentials object, so the credentials pointer stands in for the account. Start and end times
+// are truncated to the minute so scanners computing their window moments apart share entries.
+func metricCacheKey(client *cloudwatch.CloudWatch, operation string, input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%p|%s|%s|%s", client.Config.Credentials, aws.StringValue(client.Config.Region), operation, data), nil
+}
+
+// fetch returns the cached response for key, calling load on a miss. Failed requests are not
+// cached so a later call can retry them. A caller waiting on another caller's request gives up
+// when ctx is done, so a hung request can't stall every scanner asking for the same metric.
+func (c *MetricCache) fetch(ctx aws.Context, key string, load func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry.err == nil {
+			atomic.AddInt64(&c.hits, 1)
+			return entry.output, nil
+		}
+		// The request this caller waited on failed; make its own attempt
+		return c.fetch(ctx, key, load)
+	}
+	entry := &metricCacheEntry{ready: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	atomic.AddInt64(&c.misses, 1)
+	entry.output, entry.err = load()
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.ready)
+
+	return entry.output, entry.err
+}
+
+// truncateMinute rounds a time pointer down to the minute, leaving nil as is
+func truncateMinute(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return aws.Time(t.Truncate(time.Minute))
+}
+
+// GetMetricStatistics calls CloudWatch GetMetricStatistics through the shared metric cache. Scanners
+// should use this rather than calling the client directly so repeated requests for the same metric
+// within an account and region are only sent once per scan.
+func GetMetricStatistics(ctx aws.Context, client *cloudwatch.CloudWatch, input *cloudwatch.GetMetricStatisticsInput) (*cloudwatch.GetMetricStatisticsOutput, error) {
+	request := *input
+	request.StartTime = truncateMinute(input.StartTime)
+	request.EndTime = truncateMinute(input.EndTime)
+
+	key, err := metricCacheKey(client, "GetMetricStatistics", &request)
+	if err != nil {
+		return client.GetMetricStatisticsWithContext(ctx, input)
+	}
+
+	output, err := DefaultMetricCache.fetch(ctx, key, func() (interface{}, error) {
+		return client.GetMetricStatisticsWithContext(ctx, &request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.(*cloudwatch.GetMetricStatisticsOutput), nil
+}
+
+// GetMetricData calls CloudWatch GetMetricData through the shared metric cache, like GetMetricStatistics
+func GetMetricData(ctx aws.Context, client *cloudwatch.CloudWatch, input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	request := *input
+	request.StartTime = truncateMinute(input.StartTime)
+	request.EndTime = truncateMinute(input.EndTime)
+
+	key, err := metricCacheKey(client, "GetMetricData", &request)
+	if err != nil {
+		return client.GetMetricDataWithContext(ctx, input)
+	}
+
+	output, err := DefaultMetricCache.fetch(ctx, key, func() (interface{}, error) {
+		return client.GetMetricDataWithContext(ctx, &request)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.(*cloudwatch.GetMetricDataOutput), nil
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricCacheFetchCancelledWaiter tests that a caller waiting on another caller's request
+// gives up when its context is done, and that the request still populates the cache
+func TestMetricCacheFetchCancelledWaiter(t *testing.T) {
+	cache := NewMetricCache()
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	loaded := make(chan error, 1)
+	go func() {
+		_, err := cache.fetch(context.Background(), "key", func() (interface{}, error) {
+			close(started)
+			<-release
+			return "output", nil
+		})
+		loaded <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := cache.fetch(ctx, "key", func() (interface{}, error) {
+		t.Fatal("waiter must not send its own request while the first is in flight")
+		return nil, nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	close(release)
+	require.NoError(t, <-loaded)
+
+	output, err := cache.fetch(context.Background(), "key", func() (interface{}, error) {
+		return nil, errors.New("cached response should be used")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "output", output)
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(1), misses)
+}
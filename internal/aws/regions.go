@@ -11,8 +11,8 @@ import (
 
 // GetAvailableRegions returns a list of regions that are enabled for the account
 func GetAvailableRegions(sess *session.Session) ([]string, error) {
-	// Start with us-east-1 to get the region list
-	svc := ec2.New(sess, aws.NewConfig().WithRegion("us-east-1"))
+	// Ask the partition's global region (us-east-1 for commercial accounts) for the region list
+	svc := ec2.New(sess, aws.NewConfig().WithRegion(GlobalRegion(SessionPartition(sess))))
 
 	input := &ec2.DescribeRegionsInput{
 		AllRegions: aws.Bool(false), // Only get enabled regions
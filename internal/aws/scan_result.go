@@ -1,17 +1,92 @@
 package aws
 
+import "time"
+
+// Confidence levels of a finding, from near-certain waste to a judgment call worth a manual review
+const (
+	ConfidenceHigh   = "high"   // The resource is almost certainly waste, e.g. an unattached Elastic IP
+	ConfidenceMedium = "medium" // Usage data points to waste, but the resource may still be wanted
+	ConfidenceLow    = "low"    // A judgment call on a weak or partial signal, e.g. low average CPU
+)
+
+// confidenceRanks orders confidence levels from lowest to highest
+var confidenceRanks = map[string]int{
+	ConfidenceLow:    1,
+	ConfidenceMedium: 2,
+	ConfidenceHigh:   3,
+}
+
+// ValidConfidence reports whether level is one of the confidence levels
+func ValidConfidence(level string) bool {
+	_, ok := confidenceRanks[level]
+	return ok
+}
+
+// ConfidenceRank returns a confidence level's position from 1 (low) to 3 (high), or 0 if it isn't one
+func ConfidenceRank(level string) int {
+	return confidenceRanks[level]
+}
+
 // ScanResult represents a single resource found during a scan
 type ScanResult struct {
 	ResourceType string                 `json:"resource_type"`
 	ResourceName string                 `json:"resource_name"`
 	ResourceID   string                 `json:"resource_id"`
+	ARN          string                 `json:"arn,omitempty"`
 	AccountID    string                 `json:"account_id"`
 	AccountName  string                 `json:"account_name"`
+	Region       string                 `json:"region"` // Region the finding was reported in, "global" for global scanners
 	Reason       string                 `json:"reason"`
+	Confidence   string                 `json:"confidence,omitempty"` // How sure the scanner is that the resource is waste: high, medium or low
 	Tags         map[string]string      `json:"tags"`
 	Details      map[string]interface{} `json:"details"`
 	Cost         map[string]interface{} `json:"cost"`
+	Occurrences  int                    `json:"occurrences,omitempty"` // Number of times the finding was reported before deduplication
+	CreatedAt    *time.Time             `json:"created_at,omitempty"`  // When the underlying resource was created, if known
+}
+
+// SavedRegion returns the region of a finding read back from a report, falling back to
+// Details["region"] for reports written before Region was a field of its own
+func (r ScanResult) SavedRegion() string {
+	if r.Region != "" {
+		return r.Region
+	}
+	region, _ := r.Details["region"].(string)
+	return region
+}
+
+// ConfidenceLevel returns the finding's confidence, treating findings without one, such as those
+// from reports written before findings had a confidence, as medium
+func (r ScanResult) ConfidenceLevel() string {
+	if r.Confidence == "" {
+		return ConfidenceMedium
+	}
+	return r.Confidence
+}
+
+// MeetsConfidence reports whether the finding's confidence is at least minimum
+func (r ScanResult) MeetsConfidence(minimum string) bool {
+	return ConfidenceRank(r.ConfidenceLevel()) >= ConfidenceRank(minimum)
 }
 
 // ScanResults is a slice of ScanResult
 type ScanResults []ScanResult
+
+// ScanError records a scanner task that failed for an account and region
+type ScanError struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	Region      string `json:"region"`
+	Scanner     string `json:"scanner"`
+	Error       string `json:"error"`
+}
+
+// TruncatedResults records a scanner task whose findings were cut down to the per-scanner limit
+type TruncatedResults struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	Region      string `json:"region"`
+	Scanner     string `json:"scanner"`
+	Kept        int    `json:"kept"`    // Findings reported
+	Omitted     int    `json:"omitted"` // Findings dropped because of the limit
+}
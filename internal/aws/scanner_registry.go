@@ -1,10 +1,14 @@
 package aws
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"sort"
 	"sync"
 
+	"cloudsift/internal/logging"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 )
 
@@ -14,18 +18,49 @@ type ScanOptions struct {
 	DaysUnused int              // Number of days a resource must be unused to be reported
 	Session    *session.Session // AWS session to use for scanning (already configured with necessary role chain)
 	AccountID  string           // AWS Account ID for the session
+	Ctx        context.Context  // Context for cancelling the scan (nil means no cancellation)
+
+	EC2Rightsizing bool // Recommend smaller instance types for running EC2 instances that aren't idle
 }
 
-// Scanner interface defines methods that must be implemented by resource scanners
+// Context returns the context scanners should use for AWS API calls
+func (o ScanOptions) Context() context.Context {
+	if o.Ctx == nil {
+		return context.Background()
+	}
+	return o.Ctx
+}
+
+// Scanner interface defines methods that must be implemented by resource scanners. Scan is called
+// once per account and region (once per account for global scanners), concurrently with other
+// scanners, so implementations must be safe for concurrent use. It should make AWS API calls with
+// opts.Context() so timeouts and cancellation are honored, and return only resources that are
+// unused, with ResourceType set to the scanner's label.
 type Scanner interface {
 	ArgumentName() string // ArgumentName returns the name used in CLI arguments
 	Label() string        // Label returns a human-readable label for the scanner
+	IsGlobal() bool       // IsGlobal returns true for scanners of global services, which run once per account
 	Scan(opts ScanOptions) (ScanResults, error)
 }
 
+// GroupingScanner is implemented by scanners that report findings of other scanners under a single
+// label. They only run when named in --scanners, and the scanners they cover are dropped when
+// selected alongside them, so no finding is counted twice.
+type GroupingScanner interface {
+	Scanner
+	Covers() []string // Covers returns the argument names of the scanners whose findings it reports
+}
+
+// ScannerFactory creates a scanner for RegisterScannerFactory
+type ScannerFactory func() Scanner
+
+// scannerNamePattern matches valid scanner argument names, which are used in --scanners lists
+var scannerNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 // ScannerRegistry manages available scanners
 type ScannerRegistry struct {
 	scanners map[string]Scanner
+	aliases  map[string]string // Deprecated scanner names, mapped to the name that replaced them
 	mu       sync.RWMutex
 }
 
@@ -33,6 +68,7 @@ type ScannerRegistry struct {
 func NewScannerRegistry() *ScannerRegistry {
 	return &ScannerRegistry{
 		scanners: make(map[string]Scanner),
+		aliases:  make(map[string]string),
 	}
 }
 
@@ -43,13 +79,64 @@ func (r *ScannerRegistry) RegisterScanner(scanner Scanner) {
 	r.scanners[scanner.ArgumentName()] = scanner
 }
 
-// GetScanner retrieves a scanner by argument name
+// RegisterAlias registers a deprecated name for a renamed scanner, so --scanners lists using the
+// old name keep working. Aliases aren't listed by ListScanners.
+func (r *ScannerRegistry) RegisterAlias(alias, argumentName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.aliases[alias] = argumentName
+}
+
+// RegisterScannerFactory registers the scanner created by factory under name. Unlike
+// RegisterScanner, it returns an error for invalid names and names that are already registered, so
+// out-of-tree scanners can't replace built-in ones. The factory is called once, at registration.
+func (r *ScannerRegistry) RegisterScannerFactory(name string, factory ScannerFactory) error {
+	if !scannerNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid scanner name %q: use lowercase letters, digits and hyphens", name)
+	}
+	if factory == nil {
+		return fmt.Errorf("scanner %s has no factory", name)
+	}
+
+	scanner := factory()
+	if scanner == nil {
+		return fmt.Errorf("factory for scanner %s returned nil", name)
+	}
+	if scanner.ArgumentName() != name {
+		return fmt.Errorf("scanner registered as %s has argument name %s", name, scanner.ArgumentName())
+	}
+	if scanner.Label() == "" {
+		return fmt.Errorf("scanner %s has no label", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.scanners[name]; ok {
+		return fmt.Errorf("scanner %s is already registered", name)
+	}
+	if _, ok := r.aliases[name]; ok {
+		return fmt.Errorf("scanner name %s is reserved for a renamed built-in scanner", name)
+	}
+	r.scanners[name] = scanner
+	return nil
+}
+
+// GetScanner retrieves a scanner by argument name, or by a deprecated name with a warning
 func (r *ScannerRegistry) GetScanner(argumentName string) (Scanner, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	scanner, ok := r.scanners[argumentName]
 	if !ok {
+		if current, isAlias := r.aliases[argumentName]; isAlias {
+			if scanner, ok = r.scanners[current]; ok {
+				logging.Warn("Scanner name is deprecated", map[string]interface{}{
+					"scanner": argumentName,
+					"use":     current,
+				})
+				return scanner, nil
+			}
+		}
 		return nil, fmt.Errorf("scanner %s not found", argumentName)
 	}
 	return scanner, nil
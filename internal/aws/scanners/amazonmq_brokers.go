@@ -0,0 +1,235 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/mq"
+)
+
+// AmazonMQBrokerScanner scans for running Amazon MQ brokers that no client has connected to
+type AmazonMQBrokerScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&AmazonMQBrokerScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *AmazonMQBrokerScanner) ArgumentName() string {
+	return "amazonmq-brokers"
+}
+
+// Label implements Scanner interface
+func (s *AmazonMQBrokerScanner) Label() string {
+	return "Amazon MQ Brokers"
+}
+
+// IsGlobal implements Scanner interface
+func (s *AmazonMQBrokerScanner) IsGlobal() bool {
+	return false
+}
+
+// brokerInstanceCount returns the number of broker instances a deployment mode runs, each of
+// which is billed
+func (s *AmazonMQBrokerScanner) brokerInstanceCount(deploymentMode string) int64 {
+	switch deploymentMode {
+	case mq.DeploymentModeActiveStandbyMultiAz:
+		return 2
+	case mq.DeploymentModeClusterMultiAz:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// activityMetrics returns the connection and consumer metrics of a broker engine
+func (s *AmazonMQBrokerScanner) activityMetrics(engineType string) []string {
+	if engineType == mq.EngineTypeRabbitmq {
+		return []string{"ConnectionCount", "ConsumerCount"}
+	}
+	return []string{"CurrentConnectionsCount", "TotalConsumerCount"}
+}
+
+// metricBrokerNames returns the Broker dimension values a broker's metrics are published under.
+// ActiveMQ active/standby brokers publish each instance separately, suffixed -1 and -2.
+func (s *AmazonMQBrokerScanner) metricBrokerNames(brokerName, engineType, deploymentMode string) []string {
+	if engineType == mq.EngineTypeActivemq && deploymentMode == mq.DeploymentModeActiveStandbyMultiAz {
+		return []string{brokerName + "-1", brokerName + "-2"}
+	}
+	return []string{brokerName}
+}
+
+// getMaxActivity returns the highest connection or consumer count a broker reported between
+// startTime and endTime
+func (s *AmazonMQBrokerScanner) getMaxActivity(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, broker *mq.BrokerSummary, startTime, endTime time.Time) (float64, error) {
+	engineType := aws.StringValue(broker.EngineType)
+	var maxActivity float64
+	for _, brokerName := range s.metricBrokerNames(aws.StringValue(broker.BrokerName), engineType, aws.StringValue(broker.DeploymentMode)) {
+		for _, metricName := range s.activityMetrics(engineType) {
+			output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+				Namespace:  aws.String("AWS/AmazonMQ"),
+				MetricName: aws.String(metricName),
+				Dimensions: []*cloudwatch.Dimension{
+					{
+						Name:  aws.String("Broker"),
+						Value: aws.String(brokerName),
+					},
+				},
+				StartTime:  aws.Time(startTime),
+				EndTime:    aws.Time(endTime),
+				Period:     aws.Int64(86400), // 1 day
+				Statistics: []*string{aws.String("Maximum")},
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+			}
+			for _, dp := range output.Datapoints {
+				if value := aws.Float64Value(dp.Maximum); value > maxActivity {
+					maxActivity = value
+				}
+			}
+		}
+	}
+	return maxActivity, nil
+}
+
+// calculateBrokerCost estimates the cost of a broker's instances since it was created
+func (s *AmazonMQBrokerScanner) calculateBrokerCost(region, instanceType string, instanceCount int64, creationTime time.Time) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "AmazonMQ",
+			ResourceSize:  instanceType,
+			Region:        region,
+			CreationTime:  creationTime,
+			InstanceCount: instanceCount,
+		})
+		if err == nil && costBreakdown.HourlyRate != 0 {
+			return costBreakdown
+		}
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable, fails or returns zero
+	hourlyRate := 0.288 * float64(instanceCount) // Default hourly rate per broker instance as fallback
+	hoursRunning := time.Since(creationTime).Hours()
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *AmazonMQBrokerScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	mqClient := mq.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var brokers []*mq.BrokerSummary
+	err = mqClient.ListBrokersPagesWithContext(opts.Context(), &mq.ListBrokersInput{}, func(page *mq.ListBrokersResponse, lastPage bool) bool {
+		for _, broker := range page.BrokerSummaries {
+			if aws.StringValue(broker.BrokerState) == mq.BrokerStateRunning {
+				brokers = append(brokers, broker)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list Amazon MQ brokers", err, nil)
+		return nil, fmt.Errorf("failed to list Amazon MQ brokers: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, broker := range brokers {
+		brokerID := aws.StringValue(broker.BrokerId)
+		brokerName := aws.StringValue(broker.BrokerName)
+
+		// Brokers created within the window haven't had the chance to be used yet
+		if aws.TimeValue(broker.Created).After(startTime) {
+			continue
+		}
+
+		maxActivity, err := s.getMaxActivity(opts, cwClient, broker, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze Amazon MQ broker usage", err, map[string]interface{}{
+				"broker_id": brokerID,
+			})
+			continue
+		}
+		if maxActivity > 0 {
+			continue
+		}
+
+		engineType := aws.StringValue(broker.EngineType)
+		deploymentMode := aws.StringValue(broker.DeploymentMode)
+		instanceType := aws.StringValue(broker.HostInstanceType)
+		instanceCount := s.brokerInstanceCount(deploymentMode)
+
+		details := map[string]interface{}{
+			"account_id":         opts.AccountID,
+			"region":             opts.Region,
+			"broker_state":       aws.StringValue(broker.BrokerState),
+			"engine_type":        engineType,
+			"host_instance_type": instanceType,
+			"deployment_mode":    deploymentMode,
+			"instance_count":     instanceCount,
+			"days_unused":        opts.DaysUnused,
+		}
+
+		// Tags and the engine version are only returned by DescribeBroker
+		tags := make(map[string]string)
+		description, err := mqClient.DescribeBrokerWithContext(opts.Context(), &mq.DescribeBrokerInput{
+			BrokerId: aws.String(brokerID),
+		})
+		if err != nil {
+			logging.Warn("Failed to describe Amazon MQ broker", map[string]interface{}{
+				"broker_id": brokerID,
+				"error":     err.Error(),
+			})
+		} else {
+			for key, value := range description.Tags {
+				tags[key] = aws.StringValue(value)
+			}
+			details["engine_version"] = aws.StringValue(description.EngineVersion)
+			details["storage_type"] = aws.StringValue(description.StorageType)
+			details["publicly_accessible"] = aws.BoolValue(description.PubliclyAccessible)
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: brokerName,
+			ResourceID:   brokerID,
+			ARN:          aws.StringValue(broker.BrokerArn),
+			CreatedAt:    broker.Created,
+			Reason: fmt.Sprintf("%s broker had no connections or consumers in the last %d days. It is %s old and billed for %d %s instance(s).",
+				engineType, opts.DaysUnused, utils.FormatTimeDifference(time.Now(), broker.Created), instanceCount, instanceType),
+			Confidence: awslib.ConfidenceHigh,
+			Details:    details,
+			Tags:       tags,
+			Cost: map[string]interface{}{
+				"total": s.calculateBrokerCost(opts.Region, instanceType, instanceCount, aws.TimeValue(broker.Created)),
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -34,6 +34,11 @@ func (s *AMIScanner) Label() string {
 	return "AMIs"
 }
 
+// IsGlobal implements Scanner interface
+func (s *AMIScanner) IsGlobal() bool {
+	return false
+}
+
 // amiTask represents a single AMI to analyze
 type amiTask struct {
 	ami         *ec2.Image
@@ -232,8 +237,11 @@ func (t *amiTask) processAMI(ctx context.Context) (*awslib.ScanResult, error) {
 		ResourceType: t.scanner.Label(),
 		ResourceName: resourceName,
 		ResourceID:   amiID,
+		ARN:          awslib.ResourceARN("ec2", t.opts.Region, "", "image/"+amiID), // AMI ARNs have no account ID,
+		CreatedAt:    &creationDate,
 		AccountID:    t.accountID,
 		Reason:       reason,
+		Confidence:   awslib.ConfidenceMedium,
 		Tags:         tags,
 		Details:      details,
 		Cost:         map[string]interface{}{"total": totalCosts},
@@ -277,7 +285,7 @@ func (s *AMIScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	var results awslib.ScanResults
 	var resultsMutex sync.Mutex
 
-	ctx := context.Background()
+	ctx := opts.Context()
 
 	// Describe AMIs owned by this account
 	input := &ec2.DescribeImagesInput{
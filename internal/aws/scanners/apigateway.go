@@ -0,0 +1,418 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// apiGatewayCacheFallbackRates are the us-east-1 hourly prices of API Gateway cache sizes, in GB
+var apiGatewayCacheFallbackRates = map[string]float64{
+	"0.5":  0.02,
+	"1.6":  0.038,
+	"6.1":  0.20,
+	"13.5": 0.25,
+	"28.4": 0.50,
+	"58.2": 1.00,
+	"118":  1.90,
+	"237":  3.80,
+}
+
+// APIGatewayScanner scans for API Gateway REST and HTTP APIs that receive no requests, and for
+// REST API stages paying for a cache cluster that serves no traffic
+type APIGatewayScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&APIGatewayScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *APIGatewayScanner) ArgumentName() string {
+	return "apigateway"
+}
+
+// Label implements Scanner interface
+func (s *APIGatewayScanner) Label() string {
+	return "API Gateway APIs"
+}
+
+// IsGlobal implements Scanner interface
+func (s *APIGatewayScanner) IsGlobal() bool {
+	return false
+}
+
+// getRequestCount returns the total number of requests recorded for the given metric dimensions
+func (s *APIGatewayScanner) getRequestCount(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, dimensions map[string]string, startTime, endTime time.Time) (float64, error) {
+	var metricDimensions []*cloudwatch.Dimension
+	for name, value := range dimensions {
+		metricDimensions = append(metricDimensions, &cloudwatch.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/ApiGateway"),
+		MetricName: aws.String("Count"),
+		Dimensions: metricDimensions,
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // Daily datapoints
+		Statistics: []*string{aws.String("Sum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get request metrics: %w", err)
+	}
+
+	var total float64
+	for _, point := range output.Datapoints {
+		total += aws.Float64Value(point.Sum)
+	}
+	return total, nil
+}
+
+// calculateCacheCost estimates the cost of a stage's cache cluster, which is billed by size whether or not it is used
+func (s *APIGatewayScanner) calculateCacheCost(cacheSize string, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "APIGatewayCache",
+			ResourceSize: cacheSize,
+			Region:       region,
+			CreationTime: creationTime,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to get API Gateway cache price, using default", map[string]interface{}{
+			"cache_size": cacheSize,
+			"region":     region,
+			"error":      err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := apiGatewayCacheFallbackRates[cacheSize]
+	hoursRunning := time.Since(creationTime).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// addCostBreakdown adds one cost breakdown to another, for APIs with several cached stages
+func addCostBreakdown(total, cost *awslib.CostBreakdown) {
+	total.HourlyRate += cost.HourlyRate
+	total.DailyRate += cost.DailyRate
+	total.MonthlyRate += cost.MonthlyRate
+	total.YearlyRate += cost.YearlyRate
+	if cost.Lifetime != nil {
+		total.Lifetime = aws.Float64(aws.Float64Value(total.Lifetime) + *cost.Lifetime)
+	}
+	if cost.HoursRunning != nil && aws.Float64Value(cost.HoursRunning) > aws.Float64Value(total.HoursRunning) {
+		total.HoursRunning = aws.Float64(*cost.HoursRunning)
+	}
+}
+
+// apiGatewayTags converts an API Gateway tag map into the scan result format
+func apiGatewayTags(tags map[string]*string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range tags {
+		result[key] = aws.StringValue(value)
+	}
+	return result
+}
+
+// scanRestAPIs checks REST APIs for idle APIs and for cached stages with no traffic
+func (s *APIGatewayScanner) scanRestAPIs(opts awslib.ScanOptions, apiClient *apigateway.APIGateway, cwClient *cloudwatch.CloudWatch, startTime, endTime time.Time) (awslib.ScanResults, error) {
+	var apis []*apigateway.RestApi
+	err := apiClient.GetRestApisPagesWithContext(opts.Context(), &apigateway.GetRestApisInput{
+		Limit: aws.Int64(500),
+	}, func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+		apis = append(apis, page.Items...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list REST APIs: %w", err)
+	}
+
+	var results awslib.ScanResults
+	for _, api := range apis {
+		apiID := aws.StringValue(api.Id)
+		apiName := aws.StringValue(api.Name)
+
+		// Skip APIs too new to have a full metric window
+		creationTime := aws.TimeValue(api.CreatedDate)
+		if creationTime.After(startTime) {
+			continue
+		}
+
+		stagesOutput, err := apiClient.GetStagesWithContext(opts.Context(), &apigateway.GetStagesInput{
+			RestApiId: aws.String(apiID),
+		})
+		if err != nil {
+			logging.Error("Failed to get REST API stages", err, map[string]interface{}{
+				"api_id": apiID,
+			})
+			continue
+		}
+
+		// REST API metrics are published by API name
+		requestCount, err := s.getRequestCount(opts, cwClient, map[string]string{"ApiName": apiName}, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to get REST API metrics", err, map[string]interface{}{
+				"api_id": apiID,
+			})
+			continue
+		}
+
+		var stageNames, cachedStages []string
+		for _, stage := range stagesOutput.Item {
+			stageNames = append(stageNames, aws.StringValue(stage.StageName))
+			if aws.BoolValue(stage.CacheClusterEnabled) {
+				cachedStages = append(cachedStages, aws.StringValue(stage.StageName))
+			}
+		}
+
+		endpointType := ""
+		if api.EndpointConfiguration != nil && len(api.EndpointConfiguration.Types) > 0 {
+			endpointType = aws.StringValue(api.EndpointConfiguration.Types[0])
+		}
+
+		if requestCount == 0 {
+			// The whole API is idle; its cost is whatever its stage caches cost
+			cost := &awslib.CostBreakdown{}
+			for _, stage := range stagesOutput.Item {
+				if aws.BoolValue(stage.CacheClusterEnabled) {
+					addCostBreakdown(cost, s.calculateCacheCost(aws.StringValue(stage.CacheClusterSize), aws.TimeValue(stage.CreatedDate), opts.Region))
+				}
+			}
+
+			reason := fmt.Sprintf("REST API has received no requests in the last %d days", opts.DaysUnused)
+			if len(cachedStages) > 0 {
+				reason = fmt.Sprintf("REST API has received no requests in the last %d days but has %d stages with cache clusters", opts.DaysUnused, len(cachedStages))
+			}
+
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: apiName,
+				ResourceID:   apiID,
+				ARN:          awslib.ResourceARN("apigateway", opts.Region, "", "/restapis/"+apiID),
+				CreatedAt:    aws.Time(creationTime),
+				Reason:       reason,
+				Confidence:   awslib.ConfidenceMedium,
+				Details: map[string]interface{}{
+					"account_id":      opts.AccountID,
+					"region":          opts.Region,
+					"finding_type":    "idle_api",
+					"api_type":        "REST",
+					"api_id":          apiID,
+					"endpoint_type":   endpointType,
+					"stage_names":     stageNames,
+					"caching_enabled": len(cachedStages) > 0,
+					"cached_stages":   cachedStages,
+					"request_count":   requestCount,
+					"creation_time":   creationTime,
+				},
+				Tags: apiGatewayTags(api.Tags),
+				Cost: map[string]interface{}{
+					"total": cost,
+				},
+			})
+			continue
+		}
+
+		// The API is in use, but a cached stage may be serving none of its traffic
+		for _, stage := range stagesOutput.Item {
+			if !aws.BoolValue(stage.CacheClusterEnabled) {
+				continue
+			}
+			stageName := aws.StringValue(stage.StageName)
+			stageCreated := aws.TimeValue(stage.CreatedDate)
+			if stageCreated.After(startTime) {
+				continue
+			}
+
+			stageCount, err := s.getRequestCount(opts, cwClient, map[string]string{"ApiName": apiName, "Stage": stageName}, startTime, endTime)
+			if err != nil {
+				logging.Error("Failed to get REST API stage metrics", err, map[string]interface{}{
+					"api_id":     apiID,
+					"stage_name": stageName,
+				})
+				continue
+			}
+			if stageCount > 0 {
+				continue
+			}
+
+			cacheSize := aws.StringValue(stage.CacheClusterSize)
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: fmt.Sprintf("%s/%s", apiName, stageName),
+				ResourceID:   fmt.Sprintf("%s/%s", apiID, stageName),
+				ARN:          awslib.ResourceARN("apigateway", opts.Region, "", fmt.Sprintf("/restapis/%s/stages/%s", apiID, stageName)),
+				CreatedAt:    aws.Time(stageCreated),
+				Reason: fmt.Sprintf("Stage has a %s GB cache cluster but has received no requests in the last %d days",
+					cacheSize, opts.DaysUnused),
+				Confidence: awslib.ConfidenceHigh,
+				Details: map[string]interface{}{
+					"account_id":           opts.AccountID,
+					"region":               opts.Region,
+					"finding_type":         "idle_cache",
+					"api_type":             "REST",
+					"api_id":               apiID,
+					"endpoint_type":        endpointType,
+					"stage_name":           stageName,
+					"stage_names":          []string{stageName},
+					"caching_enabled":      true,
+					"cache_cluster_size":   cacheSize,
+					"cache_cluster_status": aws.StringValue(stage.CacheClusterStatus),
+					"request_count":        stageCount,
+					"creation_time":        stageCreated,
+				},
+				Tags: apiGatewayTags(stage.Tags),
+				Cost: map[string]interface{}{
+					"total": s.calculateCacheCost(cacheSize, stageCreated, opts.Region),
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// scanHTTPAPIs checks HTTP APIs for idle APIs. HTTP APIs have no cache, so an idle one costs nothing
+// but is still worth removing.
+func (s *APIGatewayScanner) scanHTTPAPIs(opts awslib.ScanOptions, apiClient *apigatewayv2.ApiGatewayV2, cwClient *cloudwatch.CloudWatch, startTime, endTime time.Time) (awslib.ScanResults, error) {
+	var apis []*apigatewayv2.Api
+	input := &apigatewayv2.GetApisInput{}
+	for {
+		output, err := apiClient.GetApisWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list HTTP APIs: %w", err)
+		}
+		apis = append(apis, output.Items...)
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var results awslib.ScanResults
+	for _, api := range apis {
+		// WebSocket APIs publish connection and message metrics instead of request counts
+		if aws.StringValue(api.ProtocolType) != apigatewayv2.ProtocolTypeHttp {
+			continue
+		}
+
+		apiID := aws.StringValue(api.ApiId)
+		apiName := aws.StringValue(api.Name)
+
+		// Skip APIs too new to have a full metric window
+		creationTime := aws.TimeValue(api.CreatedDate)
+		if creationTime.After(startTime) {
+			continue
+		}
+
+		// HTTP API metrics are published by API ID
+		requestCount, err := s.getRequestCount(opts, cwClient, map[string]string{"ApiId": apiID}, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to get HTTP API metrics", err, map[string]interface{}{
+				"api_id": apiID,
+			})
+			continue
+		}
+		if requestCount > 0 {
+			continue
+		}
+
+		var stageNames []string
+		stagesInput := &apigatewayv2.GetStagesInput{ApiId: aws.String(apiID)}
+		for {
+			stagesOutput, err := apiClient.GetStagesWithContext(opts.Context(), stagesInput)
+			if err != nil {
+				logging.Debug("Failed to get HTTP API stages", map[string]interface{}{
+					"api_id": apiID,
+					"error":  err.Error(),
+				})
+				break
+			}
+			for _, stage := range stagesOutput.Items {
+				stageNames = append(stageNames, aws.StringValue(stage.StageName))
+			}
+			if aws.StringValue(stagesOutput.NextToken) == "" {
+				break
+			}
+			stagesInput.NextToken = stagesOutput.NextToken
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: apiName,
+			ResourceID:   apiID,
+			ARN:          awslib.ResourceARN("apigateway", opts.Region, "", "/apis/"+apiID),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       fmt.Sprintf("HTTP API has received no requests in the last %d days", opts.DaysUnused),
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":      opts.AccountID,
+				"region":          opts.Region,
+				"finding_type":    "idle_api",
+				"api_type":        "HTTP",
+				"api_id":          apiID,
+				"api_endpoint":    aws.StringValue(api.ApiEndpoint),
+				"stage_names":     stageNames,
+				"caching_enabled": false,
+				"request_count":   requestCount,
+				"creation_time":   creationTime,
+			},
+			Tags: apiGatewayTags(api.Tags),
+			Cost: map[string]interface{}{
+				"total": &awslib.CostBreakdown{},
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// Scan implements Scanner interface
+func (s *APIGatewayScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	cwClient := cloudwatch.New(sess)
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	results, err := s.scanRestAPIs(opts, apigateway.New(sess), cwClient, startTime, endTime)
+	if err != nil {
+		logging.Error("Failed to scan REST APIs", err, nil)
+		return nil, err
+	}
+
+	httpResults, err := s.scanHTTPAPIs(opts, apigatewayv2.New(sess), cwClient, startTime, endTime)
+	if err != nil {
+		logging.Error("Failed to scan HTTP APIs", err, nil)
+		return nil, err
+	}
+
+	return append(results, httpResults...), nil
+}
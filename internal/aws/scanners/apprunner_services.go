@@ -0,0 +1,344 @@
+package scanners
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/apprunner"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// appRunnerCPUThreshold is the average CPU utilization, in percent, below which a running App
+// Runner service that still serves requests is considered idle
+const appRunnerCPUThreshold = 5.0
+
+// AppRunnerServiceScanner scans for App Runner services, including those deployed by AWS Copilot,
+// that served no requests or barely used their CPU, and for services left paused
+type AppRunnerServiceScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&AppRunnerServiceScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *AppRunnerServiceScanner) ArgumentName() string {
+	return "apprunner-services"
+}
+
+// Label implements Scanner interface
+func (s *AppRunnerServiceScanner) Label() string {
+	return "App Runner Services"
+}
+
+// IsGlobal implements Scanner interface
+func (s *AppRunnerServiceScanner) IsGlobal() bool {
+	return false
+}
+
+// parseAppRunnerSize converts an App Runner CPU or memory setting to vCPUs or GB. Settings are
+// either in the unit itself, such as "0.25 vCPU" and "2 GB", or in thousandths of it, such as
+// "1024" and "2048".
+func parseAppRunnerSize(value string) float64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	size, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	if len(fields) == 1 {
+		return size / 1024
+	}
+	return size
+}
+
+// getMetric returns the sum of a statistic's daily datapoints for a service, and the number of
+// datapoints
+func (s *AppRunnerServiceScanner) getMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, service *apprunner.Service, metricName, statistic string, startTime, endTime time.Time) (float64, int, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/AppRunner"),
+		MetricName: aws.String(metricName),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("ServiceName"),
+				Value: service.ServiceName,
+			},
+			{
+				Name:  aws.String("ServiceID"),
+				Value: service.ServiceId,
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String(statistic)},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+	}
+
+	var total float64
+	for _, dp := range output.Datapoints {
+		switch statistic {
+		case "Sum":
+			total += aws.Float64Value(dp.Sum)
+		case "Average":
+			total += aws.Float64Value(dp.Average)
+		}
+	}
+	return total, len(output.Datapoints), nil
+}
+
+// getMinInstances returns the number of instances a service's auto scaling configuration keeps
+// provisioned, defaulting to 1 when it can't be described
+func (s *AppRunnerServiceScanner) getMinInstances(opts awslib.ScanOptions, client *apprunner.AppRunner, service *apprunner.Service, cache map[string]int64) int64 {
+	if service.AutoScalingConfigurationSummary == nil {
+		return 1
+	}
+	arn := aws.StringValue(service.AutoScalingConfigurationSummary.AutoScalingConfigurationArn)
+	if minSize, ok := cache[arn]; ok {
+		return minSize
+	}
+
+	minSize := int64(1)
+	output, err := client.DescribeAutoScalingConfigurationWithContext(opts.Context(), &apprunner.DescribeAutoScalingConfigurationInput{
+		AutoScalingConfigurationArn: aws.String(arn),
+	})
+	if err != nil {
+		logging.Debug("Failed to describe App Runner auto scaling configuration", map[string]interface{}{
+			"auto_scaling_configuration_arn": arn,
+			"error":                          err.Error(),
+		})
+	} else if output.AutoScalingConfiguration != nil && aws.Int64Value(output.AutoScalingConfiguration.MinSize) > 0 {
+		minSize = aws.Int64Value(output.AutoScalingConfiguration.MinSize)
+	}
+	cache[arn] = minSize
+	return minSize
+}
+
+// getTags returns the tags of a service
+func (s *AppRunnerServiceScanner) getTags(opts awslib.ScanOptions, client *apprunner.AppRunner, serviceARN string) map[string]string {
+	tags := make(map[string]string)
+	output, err := client.ListTagsForResourceWithContext(opts.Context(), &apprunner.ListTagsForResourceInput{
+		ResourceArn: aws.String(serviceARN),
+	})
+	if err != nil {
+		logging.Debug("Failed to get App Runner service tags", map[string]interface{}{
+			"service_arn": serviceARN,
+			"error":       err.Error(),
+		})
+		return tags
+	}
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
+
+// calculateCost estimates the cost of a service's provisioned instances since it was created.
+// vcpus is 0 for services that served no requests, since idle instances are only billed for memory.
+func (s *AppRunnerServiceScanner) calculateCost(region string, vcpus, memoryGB float64, instanceCount int64, creationTime time.Time) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "AppRunner",
+			Region:        region,
+			CreationTime:  creationTime,
+			VCPUs:         vcpus,
+			MemoryGB:      memoryGB,
+			InstanceCount: instanceCount,
+		})
+		if err == nil {
+			return costBreakdown
+		}
+		logging.Warn("Failed to calculate App Runner cost, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := (0.064*vcpus + 0.007*memoryGB) * float64(instanceCount)
+	hoursRunning := time.Since(creationTime).Hours()
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *AppRunnerServiceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := apprunner.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var summaries []*apprunner.ServiceSummary
+	err = client.ListServicesPagesWithContext(opts.Context(), &apprunner.ListServicesInput{}, func(page *apprunner.ListServicesOutput, lastPage bool) bool {
+		for _, summary := range page.ServiceSummaryList {
+			switch aws.StringValue(summary.Status) {
+			case apprunner.ServiceStatusRunning, apprunner.ServiceStatusPaused:
+				summaries = append(summaries, summary)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list App Runner services", err, nil)
+		return nil, fmt.Errorf("failed to list App Runner services: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+	minInstancesCache := make(map[string]int64)
+
+	for _, summary := range summaries {
+		serviceARN := aws.StringValue(summary.ServiceArn)
+
+		// Services created within the window haven't had the chance to be used yet
+		if aws.TimeValue(summary.CreatedAt).After(startTime) {
+			continue
+		}
+		// Services paused within the window may be resumed soon
+		status := aws.StringValue(summary.Status)
+		if status == apprunner.ServiceStatusPaused && aws.TimeValue(summary.UpdatedAt).After(startTime) {
+			continue
+		}
+
+		description, err := client.DescribeServiceWithContext(opts.Context(), &apprunner.DescribeServiceInput{
+			ServiceArn: aws.String(serviceARN),
+		})
+		if err != nil {
+			logging.Error("Failed to describe App Runner service", err, map[string]interface{}{
+				"service_arn": serviceARN,
+			})
+			continue
+		}
+		service := description.Service
+
+		var cpu, memory string
+		if service.InstanceConfiguration != nil {
+			cpu = aws.StringValue(service.InstanceConfiguration.Cpu)
+			memory = aws.StringValue(service.InstanceConfiguration.Memory)
+		}
+		vcpus := parseAppRunnerSize(cpu)
+		memoryGB := parseAppRunnerSize(memory)
+
+		details := map[string]interface{}{
+			"account_id":  opts.AccountID,
+			"region":      opts.Region,
+			"status":      status,
+			"service_id":  aws.StringValue(service.ServiceId),
+			"service_url": aws.StringValue(service.ServiceUrl),
+			"cpu":         cpu,
+			"memory":      memory,
+			"vcpus":       vcpus,
+			"memory_gb":   memoryGB,
+			"days_unused": opts.DaysUnused,
+		}
+		if source := service.SourceConfiguration; source != nil {
+			details["auto_deployments_enabled"] = aws.BoolValue(source.AutoDeploymentsEnabled)
+			switch {
+			case source.ImageRepository != nil:
+				details["source_type"] = "image"
+				details["image_identifier"] = aws.StringValue(source.ImageRepository.ImageIdentifier)
+				details["image_repository_type"] = aws.StringValue(source.ImageRepository.ImageRepositoryType)
+			case source.CodeRepository != nil:
+				details["source_type"] = "code"
+				details["repository_url"] = aws.StringValue(source.CodeRepository.RepositoryUrl)
+			}
+		}
+
+		tags := s.getTags(opts, client, serviceARN)
+		// Copilot's Request-Driven Web Services are App Runner services tagged with their workload
+		if app, ok := tags["copilot-application"]; ok {
+			details["copilot_application"] = app
+			details["copilot_environment"] = tags["copilot-environment"]
+			details["copilot_service"] = tags["copilot-service"]
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: aws.StringValue(service.ServiceName),
+			ResourceID:   aws.StringValue(service.ServiceId),
+			ARN:          serviceARN,
+			CreatedAt:    service.CreatedAt,
+			Details:      details,
+			Tags:         tags,
+		}
+
+		// Paused services have no provisioned instances, so they aren't billed for compute
+		if status == apprunner.ServiceStatusPaused {
+			result.Reason = fmt.Sprintf("App Runner service has been paused since %s and was never deleted. It is %s old.",
+				aws.TimeValue(service.UpdatedAt).Format("2006-01-02"), utils.FormatTimeDifference(time.Now(), service.CreatedAt))
+			result.Confidence = awslib.ConfidenceHigh
+			results = append(results, result)
+			continue
+		}
+
+		requests, _, err := s.getMetric(opts, cwClient, service, "Requests", "Sum", startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze App Runner service usage", err, map[string]interface{}{
+				"service_arn": serviceARN,
+			})
+			continue
+		}
+
+		billedVCPUs := vcpus
+		if requests == 0 {
+			// Instances that serve no requests are only billed for their provisioned memory
+			billedVCPUs = 0
+			result.Reason = fmt.Sprintf("No requests in the last %d days. App Runner service is %s old.",
+				opts.DaysUnused, utils.FormatTimeDifference(time.Now(), service.CreatedAt))
+			result.Confidence = awslib.ConfidenceMedium
+		} else {
+			cpuTotal, datapoints, err := s.getMetric(opts, cwClient, service, "CPUUtilization", "Average", startTime, endTime)
+			if err != nil {
+				logging.Error("Failed to analyze App Runner service usage", err, map[string]interface{}{
+					"service_arn": serviceARN,
+				})
+				continue
+			}
+			if datapoints == 0 {
+				continue
+			}
+			cpuAvg := cpuTotal / float64(datapoints)
+			if cpuAvg >= appRunnerCPUThreshold {
+				continue
+			}
+			details["avg_cpu_utilization"] = cpuAvg
+			result.Reason = fmt.Sprintf("Very low CPU utilization (%.2f%%) with %.0f requests in the last %d days. App Runner service is %s old.",
+				cpuAvg, requests, opts.DaysUnused, utils.FormatTimeDifference(time.Now(), service.CreatedAt))
+			result.Confidence = awslib.ConfidenceLow
+		}
+
+		minInstances := s.getMinInstances(opts, client, service, minInstancesCache)
+		details["requests"] = requests
+		details["min_instances"] = minInstances
+		result.Cost = map[string]interface{}{
+			"total": s.calculateCost(opts.Region, billedVCPUs, memoryGB, minInstances, aws.TimeValue(service.CreatedAt)),
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,294 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// AuroraServerlessScanner scans for Aurora Serverless clusters that stay scaled up while unused
+type AuroraServerlessScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&AuroraServerlessScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *AuroraServerlessScanner) ArgumentName() string {
+	return "aurora-serverless"
+}
+
+// Label implements Scanner interface
+func (s *AuroraServerlessScanner) Label() string {
+	return "Aurora Serverless Clusters"
+}
+
+// IsGlobal implements Scanner interface
+func (s *AuroraServerlessScanner) IsGlobal() bool {
+	return false
+}
+
+// capacityStats summarizes a cluster's ServerlessDatabaseCapacity over the scan window
+type capacityStats struct {
+	Min     float64
+	Max     float64
+	Average float64
+}
+
+// getCapacityStats returns the observed capacity of a cluster in ACUs, or nil if there are no datapoints
+func (s *AuroraServerlessScanner) getCapacityStats(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, clusterID string, startTime, endTime time.Time) (*capacityStats, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/RDS"),
+		MetricName: aws.String("ServerlessDatabaseCapacity"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("DBClusterIdentifier"),
+				Value: aws.String(clusterID),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Minimum"), aws.String("Maximum"), aws.String("Average")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ServerlessDatabaseCapacity metric: %w", err)
+	}
+	if len(output.Datapoints) == 0 {
+		return nil, nil
+	}
+
+	// Daily minimums and maximums bound the whole window; the average is over days
+	stats := &capacityStats{Min: math.MaxFloat64}
+	for _, dp := range output.Datapoints {
+		stats.Min = math.Min(stats.Min, aws.Float64Value(dp.Minimum))
+		stats.Max = math.Max(stats.Max, aws.Float64Value(dp.Maximum))
+		stats.Average += aws.Float64Value(dp.Average)
+	}
+	stats.Average /= float64(len(output.Datapoints))
+
+	return stats, nil
+}
+
+// getMaxConnections returns the most database connections open at once over the scan window
+func (s *AuroraServerlessScanner) getMaxConnections(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, clusterID string, startTime, endTime time.Time) (float64, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/RDS"),
+		MetricName: aws.String("DatabaseConnections"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("DBClusterIdentifier"),
+				Value: aws.String(clusterID),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Maximum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch DatabaseConnections metric: %w", err)
+	}
+
+	var maxConnections float64
+	for _, dp := range output.Datapoints {
+		maxConnections = math.Max(maxConnections, aws.Float64Value(dp.Maximum))
+	}
+	return maxConnections, nil
+}
+
+// calculateACUCost estimates the cost of running a cluster at the given capacity
+func (s *AuroraServerlessScanner) calculateACUCost(version, engine string, acus float64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "AuroraServerless",
+			ResourceSize:  version,
+			Region:        region,
+			CreationTime:  creationTime,
+			Engine:        engine,
+			CapacityUnits: acus,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to get Aurora Serverless price, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.06 * acus // $0.06 per ACU-hour for v1 in us-east-1
+	if version == "v2" {
+		hourlyRate = 0.12 * acus // $0.12 per ACU-hour for v2 in us-east-1
+	}
+	hoursRunning := time.Since(creationTime).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *AuroraServerlessScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	rdsClient := rds.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var clusters []*rds.DBCluster
+	err = rdsClient.DescribeDBClustersPagesWithContext(opts.Context(), &rds.DescribeDBClustersInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("engine"),
+				Values: aws.StringSlice([]string{"aurora", "aurora-mysql", "aurora-postgresql"}),
+			},
+		},
+	}, func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+		clusters = append(clusters, page.DBClusters...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe Aurora clusters", err, nil)
+		return nil, fmt.Errorf("failed to describe Aurora clusters: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, cluster := range clusters {
+		clusterID := aws.StringValue(cluster.DBClusterIdentifier)
+		creationTime := aws.TimeValue(cluster.ClusterCreateTime)
+
+		// Only running clusters bill for capacity, and new ones don't have a full metric window
+		if aws.StringValue(cluster.Status) != "available" || creationTime.After(startTime) {
+			continue
+		}
+
+		// v1 clusters run in serverless engine mode; v2 clusters are provisioned clusters with a v2 scaling range
+		var version string
+		var minCapacity, maxCapacity, floor float64
+		autoPause := false
+		switch {
+		case aws.StringValue(cluster.EngineMode) == "serverless" && cluster.ScalingConfigurationInfo != nil:
+			version = "v1"
+			minCapacity = float64(aws.Int64Value(cluster.ScalingConfigurationInfo.MinCapacity))
+			maxCapacity = float64(aws.Int64Value(cluster.ScalingConfigurationInfo.MaxCapacity))
+			autoPause = aws.BoolValue(cluster.ScalingConfigurationInfo.AutoPause)
+			// A v1 cluster with auto-pause should reach zero ACUs whenever it is idle
+			floor = minCapacity
+			if autoPause {
+				floor = 0
+			}
+		case cluster.ServerlessV2ScalingConfiguration != nil:
+			version = "v2"
+			minCapacity = aws.Float64Value(cluster.ServerlessV2ScalingConfiguration.MinCapacity)
+			maxCapacity = aws.Float64Value(cluster.ServerlessV2ScalingConfiguration.MaxCapacity)
+			floor = minCapacity
+		default:
+			continue
+		}
+
+		logging.Debug("Analyzing Aurora Serverless cluster", map[string]interface{}{
+			"cluster_id": clusterID,
+			"version":    version,
+		})
+
+		capacity, err := s.getCapacityStats(opts, cwClient, clusterID, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze Aurora Serverless capacity", err, map[string]interface{}{
+				"cluster_id": clusterID,
+			})
+			continue
+		}
+		if capacity == nil || capacity.Min <= floor {
+			continue
+		}
+
+		maxConnections, err := s.getMaxConnections(opts, cwClient, clusterID, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze Aurora Serverless connections", err, map[string]interface{}{
+				"cluster_id": clusterID,
+			})
+			continue
+		}
+
+		var reason, confidence string
+		if version == "v1" {
+			// The cluster may still be in use; it just never got the chance to pause
+			confidence = awslib.ConfidenceLow
+			reason = fmt.Sprintf("Aurora Serverless v1 cluster never scaled down to its floor of %g ACUs in the last %d days (lowest observed capacity %g ACUs)",
+				floor, opts.DaysUnused, capacity.Min)
+			if !autoPause {
+				reason += "; auto-pause is disabled"
+			}
+		} else {
+			// v2 clusters can't pause, so a cluster above its minimum is only waste when nothing connects to it
+			if maxConnections > 0 {
+				continue
+			}
+			confidence = awslib.ConfidenceMedium
+			reason = fmt.Sprintf("Aurora Serverless v2 cluster has had no connections in the last %d days but stayed above its minimum of %g ACUs (lowest observed capacity %g ACUs)",
+				opts.DaysUnused, minCapacity, capacity.Min)
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range cluster.TagList {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		engine := aws.StringValue(cluster.Engine)
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: clusterID,
+			ResourceID:   clusterID,
+			ARN:          aws.StringValue(cluster.DBClusterArn),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       reason,
+			Confidence:   confidence,
+			Details: map[string]interface{}{
+				"account_id":           opts.AccountID,
+				"region":               opts.Region,
+				"serverless_version":   version,
+				"engine":               engine,
+				"engine_version":       aws.StringValue(cluster.EngineVersion),
+				"min_capacity":         minCapacity,
+				"max_capacity":         maxCapacity,
+				"auto_pause":           autoPause,
+				"observed_min_acu":     capacity.Min,
+				"observed_max_acu":     capacity.Max,
+				"observed_average_acu": capacity.Average,
+				"max_connections":      maxConnections,
+				"creation_time":        creationTime,
+				"hours_running":        time.Since(creationTime).Hours(),
+			},
+			Tags: tags,
+			// The cluster is billed for the capacity it actually ran at
+			Cost: map[string]interface{}{
+				"total": s.calculateACUCost(version, engine, capacity.Average, creationTime, opts.Region),
+			},
+		})
+	}
+
+	return results, nil
+}
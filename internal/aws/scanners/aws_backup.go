@@ -0,0 +1,338 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/backup"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/efs"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// AWSBackupScanner scans for AWS Backup recovery points whose source resource no longer exists
+type AWSBackupScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&AWSBackupScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *AWSBackupScanner) ArgumentName() string {
+	return "aws-backup"
+}
+
+// Label implements Scanner interface
+func (s *AWSBackupScanner) Label() string {
+	return "AWS Backup Recovery Points"
+}
+
+// IsGlobal implements Scanner interface
+func (s *AWSBackupScanner) IsGlobal() bool {
+	return false
+}
+
+// backupSourceChecker looks up the resources recovery points were taken from, remembering each answer
+// since a resource usually has many recovery points
+type backupSourceChecker struct {
+	sess    *session.Session
+	opts    awslib.ScanOptions
+	results map[string]bool
+	ec2     *ec2.EC2
+	rds     *rds.RDS
+	dynamo  *dynamodb.DynamoDB
+	efs     *efs.EFS
+	s3      *s3.S3
+}
+
+// exists reports whether the resource behind resourceARN still exists. known is false when that can't
+// be determined, either because the resource type isn't supported or because the resource lives in
+// another account or region, as it does for recovery points copied in from elsewhere.
+func (c *backupSourceChecker) exists(resourceType, resourceARN string) (exists bool, known bool, err error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return false, false, nil
+	}
+	if (parsed.AccountID != "" && parsed.AccountID != c.opts.AccountID) || (parsed.Region != "" && parsed.Region != c.opts.Region) {
+		return false, false, nil
+	}
+	if exists, ok := c.results[resourceARN]; ok {
+		return exists, true, nil
+	}
+
+	// Resource IDs are the last element of the ARN, e.g. volume/vol-123 or db:my-database
+	resourceID := parsed.Resource[strings.LastIndexAny(parsed.Resource, "/:")+1:]
+	ctx := c.opts.Context()
+
+	switch resourceType {
+	case "EBS":
+		if c.ec2 == nil {
+			c.ec2 = ec2.New(c.sess)
+		}
+		_, err = c.ec2.DescribeVolumesWithContext(ctx, &ec2.DescribeVolumesInput{VolumeIds: []*string{aws.String(resourceID)}})
+		exists, err = resourceExists(err, "InvalidVolume.NotFound")
+	case "EC2":
+		if c.ec2 == nil {
+			c.ec2 = ec2.New(c.sess)
+		}
+		var output *ec2.DescribeInstancesOutput
+		output, err = c.ec2.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: []*string{aws.String(resourceID)}})
+		exists, err = resourceExists(err, "InvalidInstanceID.NotFound")
+		// Terminated instances stay visible for a while after they are gone
+		if exists {
+			for _, reservation := range output.Reservations {
+				for _, instance := range reservation.Instances {
+					if instance.State != nil && aws.StringValue(instance.State.Name) == ec2.InstanceStateNameTerminated {
+						exists = false
+					}
+				}
+			}
+		}
+	case "RDS":
+		if c.rds == nil {
+			c.rds = rds.New(c.sess)
+		}
+		_, err = c.rds.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(resourceID)})
+		exists, err = resourceExists(err, rds.ErrCodeDBInstanceNotFoundFault)
+	case "Aurora", "DocumentDB", "Neptune":
+		if c.rds == nil {
+			c.rds = rds.New(c.sess)
+		}
+		_, err = c.rds.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(resourceID)})
+		exists, err = resourceExists(err, rds.ErrCodeDBClusterNotFoundFault)
+	case "DynamoDB":
+		if c.dynamo == nil {
+			c.dynamo = dynamodb.New(c.sess)
+		}
+		_, err = c.dynamo.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(resourceID)})
+		exists, err = resourceExists(err, dynamodb.ErrCodeResourceNotFoundException)
+	case "EFS":
+		if c.efs == nil {
+			c.efs = efs.New(c.sess)
+		}
+		_, err = c.efs.DescribeFileSystemsWithContext(ctx, &efs.DescribeFileSystemsInput{FileSystemId: aws.String(resourceID)})
+		exists, err = resourceExists(err, efs.ErrCodeFileSystemNotFound)
+	case "S3":
+		if c.s3 == nil {
+			c.s3 = s3.New(c.sess)
+		}
+		_, err = c.s3.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(resourceID)})
+		exists, err = resourceExists(err, "NotFound", s3.ErrCodeNoSuchBucket)
+	default:
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+
+	c.results[resourceARN] = exists
+	return exists, true, nil
+}
+
+// resourceExists interprets the error from a describe call, reporting whether the resource exists. Errors
+// other than the given not-found codes are returned as is.
+func resourceExists(err error, notFoundCodes ...string) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		for _, code := range notFoundCodes {
+			if aerr.Code() == code {
+				return false, nil
+			}
+		}
+	}
+	return false, err
+}
+
+// calculateStorageCost estimates the cost of keeping a recovery point
+func (s *AWSBackupScanner) calculateStorageCost(resourceType, storageClass string, sizeGB int64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "AWSBackup",
+			ResourceSize: resourceType,
+			VolumeType:   storageClass,
+			StorageSize:  sizeGB,
+			Region:       region,
+			CreationTime: creationTime,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to get AWS Backup storage price, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	gbMonthRate := 0.05 // $0.05 per GB-month of warm storage
+	if storageClass == "cold" {
+		gbMonthRate = 0.01 // $0.01 per GB-month of cold storage
+	}
+	hourlyRate := gbMonthRate * float64(sizeGB) / 730
+	hoursRunning := time.Since(creationTime).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *AWSBackupScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := backup.New(sess)
+
+	var vaults []*backup.VaultListMember
+	err = client.ListBackupVaultsPagesWithContext(opts.Context(), &backup.ListBackupVaultsInput{},
+		func(page *backup.ListBackupVaultsOutput, lastPage bool) bool {
+			vaults = append(vaults, page.BackupVaultList...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to list backup vaults", err, nil)
+		return nil, fmt.Errorf("failed to list backup vaults: %w", err)
+	}
+
+	var results awslib.ScanResults
+	now := time.Now().UTC()
+	startTime := now.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+	sources := &backupSourceChecker{sess: sess, opts: opts, results: make(map[string]bool)}
+
+	for _, vault := range vaults {
+		vaultName := aws.StringValue(vault.BackupVaultName)
+
+		var recoveryPoints []*backup.RecoveryPointByBackupVault
+		err := client.ListRecoveryPointsByBackupVaultPagesWithContext(opts.Context(), &backup.ListRecoveryPointsByBackupVaultInput{
+			BackupVaultName: aws.String(vaultName),
+			ByCreatedBefore: aws.Time(startTime),
+		}, func(page *backup.ListRecoveryPointsByBackupVaultOutput, lastPage bool) bool {
+			recoveryPoints = append(recoveryPoints, page.RecoveryPoints...)
+			return !lastPage
+		})
+		if err != nil {
+			logging.Error("Failed to list recovery points", err, map[string]interface{}{
+				"backup_vault": vaultName,
+			})
+			continue
+		}
+
+		for _, point := range recoveryPoints {
+			// Recovery points on their way out are already handled by the lifecycle
+			switch aws.StringValue(point.Status) {
+			case backup.RecoveryPointStatusDeleting, backup.RecoveryPointStatusExpired:
+				continue
+			}
+
+			resourceType := aws.StringValue(point.ResourceType)
+			resourceARN := aws.StringValue(point.ResourceArn)
+			exists, known, err := sources.exists(resourceType, resourceARN)
+			if err != nil {
+				logging.Error("Failed to look up recovery point source resource", err, map[string]interface{}{
+					"resource_arn": resourceARN,
+				})
+				continue
+			}
+			if exists || !known {
+				continue
+			}
+
+			recoveryPointARN := aws.StringValue(point.RecoveryPointArn)
+			creationTime := aws.TimeValue(point.CreationDate)
+			sizeBytes := aws.Int64Value(point.BackupSizeInBytes)
+			sizeGB := int64(math.Ceil(float64(sizeBytes) / (1024 * 1024 * 1024)))
+
+			// Lifecycle rules can move recovery points to cheaper cold storage after a while
+			storageClass := "warm"
+			var deleteAt *time.Time
+			if point.CalculatedLifecycle != nil {
+				if moveAt := point.CalculatedLifecycle.MoveToColdStorageAt; moveAt != nil && moveAt.Before(now) {
+					storageClass = "cold"
+				}
+				deleteAt = point.CalculatedLifecycle.DeleteAt
+			}
+
+			reason := fmt.Sprintf("Recovery point is %s old and its source %s resource no longer exists",
+				utils.FormatTimeDifference(now, &creationTime), resourceType)
+			if deleteAt == nil {
+				reason += "; it has no lifecycle and will be kept indefinitely"
+			}
+
+			details := map[string]interface{}{
+				"account_id":          opts.AccountID,
+				"region":              opts.Region,
+				"backup_vault":        vaultName,
+				"source_resource_arn": resourceARN,
+				"source_type":         resourceType,
+				"status":              aws.StringValue(point.Status),
+				"backup_size_bytes":   sizeBytes,
+				"backup_size_gb":      sizeGB,
+				"storage_class":       storageClass,
+				"encrypted":           aws.BoolValue(point.IsEncrypted),
+				"creation_time":       creationTime,
+				"hours_running":       time.Since(creationTime).Hours(),
+			}
+			if point.CreatedBy != nil {
+				details["backup_plan_id"] = aws.StringValue(point.CreatedBy.BackupPlanId)
+			}
+			if deleteAt != nil {
+				details["delete_at"] = deleteAt.Format(time.RFC3339)
+			}
+
+			tags := make(map[string]string)
+			tagsOutput, err := client.ListTagsWithContext(opts.Context(), &backup.ListTagsInput{
+				ResourceArn: aws.String(recoveryPointARN),
+			})
+			if err != nil {
+				logging.Debug("Failed to get recovery point tags", map[string]interface{}{
+					"recovery_point_arn": recoveryPointARN,
+					"error":              err.Error(),
+				})
+			} else {
+				tags = aws.StringValueMap(tagsOutput.Tags)
+			}
+
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: recoveryPointARN[strings.LastIndexAny(recoveryPointARN, "/:")+1:],
+				ResourceID:   recoveryPointARN,
+				ARN:          recoveryPointARN,
+				CreatedAt:    aws.Time(creationTime),
+				Reason:       reason,
+				Confidence:   awslib.ConfidenceMedium,
+				Details:      details,
+				Tags:         tags,
+				Cost: map[string]interface{}{
+					"total": s.calculateStorageCost(resourceType, storageClass, sizeGB, creationTime, opts.Region),
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
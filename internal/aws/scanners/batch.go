@@ -0,0 +1,266 @@
+package scanners
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/batch"
+)
+
+// BatchComputeEnvironmentScanner scans for idle, disabled or over-provisioned Batch compute environments
+type BatchComputeEnvironmentScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&BatchComputeEnvironmentScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *BatchComputeEnvironmentScanner) ArgumentName() string {
+	return "batch-compute-environments"
+}
+
+// Label implements Scanner interface
+func (s *BatchComputeEnvironmentScanner) Label() string {
+	return "Batch Compute Environments"
+}
+
+// IsGlobal implements Scanner interface
+func (s *BatchComputeEnvironmentScanner) IsGlobal() bool {
+	return false
+}
+
+// hasRecentJobs reports whether any job was submitted to a queue since startTime, in any status
+func (s *BatchComputeEnvironmentScanner) hasRecentJobs(opts awslib.ScanOptions, client *batch.Batch, queueArn string, startTime time.Time) (bool, error) {
+	// Filtering on creation time returns jobs in every status, so a single result is enough
+	output, err := client.ListJobsWithContext(opts.Context(), &batch.ListJobsInput{
+		JobQueue:   aws.String(queueArn),
+		MaxResults: aws.Int64(1),
+		Filters: []*batch.KeyValuesPair{
+			{
+				Name:   aws.String("AFTER_CREATED_AT"),
+				Values: []*string{aws.String(strconv.FormatInt(startTime.UnixMilli(), 10))},
+			},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return len(output.JobSummaryList) > 0, nil
+}
+
+// calculateFloorCost estimates the cost of the vCPUs a compute environment keeps running at its minimum
+func (s *BatchComputeEnvironmentScanner) calculateFloorCost(minvCpus int64, region string) *awslib.CostBreakdown {
+	if minvCpus == 0 {
+		return &awslib.CostBreakdown{}
+	}
+
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "BatchvCPU",
+			Region:        region,
+			CreationTime:  time.Now(),
+			InstanceCount: minvCpus,
+		})
+		if err == nil {
+			// Batch doesn't report when an environment was created, so there is no lifetime cost
+			cost.HoursRunning = nil
+			cost.Lifetime = nil
+			return cost
+		}
+		logging.Warn("Failed to get Batch vCPU price, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.048 * float64(minvCpus) // m5.large in us-east-1, per vCPU
+
+	return &awslib.CostBreakdown{
+		HourlyRate:  hourlyRate,
+		DailyRate:   hourlyRate * 24,
+		MonthlyRate: hourlyRate * 24 * 30,
+		YearlyRate:  hourlyRate * 24 * 365,
+	}
+}
+
+// Scan implements Scanner interface
+func (s *BatchComputeEnvironmentScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := batch.New(sess)
+
+	var environments []*batch.ComputeEnvironmentDetail
+	err = client.DescribeComputeEnvironmentsPagesWithContext(opts.Context(), &batch.DescribeComputeEnvironmentsInput{},
+		func(page *batch.DescribeComputeEnvironmentsOutput, lastPage bool) bool {
+			environments = append(environments, page.ComputeEnvironments...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to describe Batch compute environments", err, nil)
+		return nil, fmt.Errorf("failed to describe Batch compute environments: %w", err)
+	}
+	if len(environments) == 0 {
+		return nil, nil
+	}
+
+	// Map each compute environment to the job queues that send work to it
+	var queues []*batch.JobQueueDetail
+	err = client.DescribeJobQueuesPagesWithContext(opts.Context(), &batch.DescribeJobQueuesInput{},
+		func(page *batch.DescribeJobQueuesOutput, lastPage bool) bool {
+			queues = append(queues, page.JobQueues...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to describe Batch job queues", err, nil)
+		return nil, fmt.Errorf("failed to describe Batch job queues: %w", err)
+	}
+	queuesByEnvironment := make(map[string][]*batch.JobQueueDetail)
+	for _, queue := range queues {
+		for _, order := range queue.ComputeEnvironmentOrder {
+			envArn := aws.StringValue(order.ComputeEnvironment)
+			queuesByEnvironment[envArn] = append(queuesByEnvironment[envArn], queue)
+		}
+	}
+
+	var results awslib.ScanResults
+	startTime := time.Now().UTC().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	// Queues can feed several environments, so each is only checked for jobs once
+	queueUsed := make(map[string]bool)
+	queueChecked := make(map[string]bool)
+
+	for _, env := range environments {
+		envName := aws.StringValue(env.ComputeEnvironmentName)
+		envArn := aws.StringValue(env.ComputeEnvironmentArn)
+
+		// Environments being created or deleted will change on their own
+		switch aws.StringValue(env.Status) {
+		case batch.CEStatusCreating, batch.CEStatusDeleting, batch.CEStatusDeleted:
+			continue
+		}
+
+		var resourceType string
+		var minvCpus, maxvCpus, desiredvCpus int64
+		var instanceTypes []string
+		if env.ComputeResources != nil {
+			resourceType = aws.StringValue(env.ComputeResources.Type)
+			minvCpus = aws.Int64Value(env.ComputeResources.MinvCpus)
+			maxvCpus = aws.Int64Value(env.ComputeResources.MaxvCpus)
+			desiredvCpus = aws.Int64Value(env.ComputeResources.DesiredvCpus)
+			instanceTypes = aws.StringValueSlice(env.ComputeResources.InstanceTypes)
+		}
+
+		// Fargate environments have no instances, so they only cost money while jobs run
+		fargate := strings.HasPrefix(resourceType, batch.CRTypeFargate)
+		if fargate {
+			minvCpus = 0
+		}
+
+		var queueNames []string
+		used := false
+		checkFailed := false
+		for _, queue := range queuesByEnvironment[envArn] {
+			queueArn := aws.StringValue(queue.JobQueueArn)
+			queueNames = append(queueNames, aws.StringValue(queue.JobQueueName))
+			if !queueChecked[queueArn] {
+				hasJobs, err := s.hasRecentJobs(opts, client, queueArn, startTime)
+				if err != nil {
+					logging.Error("Failed to check Batch job queue activity", err, map[string]interface{}{
+						"job_queue": aws.StringValue(queue.JobQueueName),
+					})
+					checkFailed = true
+					continue
+				}
+				queueChecked[queueArn] = true
+				queueUsed[queueArn] = hasJobs
+			}
+			if queueUsed[queueArn] {
+				used = true
+			}
+		}
+		sort.Strings(queueNames)
+
+		// Only report an environment as idle when every one of its queues was checked
+		if checkFailed && !used {
+			continue
+		}
+
+		var findingType, reason string
+		confidence := awslib.ConfidenceMedium
+		switch {
+		case aws.StringValue(env.State) == batch.CEStateDisabled:
+			findingType = "disabled"
+			confidence = awslib.ConfidenceHigh
+			reason = "Compute environment is disabled and no longer runs jobs"
+			if minvCpus > 0 {
+				reason += fmt.Sprintf("; its minimum of %d vCPUs is still configured", minvCpus)
+			}
+		case !used:
+			findingType = "idle"
+			if len(queueNames) == 0 {
+				confidence = awslib.ConfidenceHigh
+				reason = "Compute environment is not attached to any job queue, so it can't receive jobs"
+			} else {
+				reason = fmt.Sprintf("No jobs were submitted to the compute environment's job queues in the last %d days", opts.DaysUnused)
+			}
+			if minvCpus > 0 {
+				reason += fmt.Sprintf("; its minimum of %d vCPUs keeps instances running", minvCpus)
+			}
+		case minvCpus > 0:
+			findingType = "vcpu_floor"
+			reason = fmt.Sprintf("Compute environment keeps a minimum of %d vCPUs running even when no jobs are queued; setting minvCpus to 0 lets it scale to zero", minvCpus)
+		default:
+			continue
+		}
+
+		tags := make(map[string]string)
+		for key, value := range env.Tags {
+			tags[key] = aws.StringValue(value)
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: envName,
+			ResourceID:   envName,
+			ARN:          envArn,
+			Reason:       reason,
+			Confidence:   confidence,
+			Details: map[string]interface{}{
+				"account_id":       opts.AccountID,
+				"region":           opts.Region,
+				"finding_type":     findingType,
+				"environment_type": aws.StringValue(env.Type),
+				"resource_type":    resourceType,
+				"state":            aws.StringValue(env.State),
+				"status":           aws.StringValue(env.Status),
+				"min_vcpus":        minvCpus,
+				"max_vcpus":        maxvCpus,
+				"desired_vcpus":    desiredvCpus,
+				"instance_types":   instanceTypes,
+				"job_queues":       queueNames,
+			},
+			Tags: tags,
+			// The floor is the capacity the environment pays for whether or not jobs run
+			Cost: map[string]interface{}{
+				"total": s.calculateFloorCost(minvCpus, opts.Region),
+			},
+		})
+	}
+
+	return results, nil
+}
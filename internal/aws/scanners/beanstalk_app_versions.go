@@ -0,0 +1,205 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elasticbeanstalk"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// beanstalkVersionQuota is the default number of application versions AWS allows per region
+const beanstalkVersionQuota = 1000
+
+// BeanstalkAppVersionScanner scans for old Elastic Beanstalk application versions that no
+// environment runs
+type BeanstalkAppVersionScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&BeanstalkAppVersionScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *BeanstalkAppVersionScanner) ArgumentName() string {
+	return "beanstalk-app-versions"
+}
+
+// Label implements Scanner interface
+func (s *BeanstalkAppVersionScanner) Label() string {
+	return "Beanstalk Application Versions"
+}
+
+// IsGlobal implements Scanner interface
+func (s *BeanstalkAppVersionScanner) IsGlobal() bool {
+	return false
+}
+
+// deployedVersions returns the application versions running in any environment, keyed by
+// application name and version label
+func (s *BeanstalkAppVersionScanner) deployedVersions(opts awslib.ScanOptions, client *elasticbeanstalk.ElasticBeanstalk) (map[string]bool, error) {
+	deployed := make(map[string]bool)
+	input := &elasticbeanstalk.DescribeEnvironmentsInput{
+		IncludeDeleted: aws.Bool(false),
+	}
+	for {
+		output, err := client.DescribeEnvironmentsWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe environments: %w", err)
+		}
+		for _, environment := range output.Environments {
+			deployed[aws.StringValue(environment.ApplicationName)+"/"+aws.StringValue(environment.VersionLabel)] = true
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			return deployed, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// getSourceBundleSize returns the size of a version's source bundle in bytes
+func (s *BeanstalkAppVersionScanner) getSourceBundleSize(opts awslib.ScanOptions, client *s3.S3, bundle *elasticbeanstalk.S3Location) (int64, error) {
+	output, err := client.HeadObjectWithContext(opts.Context(), &s3.HeadObjectInput{
+		Bucket: bundle.S3Bucket,
+		Key:    bundle.S3Key,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get source bundle s3://%s/%s: %w", aws.StringValue(bundle.S3Bucket), aws.StringValue(bundle.S3Key), err)
+	}
+	return aws.Int64Value(output.ContentLength), nil
+}
+
+// calculateBundleCost estimates the cost of storing a source bundle in S3 Standard
+func (s *BeanstalkAppVersionScanner) calculateBundleCost(sizeBytes int64, createdAt time.Time, region string) *awslib.CostBreakdown {
+	sizeGB := float64(sizeBytes) / (1024 * 1024 * 1024)
+
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "S3Storage",
+			ResourceSize: sizeGB,
+			Region:       region,
+			CreationTime: createdAt,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to calculate source bundle cost, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.023 * sizeGB / 730 // $0.023 per GB-month in us-east-1
+	hoursRunning := time.Since(createdAt).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *BeanstalkAppVersionScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	ebClient := elasticbeanstalk.New(sess)
+	s3Client := s3.New(sess)
+
+	deployed, err := s.deployedVersions(opts, ebClient)
+	if err != nil {
+		logging.Error("Failed to describe Elastic Beanstalk environments", err, nil)
+		return nil, err
+	}
+
+	var versions []*elasticbeanstalk.ApplicationVersionDescription
+	input := &elasticbeanstalk.DescribeApplicationVersionsInput{
+		MaxRecords: aws.Int64(1000),
+	}
+	for {
+		output, err := ebClient.DescribeApplicationVersionsWithContext(opts.Context(), input)
+		if err != nil {
+			logging.Error("Failed to describe Elastic Beanstalk application versions", err, nil)
+			return nil, fmt.Errorf("failed to describe application versions: %w", err)
+		}
+		versions = append(versions, output.ApplicationVersions...)
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var results awslib.ScanResults
+	cutoff := time.Now().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, version := range versions {
+		applicationName := aws.StringValue(version.ApplicationName)
+		versionLabel := aws.StringValue(version.VersionLabel)
+		versionKey := applicationName + "/" + versionLabel
+
+		if deployed[versionKey] || !aws.TimeValue(version.DateCreated).Before(cutoff) {
+			continue
+		}
+
+		details := map[string]interface{}{
+			"account_id":           opts.AccountID,
+			"region":               opts.Region,
+			"application_name":     applicationName,
+			"version_label":        versionLabel,
+			"status":               aws.StringValue(version.Status),
+			"description":          aws.StringValue(version.Description),
+			"region_version_count": len(versions),
+			"version_quota":        beanstalkVersionQuota,
+		}
+
+		// Versions built from CodeCommit or CodeBuild may have no bundle of their own
+		var sizeBytes int64
+		if bundle := version.SourceBundle; bundle != nil && aws.StringValue(bundle.S3Bucket) != "" {
+			details["source_bundle_bucket"] = aws.StringValue(bundle.S3Bucket)
+			details["source_bundle_key"] = aws.StringValue(bundle.S3Key)
+			sizeBytes, err = s.getSourceBundleSize(opts, s3Client, bundle)
+			if err != nil {
+				// The bundle may have been deleted or live in a bucket in another region
+				logging.Debug("Failed to get source bundle size", map[string]interface{}{
+					"application_name": applicationName,
+					"version_label":    versionLabel,
+					"error":            err.Error(),
+				})
+			} else {
+				details["source_bundle_size_bytes"] = sizeBytes
+			}
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: versionLabel,
+			ResourceID:   versionKey,
+			ARN:          aws.StringValue(version.ApplicationVersionArn),
+			CreatedAt:    version.DateCreated,
+			Reason: fmt.Sprintf("Application version is %s old and not deployed to any environment. Its source bundle is still billed for S3 storage, and it counts towards the limit of %d versions per region (%d used), beyond which new deployments fail.",
+				utils.FormatTimeDifference(time.Now(), version.DateCreated), beanstalkVersionQuota, len(versions)),
+			Confidence: awslib.ConfidenceMedium,
+			Details:    details,
+			Cost: map[string]interface{}{
+				"total": s.calculateBundleCost(sizeBytes, aws.TimeValue(version.DateCreated), opts.Region),
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,210 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CapacityReservationScanner scans for On-Demand Capacity Reservations holding capacity that no
+// instance has used
+type CapacityReservationScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&CapacityReservationScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *CapacityReservationScanner) ArgumentName() string {
+	return "capacity-reservations"
+}
+
+// Label implements Scanner interface
+func (s *CapacityReservationScanner) Label() string {
+	return "Capacity Reservations"
+}
+
+// IsGlobal implements Scanner interface
+func (s *CapacityReservationScanner) IsGlobal() bool {
+	return false
+}
+
+// getMinAvailableInstances returns the lowest number of unused instances a reservation reported
+// between startTime and endTime, which is the capacity that went unused for the whole period. It
+// returns -1 when the reservation reported no datapoints.
+func (s *CapacityReservationScanner) getMinAvailableInstances(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, reservationID string, startTime, endTime time.Time) (float64, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/EC2CapacityReservations"),
+		MetricName: aws.String("AvailableInstanceCount"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("CapacityReservationId"),
+				Value: aws.String(reservationID),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Minimum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch AvailableInstanceCount metric: %w", err)
+	}
+	if len(output.Datapoints) == 0 {
+		return -1, nil
+	}
+
+	minAvailable := math.MaxFloat64
+	for _, dp := range output.Datapoints {
+		minAvailable = math.Min(minAvailable, aws.Float64Value(dp.Minimum))
+	}
+	return minAvailable, nil
+}
+
+// calculateUnusedCost estimates the cost of a reservation's unused instances. It returns nil when
+// there is no price for the instance type.
+func (s *CapacityReservationScanner) calculateUnusedCost(instanceType string, unused int64, startDate time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
+
+	cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+		ResourceType:  "EC2CapacityReservation",
+		ResourceSize:  instanceType,
+		Region:        region,
+		CreationTime:  startDate,
+		InstanceCount: unused,
+	})
+	if err != nil {
+		logging.Warn("Failed to calculate capacity reservation cost", map[string]interface{}{
+			"region":        region,
+			"instance_type": instanceType,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+	return cost
+}
+
+// Scan implements Scanner interface
+func (s *CapacityReservationScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := ec2.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var reservations []*ec2.CapacityReservation
+	err = client.DescribeCapacityReservationsPagesWithContext(opts.Context(), &ec2.DescribeCapacityReservationsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []*string{aws.String(ec2.CapacityReservationStateActive)},
+			},
+		},
+	}, func(page *ec2.DescribeCapacityReservationsOutput, lastPage bool) bool {
+		reservations = append(reservations, page.CapacityReservations...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe capacity reservations", err, nil)
+		return nil, fmt.Errorf("failed to describe capacity reservations: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, reservation := range reservations {
+		reservationID := aws.StringValue(reservation.CapacityReservationId)
+		instanceType := aws.StringValue(reservation.InstanceType)
+		startDate := aws.TimeValue(reservation.StartDate)
+
+		// Reservations shared from another account are billed to their owner
+		if opts.AccountID != "" && aws.StringValue(reservation.OwnerId) != opts.AccountID {
+			continue
+		}
+		// New reservations don't have a full metric window
+		if startDate.After(startTime) {
+			continue
+		}
+
+		total := aws.Int64Value(reservation.TotalInstanceCount)
+		available := aws.Int64Value(reservation.AvailableInstanceCount)
+
+		// Use the capacity that stayed unused for the whole period, so a reservation that is only
+		// empty right now isn't flagged
+		minAvailable, err := s.getMinAvailableInstances(opts, cwClient, reservationID, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze capacity reservation usage", err, map[string]interface{}{
+				"capacity_reservation_id": reservationID,
+			})
+			continue
+		}
+		unused := available
+		if minAvailable >= 0 && int64(minAvailable) < unused {
+			unused = int64(minAvailable)
+		}
+		if unused <= 0 {
+			continue
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range reservation.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		details := map[string]interface{}{
+			"account_id":             opts.AccountID,
+			"region":                 opts.Region,
+			"instance_type":          instanceType,
+			"availability_zone":      aws.StringValue(reservation.AvailabilityZone),
+			"instance_platform":      aws.StringValue(reservation.InstancePlatform),
+			"tenancy":                aws.StringValue(reservation.Tenancy),
+			"instance_match":         aws.StringValue(reservation.InstanceMatchCriteria),
+			"total_instance_count":   total,
+			"used_instance_count":    total - available,
+			"unused_instance_count":  unused,
+			"end_date_type":          aws.StringValue(reservation.EndDateType),
+			"start_date":             startDate.Format(time.RFC3339),
+			"usage_metrics_reported": minAvailable >= 0,
+		}
+		if reservation.EndDate != nil {
+			details["end_date"] = aws.TimeValue(reservation.EndDate).Format(time.RFC3339)
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: reservationID,
+			ResourceID:   reservationID,
+			ARN:          aws.StringValue(reservation.CapacityReservationArn),
+			CreatedAt:    aws.Time(startDate),
+			Reason: fmt.Sprintf("%d of %d reserved %s instances in %s went unused for the last %d days",
+				unused, total, instanceType, aws.StringValue(reservation.AvailabilityZone), opts.DaysUnused),
+			Confidence: awslib.ConfidenceHigh,
+			Details:    details,
+			Tags:       tags,
+		}
+		if cost := s.calculateUnusedCost(instanceType, unused, startDate, opts.Region); cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,226 @@
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+// failedStackStatuses are the stack statuses a stack stays stuck in until someone deletes it or
+// finishes its rollback
+var failedStackStatuses = map[string]bool{
+	cloudformation.StackStatusCreateFailed:           true,
+	cloudformation.StackStatusRollbackComplete:       true,
+	cloudformation.StackStatusRollbackFailed:         true,
+	cloudformation.StackStatusDeleteFailed:           true,
+	cloudformation.StackStatusUpdateRollbackFailed:   true,
+	cloudformation.StackStatusImportRollbackFailed:   true,
+	cloudformation.StackStatusImportRollbackComplete: true,
+}
+
+// billableStackResourceTypes are the CloudFormation resource types that are billed while they
+// exist, whatever state the stack that created them is in
+var billableStackResourceTypes = map[string]bool{
+	"AWS::AutoScaling::AutoScalingGroup":        true,
+	"AWS::DocDB::DBCluster":                     true,
+	"AWS::DynamoDB::Table":                      true,
+	"AWS::EC2::EIP":                             true,
+	"AWS::EC2::Instance":                        true,
+	"AWS::EC2::NatGateway":                      true,
+	"AWS::EC2::TransitGateway":                  true,
+	"AWS::EC2::VPCEndpoint":                     true,
+	"AWS::EC2::Volume":                          true,
+	"AWS::ECS::Service":                         true,
+	"AWS::EFS::FileSystem":                      true,
+	"AWS::EKS::Cluster":                         true,
+	"AWS::ElastiCache::CacheCluster":            true,
+	"AWS::ElastiCache::ReplicationGroup":        true,
+	"AWS::ElasticLoadBalancing::LoadBalancer":   true,
+	"AWS::ElasticLoadBalancingV2::LoadBalancer": true,
+	"AWS::Elasticsearch::Domain":                true,
+	"AWS::Kinesis::Stream":                      true,
+	"AWS::KMS::Key":                             true,
+	"AWS::MSK::Cluster":                         true,
+	"AWS::Neptune::DBCluster":                   true,
+	"AWS::OpenSearchService::Domain":            true,
+	"AWS::RDS::DBCluster":                       true,
+	"AWS::RDS::DBInstance":                      true,
+	"AWS::Redshift::Cluster":                    true,
+	"AWS::S3::Bucket":                           true,
+	"AWS::SecretsManager::Secret":               true,
+	"AWS::SageMaker::Endpoint":                  true,
+	"AWS::SageMaker::NotebookInstance":          true,
+}
+
+// CloudFormationStackScanner scans for CloudFormation stacks stuck in a failed or rolled back
+// state, and for stacks whose resources have drifted from their template
+type CloudFormationStackScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&CloudFormationStackScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *CloudFormationStackScanner) ArgumentName() string {
+	return "cloudformation-stacks"
+}
+
+// Label implements Scanner interface
+func (s *CloudFormationStackScanner) Label() string {
+	return "CloudFormation Stacks"
+}
+
+// IsGlobal implements Scanner interface
+func (s *CloudFormationStackScanner) IsGlobal() bool {
+	return false
+}
+
+// getStackResources returns how many resources a stack still has and which of them are billable,
+// as "Type PhysicalID". Resources that were deleted, such as by a rollback, are left out.
+func (s *CloudFormationStackScanner) getStackResources(opts awslib.ScanOptions, client *cloudformation.CloudFormation, stackName string) (int, []string, error) {
+	count := 0
+	var billable []string
+	err := client.ListStackResourcesPagesWithContext(opts.Context(), &cloudformation.ListStackResourcesInput{
+		StackName: aws.String(stackName),
+	}, func(page *cloudformation.ListStackResourcesOutput, lastPage bool) bool {
+		for _, resource := range page.StackResourceSummaries {
+			if aws.StringValue(resource.ResourceStatus) == cloudformation.ResourceStatusDeleteComplete {
+				continue
+			}
+			count++
+			resourceType := aws.StringValue(resource.ResourceType)
+			if physicalID := aws.StringValue(resource.PhysicalResourceId); physicalID != "" && billableStackResourceTypes[resourceType] {
+				billable = append(billable, resourceType+" "+physicalID)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to list stack resources: %w", err)
+	}
+	return count, billable, nil
+}
+
+// Scan implements Scanner interface
+func (s *CloudFormationStackScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := cloudformation.New(sess)
+
+	// DescribeStacks leaves out deleted stacks and, unlike ListStacks, includes their tags
+	var stacks []*cloudformation.Stack
+	err = client.DescribeStacksPagesWithContext(opts.Context(), &cloudformation.DescribeStacksInput{}, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+		stacks = append(stacks, page.Stacks...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe CloudFormation stacks", err, nil)
+		return nil, fmt.Errorf("failed to describe CloudFormation stacks: %w", err)
+	}
+
+	var results awslib.ScanResults
+	cutoff := time.Now().UTC().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, stack := range stacks {
+		// Nested stacks are cleaned up with their root stack, which is reported instead
+		if stack.ParentId != nil {
+			continue
+		}
+
+		stackName := aws.StringValue(stack.StackName)
+		status := aws.StringValue(stack.StackStatus)
+		lastChanged := aws.TimeValue(stack.CreationTime)
+		if stack.LastUpdatedTime != nil {
+			lastChanged = aws.TimeValue(stack.LastUpdatedTime)
+		}
+
+		var driftStatus string
+		var driftChecked *time.Time
+		if stack.DriftInformation != nil {
+			driftStatus = aws.StringValue(stack.DriftInformation.StackDriftStatus)
+			driftChecked = stack.DriftInformation.LastCheckTimestamp
+		}
+
+		// Drift is only known from the last time drift detection ran; it isn't started here
+		var findingType, reason, confidence string
+		switch {
+		case failedStackStatuses[status] && lastChanged.Before(cutoff):
+			findingType = "failed"
+			confidence = awslib.ConfidenceHigh
+			reason = fmt.Sprintf("Stack has been in %s for %d days.", status, int(time.Since(lastChanged).Hours()/24))
+			if statusReason := aws.StringValue(stack.StackStatusReason); statusReason != "" {
+				reason += " " + strings.TrimSuffix(statusReason, ".") + "."
+			}
+		case driftStatus == cloudformation.StackDriftStatusDrifted:
+			findingType = "drifted"
+			// Drift makes a stack harder to manage, but the drifted resources may be exactly what's wanted
+			confidence = awslib.ConfidenceLow
+			reason = "Stack resources have drifted from its template."
+		default:
+			continue
+		}
+
+		resourceCount, billable, err := s.getStackResources(opts, client, stackName)
+		if err != nil {
+			logging.Error("Failed to analyze CloudFormation stack resources", err, map[string]interface{}{
+				"stack_name": stackName,
+			})
+			continue
+		}
+		if findingType == "failed" && len(billable) > 0 {
+			reason += fmt.Sprintf(" It still owns %d billable resources.", len(billable))
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range stack.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		details := map[string]interface{}{
+			"account_id":         opts.AccountID,
+			"region":             opts.Region,
+			"finding_type":       findingType,
+			"stack_status":       status,
+			"status_reason":      aws.StringValue(stack.StackStatusReason),
+			"drift_status":       driftStatus,
+			"resource_count":     resourceCount,
+			"billable_resources": billable,
+			"last_changed":       lastChanged.Format(time.RFC3339),
+		}
+		if driftChecked != nil {
+			details["drift_checked_at"] = aws.TimeValue(driftChecked).Format(time.RFC3339)
+		}
+		if stack.RoleARN != nil {
+			details["role_arn"] = aws.StringValue(stack.RoleARN)
+		}
+
+		// The stack itself is free; what it costs is the billable resources it still owns
+		stackID := aws.StringValue(stack.StackId)
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: stackName,
+			ResourceID:   stackName,
+			ARN:          stackID,
+			CreatedAt:    stack.CreationTime,
+			Reason:       reason,
+			Confidence:   confidence,
+			Details:      details,
+			Tags:         tags,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,210 @@
+package scanners
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudhsmv2"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// CloudHSMClusterScanner scans for active CloudHSM clusters that no client has opened a session
+// on. Every HSM in a cluster is billed by the hour, so a single forgotten cluster is expensive.
+type CloudHSMClusterScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&CloudHSMClusterScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *CloudHSMClusterScanner) ArgumentName() string {
+	return "cloudhsm-clusters"
+}
+
+// Label implements Scanner interface
+func (s *CloudHSMClusterScanner) Label() string {
+	return "CloudHSM Clusters"
+}
+
+// IsGlobal implements Scanner interface
+func (s *CloudHSMClusterScanner) IsGlobal() bool {
+	return false
+}
+
+// getMaxSessionCount returns the highest number of client sessions open on a cluster's HSMs
+// between startTime and endTime
+func (s *CloudHSMClusterScanner) getMaxSessionCount(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, clusterID string, startTime, endTime time.Time) (float64, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/CloudHSM"),
+		MetricName: aws.String("HsmSessionCount"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("ClusterId"),
+				Value: aws.String(clusterID),
+			},
+			{
+				Name:  aws.String("Region"),
+				Value: aws.String(opts.Region),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Maximum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch HsmSessionCount metric: %w", err)
+	}
+
+	var maxSessions float64
+	for _, dp := range output.Datapoints {
+		if sessions := aws.Float64Value(dp.Maximum); sessions > maxSessions {
+			maxSessions = sessions
+		}
+	}
+	return maxSessions, nil
+}
+
+// calculateClusterCost estimates the cost of a cluster's HSMs since the cluster was created
+func (s *CloudHSMClusterScanner) calculateClusterCost(region, hsmType string, hsmCount int64, creationTime time.Time) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "CloudHSM",
+			ResourceSize:  hsmType,
+			Region:        region,
+			CreationTime:  creationTime,
+			InstanceCount: hsmCount,
+		})
+		if err == nil && costBreakdown.HourlyRate != 0 {
+			return costBreakdown
+		}
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable, fails or returns zero
+	hourlyRate := 1.45 * float64(hsmCount) // Default hourly rate per HSM as fallback
+	hoursRunning := time.Since(creationTime).Hours()
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *CloudHSMClusterScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	hsmClient := cloudhsmv2.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var clusters []*cloudhsmv2.Cluster
+	err = hsmClient.DescribeClustersPagesWithContext(opts.Context(), &cloudhsmv2.DescribeClustersInput{
+		Filters: map[string][]*string{
+			"states": {aws.String(cloudhsmv2.ClusterStateActive)},
+		},
+	}, func(page *cloudhsmv2.DescribeClustersOutput, lastPage bool) bool {
+		clusters = append(clusters, page.Clusters...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe CloudHSM clusters", err, nil)
+		return nil, fmt.Errorf("failed to describe CloudHSM clusters: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, cluster := range clusters {
+		clusterID := aws.StringValue(cluster.ClusterId)
+
+		// Clusters created within the window haven't had the chance to be used yet
+		if aws.TimeValue(cluster.CreateTimestamp).After(startTime) {
+			continue
+		}
+
+		// A cluster without HSMs isn't billed by the hour
+		hsmCount := int64(len(cluster.Hsms))
+		if hsmCount == 0 {
+			continue
+		}
+
+		maxSessions, err := s.getMaxSessionCount(opts, cwClient, clusterID, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze CloudHSM cluster usage", err, map[string]interface{}{
+				"cluster_id": clusterID,
+			})
+			continue
+		}
+		if maxSessions > 0 {
+			continue
+		}
+
+		var hsmIDs []string
+		for _, hsm := range cluster.Hsms {
+			hsmIDs = append(hsmIDs, aws.StringValue(hsm.HsmId))
+		}
+		sort.Strings(hsmIDs)
+
+		// Subnets the cluster can place HSMs in, by Availability Zone
+		subnets := make(map[string]string)
+		for availabilityZone, subnetID := range cluster.SubnetMapping {
+			subnets[availabilityZone] = aws.StringValue(subnetID)
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range cluster.TagList {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		clusterName := tags["Name"]
+		if clusterName == "" {
+			clusterName = clusterID
+		}
+
+		hsmType := aws.StringValue(cluster.HsmType)
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: clusterName,
+			ResourceID:   clusterID,
+			ARN:          awslib.ResourceARN("cloudhsm", opts.Region, opts.AccountID, "cluster/"+clusterID),
+			CreatedAt:    cluster.CreateTimestamp,
+			Reason: fmt.Sprintf("CloudHSM cluster with %d %s HSM(s) had no client sessions in the last %d days. It is %s old and billed for every HSM-hour.",
+				hsmCount, hsmType, opts.DaysUnused, utils.FormatTimeDifference(time.Now(), cluster.CreateTimestamp)),
+			Confidence: awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":    opts.AccountID,
+				"region":        opts.Region,
+				"state":         aws.StringValue(cluster.State),
+				"hsm_type":      hsmType,
+				"hsm_count":     hsmCount,
+				"hsm_ids":       hsmIDs,
+				"vpc_id":        aws.StringValue(cluster.VpcId),
+				"subnets":       subnets,
+				"backup_policy": aws.StringValue(cluster.BackupPolicy),
+				"days_unused":   opts.DaysUnused,
+			},
+			Tags: tags,
+			Cost: map[string]interface{}{
+				"total": s.calculateClusterCost(opts.Region, hsmType, hsmCount, aws.TimeValue(cluster.CreateTimestamp)),
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,403 @@
+package scanners
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/datasync"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// DataSyncTaskScanner scans for DataSync tasks that haven't run recently, along with the
+// locations and agents they leave behind
+type DataSyncTaskScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&DataSyncTaskScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *DataSyncTaskScanner) ArgumentName() string {
+	return "datasync-tasks"
+}
+
+// Label implements Scanner interface
+func (s *DataSyncTaskScanner) Label() string {
+	return "DataSync Tasks"
+}
+
+// IsGlobal implements Scanner interface
+func (s *DataSyncTaskScanner) IsGlobal() bool {
+	return false
+}
+
+// getLastExecution returns the start time of a task's most recent execution, or nil if it has
+// none. Executions aren't listed in order, so each is described until one inside the window
+// shows the task is still in use.
+func (s *DataSyncTaskScanner) getLastExecution(opts awslib.ScanOptions, client *datasync.DataSync, taskArn string, cutoff time.Time) (*time.Time, error) {
+	var executionArns []string
+	err := client.ListTaskExecutionsPagesWithContext(opts.Context(), &datasync.ListTaskExecutionsInput{
+		TaskArn: aws.String(taskArn),
+	}, func(page *datasync.ListTaskExecutionsOutput, lastPage bool) bool {
+		for _, execution := range page.TaskExecutions {
+			executionArns = append(executionArns, aws.StringValue(execution.TaskExecutionArn))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task executions: %w", err)
+	}
+
+	var lastStarted *time.Time
+	for _, executionArn := range executionArns {
+		execution, err := client.DescribeTaskExecutionWithContext(opts.Context(), &datasync.DescribeTaskExecutionInput{
+			TaskExecutionArn: aws.String(executionArn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe task execution %s: %w", executionArn, err)
+		}
+		if execution.StartTime == nil {
+			continue
+		}
+		if lastStarted == nil || execution.StartTime.After(*lastStarted) {
+			lastStarted = execution.StartTime
+		}
+		if lastStarted.After(cutoff) {
+			break
+		}
+	}
+	return lastStarted, nil
+}
+
+// getLocationAgents returns the ARNs of the agents a location transfers through. Only on-premises
+// and other self-managed storage locations use agents; the location type is taken from its URI.
+func (s *DataSyncTaskScanner) getLocationAgents(opts awslib.ScanOptions, client *datasync.DataSync, locationArn, locationURI string) ([]string, error) {
+	var agentArns []*string
+	switch {
+	case strings.HasPrefix(locationURI, "nfs://"):
+		output, err := client.DescribeLocationNfsWithContext(opts.Context(), &datasync.DescribeLocationNfsInput{LocationArn: aws.String(locationArn)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe NFS location: %w", err)
+		}
+		if output.OnPremConfig != nil {
+			agentArns = output.OnPremConfig.AgentArns
+		}
+	case strings.HasPrefix(locationURI, "smb://"):
+		output, err := client.DescribeLocationSmbWithContext(opts.Context(), &datasync.DescribeLocationSmbInput{LocationArn: aws.String(locationArn)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe SMB location: %w", err)
+		}
+		agentArns = output.AgentArns
+	case strings.HasPrefix(locationURI, "hdfs://"):
+		output, err := client.DescribeLocationHdfsWithContext(opts.Context(), &datasync.DescribeLocationHdfsInput{LocationArn: aws.String(locationArn)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe HDFS location: %w", err)
+		}
+		agentArns = output.AgentArns
+	case strings.HasPrefix(locationURI, "object-storage://"):
+		output, err := client.DescribeLocationObjectStorageWithContext(opts.Context(), &datasync.DescribeLocationObjectStorageInput{LocationArn: aws.String(locationArn)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe object storage location: %w", err)
+		}
+		agentArns = output.AgentArns
+	}
+	return aws.StringValueSlice(agentArns), nil
+}
+
+// getAgentInstances returns the EC2 instances launched from DataSync agent AMIs, keyed by
+// instance ID and Name tag so they can be matched to the agents they were activated as
+func (s *DataSyncTaskScanner) getAgentInstances(opts awslib.ScanOptions, client *ec2.EC2) (map[string]*ec2.Instance, error) {
+	instancesByImage := make(map[string][]*ec2.Instance)
+	err := client.DescribeInstancesPagesWithContext(opts.Context(), &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{"pending", "running", "stopping", "stopped"}),
+			},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				imageID := aws.StringValue(instance.ImageId)
+				instancesByImage[imageID] = append(instancesByImage[imageID], instance)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instances: %w", err)
+	}
+
+	imageIDs := make([]string, 0, len(instancesByImage))
+	for imageID := range instancesByImage {
+		imageIDs = append(imageIDs, imageID)
+	}
+	sort.Strings(imageIDs)
+
+	agentInstances := make(map[string]*ec2.Instance)
+	for start := 0; start < len(imageIDs); start += 200 {
+		end := start + 200
+		if end > len(imageIDs) {
+			end = len(imageIDs)
+		}
+		// Filtering on image-id rather than passing ImageIds tolerates AMIs that were deregistered
+		output, err := client.DescribeImagesWithContext(opts.Context(), &ec2.DescribeImagesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("image-id"),
+					Values: aws.StringSlice(imageIDs[start:end]),
+				},
+				{
+					Name:   aws.String("name"),
+					Values: aws.StringSlice([]string{"aws-datasync-*"}),
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe images: %w", err)
+		}
+		for _, image := range output.Images {
+			for _, instance := range instancesByImage[aws.StringValue(image.ImageId)] {
+				agentInstances[aws.StringValue(instance.InstanceId)] = instance
+				for _, tag := range instance.Tags {
+					if aws.StringValue(tag.Key) == "Name" && aws.StringValue(tag.Value) != "" {
+						agentInstances[aws.StringValue(tag.Value)] = instance
+					}
+				}
+			}
+		}
+	}
+	return agentInstances, nil
+}
+
+// getTags returns a task's tags
+func (s *DataSyncTaskScanner) getTags(opts awslib.ScanOptions, client *datasync.DataSync, taskArn string) map[string]string {
+	tags := make(map[string]string)
+	err := client.ListTagsForResourcePagesWithContext(opts.Context(), &datasync.ListTagsForResourceInput{
+		ResourceArn: aws.String(taskArn),
+	}, func(page *datasync.ListTagsForResourceOutput, lastPage bool) bool {
+		for _, tag := range page.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Debug("Failed to list DataSync task tags", map[string]interface{}{
+			"task_arn": taskArn,
+			"error":    err.Error(),
+		})
+	}
+	return tags
+}
+
+// Scan implements Scanner interface
+func (s *DataSyncTaskScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := datasync.New(sess)
+	ec2Client := ec2.New(sess)
+
+	var tasks []*datasync.TaskListEntry
+	err = client.ListTasksPagesWithContext(opts.Context(), &datasync.ListTasksInput{}, func(page *datasync.ListTasksOutput, lastPage bool) bool {
+		tasks = append(tasks, page.Tasks...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list DataSync tasks", err, nil)
+		return nil, fmt.Errorf("failed to list DataSync tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	locationURIs := make(map[string]string)
+	err = client.ListLocationsPagesWithContext(opts.Context(), &datasync.ListLocationsInput{}, func(page *datasync.ListLocationsOutput, lastPage bool) bool {
+		for _, location := range page.Locations {
+			locationURIs[aws.StringValue(location.LocationArn)] = aws.StringValue(location.LocationUri)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list DataSync locations", err, nil)
+		return nil, fmt.Errorf("failed to list DataSync locations: %w", err)
+	}
+
+	// Locations and agents are often shared between tasks, so each is only described once
+	locationAgents := make(map[string][]string)
+	agents := make(map[string]map[string]interface{})
+	var agentInstances map[string]*ec2.Instance
+
+	describeAgent := func(agentArn string) map[string]interface{} {
+		if agent, ok := agents[agentArn]; ok {
+			return agent
+		}
+		agent := map[string]interface{}{"agent_arn": agentArn}
+		agents[agentArn] = agent
+
+		output, err := client.DescribeAgentWithContext(opts.Context(), &datasync.DescribeAgentInput{AgentArn: aws.String(agentArn)})
+		if err != nil {
+			logging.Warn("Failed to describe DataSync agent", map[string]interface{}{
+				"agent_arn": agentArn,
+				"error":     err.Error(),
+			})
+			return agent
+		}
+		agentName := aws.StringValue(output.Name)
+		agent["name"] = agentName
+		agent["status"] = aws.StringValue(output.Status)
+		agent["endpoint_type"] = aws.StringValue(output.EndpointType)
+		if output.LastConnectionTime != nil {
+			agent["last_connection_time"] = output.LastConnectionTime.Format(time.RFC3339)
+		}
+
+		// Agents deployed on EC2 keep their instance running and billed; DataSync doesn't record
+		// the instance, so it is matched by the agent's name
+		if agentInstances == nil {
+			agentInstances, err = s.getAgentInstances(opts, ec2Client)
+			if err != nil {
+				logging.Warn("Failed to look up DataSync agent instances", map[string]interface{}{
+					"error": err.Error(),
+				})
+				agentInstances = make(map[string]*ec2.Instance)
+			}
+		}
+		if instance, ok := agentInstances[agentName]; ok {
+			agent["instance_id"] = aws.StringValue(instance.InstanceId)
+			agent["instance_type"] = aws.StringValue(instance.InstanceType)
+			if instance.State != nil {
+				agent["instance_state"] = aws.StringValue(instance.State.Name)
+			}
+		}
+		return agent
+	}
+
+	var results awslib.ScanResults
+	cutoff := time.Now().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, entry := range tasks {
+		taskArn := aws.StringValue(entry.TaskArn)
+
+		task, err := client.DescribeTaskWithContext(opts.Context(), &datasync.DescribeTaskInput{TaskArn: aws.String(taskArn)})
+		if err != nil {
+			logging.Error("Failed to describe DataSync task", err, map[string]interface{}{
+				"task_arn": taskArn,
+			})
+			continue
+		}
+
+		// Tasks that are running, or were created within the window, are in use
+		status := aws.StringValue(task.Status)
+		if status == datasync.TaskStatusQueued || status == datasync.TaskStatusRunning || aws.StringValue(task.CurrentTaskExecutionArn) != "" {
+			continue
+		}
+		if aws.TimeValue(task.CreationTime).After(cutoff) {
+			continue
+		}
+
+		lastExecution, err := s.getLastExecution(opts, client, taskArn, cutoff)
+		if err != nil {
+			logging.Error("Failed to analyze DataSync task usage", err, map[string]interface{}{
+				"task_arn": taskArn,
+			})
+			continue
+		}
+		if lastExecution != nil && lastExecution.After(cutoff) {
+			continue
+		}
+
+		details := map[string]interface{}{
+			"account_id":  opts.AccountID,
+			"region":      opts.Region,
+			"task_status": status,
+			"days_unused": opts.DaysUnused,
+		}
+		if task.Schedule != nil {
+			details["schedule"] = aws.StringValue(task.Schedule.ScheduleExpression)
+		}
+		if arn := aws.StringValue(task.CloudWatchLogGroupArn); arn != "" {
+			details["cloudwatch_log_group_arn"] = arn
+		}
+		reason := fmt.Sprintf("DataSync task has never run since it was created %s ago.", utils.FormatTimeDifference(time.Now(), task.CreationTime))
+		confidence := awslib.ConfidenceHigh
+		if lastExecution != nil {
+			// A task that ran before may be kept for occasional or on-demand transfers
+			confidence = awslib.ConfidenceMedium
+			details["last_execution_time"] = lastExecution.Format(time.RFC3339)
+			reason = fmt.Sprintf("DataSync task hasn't run in the last %d days; it last ran %s ago.", opts.DaysUnused, utils.FormatTimeDifference(time.Now(), lastExecution))
+		}
+
+		var taskAgents []map[string]interface{}
+		seenAgents := make(map[string]bool)
+		for _, location := range []struct{ role, arn string }{
+			{"source", aws.StringValue(task.SourceLocationArn)},
+			{"destination", aws.StringValue(task.DestinationLocationArn)},
+		} {
+			locationURI := locationURIs[location.arn]
+			details[location.role+"_location_arn"] = location.arn
+			details[location.role+"_location_uri"] = locationURI
+
+			agentArns, ok := locationAgents[location.arn]
+			if !ok {
+				agentArns, err = s.getLocationAgents(opts, client, location.arn, locationURI)
+				if err != nil {
+					logging.Warn("Failed to describe DataSync location", map[string]interface{}{
+						"location_arn": location.arn,
+						"error":        err.Error(),
+					})
+				}
+				locationAgents[location.arn] = agentArns
+			}
+			for _, agentArn := range agentArns {
+				if !seenAgents[agentArn] {
+					seenAgents[agentArn] = true
+					taskAgents = append(taskAgents, describeAgent(agentArn))
+				}
+			}
+		}
+
+		if len(taskAgents) > 0 {
+			details["agents"] = taskAgents
+
+			var instanceIDs []string
+			for _, agent := range taskAgents {
+				if instanceID, ok := agent["instance_id"].(string); ok {
+					instanceIDs = append(instanceIDs, instanceID)
+				}
+			}
+			if len(instanceIDs) > 0 {
+				details["agent_instance_ids"] = instanceIDs
+				reason += fmt.Sprintf(" Its agent EC2 instance(s) %s are still billed.", strings.Join(instanceIDs, ", "))
+			} else {
+				reason += fmt.Sprintf(" It uses %d agent(s) that may be left running.", len(taskAgents))
+			}
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: aws.StringValue(task.Name),
+			ResourceID:   taskArn[strings.LastIndex(taskArn, "/")+1:],
+			ARN:          taskArn,
+			CreatedAt:    task.CreationTime,
+			Reason:       reason,
+			Confidence:   confidence,
+			Details:      details,
+			Tags:         s.getTags(opts, client, taskArn),
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,307 @@
+package scanners
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// stoppedClusterAutoStartDays is how long AWS keeps a DocumentDB or Neptune cluster stopped before
+// starting it again automatically
+const stoppedClusterAutoStartDays = 7
+
+// dbClusterEngine describes how to find and measure clusters of one engine
+type dbClusterEngine struct {
+	Engine           string  // Engine name used by the RDS API
+	CostType         string  // Resource type passed to the cost estimator
+	Namespace        string  // CloudWatch namespace for cluster metrics
+	ActivityMetric   string  // Metric that is zero when nobody uses the cluster
+	FallbackInstance float64 // Hourly price per instance used when the cost estimator is unavailable
+}
+
+// DocumentDBScanner scans for idle or stopped DocumentDB clusters
+type DocumentDBScanner struct{}
+
+// NeptuneScanner scans for idle or stopped Neptune clusters
+type NeptuneScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&DocumentDBScanner{})
+	awslib.DefaultRegistry.RegisterScanner(&NeptuneScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *DocumentDBScanner) ArgumentName() string {
+	return "documentdb-clusters"
+}
+
+// Label implements Scanner interface
+func (s *DocumentDBScanner) Label() string {
+	return "DocumentDB Clusters"
+}
+
+// IsGlobal implements Scanner interface
+func (s *DocumentDBScanner) IsGlobal() bool {
+	return false
+}
+
+// Scan implements Scanner interface
+func (s *DocumentDBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	return scanDBClusters(opts, s.Label(), dbClusterEngine{
+		Engine:           "docdb",
+		CostType:         "DocumentDB",
+		Namespace:        "AWS/DocDB",
+		ActivityMetric:   "DatabaseConnections",
+		FallbackInstance: 0.277, // db.r5.large in us-east-1
+	})
+}
+
+// ArgumentName implements Scanner interface
+func (s *NeptuneScanner) ArgumentName() string {
+	return "neptune-clusters"
+}
+
+// Label implements Scanner interface
+func (s *NeptuneScanner) Label() string {
+	return "Neptune Clusters"
+}
+
+// IsGlobal implements Scanner interface
+func (s *NeptuneScanner) IsGlobal() bool {
+	return false
+}
+
+// Scan implements Scanner interface
+func (s *NeptuneScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Neptune has no connection count metric, so use request throughput instead
+	return scanDBClusters(opts, s.Label(), dbClusterEngine{
+		Engine:           "neptune",
+		CostType:         "Neptune",
+		Namespace:        "AWS/Neptune",
+		ActivityMetric:   "TotalRequestsPerSec",
+		FallbackInstance: 0.348, // db.r5.large in us-east-1
+	})
+}
+
+// isClusterIdle reports whether a cluster's activity metric stayed at zero over the period
+func isClusterIdle(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, engine dbClusterEngine, clusterID string, startTime, endTime time.Time) (bool, error) {
+	value, err := utils.GetResourceMetrics(opts.Context(), cwClient, utils.MetricConfig{
+		Namespace:     engine.Namespace,
+		ResourceID:    clusterID,
+		DimensionName: "DBClusterIdentifier",
+		MetricName:    engine.ActivityMetric,
+		Statistic:     "Maximum",
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Period:        86400, // 1 day
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s metric: %w", engine.ActivityMetric, err)
+	}
+	return value == 0, nil
+}
+
+// calculateClusterCost sums the instance cost of a cluster's members, pricing each instance class once
+func calculateClusterCost(engine dbClusterEngine, instanceClasses map[string]int64, creationTime time.Time, region string) (*awslib.CostBreakdown, error) {
+	total := &awslib.CostBreakdown{}
+	hoursRunning := time.Since(creationTime).Hours()
+
+	for instanceClass, count := range instanceClasses {
+		var hourlyRate float64
+		if awslib.DefaultCostEstimator != nil {
+			cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+				ResourceType:  engine.CostType,
+				ResourceSize:  instanceClass,
+				Region:        region,
+				CreationTime:  creationTime,
+				InstanceCount: count,
+			})
+			if err != nil {
+				logging.Warn("Failed to get cluster instance price, using default", map[string]interface{}{
+					"engine":         engine.Engine,
+					"instance_class": instanceClass,
+					"error":          err.Error(),
+				})
+			} else {
+				hourlyRate = cost.HourlyRate
+			}
+		}
+		if hourlyRate == 0 {
+			// Fallback to default pricing if cost estimator is unavailable or fails
+			hourlyRate = engine.FallbackInstance * float64(count)
+		}
+
+		total.HourlyRate += hourlyRate
+		total.DailyRate += hourlyRate * 24
+		total.MonthlyRate += hourlyRate * 24 * 30
+		total.YearlyRate += hourlyRate * 24 * 365
+	}
+
+	lifetime := total.HourlyRate * hoursRunning
+	total.HoursRunning = aws.Float64(hoursRunning)
+	total.Lifetime = aws.Float64(lifetime)
+
+	return total, nil
+}
+
+// scanDBClusters flags idle or stopped clusters of one engine. Findings are reported per cluster with
+// member instances rolled up, so a cluster and its instances are never counted twice.
+func scanDBClusters(opts awslib.ScanOptions, label string, engine dbClusterEngine) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	// DocumentDB and Neptune share the RDS control plane, filtered by engine
+	rdsClient := rds.New(sess)
+	cwClient := cloudwatch.New(sess)
+	engineFilter := []*rds.Filter{
+		{
+			Name:   aws.String("engine"),
+			Values: []*string{aws.String(engine.Engine)},
+		},
+	}
+
+	var clusters []*rds.DBCluster
+	err = rdsClient.DescribeDBClustersPagesWithContext(opts.Context(), &rds.DescribeDBClustersInput{Filters: engineFilter},
+		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+			clusters = append(clusters, page.DBClusters...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to describe DB clusters", err, map[string]interface{}{
+			"engine": engine.Engine,
+		})
+		return nil, fmt.Errorf("failed to describe %s clusters: %w", engine.Engine, err)
+	}
+	if len(clusters) == 0 {
+		return nil, nil
+	}
+
+	// Group member instances by cluster
+	instancesByCluster := make(map[string][]*rds.DBInstance)
+	err = rdsClient.DescribeDBInstancesPagesWithContext(opts.Context(), &rds.DescribeDBInstancesInput{Filters: engineFilter},
+		func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+			for _, instance := range page.DBInstances {
+				clusterID := aws.StringValue(instance.DBClusterIdentifier)
+				instancesByCluster[clusterID] = append(instancesByCluster[clusterID], instance)
+			}
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to describe DB cluster instances", err, map[string]interface{}{
+			"engine": engine.Engine,
+		})
+		return nil, fmt.Errorf("failed to describe %s instances: %w", engine.Engine, err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, cluster := range clusters {
+		clusterID := aws.StringValue(cluster.DBClusterIdentifier)
+		status := aws.StringValue(cluster.Status)
+		creationTime := aws.TimeValue(cluster.ClusterCreateTime)
+
+		// Skip clusters too new to have a full metric window
+		if creationTime.After(startTime) {
+			continue
+		}
+
+		var reason string
+		switch status {
+		case "stopped":
+			// Stopped clusters still bill for storage and restart on their own after the auto-start window
+			reason = fmt.Sprintf("Cluster is stopped; AWS starts stopped clusters automatically after %d days, so it resumes billing unless it is stopped again or deleted", stoppedClusterAutoStartDays)
+		case "available":
+			idle, err := isClusterIdle(opts, cwClient, engine, clusterID, startTime, endTime)
+			if err != nil {
+				logging.Error("Failed to analyze DB cluster usage", err, map[string]interface{}{
+					"cluster_id": clusterID,
+				})
+				continue
+			}
+			if !idle {
+				continue
+			}
+			reason = fmt.Sprintf("Cluster has had no activity (%s) in the last %d days", engine.ActivityMetric, opts.DaysUnused)
+		default:
+			continue
+		}
+
+		// Roll member instances up into the cluster finding
+		instanceClasses := make(map[string]int64)
+		var instanceIDs []string
+		for _, instance := range instancesByCluster[clusterID] {
+			instanceClasses[aws.StringValue(instance.DBInstanceClass)]++
+			instanceIDs = append(instanceIDs, aws.StringValue(instance.DBInstanceIdentifier))
+		}
+		sort.Strings(instanceIDs)
+
+		cost, err := calculateClusterCost(engine, instanceClasses, creationTime, opts.Region)
+		if err != nil {
+			logging.Error("Failed to calculate DB cluster cost", err, map[string]interface{}{
+				"cluster_id": clusterID,
+			})
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range cluster.TagList {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		classNames := make([]string, 0, len(instanceClasses))
+		for instanceClass, count := range instanceClasses {
+			classNames = append(classNames, fmt.Sprintf("%s x%d", instanceClass, count))
+		}
+		sort.Strings(classNames)
+
+		result := awslib.ScanResult{
+			ResourceType: label,
+			ResourceName: clusterID,
+			ResourceID:   clusterID,
+			ARN:          aws.StringValue(cluster.DBClusterArn),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":        opts.AccountID,
+				"region":            opts.Region,
+				"engine":            aws.StringValue(cluster.Engine),
+				"engine_version":    aws.StringValue(cluster.EngineVersion),
+				"status":            status,
+				"instance_count":    len(instanceIDs),
+				"instance_classes":  strings.Join(classNames, ", "),
+				"instance_ids":      instanceIDs,
+				"storage_encrypted": aws.BoolValue(cluster.StorageEncrypted),
+				"creation_time":     creationTime,
+				"hours_running":     time.Since(creationTime).Hours(),
+				"cluster_arn":       aws.StringValue(cluster.DBClusterArn),
+			},
+			Tags: tags,
+		}
+		if cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
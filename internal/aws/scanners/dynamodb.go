@@ -31,8 +31,13 @@ func (s *DynamoDBScanner) Label() string {
 	return "DynamoDB Tables"
 }
 
+// IsGlobal implements Scanner interface
+func (s *DynamoDBScanner) IsGlobal() bool {
+	return false
+}
+
 // getTableMetrics retrieves CloudWatch metrics for a DynamoDB table
-func (s *DynamoDBScanner) getTableMetrics(cwClient *cloudwatch.CloudWatch, tableName string, startTime, endTime time.Time) (map[string]float64, error) {
+func (s *DynamoDBScanner) getTableMetrics(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, tableName string, startTime, endTime time.Time) (map[string]float64, error) {
 	metrics := []utils.MetricConfig{
 		{
 			Namespace:     "AWS/DynamoDB",
@@ -63,7 +68,7 @@ func (s *DynamoDBScanner) getTableMetrics(cwClient *cloudwatch.CloudWatch, table
 		},
 	}
 
-	results, err := utils.GetResourceMetricsData(cwClient, metrics)
+	results, err := utils.GetResourceMetricsData(opts.Context(), cwClient, metrics)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metrics: %w", err)
 	}
@@ -139,7 +144,7 @@ func (s *DynamoDBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, err
 
 	// Get all DynamoDB tables
 	var tableNames []*string
-	err = dynamodbClient.ListTablesPages(&dynamodb.ListTablesInput{},
+	err = dynamodbClient.ListTablesPagesWithContext(opts.Context(), &dynamodb.ListTablesInput{},
 		func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
 			tableNames = append(tableNames, page.TableNames...)
 			return !lastPage
@@ -170,7 +175,7 @@ func (s *DynamoDBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, err
 		}
 
 		// Get table metrics
-		metrics, err := s.getTableMetrics(cwClient, *tableName, startTime, endTime)
+		metrics, err := s.getTableMetrics(opts, cwClient, *tableName, startTime, endTime)
 		if err != nil {
 			logging.Error("Failed to get table metrics", err, map[string]interface{}{
 				"table_name": *tableName,
@@ -217,7 +222,10 @@ func (s *DynamoDBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, err
 				ResourceType: s.Label(),
 				ResourceName: *tableName,
 				ResourceID:   *tableName,
+				ARN:          aws.StringValue(tableDesc.Table.TableArn),
+				CreatedAt:    tableDesc.Table.CreationDateTime,
 				Reason:       strings.Join(reasons, "\n"),
+				Confidence:   awslib.ConfidenceMedium,
 				Details:      details,
 			}
 
@@ -2,6 +2,7 @@ package scanners
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	awslib "cloudsift/internal/aws"
@@ -9,9 +10,32 @@ import (
 	"cloudsift/internal/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
+const (
+	// dlmPolicyTag is added by Data Lifecycle Manager to the snapshots its policies create
+	dlmPolicyTag = "aws:dlm:lifecycle-policy-id"
+
+	// backupSourceTag is added by AWS Backup to the EBS snapshots it creates
+	backupSourceTag = "aws:backup:source-resource"
+
+	// maxSnapshotEventPages bounds the CloudTrail pages read per snapshot while looking for its creator
+	maxSnapshotEventPages = 3
+)
+
+// snapshotCreatorTags are tag keys commonly used to record who created a resource, compared
+// case-insensitively
+var snapshotCreatorTags = []string{"CreatedBy", "Created_By", "Created-By", "Creator"}
+
+// snapshotCreateEvents are the CloudTrail events that create a snapshot
+var snapshotCreateEvents = map[string]bool{
+	"CreateSnapshot":  true,
+	"CreateSnapshots": true,
+	"CopySnapshot":    true,
+}
+
 // EBSSnapshotScanner scans for EBS snapshots
 type EBSSnapshotScanner struct{}
 
@@ -29,6 +53,11 @@ func (s *EBSSnapshotScanner) Label() string {
 	return "EBS Snapshots"
 }
 
+// IsGlobal implements Scanner interface
+func (s *EBSSnapshotScanner) IsGlobal() bool {
+	return false
+}
+
 // calculateSnapshotCosts calculates the cost of storing an EBS snapshot
 func (s *EBSSnapshotScanner) calculateSnapshotCosts(sizeGiB int64, hoursRunning float64) *awslib.CostBreakdown {
 	// EBS snapshot pricing is typically around $0.05 per GB-month
@@ -58,6 +87,60 @@ func (s *EBSSnapshotScanner) calculateSnapshotCosts(sizeGiB int64, hoursRunning
 	}
 }
 
+// lifecycleManagement returns what deletes a snapshot automatically: "dlm" for a Data Lifecycle
+// Manager policy, "aws_backup" for a backup plan, or "none" for snapshots created manually
+func (s *EBSSnapshotScanner) lifecycleManagement(tags map[string]string) string {
+	if tags[dlmPolicyTag] != "" {
+		return "dlm"
+	}
+	if tags[backupSourceTag] != "" {
+		return "aws_backup"
+	}
+	return "none"
+}
+
+// creatorFromTags returns the creator recorded in a snapshot's tags, if any
+func (s *EBSSnapshotScanner) creatorFromTags(tags map[string]string) string {
+	for key, value := range tags {
+		for _, creatorTag := range snapshotCreatorTags {
+			if strings.EqualFold(key, creatorTag) && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// creatorFromCloudTrail returns the principal that created a snapshot according to CloudTrail, or
+// an empty string if no creating event was found
+func (s *EBSSnapshotScanner) creatorFromCloudTrail(opts awslib.ScanOptions, ctClient *cloudtrail.CloudTrail, snapshotID string, startTime time.Time) (string, error) {
+	var creator string
+	pages := 0
+	err := ctClient.LookupEventsPagesWithContext(opts.Context(), &cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{
+				AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+				AttributeValue: aws.String(snapshotID),
+			},
+		},
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(time.Now().UTC()),
+	}, func(page *cloudtrail.LookupEventsOutput, lastPage bool) bool {
+		pages++
+		for _, event := range page.Events {
+			if snapshotCreateEvents[aws.StringValue(event.EventName)] {
+				creator = aws.StringValue(event.Username)
+				return false
+			}
+		}
+		return !lastPage && pages < maxSnapshotEventPages
+	})
+	if err != nil {
+		return "", err
+	}
+	return creator, nil
+}
+
 // Scan implements Scanner interface
 func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	// Get regional session
@@ -71,6 +154,10 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 
 	// Create EC2 service client
 	svc := ec2.New(sess)
+	ctClient := cloudtrail.New(sess)
+
+	// CloudTrail only keeps 90 days of event history, so older snapshots' creators can't be looked up
+	trailStartTime := time.Now().UTC().Add(-cloudTrailLookupDays * 24 * time.Hour)
 
 	// Log the start of the scan with account details
 	logging.Debug("Starting EBS snapshot scan", map[string]interface{}{
@@ -93,7 +180,7 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 	var volumeLookups int
 	var costCalculations int
 
-	err = svc.DescribeSnapshotsPages(input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+	err = svc.DescribeSnapshotsPagesWithContext(opts.Context(), input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
 		// Batch collect volume IDs that need lookup
 		volumesToLookup := make([]*string, 0)
 		snapshotsToProcess := make([]*ec2.Snapshot, 0)
@@ -187,6 +274,8 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 			ageInDays := int(age.Hours() / 24)
 			ageString := utils.FormatTimeDifference(time.Now(), snapshot.StartTime)
 
+			management := s.lifecycleManagement(tags)
+
 			details := map[string]interface{}{
 				"snapshot_id":   aws.StringValue(snapshot.SnapshotId),
 				"description":   aws.StringValue(snapshot.Description),
@@ -203,6 +292,11 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 				"account_id":    opts.AccountID,
 				"region":        opts.Region,
 				"hours_running": time.Since(*snapshot.StartTime).Hours(),
+				// What deletes the snapshot automatically, and who created it when nothing does
+				"lifecycle_management": management,
+				"dlm_policy_id":        tags[dlmPolicyTag],
+				"created_by":           "",
+				"created_by_source":    "",
 			}
 
 			// Log that we found a result
@@ -233,6 +327,35 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 			}
 
 			if len(reasons) > 0 {
+				// Snapshots nothing will clean up are the ones that need a person to review them
+				reason := reasons[0]
+				confidence := awslib.ConfidenceLow
+				switch management {
+				case "dlm":
+					reason = fmt.Sprintf("Snapshot is managed by DLM policy %s and will be deleted on its retention schedule. %s", tags[dlmPolicyTag], reason)
+				case "aws_backup":
+					reason = "Snapshot was created by AWS Backup and will be deleted by its backup plan's lifecycle. " + reason
+				default:
+					reason = "Snapshot was created outside Data Lifecycle Manager and will never be deleted automatically. " + reason
+					confidence = awslib.ConfidenceMedium
+
+					if creator := s.creatorFromTags(tags); creator != "" {
+						details["created_by"] = creator
+						details["created_by_source"] = "tag"
+					} else if snapshot.StartTime.After(trailStartTime) {
+						creator, err := s.creatorFromCloudTrail(opts, ctClient, aws.StringValue(snapshot.SnapshotId), trailStartTime)
+						if err != nil {
+							logging.Debug("Failed to look up snapshot creator in CloudTrail", map[string]interface{}{
+								"snapshot_id": aws.StringValue(snapshot.SnapshotId),
+								"error":       err.Error(),
+							})
+						} else if creator != "" {
+							details["created_by"] = creator
+							details["created_by_source"] = "cloudtrail"
+						}
+					}
+				}
+
 				// Calculate costs based on snapshot size and age
 				costCalculations++
 				hoursRunning := time.Since(*snapshot.StartTime).Hours()
@@ -244,7 +367,10 @@ func (s *EBSSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 					ResourceType: s.Label(),
 					ResourceName: resourceName,
 					ResourceID:   aws.StringValue(snapshot.SnapshotId),
-					Reason:       reasons[0],
+					ARN:          awslib.ResourceARN("ec2", opts.Region, "", "snapshot/"+aws.StringValue(snapshot.SnapshotId)), // Snapshot ARNs have no account ID
+					CreatedAt:    snapshot.StartTime,
+					Reason:       reason,
+					Confidence:   confidence,
 					Tags:         tags,
 					Details:      details,
 					Cost:         cost,
@@ -2,6 +2,8 @@ package scanners
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +16,29 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
 
+const (
+	// iopsUtilizationThreshold is the fraction of provisioned IOPS that peak usage must stay under to be over-provisioned
+	iopsUtilizationThreshold = 0.25
+
+	// iopsHeadroom is the margin added on top of observed peak IOPS when recommending new provisioned IOPS
+	iopsHeadroom = 1.2
+
+	// gp3BaselineIOPS is the IOPS included with every gp3 volume at no extra charge
+	gp3BaselineIOPS = 3000
+
+	// gp3MaxIOPS is the most IOPS a gp3 volume can be provisioned with
+	gp3MaxIOPS = 16000
+
+	// provisionedIOPSMin is the least IOPS an io1/io2 volume can be provisioned with
+	provisionedIOPSMin = 100
+)
+
+// Fallback EBS prices per GB-month and per provisioned IOPS-month, used when the cost estimator is unavailable
+var (
+	fallbackEBSStoragePrices = map[string]float64{"gp2": 0.10, "gp3": 0.08, "io1": 0.125, "io2": 0.125, "st1": 0.045, "sc1": 0.015, "standard": 0.05}
+	fallbackEBSIOPSPrices    = map[string]float64{"gp3": 0.005, "io1": 0.065, "io2": 0.065}
+)
+
 // EBSVolumeScanner scans for EBS volumes
 type EBSVolumeScanner struct{}
 
@@ -31,6 +56,11 @@ func (s *EBSVolumeScanner) Label() string {
 	return "EBS Volumes"
 }
 
+// IsGlobal implements Scanner interface
+func (s *EBSVolumeScanner) IsGlobal() bool {
+	return false
+}
+
 // Scan implements Scanner interface
 func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	// Get regional session
@@ -57,12 +87,22 @@ func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 		"region":     opts.Region,
 	})
 
+	// Volumes attached to long-stopped instances still bill for storage, so stopped instances are
+	// looked up once up front
+	stoppedInstances, err := s.getStoppedInstances(opts, svc)
+	if err != nil {
+		logging.Error("Failed to list stopped instances; volumes on stopped instances will not be checked", err, map[string]interface{}{
+			"account_id": opts.AccountID,
+			"region":     opts.Region,
+		})
+	}
+
 	input := &ec2.DescribeVolumesInput{
 		MaxResults: nil, // Ensure we don't limit results per page
 	}
 
 	var results awslib.ScanResults
-	err = svc.DescribeVolumesPages(input, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+	err = svc.DescribeVolumesPagesWithContext(opts.Context(), input, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
 		// Log page processing
 		logging.Debug("Processing volume page", map[string]interface{}{
 			"account_id":   opts.AccountID,
@@ -130,6 +170,29 @@ func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 				}
 			}
 
+			// Volumes whose instances have all been stopped for the whole window are effectively unused
+			if isCurrentlyAttached {
+				if result := s.checkStoppedInstanceVolume(opts, volume, stoppedInstances); result != nil {
+					results = append(results, *result)
+					continue
+				}
+			}
+
+			// Attached provisioned IOPS volumes are checked for over-provisioning instead of disuse
+			if isCurrentlyAttached && isProvisionedIOPSVolume(volume) {
+				result, err := s.checkProvisionedIOPS(opts, clients.CloudWatch, volume)
+				if err != nil {
+					logging.Error("Failed to check provisioned IOPS utilization", err, map[string]interface{}{
+						"account_id": opts.AccountID,
+						"region":     opts.Region,
+						"volume_id":  aws.StringValue(volume.VolumeId),
+					})
+				} else if result != nil {
+					results = append(results, *result)
+				}
+				continue
+			}
+
 			// Skip if currently attached
 			if isCurrentlyAttached {
 				continue
@@ -245,7 +308,7 @@ func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 			endTime := time.Now().UTC().Truncate(time.Minute)
 			daysUnused := utils.Max(1, opts.DaysUnused)
 			metricStartTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
-			metrics, err := s.getVolumeMetrics(clients.CloudWatch, volumeID, metricStartTime, endTime)
+			metrics, err := s.getVolumeMetrics(opts, clients.CloudWatch, volumeID, metricStartTime, endTime)
 			if err != nil {
 				logging.Error("Failed to get volume metrics", err, map[string]interface{}{
 					"volume_id": volumeID,
@@ -378,10 +441,13 @@ func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 			result := awslib.ScanResult{
 				ResourceType: s.Label(),
 				ResourceID:   aws.StringValue(volume.VolumeId),
+				ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "volume/"+aws.StringValue(volume.VolumeId)),
+				CreatedAt:    volume.CreateTime,
 				ResourceName: resourceName,
 				Details:      details,
 				Cost:         costDetails,
 				Reason:       strings.Join(unusedReasons, "\n"),
+				Confidence:   awslib.ConfidenceHigh,
 			}
 
 			results = append(results, result)
@@ -420,7 +486,7 @@ func (s *EBSVolumeScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 	return results, nil
 }
 
-func (s *EBSVolumeScanner) getVolumeMetrics(cwClient *cloudwatch.CloudWatch, volumeID string, startTime time.Time, endTime time.Time) (map[string]float64, error) {
+func (s *EBSVolumeScanner) getVolumeMetrics(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, volumeID string, startTime time.Time, endTime time.Time) (map[string]float64, error) {
 	metrics := make(map[string]float64)
 	period := int64(86400) // 1 day
 	metricConfigs := []utils.MetricConfig{
@@ -457,7 +523,7 @@ func (s *EBSVolumeScanner) getVolumeMetrics(cwClient *cloudwatch.CloudWatch, vol
 	}
 
 	for _, config := range metricConfigs {
-		value, err := utils.GetResourceMetrics(cwClient, config)
+		value, err := utils.GetResourceMetrics(opts.Context(), cwClient, config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get metric %s: %w", config.MetricName, err)
 		}
@@ -469,3 +535,324 @@ func (s *EBSVolumeScanner) getVolumeMetrics(cwClient *cloudwatch.CloudWatch, vol
 
 	return metrics, nil
 }
+
+// getStoppedInstances returns the stopped instances in the region, keyed by instance ID, with the
+// time each was stopped. Instances whose stop time can't be determined are left out.
+func (s *EBSVolumeScanner) getStoppedInstances(opts awslib.ScanOptions, svc *ec2.EC2) (map[string]time.Time, error) {
+	stopped := make(map[string]time.Time)
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String(ec2.InstanceStateNameStopped)},
+			},
+		},
+	}
+	err := svc.DescribeInstancesPagesWithContext(opts.Context(), input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if stopTime, ok := instanceStopTime(instance); ok {
+					stopped[aws.StringValue(instance.InstanceId)] = stopTime
+				}
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe stopped instances: %w", err)
+	}
+	return stopped, nil
+}
+
+// checkStoppedInstanceVolume flags an attached volume when every instance it is attached to has been
+// stopped for at least DaysUnused days. Returns nil when any attached instance is running or was
+// stopped more recently.
+func (s *EBSVolumeScanner) checkStoppedInstanceVolume(opts awslib.ScanOptions, volume *ec2.Volume, stoppedInstances map[string]time.Time) *awslib.ScanResult {
+	if len(volume.Attachments) == 0 {
+		return nil
+	}
+
+	// With multi-attach the volume is only unused once the most recently stopped instance qualifies
+	var instanceID string
+	var stoppedSince time.Time
+	for _, attachment := range volume.Attachments {
+		id := aws.StringValue(attachment.InstanceId)
+		stopTime, ok := stoppedInstances[id]
+		if !ok {
+			return nil
+		}
+		if instanceID == "" || stopTime.After(stoppedSince) {
+			instanceID = id
+			stoppedSince = stopTime
+		}
+	}
+	stoppedDays := int(time.Since(stoppedSince).Hours() / 24)
+	if stoppedDays < opts.DaysUnused {
+		return nil
+	}
+
+	volumeID := aws.StringValue(volume.VolumeId)
+	volumeType := aws.StringValue(volume.VolumeType)
+	sizeGB := aws.Int64Value(volume.Size)
+
+	tags := make(map[string]string)
+	for _, tag := range volume.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	resourceName := volumeID
+	if name, ok := tags["Name"]; ok {
+		resourceName = name
+	}
+
+	var device string
+	for _, attachment := range volume.Attachments {
+		if aws.StringValue(attachment.InstanceId) == instanceID {
+			device = aws.StringValue(attachment.Device)
+		}
+	}
+
+	stoppedAge := utils.FormatTimeDifference(time.Now(), &stoppedSince)
+	reason := fmt.Sprintf("Volume is attached to instance %s, which has been stopped for %s; the volume is still billed for its full size", instanceID, stoppedAge)
+
+	// Stopped instances don't pay for compute, but the volume keeps its full storage and IOPS charges
+	hourlyRate := s.calculateMonthlyVolumeCost(opts.Region, volumeType, sizeGB, aws.Int64Value(volume.Iops)) / 730
+	hoursRunning := time.Since(aws.TimeValue(volume.CreateTime)).Hours()
+
+	return &awslib.ScanResult{
+		ResourceType: s.Label(),
+		ResourceID:   volumeID,
+		ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "volume/"+volumeID),
+		CreatedAt:    volume.CreateTime,
+		ResourceName: resourceName,
+		Reason:       reason,
+		Confidence:   awslib.ConfidenceMedium,
+		Tags:         tags,
+		Details: map[string]interface{}{
+			"account_id":             opts.AccountID,
+			"region":                 opts.Region,
+			"volume_id":              volumeID,
+			"volume_type":            volumeType,
+			"size_gb":                sizeGB,
+			"iops":                   aws.Int64Value(volume.Iops),
+			"encrypted":              aws.BoolValue(volume.Encrypted),
+			"availability_zone":      aws.StringValue(volume.AvailabilityZone),
+			"state":                  aws.StringValue(volume.State),
+			"created":                aws.TimeValue(volume.CreateTime).Format(time.RFC3339),
+			"attached_instance_id":   instanceID,
+			"attached_device":        device,
+			"instance_stopped_since": stoppedSince.Format(time.RFC3339),
+			"instance_stopped_days":  stoppedDays,
+		},
+		Cost: map[string]interface{}{
+			"total": &awslib.CostBreakdown{
+				HourlyRate:   hourlyRate,
+				DailyRate:    hourlyRate * 24,
+				MonthlyRate:  hourlyRate * 24 * 30,
+				YearlyRate:   hourlyRate * 24 * 365,
+				HoursRunning: aws.Float64(hoursRunning),
+				Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+			},
+		},
+	}
+}
+
+// isProvisionedIOPSVolume reports whether a volume is an io1/io2 provisioned IOPS volume
+func isProvisionedIOPSVolume(volume *ec2.Volume) bool {
+	volumeType := aws.StringValue(volume.VolumeType)
+	return volumeType == ec2.VolumeTypeIo1 || volumeType == ec2.VolumeTypeIo2
+}
+
+// getObservedIOPS returns the average and peak IOPS of a volume between startTime and endTime. The
+// peak is the busiest metric period, so short bursts within a period are averaged out.
+func (s *EBSVolumeScanner) getObservedIOPS(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, volumeID string, startTime, endTime time.Time) (float64, float64, error) {
+	// Use the finest period that keeps the window within CloudWatch's 1440 datapoint limit
+	seconds := int64(endTime.Sub(startTime).Seconds())
+	period := int64(math.Ceil(float64(seconds)/1440/60)) * 60
+	if period < 300 {
+		period = 300
+	}
+
+	// Read and write ops are summed per period so they can be combined into total ops per period
+	opsByTime := make(map[int64]float64)
+	var totalOps float64
+	for _, metricName := range []string{"VolumeReadOps", "VolumeWriteOps"} {
+		output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/EBS"),
+			MetricName: aws.String(metricName),
+			Dimensions: []*cloudwatch.Dimension{
+				{
+					Name:  aws.String("VolumeId"),
+					Value: aws.String(volumeID),
+				},
+			},
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int64(period),
+			Statistics: []*string{aws.String(cloudwatch.StatisticSum)},
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get metric %s: %w", metricName, err)
+		}
+		for _, datapoint := range output.Datapoints {
+			ops := aws.Float64Value(datapoint.Sum)
+			opsByTime[aws.TimeValue(datapoint.Timestamp).Unix()] += ops
+			totalOps += ops
+		}
+	}
+
+	var peakOps float64
+	for _, ops := range opsByTime {
+		peakOps = math.Max(peakOps, ops)
+	}
+
+	return totalOps / float64(seconds), peakOps / float64(period), nil
+}
+
+// calculateMonthlyVolumeCost estimates the monthly storage and provisioned IOPS cost of a volume
+// configuration. For gp3 only IOPS above the included baseline are billed.
+func (s *EBSVolumeScanner) calculateMonthlyVolumeCost(region, volumeType string, sizeGB, iops int64) float64 {
+	billedIOPS := iops
+	if volumeType == ec2.VolumeTypeGp3 {
+		billedIOPS = int64(math.Max(0, float64(iops-gp3BaselineIOPS)))
+	}
+
+	storageCost := fallbackEBSStoragePrices[volumeType] * float64(sizeGB)
+	iopsCost := fallbackEBSIOPSPrices[volumeType] * float64(billedIOPS)
+
+	if awslib.DefaultCostEstimator != nil {
+		storage, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "EBSVolumes",
+			ResourceSize: sizeGB,
+			Region:       region,
+			VolumeType:   volumeType,
+		})
+		if err == nil && storage.MonthlyRate > 0 {
+			storageCost = storage.MonthlyRate
+		}
+
+		if billedIOPS > 0 {
+			provisioned, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+				ResourceType: "EBSProvisionedIOPS",
+				ResourceSize: billedIOPS,
+				Region:       region,
+				VolumeType:   volumeType,
+			})
+			if err == nil && provisioned.MonthlyRate > 0 {
+				iopsCost = provisioned.MonthlyRate
+			}
+		}
+	}
+
+	return storageCost + iopsCost
+}
+
+// recommendIOPS returns the volume type and provisioned IOPS to move an io1/io2 volume to given its
+// observed peak IOPS, preferring gp3 when it can serve the peak with headroom. It returns false when
+// the peak uses too much of the provisioned IOPS for the volume to be over-provisioned.
+func recommendIOPS(volumeType string, provisionedIOPS int64, peakIOPS float64) (string, int64, bool) {
+	if peakIOPS >= float64(provisionedIOPS)*iopsUtilizationThreshold {
+		return "", 0, false
+	}
+
+	targetIOPS := int64(math.Ceil(peakIOPS * iopsHeadroom))
+	if targetIOPS > gp3MaxIOPS {
+		return volumeType, int64(math.Max(float64(targetIOPS), provisionedIOPSMin)), true
+	}
+	return ec2.VolumeTypeGp3, int64(math.Max(float64(targetIOPS), gp3BaselineIOPS)), true
+}
+
+// checkProvisionedIOPS flags an attached io1/io2 volume whose observed IOPS stayed far below its
+// provisioned IOPS, recommending gp3 or lower provisioned IOPS. Returns nil when the volume is
+// reasonably utilized or rightsizing would not save money.
+func (s *EBSVolumeScanner) checkProvisionedIOPS(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, volume *ec2.Volume) (*awslib.ScanResult, error) {
+	volumeID := aws.StringValue(volume.VolumeId)
+	volumeType := aws.StringValue(volume.VolumeType)
+	sizeGB := aws.Int64Value(volume.Size)
+	provisionedIOPS := aws.Int64Value(volume.Iops)
+	if provisionedIOPS == 0 {
+		return nil, nil
+	}
+
+	daysUnused := utils.Max(1, opts.DaysUnused)
+	endTime := time.Now().UTC().Truncate(time.Minute)
+	startTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
+
+	avgIOPS, peakIOPS, err := s.getObservedIOPS(opts, cwClient, volumeID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	recommendedType, recommendedIOPS, ok := recommendIOPS(volumeType, provisionedIOPS, peakIOPS)
+	if !ok {
+		return nil, nil
+	}
+
+	currentMonthly := s.calculateMonthlyVolumeCost(opts.Region, volumeType, sizeGB, provisionedIOPS)
+	recommendedMonthly := s.calculateMonthlyVolumeCost(opts.Region, recommendedType, sizeGB, recommendedIOPS)
+	savingsMonthly := currentMonthly - recommendedMonthly
+	if savingsMonthly <= 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range volume.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	resourceName := volumeID
+	if name, ok := tags["Name"]; ok {
+		resourceName = name
+	}
+
+	var instanceIDs []string
+	for _, attachment := range volume.Attachments {
+		instanceIDs = append(instanceIDs, aws.StringValue(attachment.InstanceId))
+	}
+	sort.Strings(instanceIDs)
+
+	reason := fmt.Sprintf("Rightsizing recommendation, not deletion: %s volume is provisioned for %d IOPS but peaked at %.0f IOPS (%.0f average) in the last %d days. "+
+		"Moving to %s with %d IOPS would save about $%.2f/month; confirm throughput requirements before changing.",
+		volumeType, provisionedIOPS, peakIOPS, avgIOPS, daysUnused, recommendedType, recommendedIOPS, savingsMonthly)
+
+	// The reported cost is the saving, since the volume itself is still needed
+	hourlySavings := savingsMonthly / 730
+	hoursRunning := time.Since(aws.TimeValue(volume.CreateTime)).Hours()
+
+	return &awslib.ScanResult{
+		ResourceType: s.Label(),
+		ResourceID:   volumeID,
+		ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "volume/"+volumeID),
+		CreatedAt:    volume.CreateTime,
+		ResourceName: resourceName,
+		Reason:       reason,
+		Confidence:   awslib.ConfidenceMedium,
+		Tags:         tags,
+		Details: map[string]interface{}{
+			"account_id":               opts.AccountID,
+			"region":                   opts.Region,
+			"volume_id":                volumeID,
+			"recommendation":           "rightsize",
+			"volume_type":              volumeType,
+			"size_gb":                  sizeGB,
+			"provisioned_iops":         provisionedIOPS,
+			"observed_peak_iops":       math.Round(peakIOPS*100) / 100,
+			"observed_avg_iops":        math.Round(avgIOPS*100) / 100,
+			"recommended_volume_type":  recommendedType,
+			"recommended_iops":         recommendedIOPS,
+			"current_monthly_cost":     math.Round(currentMonthly*100) / 100,
+			"recommended_monthly_cost": math.Round(recommendedMonthly*100) / 100,
+			"attached_instances":       instanceIDs,
+			"availability_zone":        aws.StringValue(volume.AvailabilityZone),
+			"created":                  aws.TimeValue(volume.CreateTime).Format(time.RFC3339),
+			"days_analyzed":            daysUnused,
+		},
+		Cost: map[string]interface{}{
+			"total": &awslib.CostBreakdown{
+				HourlyRate:   hourlySavings,
+				DailyRate:    hourlySavings * 24,
+				MonthlyRate:  hourlySavings * 24 * 30,
+				YearlyRate:   hourlySavings * 24 * 365,
+				HoursRunning: aws.Float64(hoursRunning),
+				Lifetime:     aws.Float64(hourlySavings * hoursRunning),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,169 @@
+package scanners
+
+import (
+	"testing"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecommendIOPS tests choosing the volume type and IOPS for an over-provisioned io1/io2 volume
+func TestRecommendIOPS(t *testing.T) {
+	tests := []struct {
+		name            string
+		volumeType      string
+		provisionedIOPS int64
+		peakIOPS        float64
+		expectedType    string
+		expectedIOPS    int64
+		expectedOK      bool
+	}{
+		{
+			name:            "peak at the utilization threshold isn't over-provisioned",
+			volumeType:      ec2.VolumeTypeIo1,
+			provisionedIOPS: 10000,
+			peakIOPS:        2500,
+		},
+		{
+			name:            "low peak moves to gp3 at its baseline",
+			volumeType:      ec2.VolumeTypeIo1,
+			provisionedIOPS: 10000,
+			peakIOPS:        100,
+			expectedType:    ec2.VolumeTypeGp3,
+			expectedIOPS:    3000,
+			expectedOK:      true,
+		},
+		{
+			name:            "gp3 is sized to the peak plus headroom",
+			volumeType:      ec2.VolumeTypeIo2,
+			provisionedIOPS: 64000,
+			peakIOPS:        5000,
+			expectedType:    ec2.VolumeTypeGp3,
+			expectedIOPS:    6000,
+			expectedOK:      true,
+		},
+		{
+			name:            "peak beyond gp3 keeps the current type with fewer IOPS",
+			volumeType:      ec2.VolumeTypeIo2,
+			provisionedIOPS: 64000,
+			peakIOPS:        14000,
+			expectedType:    ec2.VolumeTypeIo2,
+			expectedIOPS:    16800,
+			expectedOK:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volumeType, iops, ok := recommendIOPS(tt.volumeType, tt.provisionedIOPS, tt.peakIOPS)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedType, volumeType)
+			assert.Equal(t, tt.expectedIOPS, iops)
+		})
+	}
+}
+
+// TestCalculateMonthlyVolumeCost tests the fallback volume pricing, including the IOPS gp3 includes
+func TestCalculateMonthlyVolumeCost(t *testing.T) {
+	scanner := &EBSVolumeScanner{}
+
+	tests := []struct {
+		name       string
+		volumeType string
+		sizeGB     int64
+		iops       int64
+		expected   float64
+	}{
+		{"gp3 at its baseline bills storage only", ec2.VolumeTypeGp3, 100, 3000, 8},
+		{"gp3 bills IOPS above the baseline", ec2.VolumeTypeGp3, 100, 6000, 23},
+		{"io1 bills every provisioned IOPS", ec2.VolumeTypeIo1, 100, 10000, 662.5},
+		{"gp2 has no IOPS charge", ec2.VolumeTypeGp2, 100, 300, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost := scanner.calculateMonthlyVolumeCost("us-east-1", tt.volumeType, tt.sizeGB, tt.iops)
+			assert.InDelta(t, tt.expected, cost, 0.0001)
+		})
+	}
+}
+
+// TestCheckStoppedInstanceVolume tests flagging volumes whose attached instances have all been
+// stopped for at least DaysUnused days
+func TestCheckStoppedInstanceVolume(t *testing.T) {
+	scanner := &EBSVolumeScanner{}
+	opts := awslib.ScanOptions{Region: "us-east-1", AccountID: "123456789012", DaysUnused: 30}
+	now := time.Now()
+
+	attachedTo := func(instanceIDs ...string) *ec2.Volume {
+		volume := &ec2.Volume{
+			VolumeId:   aws.String("vol-1"),
+			VolumeType: aws.String(ec2.VolumeTypeGp2),
+			Size:       aws.Int64(100),
+			CreateTime: aws.Time(now.AddDate(-1, 0, 0)),
+			Tags:       []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("data")}},
+		}
+		for i, id := range instanceIDs {
+			volume.Attachments = append(volume.Attachments, &ec2.VolumeAttachment{
+				InstanceId: aws.String(id),
+				Device:     aws.String("/dev/sd" + string(rune('f'+i))),
+			})
+		}
+		return volume
+	}
+	stopped := map[string]time.Time{
+		"i-old":    now.AddDate(0, 0, -60),
+		"i-older":  now.AddDate(0, 0, -90),
+		"i-recent": now.AddDate(0, 0, -10),
+	}
+
+	tests := []struct {
+		name             string
+		volume           *ec2.Volume
+		expectedInstance string // Empty when the volume isn't flagged
+		expectedDevice   string
+	}{
+		{name: "unattached", volume: attachedTo()},
+		{name: "instance not stopped", volume: attachedTo("i-running")},
+		{name: "stopped for fewer than DaysUnused days", volume: attachedTo("i-recent")},
+		{name: "multi-attach with a running instance", volume: attachedTo("i-old", "i-running")},
+		{name: "multi-attach uses the most recent stop", volume: attachedTo("i-old", "i-recent")},
+		{
+			name:             "stopped long enough",
+			volume:           attachedTo("i-old"),
+			expectedInstance: "i-old",
+			expectedDevice:   "/dev/sdf",
+		},
+		{
+			name:             "multi-attach reports the most recently stopped instance",
+			volume:           attachedTo("i-older", "i-old"),
+			expectedInstance: "i-old",
+			expectedDevice:   "/dev/sdg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := scanner.checkStoppedInstanceVolume(opts, tt.volume, stopped)
+			if tt.expectedInstance == "" {
+				assert.Nil(t, result)
+				return
+			}
+			require.NotNil(t, result)
+			assert.Equal(t, "vol-1", result.ResourceID)
+			assert.Equal(t, "data", result.ResourceName)
+			assert.Equal(t, awslib.ConfidenceMedium, result.Confidence)
+			assert.Equal(t, tt.expectedInstance, result.Details["attached_instance_id"])
+			assert.Equal(t, tt.expectedDevice, result.Details["attached_device"])
+			assert.Equal(t, 60, result.Details["instance_stopped_days"])
+
+			cost := result.Cost["total"].(*awslib.CostBreakdown)
+			assert.InDelta(t, 10.0/730, cost.HourlyRate, 0.0001)
+		})
+	}
+}
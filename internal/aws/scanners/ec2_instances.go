@@ -36,8 +36,13 @@ func (s *EC2InstanceScanner) Label() string {
 	return "EC2 Instances"
 }
 
+// IsGlobal implements Scanner interface
+func (s *EC2InstanceScanner) IsGlobal() bool {
+	return false
+}
+
 // fetchMetric gets CloudWatch metrics for a given resource
-func (s *EC2InstanceScanner) fetchMetric(cwClient *cloudwatch.CloudWatch, namespace, resourceID, dimensionName, metricName, stat string, startTime, endTime time.Time) ([]float64, error) {
+func (s *EC2InstanceScanner) fetchMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, namespace, resourceID, dimensionName, metricName, stat string, startTime, endTime time.Time) ([]float64, error) {
 	// Ensure start time is before end time and they're not equal
 	if startTime.Equal(endTime) {
 		startTime = startTime.Add(-1 * time.Hour)
@@ -81,7 +86,7 @@ func (s *EC2InstanceScanner) fetchMetric(cwClient *cloudwatch.CloudWatch, namesp
 		EndTime:   aws.Time(config.EndTime),
 	}
 
-	result, err := cwClient.GetMetricData(input)
+	result, err := awslib.GetMetricData(opts.Context(), cwClient, input)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +104,7 @@ func (s *EC2InstanceScanner) fetchMetric(cwClient *cloudwatch.CloudWatch, namesp
 }
 
 // analyzeInstanceUsage checks if an instance is underutilized
-func (s *EC2InstanceScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatch, instance *ec2.Instance, startTime, endTime time.Time, daysUnused int) ([]string, error) {
+func (s *EC2InstanceScanner) analyzeInstanceUsage(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, instance *ec2.Instance, startTime, endTime time.Time, daysUnused int) ([]string, error) {
 	instanceID := aws.StringValue(instance.InstanceId)
 	var reasons []string
 
@@ -109,7 +114,7 @@ func (s *EC2InstanceScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatc
 		"start_time":  startTime,
 		"end_time":    endTime,
 	})
-	cpuUsage, err := s.fetchMetric(cwClient, "AWS/EC2", instanceID, "InstanceId", "CPUUtilization", "Average", startTime, endTime)
+	cpuUsage, err := s.fetchMetric(opts, cwClient, "AWS/EC2", instanceID, "InstanceId", "CPUUtilization", "Average", startTime, endTime)
 	if err != nil {
 		logging.Error("Failed to fetch CPU metrics", err, map[string]interface{}{
 			"instance_id": instanceID,
@@ -125,7 +130,7 @@ func (s *EC2InstanceScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatc
 		"start_time":  startTime,
 		"end_time":    endTime,
 	})
-	networkIn, err := s.fetchMetric(cwClient, "AWS/EC2", instanceID, "InstanceId", "NetworkPacketsIn", "Sum", startTime, endTime)
+	networkIn, err := s.fetchMetric(opts, cwClient, "AWS/EC2", instanceID, "InstanceId", "NetworkPacketsIn", "Sum", startTime, endTime)
 	if err != nil {
 		logging.Error("Failed to fetch NetworkIn metrics", err, map[string]interface{}{
 			"instance_id": instanceID,
@@ -135,7 +140,7 @@ func (s *EC2InstanceScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatc
 		return nil, fmt.Errorf("failed to fetch NetworkIn metrics: %w", err)
 	}
 
-	networkOut, err := s.fetchMetric(cwClient, "AWS/EC2", instanceID, "InstanceId", "NetworkPacketsOut", "Sum", startTime, endTime)
+	networkOut, err := s.fetchMetric(opts, cwClient, "AWS/EC2", instanceID, "InstanceId", "NetworkPacketsOut", "Sum", startTime, endTime)
 	if err != nil {
 		logging.Error("Failed to fetch NetworkOut metrics", err, map[string]interface{}{
 			"instance_id": instanceID,
@@ -275,7 +280,10 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 	// Create a channel to collect tasks
 	var tasks []worker.Task
 
-	err = ec2Client.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+	// Instance types are looked up once per family when rightsizing
+	families := newInstanceFamilyCache()
+
+	err = ec2Client.DescribeInstancesPagesWithContext(opts.Context(), input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
 		// Log page processing
 		logging.Debug("Processing instance page", map[string]interface{}{
 			"account_id":   opts.AccountID,
@@ -326,6 +334,7 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 
 					// Check if instance is unused based on state
 					var reasons []string
+					analyzed := false
 					if aws.StringValue(instanceCopy.State.Name) == "stopped" {
 						logging.Info("Found stopped instance", map[string]interface{}{
 							"instance_id": aws.StringValue(instanceCopy.InstanceId),
@@ -333,19 +342,12 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 						})
 
 						// Get stop time from state transition reason
-						if instanceCopy.StateTransitionReason != nil {
-							// AWS format: "User initiated (2024-02-27 11:51:43 GMT)"
-							reason := aws.StringValue(instanceCopy.StateTransitionReason)
-							if strings.Contains(reason, "(") && strings.Contains(reason, ")") {
-								timeStr := strings.TrimSpace(strings.Split(strings.Split(reason, "(")[1], ")")[0])
-								if stopTime, err := time.Parse("2006-01-02 15:04:05 MST", timeStr); err == nil {
-									stoppedDuration := time.Since(stopTime)
-									stoppedDays := int(stoppedDuration.Hours() / 24)
-									if stoppedDays >= opts.DaysUnused {
-										stoppedAgeStr := utils.FormatTimeDifference(time.Now(), &stopTime)
-										reasons = append(reasons, fmt.Sprintf("Instance has been stopped for %s", stoppedAgeStr))
-									}
-								}
+						if stopTime, ok := instanceStopTime(instanceCopy); ok {
+							stoppedDuration := time.Since(stopTime)
+							stoppedDays := int(stoppedDuration.Hours() / 24)
+							if stoppedDays >= opts.DaysUnused {
+								stoppedAgeStr := utils.FormatTimeDifference(time.Now(), &stopTime)
+								reasons = append(reasons, fmt.Sprintf("Instance has been stopped for %s", stoppedAgeStr))
 							}
 						}
 					} else if aws.StringValue(instanceCopy.State.Name) != "running" {
@@ -355,13 +357,14 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 						instanceAge := time.Since(*instanceCopy.LaunchTime)
 						if instanceAge.Hours()/24 >= float64(opts.DaysUnused) {
 							// Analyze running instances using launch time
-							usageReasons, err := s.analyzeInstanceUsage(clients.CloudWatch, instanceCopy, metricStartTime, endTime, opts.DaysUnused)
+							usageReasons, err := s.analyzeInstanceUsage(opts, clients.CloudWatch, instanceCopy, metricStartTime, endTime, opts.DaysUnused)
 							if err != nil {
 								logging.Error("Failed to analyze instance usage", err, map[string]interface{}{
 									"instance_id": aws.StringValue(instanceCopy.InstanceId),
 								})
 							} else {
 								reasons = append(reasons, usageReasons...)
+								analyzed = true
 							}
 						} else {
 							logging.Debug("Skipping instance usage analysis - too new", map[string]interface{}{
@@ -520,10 +523,17 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 						result := awslib.ScanResult{
 							ResourceType: s.Label(),
 							ResourceID:   aws.StringValue(instanceCopy.InstanceId),
+							ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "instance/"+aws.StringValue(instanceCopy.InstanceId)),
+							CreatedAt:    instanceCopy.LaunchTime,
 							ResourceName: name,
 							Details:      details,
 							Cost:         costDetails,
 							Reason:       strings.Join(reasons, "\n"),
+							Confidence:   awslib.ConfidenceMedium,
+						}
+						// Low utilization from metrics is weaker evidence than an instance being stopped
+						if analyzed {
+							result.Confidence = awslib.ConfidenceLow
 						}
 
 						// Thread-safe append to results
@@ -539,6 +549,22 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 							"reasons":     reasons,
 						})
 					}
+
+					// Instances in use may still be larger than they need to be
+					if len(reasons) == 0 && analyzed && opts.EC2Rightsizing {
+						if result := s.rightsizeInstance(opts, ec2Client, clients.CloudWatch, families, instanceCopy, name, tags, metricStartTime, endTime); result != nil {
+							resultsMutex.Lock()
+							results = append(results, *result)
+							resultsMutex.Unlock()
+
+							logging.Info("Found oversized instance", map[string]interface{}{
+								"instance_id":      aws.StringValue(instanceCopy.InstanceId),
+								"name":             name,
+								"instance_type":    aws.StringValue(instanceCopy.InstanceType),
+								"recommended_type": result.Details["recommended_instance_type"],
+							})
+						}
+					}
 					return nil
 				}
 				tasks = append(tasks, task)
@@ -573,6 +599,21 @@ func (s *EC2InstanceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults,
 }
 
 // roundCost rounds a cost value to 2 decimal places
+// instanceStopTime returns when a stopped instance was stopped, parsed from its state transition
+// reason. AWS formats the reason as "User initiated (2024-02-27 11:51:43 GMT)".
+func instanceStopTime(instance *ec2.Instance) (time.Time, bool) {
+	reason := aws.StringValue(instance.StateTransitionReason)
+	if !strings.Contains(reason, "(") || !strings.Contains(reason, ")") {
+		return time.Time{}, false
+	}
+	timeStr := strings.TrimSpace(strings.Split(strings.Split(reason, "(")[1], ")")[0])
+	stopTime, err := time.Parse("2006-01-02 15:04:05 MST", timeStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return stopTime, true
+}
+
 // nolint:unused
 func roundCost(cost float64) float64 {
 	return math.Round(cost*100) / 100
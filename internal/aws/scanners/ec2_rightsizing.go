@@ -0,0 +1,365 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// rightsizingHeadroom is the share of a smaller instance type's capacity the observed peak may use,
+// leaving room for peaks the scan window didn't see
+const rightsizingHeadroom = 0.8
+
+// networkPerformancePattern matches the bandwidth in network performance descriptions such as
+// "Up to 12.5 Gigabit" and "25 Gigabit"
+var networkPerformancePattern = regexp.MustCompile(`([0-9.]+) Gigabit`)
+
+// networkPerformanceTiers approximates the bandwidth in Gbps of older instance types, which describe
+// their network performance without a number
+var networkPerformanceTiers = map[string]float64{
+	"very low":        0.05,
+	"low":             0.1,
+	"low to moderate": 0.3,
+	"moderate":        0.5,
+	"high":            1,
+}
+
+// instanceTypeSpec is the capacity of an instance type, used to pick a smaller type in its family
+type instanceTypeSpec struct {
+	name        string
+	vcpus       int64
+	memoryMiB   int64
+	networkGbps float64 // 0 when the network performance isn't known
+}
+
+// instanceUtilization is an instance's peak utilization over the scan window
+type instanceUtilization struct {
+	cpuPercent    float64
+	memoryPercent float64 // -1 when the CloudWatch agent doesn't publish memory metrics
+	networkMbps   float64 // Busiest hour's average throughput in either direction
+}
+
+// instanceFamilyCache holds the instance types of each family looked up during a scan, so instances
+// of the same family only describe it once
+type instanceFamilyCache struct {
+	families map[string][]instanceTypeSpec
+	mu       sync.Mutex
+}
+
+// newInstanceFamilyCache creates an empty instance family cache
+func newInstanceFamilyCache() *instanceFamilyCache {
+	return &instanceFamilyCache{
+		families: make(map[string][]instanceTypeSpec),
+	}
+}
+
+// instanceFamily returns the family of an instance type, e.g. "m5" for "m5.2xlarge"
+func instanceFamily(instanceType string) string {
+	family, _, _ := strings.Cut(instanceType, ".")
+	return family
+}
+
+// parseNetworkGbps returns the bandwidth in Gbps of an instance type's network performance
+// description, or 0 if it isn't recognized
+func parseNetworkGbps(performance string) float64 {
+	if match := networkPerformancePattern.FindStringSubmatch(performance); match != nil {
+		gbps, err := strconv.ParseFloat(match[1], 64)
+		if err == nil {
+			return gbps
+		}
+	}
+	return networkPerformanceTiers[strings.ToLower(strings.TrimSpace(performance))]
+}
+
+// getFamilySpecs returns the instance types in a family, smallest first
+func (s *EC2InstanceScanner) getFamilySpecs(opts awslib.ScanOptions, ec2Client *ec2.EC2, cache *instanceFamilyCache, family string) ([]instanceTypeSpec, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if specs, ok := cache.families[family]; ok {
+		return specs, nil
+	}
+
+	var specs []instanceTypeSpec
+	err := ec2Client.DescribeInstanceTypesPagesWithContext(opts.Context(), &ec2.DescribeInstanceTypesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-type"),
+				Values: []*string{aws.String(family + ".*")},
+			},
+		},
+	}, func(page *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
+		for _, info := range page.InstanceTypes {
+			spec := instanceTypeSpec{
+				name: aws.StringValue(info.InstanceType),
+			}
+			if info.VCpuInfo != nil {
+				spec.vcpus = aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+			}
+			if info.MemoryInfo != nil {
+				spec.memoryMiB = aws.Int64Value(info.MemoryInfo.SizeInMiB)
+			}
+			if info.NetworkInfo != nil {
+				spec.networkGbps = parseNetworkGbps(aws.StringValue(info.NetworkInfo.NetworkPerformance))
+			}
+			specs = append(specs, spec)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe %s instance types: %w", family, err)
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].vcpus != specs[j].vcpus {
+			return specs[i].vcpus < specs[j].vcpus
+		}
+		if specs[i].memoryMiB != specs[j].memoryMiB {
+			return specs[i].memoryMiB < specs[j].memoryMiB
+		}
+		return specs[i].name < specs[j].name
+	})
+	cache.families[family] = specs
+	return specs, nil
+}
+
+// getPeakMemoryPercent returns the highest memory utilization the CloudWatch agent reported for an
+// instance, or -1 if the agent doesn't publish memory metrics for it. The agent adds dimensions
+// such as ImageId and InstanceType, so the metric is looked up before fetching its statistics.
+func (s *EC2InstanceScanner) getPeakMemoryPercent(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, instanceID string, startTime, endTime time.Time) (float64, error) {
+	metrics, err := cwClient.ListMetricsWithContext(opts.Context(), &cloudwatch.ListMetricsInput{
+		Namespace:  aws.String("CWAgent"),
+		MetricName: aws.String("mem_used_percent"),
+		Dimensions: []*cloudwatch.DimensionFilter{
+			{
+				Name:  aws.String("InstanceId"),
+				Value: aws.String(instanceID),
+			},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list memory metrics: %w", err)
+	}
+	if len(metrics.Metrics) == 0 {
+		return -1, nil
+	}
+
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("CWAgent"),
+		MetricName: aws.String("mem_used_percent"),
+		Dimensions: metrics.Metrics[0].Dimensions,
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(3600), // 1 hour
+		Statistics: []*string{aws.String("Maximum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch mem_used_percent metric: %w", err)
+	}
+	if len(output.Datapoints) == 0 {
+		return -1, nil
+	}
+
+	var peak float64
+	for _, dp := range output.Datapoints {
+		peak = math.Max(peak, aws.Float64Value(dp.Maximum))
+	}
+	return peak, nil
+}
+
+// getPeakUtilization returns an instance's peak CPU, memory and network utilization
+func (s *EC2InstanceScanner) getPeakUtilization(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, instanceID string, startTime, endTime time.Time) (instanceUtilization, error) {
+	var utilization instanceUtilization
+
+	cpu, err := s.fetchMetric(opts, cwClient, "AWS/EC2", instanceID, "InstanceId", "CPUUtilization", "Maximum", startTime, endTime)
+	if err != nil {
+		return utilization, fmt.Errorf("failed to fetch CPU metrics: %w", err)
+	}
+	if len(cpu) == 0 {
+		return utilization, fmt.Errorf("no CPU metrics for instance %s", instanceID)
+	}
+	for _, v := range cpu {
+		utilization.cpuPercent = math.Max(utilization.cpuPercent, v)
+	}
+
+	// NetworkIn and NetworkOut are bytes per period, so the busiest hour's sum gives its throughput
+	for _, metricName := range []string{"NetworkIn", "NetworkOut"} {
+		values, err := s.fetchMetric(opts, cwClient, "AWS/EC2", instanceID, "InstanceId", metricName, "Sum", startTime, endTime)
+		if err != nil {
+			return utilization, fmt.Errorf("failed to fetch %s metrics: %w", metricName, err)
+		}
+		for _, v := range values {
+			utilization.networkMbps = math.Max(utilization.networkMbps, v*8/3600/1_000_000)
+		}
+	}
+
+	utilization.memoryPercent, err = s.getPeakMemoryPercent(opts, cwClient, instanceID, startTime, endTime)
+	if err != nil {
+		return utilization, err
+	}
+	return utilization, nil
+}
+
+// recommendInstanceType returns the smallest instance type in a family that fits an instance's peak
+// utilization with headroom. It returns false when nothing smaller than the current type fits.
+// Memory isn't constrained when its utilization is unknown.
+func recommendInstanceType(current instanceTypeSpec, family []instanceTypeSpec, utilization instanceUtilization) (instanceTypeSpec, bool) {
+	requiredVCPUs := float64(current.vcpus) * utilization.cpuPercent / 100 / rightsizingHeadroom
+	requiredMemoryMiB := 0.0
+	if utilization.memoryPercent >= 0 {
+		requiredMemoryMiB = float64(current.memoryMiB) * utilization.memoryPercent / 100 / rightsizingHeadroom
+	}
+	requiredNetworkGbps := utilization.networkMbps / 1000 / rightsizingHeadroom
+
+	for _, candidate := range family {
+		smaller := candidate.vcpus <= current.vcpus && candidate.memoryMiB <= current.memoryMiB &&
+			(candidate.vcpus < current.vcpus || candidate.memoryMiB < current.memoryMiB)
+		if !smaller {
+			continue
+		}
+		if float64(candidate.vcpus) < requiredVCPUs || float64(candidate.memoryMiB) < requiredMemoryMiB {
+			continue
+		}
+		if candidate.networkGbps > 0 && candidate.networkGbps < requiredNetworkGbps {
+			continue
+		}
+		return candidate, true
+	}
+	return instanceTypeSpec{}, false
+}
+
+// calculateRightsizingSavings estimates the savings of moving an instance to a smaller type. It
+// returns nil when either type has no price.
+func (s *EC2InstanceScanner) calculateRightsizingSavings(region, currentType, recommendedType string, launchTime time.Time) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
+
+	var costs []*awslib.CostBreakdown
+	for _, instanceType := range []string{currentType, recommendedType} {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "EC2",
+			ResourceSize: instanceType,
+			Region:       region,
+			CreationTime: launchTime,
+		})
+		if err != nil || cost == nil {
+			logging.Warn("Failed to calculate EC2 rightsizing savings", map[string]interface{}{
+				"region":        region,
+				"instance_type": instanceType,
+				"error":         fmt.Sprint(err),
+			})
+			return nil
+		}
+		costs = append(costs, cost)
+	}
+
+	return &awslib.CostBreakdown{
+		HourlyRate:  costs[0].HourlyRate - costs[1].HourlyRate,
+		DailyRate:   costs[0].DailyRate - costs[1].DailyRate,
+		MonthlyRate: costs[0].MonthlyRate - costs[1].MonthlyRate,
+		YearlyRate:  costs[0].YearlyRate - costs[1].YearlyRate,
+	}
+}
+
+// rightsizeInstance recommends a smaller instance type in the same family for a running instance
+// that isn't idle. It returns nil when the instance is already the smallest in its family or its
+// peak utilization doesn't fit anything smaller.
+func (s *EC2InstanceScanner) rightsizeInstance(opts awslib.ScanOptions, ec2Client *ec2.EC2, cwClient *cloudwatch.CloudWatch, cache *instanceFamilyCache, instance *ec2.Instance, name string, tags map[string]string, startTime, endTime time.Time) *awslib.ScanResult {
+	instanceID := aws.StringValue(instance.InstanceId)
+	instanceType := aws.StringValue(instance.InstanceType)
+
+	family, err := s.getFamilySpecs(opts, ec2Client, cache, instanceFamily(instanceType))
+	if err != nil {
+		logging.Error("Failed to get instance family for rightsizing", err, map[string]interface{}{
+			"instance_id":   instanceID,
+			"instance_type": instanceType,
+		})
+		return nil
+	}
+	var current instanceTypeSpec
+	for _, spec := range family {
+		if spec.name == instanceType {
+			current = spec
+			break
+		}
+	}
+	// The smallest size in a family has nothing to move down to
+	if current.name == "" || len(family) == 0 || family[0].name == instanceType {
+		return nil
+	}
+
+	utilization, err := s.getPeakUtilization(opts, cwClient, instanceID, startTime, endTime)
+	if err != nil {
+		logging.Debug("Skipping rightsizing - utilization unavailable", map[string]interface{}{
+			"instance_id": instanceID,
+			"error":       err.Error(),
+		})
+		return nil
+	}
+
+	recommended, ok := recommendInstanceType(current, family, utilization)
+	if !ok {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"account_id":                opts.AccountID,
+		"region":                    opts.Region,
+		"finding_type":              "rightsize",
+		"instance_id":               instanceID,
+		"instance_type":             instanceType,
+		"recommended_instance_type": recommended.name,
+		"current_vcpus":             current.vcpus,
+		"recommended_vcpus":         recommended.vcpus,
+		"current_memory_gib":        float64(current.memoryMiB) / 1024,
+		"recommended_memory_gib":    float64(recommended.memoryMiB) / 1024,
+		"peak_cpu_percent":          math.Round(utilization.cpuPercent*100) / 100,
+		"peak_network_mbps":         math.Round(utilization.networkMbps*100) / 100,
+		"memory_metrics_available":  utilization.memoryPercent >= 0,
+		"state":                     aws.StringValue(instance.State.Name),
+		"launch_time":               instance.LaunchTime.Format(time.RFC3339),
+		"tags":                      tags,
+	}
+	if utilization.memoryPercent >= 0 {
+		details["peak_memory_percent"] = math.Round(utilization.memoryPercent*100) / 100
+	}
+
+	// Without the CloudWatch agent the recommendation can't account for memory pressure
+	memory := "memory unknown, confirm it fits before resizing"
+	confidence := awslib.ConfidenceLow
+	if utilization.memoryPercent >= 0 {
+		memory = fmt.Sprintf("memory %.1f%%", utilization.memoryPercent)
+		confidence = awslib.ConfidenceMedium
+	}
+	reason := fmt.Sprintf("Downsize recommendation, not a deletion: %s can move to %s based on peak utilization over the last %d days (CPU %.1f%%, %s, network %.1f Mbps)",
+		instanceType, recommended.name, opts.DaysUnused, utilization.cpuPercent, memory, utilization.networkMbps)
+
+	result := &awslib.ScanResult{
+		ResourceType: s.Label(),
+		ResourceID:   instanceID,
+		ResourceName: name,
+		ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "instance/"+instanceID),
+		CreatedAt:    instance.LaunchTime,
+		Reason:       reason,
+		Confidence:   confidence,
+		Details:      details,
+	}
+	if savings := s.calculateRightsizingSavings(opts.Region, instanceType, recommended.name, aws.TimeValue(instance.LaunchTime)); savings != nil {
+		result.Cost = map[string]interface{}{
+			"total": savings,
+		}
+	}
+	return result
+}
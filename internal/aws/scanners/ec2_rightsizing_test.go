@@ -0,0 +1,101 @@
+package scanners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseNetworkGbps tests reading bandwidth from EC2 network performance descriptions
+func TestParseNetworkGbps(t *testing.T) {
+	tests := []struct {
+		performance string
+		expected    float64
+	}{
+		{"Up to 12.5 Gigabit", 12.5},
+		{"25 Gigabit", 25},
+		{"4x 100 Gigabit", 100},
+		{"Moderate", 0.5},
+		{" Low to Moderate ", 0.3},
+		{"High", 1},
+		{"Very Low", 0.05},
+		{"", 0},
+		{"Unknown", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.performance, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, parseNetworkGbps(tt.performance), 0.0001)
+		})
+	}
+}
+
+// TestRecommendInstanceType tests picking the smallest type in a family that fits peak utilization
+// with headroom
+func TestRecommendInstanceType(t *testing.T) {
+	family := []instanceTypeSpec{
+		{name: "m5.large", vcpus: 2, memoryMiB: 8192, networkGbps: 10},
+		{name: "m5.xlarge", vcpus: 4, memoryMiB: 16384, networkGbps: 10},
+		{name: "m5.2xlarge", vcpus: 8, memoryMiB: 32768, networkGbps: 10},
+		{name: "m5.4xlarge", vcpus: 16, memoryMiB: 65536, networkGbps: 10},
+	}
+	m5Large, m52XLarge, m54XLarge := family[0], family[2], family[3]
+
+	tests := []struct {
+		name        string
+		current     instanceTypeSpec
+		utilization instanceUtilization
+		expected    string // Empty when no smaller type fits
+	}{
+		{
+			name:        "memory keeps a larger type than CPU needs",
+			current:     m54XLarge,
+			utilization: instanceUtilization{cpuPercent: 10, memoryPercent: 20},
+			expected:    "m5.xlarge",
+		},
+		{
+			name:        "unknown memory isn't constrained",
+			current:     m54XLarge,
+			utilization: instanceUtilization{cpuPercent: 10, memoryPercent: -1},
+			expected:    "m5.large",
+		},
+		{
+			name:        "peak exactly at the headroom fits",
+			current:     m52XLarge,
+			utilization: instanceUtilization{cpuPercent: 40, memoryPercent: 10},
+			expected:    "m5.xlarge",
+		},
+		{
+			name:        "peak just over the headroom keeps the current type",
+			current:     m52XLarge,
+			utilization: instanceUtilization{cpuPercent: 41, memoryPercent: 10},
+			expected:    "",
+		},
+		{
+			name:        "busy CPU leaves nothing smaller",
+			current:     m54XLarge,
+			utilization: instanceUtilization{cpuPercent: 50, memoryPercent: 10},
+			expected:    "",
+		},
+		{
+			name:        "network throughput beyond every candidate",
+			current:     m54XLarge,
+			utilization: instanceUtilization{cpuPercent: 5, memoryPercent: 5, networkMbps: 9000},
+			expected:    "",
+		},
+		{
+			name:        "smallest type in the family",
+			current:     m5Large,
+			utilization: instanceUtilization{cpuPercent: 1, memoryPercent: 1},
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommended, ok := recommendInstanceType(tt.current, family, tt.utilization)
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, recommended.name)
+		})
+	}
+}
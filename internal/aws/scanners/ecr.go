@@ -0,0 +1,247 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+// ECRImageScanner scans for ECR images that are untagged or haven't been pulled recently
+type ECRImageScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&ECRImageScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *ECRImageScanner) ArgumentName() string {
+	return "ecr-images"
+}
+
+// Label implements Scanner interface
+func (s *ECRImageScanner) Label() string {
+	return "ECR Images"
+}
+
+// IsGlobal implements Scanner interface
+func (s *ECRImageScanner) IsGlobal() bool {
+	return false
+}
+
+// hasLifecyclePolicy reports whether a repository has a lifecycle policy, which may already expire
+// the images it flags
+func (s *ECRImageScanner) hasLifecyclePolicy(opts awslib.ScanOptions, client *ecr.ECR, repositoryName string) (bool, error) {
+	_, err := client.GetLifecyclePolicyWithContext(opts.Context(), &ecr.GetLifecyclePolicyInput{
+		RepositoryName: aws.String(repositoryName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == ecr.ErrCodeLifecyclePolicyNotFoundException {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// getRepositoryTags returns a repository's tags, which images don't have of their own
+func (s *ECRImageScanner) getRepositoryTags(opts awslib.ScanOptions, client *ecr.ECR, repositoryArn string) map[string]string {
+	tags := make(map[string]string)
+	output, err := client.ListTagsForResourceWithContext(opts.Context(), &ecr.ListTagsForResourceInput{
+		ResourceArn: aws.String(repositoryArn),
+	})
+	if err != nil {
+		logging.Debug("Failed to list ECR repository tags", map[string]interface{}{
+			"repository_arn": repositoryArn,
+			"error":          err.Error(),
+		})
+		return tags
+	}
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
+
+// calculateImageCost estimates the cost of storing an image. Layers shared with other images in the
+// repository are only billed once, so this is an upper bound on what deleting the image saves.
+func (s *ECRImageScanner) calculateImageCost(sizeBytes int64, pushedAt time.Time, region string) *awslib.CostBreakdown {
+	sizeGB := float64(sizeBytes) / (1024 * 1024 * 1024)
+
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "ECR",
+			ResourceSize: sizeGB,
+			Region:       region,
+			CreationTime: pushedAt,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to calculate ECR image cost, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.10 * sizeGB / 730 // $0.10 per GB-month in us-east-1
+	hoursRunning := time.Since(pushedAt).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *ECRImageScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := ecr.New(sess)
+
+	var repositories []*ecr.Repository
+	err = client.DescribeRepositoriesPagesWithContext(opts.Context(), &ecr.DescribeRepositoriesInput{},
+		func(page *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
+			repositories = append(repositories, page.Repositories...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to describe ECR repositories", err, nil)
+		return nil, fmt.Errorf("failed to describe ECR repositories: %w", err)
+	}
+
+	var results awslib.ScanResults
+	startTime := time.Now().UTC().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, repository := range repositories {
+		repositoryName := aws.StringValue(repository.RepositoryName)
+		repositoryArn := aws.StringValue(repository.RepositoryArn)
+
+		var images []*ecr.ImageDetail
+		err := client.DescribeImagesPagesWithContext(opts.Context(), &ecr.DescribeImagesInput{
+			RepositoryName: repository.RepositoryName,
+			RegistryId:     repository.RegistryId,
+		}, func(page *ecr.DescribeImagesOutput, lastPage bool) bool {
+			images = append(images, page.ImageDetails...)
+			return !lastPage
+		})
+		if err != nil {
+			logging.Error("Failed to describe ECR images", err, map[string]interface{}{
+				"repository_name": repositoryName,
+			})
+			continue
+		}
+
+		// Repository details are only fetched for repositories with something to report
+		var lifecyclePolicy bool
+		var tags map[string]string
+		repositoryChecked := false
+
+		for _, image := range images {
+			pushedAt := aws.TimeValue(image.ImagePushedAt)
+			if pushedAt.After(startTime) {
+				continue
+			}
+
+			// Images pulled within the window are in use, including untagged ones pulled by digest,
+			// such as the per-platform images of a multi-architecture image
+			lastPull := image.LastRecordedPullTime
+			if lastPull != nil && lastPull.After(startTime) {
+				continue
+			}
+
+			imageTags := aws.StringValueSlice(image.ImageTags)
+			digest := aws.StringValue(image.ImageDigest)
+
+			var findingType, reason, confidence string
+			switch {
+			case len(imageTags) == 0:
+				findingType = "untagged"
+				confidence = awslib.ConfidenceHigh
+				reason = fmt.Sprintf("Image is untagged and has not been pulled in the last %d days", opts.DaysUnused)
+			case lastPull == nil:
+				findingType = "never_pulled"
+				confidence = awslib.ConfidenceMedium
+				reason = fmt.Sprintf("Image has never been pulled since it was pushed on %s", pushedAt.Format("2006-01-02"))
+			default:
+				findingType = "not_pulled"
+				confidence = awslib.ConfidenceMedium
+				reason = fmt.Sprintf("Image has not been pulled in the last %d days (last pulled %s)",
+					opts.DaysUnused, lastPull.Format("2006-01-02"))
+			}
+
+			if !repositoryChecked {
+				repositoryChecked = true
+				lifecyclePolicy, err = s.hasLifecyclePolicy(opts, client, repositoryName)
+				if err != nil {
+					logging.Debug("Failed to get ECR lifecycle policy", map[string]interface{}{
+						"repository_name": repositoryName,
+						"error":           err.Error(),
+					})
+				}
+				tags = s.getRepositoryTags(opts, client, repositoryArn)
+			}
+			if lifecyclePolicy {
+				reason += "; the repository has a lifecycle policy that may already expire it"
+			}
+
+			resourceName := repositoryName + "@" + digest
+			if len(imageTags) > 0 {
+				resourceName = repositoryName + ":" + imageTags[0]
+			}
+
+			sizeBytes := aws.Int64Value(image.ImageSizeInBytes)
+			details := map[string]interface{}{
+				"account_id":       opts.AccountID,
+				"region":           opts.Region,
+				"finding_type":     findingType,
+				"repository_name":  repositoryName,
+				"repository_uri":   aws.StringValue(repository.RepositoryUri),
+				"image_digest":     digest,
+				"image_tags":       imageTags,
+				"image_size_bytes": sizeBytes,
+				"image_size_gb":    float64(sizeBytes) / (1024 * 1024 * 1024),
+				"pushed_at":        pushedAt.Format(time.RFC3339),
+				"last_pulled_at":   "",
+				"lifecycle_policy": lifecyclePolicy,
+			}
+			if lastPull != nil {
+				details["last_pulled_at"] = lastPull.Format(time.RFC3339)
+			}
+
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: resourceName,
+				ResourceID:   repositoryName + "@" + digest,
+				ARN:          repositoryArn, // Images have no ARN of their own
+				CreatedAt:    aws.Time(pushedAt),
+				Reason:       reason,
+				Confidence:   confidence,
+				Details:      details,
+				Tags:         tags,
+				Cost: map[string]interface{}{
+					"total": s.calculateImageCost(sizeBytes, pushedAt, opts.Region),
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
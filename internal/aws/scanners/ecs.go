@@ -0,0 +1,361 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ecs"
+)
+
+const (
+	// ecsIdleCPUPercent is the peak CPU utilization below which a service is treated as having no load
+	ecsIdleCPUPercent = 1.0
+
+	// ecsLowCPUPercent and ecsLowMemoryPercent are the average utilizations below which a service's
+	// tasks are treated as oversized for their work
+	ecsLowCPUPercent    = 5.0
+	ecsLowMemoryPercent = 20.0
+
+	// ecsDescribeServicesBatch is the most services DescribeServices accepts in one call
+	ecsDescribeServicesBatch = 10
+)
+
+// ECSServiceScanner scans for ECS services that keep tasks running with little or no load
+type ECSServiceScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&ECSServiceScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *ECSServiceScanner) ArgumentName() string {
+	return "ecs-services"
+}
+
+// Label implements Scanner interface
+func (s *ECSServiceScanner) Label() string {
+	return "ECS Services"
+}
+
+// IsGlobal implements Scanner interface
+func (s *ECSServiceScanner) IsGlobal() bool {
+	return false
+}
+
+// serviceUtilization holds a service's CPU and memory utilization over the scan window
+type serviceUtilization struct {
+	cpuAverage    float64
+	cpuMaximum    float64
+	memoryAverage float64
+}
+
+// getUtilization returns a service's average and peak utilization. It returns false when the
+// service reported no CPU datapoints.
+func (s *ECSServiceScanner) getUtilization(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, clusterName, serviceName string, startTime, endTime time.Time) (serviceUtilization, bool, error) {
+	var utilization serviceUtilization
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("ClusterName"),
+			Value: aws.String(clusterName),
+		},
+		{
+			Name:  aws.String("ServiceName"),
+			Value: aws.String(serviceName),
+		},
+	}
+
+	for _, metricName := range []string{"CPUUtilization", "MemoryUtilization"} {
+		output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/ECS"),
+			MetricName: aws.String(metricName),
+			Dimensions: dimensions,
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int64(86400), // 1 day
+			Statistics: []*string{aws.String("Average"), aws.String("Maximum")},
+		})
+		if err != nil {
+			return utilization, false, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+		}
+		if len(output.Datapoints) == 0 {
+			if metricName == "CPUUtilization" {
+				return utilization, false, nil
+			}
+			continue
+		}
+
+		var sum, maximum float64
+		for _, dp := range output.Datapoints {
+			sum += aws.Float64Value(dp.Average)
+			maximum = math.Max(maximum, aws.Float64Value(dp.Maximum))
+		}
+		average := sum / float64(len(output.Datapoints))
+		if metricName == "CPUUtilization" {
+			utilization.cpuAverage = average
+			utilization.cpuMaximum = maximum
+		} else {
+			utilization.memoryAverage = average
+		}
+	}
+	return utilization, true, nil
+}
+
+// launchType returns how a service's tasks run: FARGATE, FARGATE_SPOT, EC2 or EXTERNAL
+func (s *ECSServiceScanner) launchType(service *ecs.Service) string {
+	if launchType := aws.StringValue(service.LaunchType); launchType != "" {
+		return launchType
+	}
+	for _, item := range service.CapacityProviderStrategy {
+		if provider := aws.StringValue(item.CapacityProvider); strings.HasPrefix(provider, "FARGATE") {
+			return provider
+		}
+	}
+	// Other capacity providers are Auto Scaling groups of container instances
+	return ecs.LaunchTypeEc2
+}
+
+// calculateFargateCost estimates the cost of a service's Fargate tasks from its task definition's
+// CPU and memory, at the On-Demand rate
+func (s *ECSServiceScanner) calculateFargateCost(taskDefinition *ecs.TaskDefinition, tasks int64, createdAt time.Time, region string) *awslib.CostBreakdown {
+	cpuUnits, _ := strconv.ParseFloat(aws.StringValue(taskDefinition.Cpu), 64)
+	memoryMiB, _ := strconv.ParseFloat(aws.StringValue(taskDefinition.Memory), 64)
+	if cpuUnits == 0 || memoryMiB == 0 {
+		return nil
+	}
+	vcpus := cpuUnits / 1024
+	memoryGB := memoryMiB / 1024
+
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "Fargate",
+			Region:        region,
+			CreationTime:  createdAt,
+			InstanceCount: tasks,
+			VCPUs:         vcpus,
+			MemoryGB:      memoryGB,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to calculate Fargate cost, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := (0.04048*vcpus + 0.004445*memoryGB) * float64(tasks) // us-east-1 Linux/x86 rates
+	hoursRunning := time.Since(createdAt).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// listServices returns the active services in a cluster, with their tags
+func (s *ECSServiceScanner) listServices(opts awslib.ScanOptions, client *ecs.ECS, clusterArn string) ([]*ecs.Service, error) {
+	var serviceArns []*string
+	err := client.ListServicesPagesWithContext(opts.Context(), &ecs.ListServicesInput{
+		Cluster: aws.String(clusterArn),
+	}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+		serviceArns = append(serviceArns, page.ServiceArns...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	var services []*ecs.Service
+	for i := 0; i < len(serviceArns); i += ecsDescribeServicesBatch {
+		end := i + ecsDescribeServicesBatch
+		if end > len(serviceArns) {
+			end = len(serviceArns)
+		}
+		output, err := client.DescribeServicesWithContext(opts.Context(), &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterArn),
+			Services: serviceArns[i:end],
+			Include:  []*string{aws.String(ecs.ServiceFieldTags)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe services: %w", err)
+		}
+		services = append(services, output.Services...)
+	}
+	return services, nil
+}
+
+// Scan implements Scanner interface
+func (s *ECSServiceScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := ecs.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var clusterArns []string
+	err = client.ListClustersPagesWithContext(opts.Context(), &ecs.ListClustersInput{},
+		func(page *ecs.ListClustersOutput, lastPage bool) bool {
+			clusterArns = append(clusterArns, aws.StringValueSlice(page.ClusterArns)...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to list ECS clusters", err, nil)
+		return nil, fmt.Errorf("failed to list ECS clusters: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	// Services often share a task definition revision, so each is only described once
+	taskDefinitions := make(map[string]*ecs.TaskDefinition)
+
+	for _, clusterArn := range clusterArns {
+		// The cluster name is the last element of its ARN
+		clusterName := clusterArn[strings.LastIndex(clusterArn, "/")+1:]
+
+		services, err := s.listServices(opts, client, clusterArn)
+		if err != nil {
+			logging.Error("Failed to list ECS services", err, map[string]interface{}{
+				"cluster_name": clusterName,
+			})
+			continue
+		}
+
+		for _, service := range services {
+			serviceName := aws.StringValue(service.ServiceName)
+			createdAt := aws.TimeValue(service.CreatedAt)
+			desired := aws.Int64Value(service.DesiredCount)
+
+			// Services scaled to zero cost nothing, and new ones don't have a full metric window
+			if aws.StringValue(service.Status) != "ACTIVE" || desired == 0 || createdAt.After(startTime) {
+				continue
+			}
+
+			utilization, ok, err := s.getUtilization(opts, cwClient, clusterName, serviceName, startTime, endTime)
+			if err != nil {
+				logging.Error("Failed to analyze ECS service utilization", err, map[string]interface{}{
+					"cluster_name": clusterName,
+					"service_name": serviceName,
+				})
+				continue
+			}
+			// A service with no datapoints at all can't be told apart from one whose metrics are missing
+			if !ok {
+				continue
+			}
+
+			var findingType, reason, confidence string
+			switch {
+			case utilization.cpuMaximum < ecsIdleCPUPercent:
+				findingType = "no_load"
+				confidence = awslib.ConfidenceMedium
+				reason = fmt.Sprintf("Service keeps %d tasks running but CPU utilization never exceeded %.2f%% in the last %d days",
+					desired, utilization.cpuMaximum, opts.DaysUnused)
+			case utilization.cpuAverage < ecsLowCPUPercent && utilization.memoryAverage < ecsLowMemoryPercent:
+				findingType = "low_utilization"
+				confidence = awslib.ConfidenceLow
+				reason = fmt.Sprintf("Service tasks averaged %.2f%% CPU and %.2f%% memory utilization in the last %d days",
+					utilization.cpuAverage, utilization.memoryAverage, opts.DaysUnused)
+			default:
+				continue
+			}
+
+			taskDefinitionArn := aws.StringValue(service.TaskDefinition)
+			taskDefinition, ok := taskDefinitions[taskDefinitionArn]
+			if !ok {
+				output, err := client.DescribeTaskDefinitionWithContext(opts.Context(), &ecs.DescribeTaskDefinitionInput{
+					TaskDefinition: aws.String(taskDefinitionArn),
+				})
+				if err != nil {
+					logging.Debug("Failed to describe ECS task definition", map[string]interface{}{
+						"task_definition": taskDefinitionArn,
+						"error":           err.Error(),
+					})
+				} else {
+					taskDefinition = output.TaskDefinition
+				}
+				taskDefinitions[taskDefinitionArn] = taskDefinition
+			}
+
+			launchType := s.launchType(service)
+			details := map[string]interface{}{
+				"account_id":             opts.AccountID,
+				"region":                 opts.Region,
+				"finding_type":           findingType,
+				"cluster_name":           clusterName,
+				"launch_type":            launchType,
+				"task_definition":        taskDefinitionArn,
+				"desired_count":          desired,
+				"running_count":          aws.Int64Value(service.RunningCount),
+				"pending_count":          aws.Int64Value(service.PendingCount),
+				"cpu_utilization_avg":    utilization.cpuAverage,
+				"cpu_utilization_max":    utilization.cpuMaximum,
+				"memory_utilization_avg": utilization.memoryAverage,
+				"load_balancers":         len(service.LoadBalancers),
+				"created_at":             createdAt.Format(time.RFC3339),
+			}
+			if taskDefinition != nil {
+				details["task_cpu_units"] = aws.StringValue(taskDefinition.Cpu)
+				details["task_memory_mib"] = aws.StringValue(taskDefinition.Memory)
+			}
+
+			tags := make(map[string]string)
+			for _, tag := range service.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+
+			result := awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: serviceName,
+				ResourceID:   clusterName + "/" + serviceName,
+				ARN:          aws.StringValue(service.ServiceArn),
+				CreatedAt:    aws.Time(createdAt),
+				Reason:       reason,
+				Confidence:   confidence,
+				Details:      details,
+				Tags:         tags,
+			}
+
+			// Tasks on EC2 container instances are billed through the instances, which the EC2
+			// instance scanner already prices, so only Fargate tasks are costed here
+			if strings.HasPrefix(launchType, "FARGATE") {
+				if taskDefinition != nil {
+					if cost := s.calculateFargateCost(taskDefinition, desired, createdAt, opts.Region); cost != nil {
+						result.Cost = map[string]interface{}{
+							"total": cost,
+						}
+					}
+				}
+				if launchType == "FARGATE_SPOT" {
+					details["cost_note"] = "Priced at the On-Demand Fargate rate; Fargate Spot costs less"
+				}
+			} else {
+				details["cost_note"] = "Tasks run on container instances; their cost is reported by the EC2 Instances scanner"
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,265 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/efs"
+)
+
+// EFSScanner scans for EFS file systems that no client is using
+type EFSScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&EFSScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *EFSScanner) ArgumentName() string {
+	return "efs-filesystems"
+}
+
+// Label implements Scanner interface
+func (s *EFSScanner) Label() string {
+	return "EFS File Systems"
+}
+
+// IsGlobal implements Scanner interface
+func (s *EFSScanner) IsGlobal() bool {
+	return false
+}
+
+// fetchMetric fetches a CloudWatch metric for an EFS file system
+func (s *EFSScanner) fetchMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, fileSystemID string, metricName string, startTime, endTime time.Time) (float64, error) {
+	config := utils.MetricConfig{
+		Namespace:     "AWS/EFS",
+		ResourceID:    fileSystemID,
+		DimensionName: "FileSystemId",
+		MetricName:    metricName,
+		Statistic:     "Sum",
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Period:        86400, // 1 day
+	}
+
+	return utils.GetResourceMetrics(opts.Context(), cwClient, config)
+}
+
+// analyzeFileSystemUsage checks whether a file system had any client connections or IO
+func (s *EFSScanner) analyzeFileSystemUsage(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, fileSystemID string, daysUnused int) (bool, error) {
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
+
+	for _, metricName := range []string{"ClientConnections", "DataReadIOBytes", "DataWriteIOBytes"} {
+		value, err := s.fetchMetric(opts, cwClient, fileSystemID, metricName, startTime, endTime)
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+		}
+		if value > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// getLifecyclePolicy returns a readable summary of the file system's lifecycle policies
+func (s *EFSScanner) getLifecyclePolicy(efsClient *efs.EFS, opts awslib.ScanOptions, fileSystemID string) (string, error) {
+	output, err := efsClient.DescribeLifecycleConfigurationWithContext(opts.Context(), &efs.DescribeLifecycleConfigurationInput{
+		FileSystemId: aws.String(fileSystemID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe lifecycle configuration: %w", err)
+	}
+
+	var policies []string
+	for _, policy := range output.LifecyclePolicies {
+		if policy.TransitionToIA != nil {
+			policies = append(policies, aws.StringValue(policy.TransitionToIA))
+		}
+		if policy.TransitionToPrimaryStorageClass != nil {
+			policies = append(policies, aws.StringValue(policy.TransitionToPrimaryStorageClass))
+		}
+	}
+	if len(policies) == 0 {
+		return "NONE", nil
+	}
+
+	return strings.Join(policies, ","), nil
+}
+
+// calculateFileSystemCost estimates the cost of a file system from stored GB and provisioned throughput
+func (s *EFSScanner) calculateFileSystemCost(fileSystem *efs.FileSystemDescription, sizeGB int64, region string) (*awslib.CostBreakdown, error) {
+	creationTime := aws.TimeValue(fileSystem.CreationTime)
+
+	var provisionedThroughput float64
+	if aws.StringValue(fileSystem.ThroughputMode) == efs.ThroughputModeProvisioned {
+		provisionedThroughput = aws.Float64Value(fileSystem.ProvisionedThroughputInMibps)
+	}
+
+	config := awslib.ResourceCostConfig{
+		ResourceType:          "EFS",
+		Region:                region,
+		CreationTime:          creationTime,
+		StorageSize:           sizeGB,
+		ProvisionedThroughput: provisionedThroughput,
+	}
+
+	if awslib.DefaultCostEstimator == nil {
+		// Only use hardcoded values if the cost estimator is not available
+		hoursRunning := time.Since(creationTime).Hours()
+		hourlyRate := (0.30*float64(sizeGB) + 6.00*provisionedThroughput) / 730
+
+		return &awslib.CostBreakdown{
+			HourlyRate:   hourlyRate,
+			DailyRate:    hourlyRate * 24,
+			MonthlyRate:  hourlyRate * 24 * 30,
+			YearlyRate:   hourlyRate * 24 * 365,
+			HoursRunning: aws.Float64(hoursRunning),
+			Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+		}, nil
+	}
+
+	return awslib.DefaultCostEstimator.CalculateCost(config)
+}
+
+// Scan implements Scanner interface
+func (s *EFSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	// Create EFS and CloudWatch service clients
+	efsClient := efs.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var fileSystems []*efs.FileSystemDescription
+	err = efsClient.DescribeFileSystemsPagesWithContext(opts.Context(), &efs.DescribeFileSystemsInput{}, func(page *efs.DescribeFileSystemsOutput, lastPage bool) bool {
+		fileSystems = append(fileSystems, page.FileSystems...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe EFS file systems", err, nil)
+		return nil, fmt.Errorf("failed to describe EFS file systems: %w", err)
+	}
+
+	var results awslib.ScanResults
+
+	for _, fileSystem := range fileSystems {
+		fileSystemID := aws.StringValue(fileSystem.FileSystemId)
+
+		// Skip file systems that are still being created or are being deleted
+		if aws.StringValue(fileSystem.LifeCycleState) != efs.LifeCycleStateAvailable {
+			logging.Debug("Skipping EFS file system not in 'available' state", map[string]interface{}{
+				"file_system_id": fileSystemID,
+				"state":          aws.StringValue(fileSystem.LifeCycleState),
+			})
+			continue
+		}
+
+		isUnused, err := s.analyzeFileSystemUsage(opts, cwClient, fileSystemID, opts.DaysUnused)
+		if err != nil {
+			logging.Error("Failed to analyze EFS file system usage", err, map[string]interface{}{
+				"file_system_id": fileSystemID,
+			})
+			continue
+		}
+		if !isUnused {
+			continue
+		}
+
+		mountTargets := aws.Int64Value(fileSystem.NumberOfMountTargets)
+		var reason string
+		confidence := awslib.ConfidenceMedium
+		if mountTargets == 0 {
+			confidence = awslib.ConfidenceHigh
+			reason = fmt.Sprintf("File system has no mount targets and no client connections or IO in the last %d days", opts.DaysUnused)
+		} else {
+			reason = fmt.Sprintf("File system has %d mount target(s) but no client connections or IO in the last %d days", mountTargets, opts.DaysUnused)
+		}
+
+		var sizeBytes int64
+		if fileSystem.SizeInBytes != nil {
+			sizeBytes = aws.Int64Value(fileSystem.SizeInBytes.Value)
+		}
+		// EFS bills fractional GB, so round up rather than report small file systems as free
+		sizeGB := int64(math.Ceil(float64(sizeBytes) / (1024 * 1024 * 1024)))
+
+		lifecyclePolicy, err := s.getLifecyclePolicy(efsClient, opts, fileSystemID)
+		if err != nil {
+			logging.Warn("Failed to get EFS lifecycle policy", map[string]interface{}{
+				"file_system_id": fileSystemID,
+				"error":          err.Error(),
+			})
+			lifecyclePolicy = "UNKNOWN"
+		}
+
+		cost, err := s.calculateFileSystemCost(fileSystem, sizeGB, opts.Region)
+		if err != nil {
+			logging.Error("Failed to calculate EFS file system cost", err, map[string]interface{}{
+				"file_system_id": fileSystemID,
+			})
+		}
+
+		// Extract all tags
+		tags := make(map[string]string)
+		for _, tag := range fileSystem.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		fileSystemName := aws.StringValue(fileSystem.Name)
+		if fileSystemName == "" {
+			fileSystemName = fileSystemID
+		}
+
+		creationTime := aws.TimeValue(fileSystem.CreationTime)
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: fileSystemName,
+			ResourceID:   fileSystemID,
+			ARN:          aws.StringValue(fileSystem.FileSystemArn),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       reason,
+			Confidence:   confidence,
+			Details: map[string]interface{}{
+				"account_id":       opts.AccountID,
+				"region":           opts.Region,
+				"size_bytes":       sizeBytes,
+				"throughput_mode":  aws.StringValue(fileSystem.ThroughputMode),
+				"performance_mode": aws.StringValue(fileSystem.PerformanceMode),
+				"lifecycle_policy": lifecyclePolicy,
+				"mount_targets":    mountTargets,
+				"encrypted":        aws.BoolValue(fileSystem.Encrypted),
+				"creation_time":    creationTime,
+				"hours_running":    time.Since(creationTime).Hours(),
+			},
+			Tags: tags,
+		}
+		if provisioned := aws.Float64Value(fileSystem.ProvisionedThroughputInMibps); provisioned > 0 {
+			result.Details["provisioned_throughput_mibps"] = provisioned
+		}
+		if cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
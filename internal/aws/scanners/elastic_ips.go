@@ -28,6 +28,11 @@ func (s *ElasticIPScanner) Label() string {
 	return "Elastic IPs"
 }
 
+// IsGlobal implements Scanner interface
+func (s *ElasticIPScanner) IsGlobal() bool {
+	return false
+}
+
 // Scan implements Scanner interface
 func (s *ElasticIPScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	// Get regional session
@@ -92,7 +97,9 @@ func (s *ElasticIPScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, er
 				ResourceType: s.Label(),
 				ResourceName: resourceName,
 				ResourceID:   allocationID,
+				ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "elastic-ip/"+allocationID),
 				Reason:       "Not associated with any resource",
+				Confidence:   awslib.ConfidenceHigh,
 				Details: map[string]interface{}{
 					"account_id":               opts.AccountID,
 					"region":                   opts.Region,
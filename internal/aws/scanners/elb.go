@@ -37,6 +37,11 @@ func (s *ELBScanner) Label() string {
 	return "Load Balancers"
 }
 
+// IsGlobal implements Scanner interface
+func (s *ELBScanner) IsGlobal() bool {
+	return false
+}
+
 // getLoadBalancerName gets the name from tags or ARN
 func (s *ELBScanner) getLoadBalancerName(elbClient *elbv2.ELBV2, lb *elbv2.LoadBalancer) string {
 	// First try to get name from tags
@@ -151,7 +156,7 @@ func (s *ELBScanner) getLoadBalancerMetrics(cwClient *cloudwatch.CloudWatch, lb
 	}
 
 	// Get request count metrics
-	requestData, err := cwClient.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+	requestData, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
 		Namespace:  aws.String(namespace),
 		MetricName: aws.String(requestMetric),
 		Dimensions: []*cloudwatch.Dimension{
@@ -172,7 +177,7 @@ func (s *ELBScanner) getLoadBalancerMetrics(cwClient *cloudwatch.CloudWatch, lb
 	}
 
 	// Get bytes processed metrics
-	bytesData, err := cwClient.GetMetricStatistics(&cloudwatch.GetMetricStatisticsInput{
+	bytesData, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
 		Namespace:  aws.String(namespace),
 		MetricName: aws.String(bytesMetric),
 		Dimensions: []*cloudwatch.Dimension{
@@ -227,8 +232,9 @@ func (s *ELBScanner) getLoadBalancerMetrics(cwClient *cloudwatch.CloudWatch, lb
 	}, nil
 }
 
-// isUnusedLoadBalancer determines if a load balancer is unused based on metrics and attached resources
-func (s *ELBScanner) isUnusedLoadBalancer(elbClient *elbv2.ELBV2, classicClient *elb.ELB, lb interface{}, metrics map[string]interface{}, opts awslib.ScanOptions) (bool, string) {
+// isUnusedLoadBalancer determines if a load balancer is unused based on metrics and attached resources,
+// returning the reason and how confident that finding is
+func (s *ELBScanner) isUnusedLoadBalancer(elbClient *elbv2.ELBV2, classicClient *elb.ELB, lb interface{}, metrics map[string]interface{}, opts awslib.ScanOptions) (bool, string, string) {
 	// First check if there are any attached resources
 	hasResources, err := s.hasAttachedResources(elbClient, classicClient, lb)
 	if err != nil {
@@ -236,12 +242,12 @@ func (s *ELBScanner) isUnusedLoadBalancer(elbClient *elbv2.ELBV2, classicClient
 			"lb_arn": aws.StringValue(lb.(*elbv2.LoadBalancer).LoadBalancerArn),
 		})
 	} else if !hasResources {
-		return true, "No resources attached"
+		return true, "No resources attached", awslib.ConfidenceHigh
 	}
 
 	// Check if we have enough datapoints
 	if metrics["DatapointCount"].(float64) < MetricDatapointThreshold {
-		return false, ""
+		return false, "", ""
 	}
 
 	totalRequests := metrics["TotalRequests"].(float64)
@@ -249,14 +255,14 @@ func (s *ELBScanner) isUnusedLoadBalancer(elbClient *elbv2.ELBV2, classicClient
 	requestDeviation := metrics["RequestDeviation"].(float64)
 
 	if totalRequests == 0 && totalBytes == 0 {
-		return true, fmt.Sprintf("No traffic recorded during the threshold period of %d days", opts.DaysUnused)
+		return true, fmt.Sprintf("No traffic recorded during the threshold period of %d days", opts.DaysUnused), awslib.ConfidenceMedium
 	}
 
 	if requestDeviation < RequestDeviationThreshold {
-		return true, fmt.Sprintf("Very low traffic variation (%.2f) over %d days", requestDeviation, opts.DaysUnused)
+		return true, fmt.Sprintf("Very low traffic variation (%.2f) over %d days", requestDeviation, opts.DaysUnused), awslib.ConfidenceLow
 	}
 
-	return false, ""
+	return false, "", ""
 }
 
 // calculateELBCosts calculates costs for a load balancer using fixed hourly rates
@@ -318,7 +324,7 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	var loadBalancers []*elbv2.LoadBalancer
 	input := &elbv2.DescribeLoadBalancersInput{}
 
-	err = elbv2Client.DescribeLoadBalancersPages(input,
+	err = elbv2Client.DescribeLoadBalancersPagesWithContext(opts.Context(), input,
 		func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
 			loadBalancers = append(loadBalancers, page.LoadBalancers...)
 			return !lastPage
@@ -350,7 +356,7 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 		}
 
 		// Check if unused based on metrics and resources
-		isUnused, reason := s.isUnusedLoadBalancer(elbv2Client, elbClassicClient, lb, metrics, opts)
+		isUnused, reason, confidence := s.isUnusedLoadBalancer(elbv2Client, elbClassicClient, lb, metrics, opts)
 		if !isUnused {
 			continue
 		}
@@ -406,7 +412,10 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 			ResourceType: s.Label(),
 			ResourceName: lbName,
 			ResourceID:   aws.StringValue(lb.LoadBalancerArn),
+			ARN:          aws.StringValue(lb.LoadBalancerArn),
+			CreatedAt:    lb.CreatedTime,
 			Reason:       reason,
+			Confidence:   confidence,
 			Tags:         tags,
 			Details:      details,
 			Cost: map[string]interface{}{
@@ -419,7 +428,7 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	var classicLoadBalancers []*elb.LoadBalancerDescription
 	classicInput := &elb.DescribeLoadBalancersInput{}
 
-	err = elbClassicClient.DescribeLoadBalancersPages(classicInput,
+	err = elbClassicClient.DescribeLoadBalancersPagesWithContext(opts.Context(), classicInput,
 		func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
 			classicLoadBalancers = append(classicLoadBalancers, page.LoadBalancerDescriptions...)
 			return !lastPage
@@ -448,7 +457,7 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 		}
 
 		// Check if unused based on metrics and resources
-		isUnused, reason := s.isUnusedLoadBalancer(elbv2Client, elbClassicClient, lb, metrics, opts)
+		isUnused, reason, confidence := s.isUnusedLoadBalancer(elbv2Client, elbClassicClient, lb, metrics, opts)
 		if !isUnused {
 			continue
 		}
@@ -539,7 +548,10 @@ func (s *ELBScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 			ResourceType: s.Label(),
 			ResourceName: lbName,
 			ResourceID:   aws.StringValue(lb.LoadBalancerName),
+			ARN:          awslib.ResourceARN("elasticloadbalancing", opts.Region, opts.AccountID, "loadbalancer/"+aws.StringValue(lb.LoadBalancerName)),
+			CreatedAt:    lb.CreatedTime,
 			Reason:       reason,
+			Confidence:   confidence,
 			Tags:         tags,
 			Details:      details,
 			Cost: map[string]interface{}{
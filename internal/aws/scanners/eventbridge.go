@@ -0,0 +1,370 @@
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/sfn"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// EventBridgeRuleScanner scans for EventBridge rules that have been disabled for a long time or
+// whose targets no longer exist
+type EventBridgeRuleScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&EventBridgeRuleScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *EventBridgeRuleScanner) ArgumentName() string {
+	return "eventbridge-rules"
+}
+
+// Label implements Scanner interface
+func (s *EventBridgeRuleScanner) Label() string {
+	return "EventBridge Rules"
+}
+
+// IsGlobal implements Scanner interface
+func (s *EventBridgeRuleScanner) IsGlobal() bool {
+	return false
+}
+
+// targetChecker reports whether the targets of a region's rules still exist. Results are cached,
+// since many rules often share a target.
+type targetChecker struct {
+	opts   awslib.ScanOptions
+	sess   *session.Session
+	exists map[string]bool
+}
+
+// targetExists returns whether the resource a target ARN points to still exists. Targets of
+// services it can't check, or in other accounts and regions, are assumed to exist.
+func (c *targetChecker) targetExists(targetArn string) (bool, error) {
+	if exists, ok := c.exists[targetArn]; ok {
+		return exists, nil
+	}
+
+	parsed, err := arn.Parse(targetArn)
+	if err != nil || parsed.Region != c.opts.Region || (c.opts.AccountID != "" && parsed.AccountID != c.opts.AccountID) {
+		return true, nil
+	}
+
+	ctx := c.opts.Context()
+	var notFoundCode string
+	switch parsed.Service {
+	case "lambda":
+		notFoundCode = lambda.ErrCodeResourceNotFoundException
+		_, err = lambda.New(c.sess).GetFunctionWithContext(ctx, &lambda.GetFunctionInput{
+			FunctionName: aws.String(targetArn),
+		})
+	case "sqs":
+		notFoundCode = sqs.ErrCodeQueueDoesNotExist
+		_, err = sqs.New(c.sess).GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{
+			QueueName:              aws.String(parsed.Resource),
+			QueueOwnerAWSAccountId: aws.String(parsed.AccountID),
+		})
+	case "sns":
+		notFoundCode = sns.ErrCodeNotFoundException
+		_, err = sns.New(c.sess).GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+			TopicArn: aws.String(targetArn),
+		})
+	case "states":
+		notFoundCode = sfn.ErrCodeStateMachineDoesNotExist
+		_, err = sfn.New(c.sess).DescribeStateMachineWithContext(ctx, &sfn.DescribeStateMachineInput{
+			StateMachineArn: aws.String(targetArn),
+		})
+	case "kinesis":
+		notFoundCode = kinesis.ErrCodeResourceNotFoundException
+		_, err = kinesis.New(c.sess).DescribeStreamSummaryWithContext(ctx, &kinesis.DescribeStreamSummaryInput{
+			StreamName: aws.String(strings.TrimPrefix(parsed.Resource, "stream/")),
+		})
+	default:
+		return true, nil
+	}
+
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == notFoundCode {
+			c.exists[targetArn] = false
+			return false, nil
+		}
+		return true, fmt.Errorf("failed to look up target %s: %w", targetArn, err)
+	}
+	c.exists[targetArn] = true
+	return true, nil
+}
+
+// listEventBuses returns the names of the region's event buses
+func (s *EventBridgeRuleScanner) listEventBuses(opts awslib.ScanOptions, client *eventbridge.EventBridge) ([]string, error) {
+	var names []string
+	input := &eventbridge.ListEventBusesInput{}
+	for {
+		output, err := client.ListEventBusesWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list event buses: %w", err)
+		}
+		for _, bus := range output.EventBuses {
+			names = append(names, aws.StringValue(bus.Name))
+		}
+		if output.NextToken == nil {
+			return names, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// listRules returns the rules on an event bus
+func (s *EventBridgeRuleScanner) listRules(opts awslib.ScanOptions, client *eventbridge.EventBridge, eventBusName string) ([]*eventbridge.Rule, error) {
+	var rules []*eventbridge.Rule
+	input := &eventbridge.ListRulesInput{
+		EventBusName: aws.String(eventBusName),
+	}
+	for {
+		output, err := client.ListRulesWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rules on event bus %s: %w", eventBusName, err)
+		}
+		rules = append(rules, output.Rules...)
+		if output.NextToken == nil {
+			return rules, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// listTargets returns a rule's targets
+func (s *EventBridgeRuleScanner) listTargets(opts awslib.ScanOptions, client *eventbridge.EventBridge, rule *eventbridge.Rule) ([]*eventbridge.Target, error) {
+	var targets []*eventbridge.Target
+	input := &eventbridge.ListTargetsByRuleInput{
+		Rule:         rule.Name,
+		EventBusName: rule.EventBusName,
+	}
+	for {
+		output, err := client.ListTargetsByRuleWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list targets: %w", err)
+		}
+		targets = append(targets, output.Targets...)
+		if output.NextToken == nil {
+			return targets, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// getTriggeredCount returns how many times a rule matched an event between startTime and endTime.
+// The metric is only published when the rule matches, so no datapoints means it never did.
+func (s *EventBridgeRuleScanner) getTriggeredCount(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, rule *eventbridge.Rule, startTime, endTime time.Time) (float64, error) {
+	dimensions := []*cloudwatch.Dimension{
+		{
+			Name:  aws.String("RuleName"),
+			Value: rule.Name,
+		},
+	}
+	// Rules on custom event buses are also dimensioned by their bus
+	if busName := aws.StringValue(rule.EventBusName); busName != "" && busName != "default" {
+		dimensions = append([]*cloudwatch.Dimension{{
+			Name:  aws.String("EventBusName"),
+			Value: aws.String(busName),
+		}}, dimensions...)
+	}
+
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/Events"),
+		MetricName: aws.String("TriggeredRules"),
+		Dimensions: dimensions,
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Sum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch TriggeredRules metric: %w", err)
+	}
+
+	var triggered float64
+	for _, dp := range output.Datapoints {
+		triggered += aws.Float64Value(dp.Sum)
+	}
+	return triggered, nil
+}
+
+// getTags returns a rule's tags
+func (s *EventBridgeRuleScanner) getTags(opts awslib.ScanOptions, client *eventbridge.EventBridge, ruleArn string) map[string]string {
+	tags := make(map[string]string)
+	output, err := client.ListTagsForResourceWithContext(opts.Context(), &eventbridge.ListTagsForResourceInput{
+		ResourceARN: aws.String(ruleArn),
+	})
+	if err != nil {
+		logging.Debug("Failed to list EventBridge rule tags", map[string]interface{}{
+			"rule_arn": ruleArn,
+			"error":    err.Error(),
+		})
+		return tags
+	}
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
+
+// Scan implements Scanner interface
+func (s *EventBridgeRuleScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := eventbridge.New(sess)
+	cwClient := cloudwatch.New(sess)
+	checker := &targetChecker{
+		opts:   opts,
+		sess:   sess,
+		exists: make(map[string]bool),
+	}
+
+	eventBuses, err := s.listEventBuses(opts, client)
+	if err != nil {
+		logging.Error("Failed to list EventBridge event buses", err, nil)
+		return nil, err
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, eventBusName := range eventBuses {
+		rules, err := s.listRules(opts, client, eventBusName)
+		if err != nil {
+			logging.Error("Failed to list EventBridge rules", err, map[string]interface{}{
+				"event_bus_name": eventBusName,
+			})
+			continue
+		}
+
+		for _, rule := range rules {
+			name := aws.StringValue(rule.Name)
+			ruleArn := aws.StringValue(rule.Arn)
+
+			// Rules managed by other AWS services are removed with the resources that created them
+			if aws.StringValue(rule.ManagedBy) != "" {
+				continue
+			}
+
+			targets, err := s.listTargets(opts, client, rule)
+			if err != nil {
+				logging.Error("Failed to analyze EventBridge rule targets", err, map[string]interface{}{
+					"rule_name": name,
+				})
+				continue
+			}
+			targetArns := make([]string, 0, len(targets))
+			targetIDs := make([]string, 0, len(targets))
+			for _, target := range targets {
+				targetArns = append(targetArns, aws.StringValue(target.Arn))
+				targetIDs = append(targetIDs, aws.StringValue(target.Id))
+			}
+
+			var findingType, reason string
+			missingTargets := []string{}
+			missingTargetIDs := []string{}
+			if aws.StringValue(rule.State) == eventbridge.RuleStateDisabled {
+				// Rules don't record when they were disabled, but a disabled rule doesn't match events,
+				// so one that hasn't matched any for the whole period was disabled at least that long ago
+				triggered, err := s.getTriggeredCount(opts, cwClient, rule, startTime, endTime)
+				if err != nil {
+					logging.Error("Failed to analyze EventBridge rule activity", err, map[string]interface{}{
+						"rule_name": name,
+					})
+					continue
+				}
+				if triggered > 0 {
+					continue
+				}
+				findingType = "disabled"
+				reason = fmt.Sprintf("Rule is disabled and has not matched any events in the last %d days", opts.DaysUnused)
+			} else if len(targetArns) == 0 {
+				findingType = "no_targets"
+				reason = "Rule is enabled but has no targets"
+			} else {
+				for i, targetArn := range targetArns {
+					exists, err := checker.targetExists(targetArn)
+					if err != nil {
+						logging.Debug("Failed to check EventBridge rule target", map[string]interface{}{
+							"rule_name":  name,
+							"target_arn": targetArn,
+							"error":      err.Error(),
+						})
+						continue
+					}
+					if !exists {
+						missingTargets = append(missingTargets, targetArn)
+						missingTargetIDs = append(missingTargetIDs, targetIDs[i])
+					}
+				}
+				if len(missingTargets) == 0 {
+					continue
+				}
+				findingType = "missing_targets"
+				reason = fmt.Sprintf("Rule is enabled but %d of its %d targets no longer exist", len(missingTargets), len(targetArns))
+			}
+
+			details := map[string]interface{}{
+				"account_id":      opts.AccountID,
+				"region":          opts.Region,
+				"finding_type":    findingType,
+				"event_bus_name":  eventBusName,
+				"state":           aws.StringValue(rule.State),
+				"target_arns":     targetArns,
+				"target_ids":      targetIDs,
+				"missing_targets": missingTargets,
+				// IDs are needed to remove the missing targets from the rule
+				"missing_target_ids": missingTargetIDs,
+			}
+			if rule.ScheduleExpression != nil {
+				details["schedule_expression"] = aws.StringValue(rule.ScheduleExpression)
+			}
+			if rule.EventPattern != nil {
+				details["event_pattern"] = aws.StringValue(rule.EventPattern)
+			}
+			if rule.Description != nil {
+				details["description"] = aws.StringValue(rule.Description)
+			}
+
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: name,
+				ResourceID:   ruleArn,
+				ARN:          ruleArn,
+				Reason:       reason,
+				Confidence:   awslib.ConfidenceHigh,
+				Details:      details,
+				Tags:         s.getTags(opts, client, ruleArn),
+				// Rules cost nothing; they are reported as clutter rather than for savings
+				Cost: map[string]interface{}{
+					"total": &awslib.CostBreakdown{},
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
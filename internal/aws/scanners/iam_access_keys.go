@@ -0,0 +1,318 @@
+package scanners
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+const (
+	// credentialReportPollInterval is how long to wait between credential report generation checks
+	credentialReportPollInterval = 2 * time.Second
+
+	// rootAccountUser is the user name the credential report uses for the account root user
+	rootAccountUser = "<root_account>"
+)
+
+// IAMAccessKeyScanner scans for unused or stale IAM access keys and console passwords
+type IAMAccessKeyScanner struct{}
+
+// credentialReportKey holds the credential report columns for one access key slot
+type credentialReportKey struct {
+	Slot        int
+	Active      bool
+	LastRotated *time.Time
+	LastUsed    *time.Time
+	LastService string
+}
+
+// credentialReportEntry holds the credential report columns for a single user
+type credentialReportEntry struct {
+	User             string
+	ARN              string
+	CreatedAt        *time.Time
+	PasswordEnabled  bool
+	PasswordLastUsed *time.Time
+	PasswordChanged  *time.Time
+	MFAActive        bool
+	AccessKeys       []credentialReportKey
+}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&IAMAccessKeyScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *IAMAccessKeyScanner) ArgumentName() string {
+	return "iam-access-keys"
+}
+
+// Label implements Scanner interface
+func (s *IAMAccessKeyScanner) Label() string {
+	return "IAM Access Keys"
+}
+
+// IsGlobal implements Scanner interface
+func (s *IAMAccessKeyScanner) IsGlobal() bool {
+	return true
+}
+
+// getCredentialReport generates the account's IAM credential report and returns its contents
+func (s *IAMAccessKeyScanner) getCredentialReport(ctx context.Context, iamClient *iam.IAM) ([]byte, error) {
+	for {
+		output, err := iamClient.GenerateCredentialReportWithContext(ctx, &iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate credential report: %w", err)
+		}
+		if aws.StringValue(output.State) == iam.ReportStateTypeComplete {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(credentialReportPollInterval):
+		}
+	}
+
+	report, err := iamClient.GetCredentialReportWithContext(ctx, &iam.GetCredentialReportInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential report: %w", err)
+	}
+
+	return report.Content, nil
+}
+
+// parseReportTime parses a credential report timestamp, returning nil for "N/A" style values
+func parseReportTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseCredentialReport parses the CSV credential report into one entry per user
+func parseCredentialReport(content []byte) ([]credentialReportEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential report header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	var entries []credentialReportEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credential report row: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		entry := credentialReportEntry{
+			User:             field("user"),
+			ARN:              field("arn"),
+			CreatedAt:        parseReportTime(field("user_creation_time")),
+			PasswordEnabled:  field("password_enabled") == "true",
+			PasswordLastUsed: parseReportTime(field("password_last_used")),
+			PasswordChanged:  parseReportTime(field("password_last_changed")),
+			MFAActive:        field("mfa_active") == "true",
+		}
+		for slot := 1; slot <= 2; slot++ {
+			prefix := fmt.Sprintf("access_key_%d_", slot)
+			entry.AccessKeys = append(entry.AccessKeys, credentialReportKey{
+				Slot:        slot,
+				Active:      field(prefix+"active") == "true",
+				LastRotated: parseReportTime(field(prefix + "last_rotated")),
+				LastUsed:    parseReportTime(field(prefix + "last_used_date")),
+				LastService: field(prefix + "last_used_service"),
+			})
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isOlderThan reports whether t is more than days in the past
+func isOlderThan(t *time.Time, now time.Time, days int) bool {
+	return t != nil && now.Sub(*t).Hours()/24 > float64(days)
+}
+
+// determineCredentialReasons returns why a user's credentials are considered stale
+func (s *IAMAccessKeyScanner) determineCredentialReasons(entry credentialReportEntry, now time.Time, daysUnused int) []string {
+	var reasons []string
+	recentlyUsed := false
+	hasCredentials := false
+
+	if entry.PasswordEnabled {
+		hasCredentials = true
+		switch {
+		case entry.PasswordLastUsed == nil && isOlderThan(entry.CreatedAt, now, daysUnused):
+			reasons = append(reasons, "Console password has never been used")
+		case isOlderThan(entry.PasswordLastUsed, now, daysUnused):
+			reasons = append(reasons, fmt.Sprintf("Console password has not been used in %s", utils.FormatTimeDifference(now, entry.PasswordLastUsed)))
+		case entry.PasswordLastUsed != nil:
+			recentlyUsed = true
+		}
+		if isOlderThan(entry.PasswordChanged, now, daysUnused) {
+			reasons = append(reasons, fmt.Sprintf("Console password has not been changed in %s", utils.FormatTimeDifference(now, entry.PasswordChanged)))
+		}
+	}
+
+	for _, key := range entry.AccessKeys {
+		if !key.Active {
+			continue
+		}
+		hasCredentials = true
+		switch {
+		case key.LastUsed == nil && isOlderThan(key.LastRotated, now, daysUnused):
+			reasons = append(reasons, fmt.Sprintf("Access key %d has never been used", key.Slot))
+		case isOlderThan(key.LastUsed, now, daysUnused):
+			reasons = append(reasons, fmt.Sprintf("Access key %d has not been used in %s", key.Slot, utils.FormatTimeDifference(now, key.LastUsed)))
+		case key.LastUsed != nil:
+			recentlyUsed = true
+		}
+		if isOlderThan(key.LastRotated, now, daysUnused) {
+			reasons = append(reasons, fmt.Sprintf("Access key %d has not been rotated in %s", key.Slot, utils.FormatTimeDifference(now, key.LastRotated)))
+		}
+	}
+
+	// A user whose credentials all sit idle is inactive as a whole
+	if hasCredentials && !recentlyUsed && len(reasons) > 0 {
+		reasons = append([]string{fmt.Sprintf("User has no credential activity in the last %d days", daysUnused)}, reasons...)
+	}
+
+	return reasons
+}
+
+// getAccessKeyIDs returns the user's access key IDs keyed by key creation time, so they can be
+// matched with the credential report's key slots
+func (s *IAMAccessKeyScanner) getAccessKeyIDs(ctx context.Context, iamClient *iam.IAM, userName string) (map[int64]string, error) {
+	output, err := iamClient.ListAccessKeysWithContext(ctx, &iam.ListAccessKeysInput{
+		UserName: aws.String(userName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access keys: %w", err)
+	}
+
+	keyIDs := make(map[int64]string, len(output.AccessKeyMetadata))
+	for _, key := range output.AccessKeyMetadata {
+		keyIDs[aws.TimeValue(key.CreateDate).Unix()] = aws.StringValue(key.AccessKeyId)
+	}
+	return keyIDs, nil
+}
+
+// Scan implements Scanner interface
+func (s *IAMAccessKeyScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	iamClient := iam.New(sess)
+	ctx := opts.Context()
+
+	content, err := s.getCredentialReport(ctx, iamClient)
+	if err != nil {
+		logging.Error("Failed to get IAM credential report", err, nil)
+		return nil, err
+	}
+
+	entries, err := parseCredentialReport(content)
+	if err != nil {
+		logging.Error("Failed to parse IAM credential report", err, nil)
+		return nil, err
+	}
+
+	var results awslib.ScanResults
+	now := time.Now()
+
+	for _, entry := range entries {
+		reasons := s.determineCredentialReasons(entry, now, opts.DaysUnused)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		// Look up key IDs so findings can be acted on; the credential report only has slots
+		var keyIDs map[int64]string
+		if entry.User != rootAccountUser {
+			keyIDs, err = s.getAccessKeyIDs(ctx, iamClient, entry.User)
+			if err != nil {
+				logging.Warn("Failed to list access keys", map[string]interface{}{
+					"user_name": entry.User,
+					"error":     err.Error(),
+				})
+			}
+		}
+
+		var accessKeys []map[string]interface{}
+		for _, key := range entry.AccessKeys {
+			if !key.Active {
+				continue
+			}
+			accessKey := map[string]interface{}{
+				"Slot":         key.Slot,
+				"LastRotated":  formatTimeOrNever(key.LastRotated),
+				"LastUsed":     formatTimeOrNever(key.LastUsed),
+				"LastUsedWith": key.LastService,
+			}
+			if key.LastRotated != nil {
+				if id, ok := keyIDs[key.LastRotated.Unix()]; ok {
+					accessKey["AccessKeyId"] = id
+				}
+			}
+			accessKeys = append(accessKeys, accessKey)
+		}
+
+		details := map[string]interface{}{
+			"PasswordEnabled":  entry.PasswordEnabled,
+			"PasswordLastUsed": formatTimeOrNever(entry.PasswordLastUsed),
+			"MFAActive":        entry.MFAActive,
+			"AccessKeys":       accessKeys,
+			"CreatedAt":        formatTimeOrNever(entry.CreatedAt),
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: entry.User,
+			ResourceID:   entry.ARN,
+			ARN:          entry.ARN,
+			CreatedAt:    entry.CreatedAt,
+			Reason:       strings.Join(reasons, "\n"),
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+		})
+	}
+
+	return results, nil
+}
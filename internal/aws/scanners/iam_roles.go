@@ -119,7 +119,10 @@ func (t *roleTask) processRole(ctx context.Context) (*awslib.ScanResult, error)
 			ResourceType: t.scanner.Label(),
 			ResourceName: roleName,
 			ResourceID:   roleARN,
+			ARN:          roleARN,
+			CreatedAt:    t.role.CreateDate,
 			Reason:       strings.Join(reasons, "\n"),
+			Confidence:   awslib.ConfidenceMedium,
 			Details:      details,
 		}, nil
 	}
@@ -141,6 +144,11 @@ func (s *IAMRoleScanner) Label() string {
 	return "IAM Roles"
 }
 
+// IsGlobal implements Scanner interface
+func (s *IAMRoleScanner) IsGlobal() bool {
+	return true
+}
+
 // isReservedRole checks if the role is reserved (service or AWS reserved)
 func (s *IAMRoleScanner) isReservedRole(roleARN string) bool {
 	return strings.Contains(roleARN, "aws-reserved")
@@ -176,7 +184,7 @@ func (t *roleTask) getRolePolicies(ctx context.Context) ([]*iam.AttachedPolicy,
 	if err := t.rateLimiter.Wait(ctx); err != nil {
 		return nil, nil, nil, fmt.Errorf("rate limit wait error: %w", err)
 	}
-	err := t.iamClient.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{
+	err := t.iamClient.ListAttachedRolePoliciesPagesWithContext(ctx, &iam.ListAttachedRolePoliciesInput{
 		RoleName: aws.String(roleName),
 	}, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
 		attachedPolicies = append(attachedPolicies, page.AttachedPolicies...)
@@ -192,7 +200,7 @@ func (t *roleTask) getRolePolicies(ctx context.Context) ([]*iam.AttachedPolicy,
 	if err := t.rateLimiter.Wait(ctx); err != nil {
 		return nil, nil, nil, fmt.Errorf("rate limit wait error: %w", err)
 	}
-	err = t.iamClient.ListRolePoliciesPages(&iam.ListRolePoliciesInput{
+	err = t.iamClient.ListRolePoliciesPagesWithContext(ctx, &iam.ListRolePoliciesInput{
 		RoleName: aws.String(roleName),
 	}, func(page *iam.ListRolePoliciesOutput, lastPage bool) bool {
 		inlinePolicies = append(inlinePolicies, aws.StringValueSlice(page.PolicyNames)...)
@@ -208,7 +216,7 @@ func (t *roleTask) getRolePolicies(ctx context.Context) ([]*iam.AttachedPolicy,
 	if err := t.rateLimiter.Wait(ctx); err != nil {
 		return nil, nil, nil, fmt.Errorf("rate limit wait error: %w", err)
 	}
-	err = t.iamClient.ListInstanceProfilesForRolePages(&iam.ListInstanceProfilesForRoleInput{
+	err = t.iamClient.ListInstanceProfilesForRolePagesWithContext(ctx, &iam.ListInstanceProfilesForRoleInput{
 		RoleName: aws.String(roleName),
 	}, func(page *iam.ListInstanceProfilesForRoleOutput, lastPage bool) bool {
 		instanceProfiles = append(instanceProfiles, page.InstanceProfiles...)
@@ -354,7 +362,7 @@ func (s *IAMRoleScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, erro
 	}()
 
 	// List and process roles
-	err = iamClient.ListRolesPages(&iam.ListRolesInput{},
+	err = iamClient.ListRolesPagesWithContext(opts.Context(), &iam.ListRolesInput{},
 		func(page *iam.ListRolesOutput, lastPage bool) bool {
 			for _, role := range page.Roles {
 				// Skip if we've encountered an error
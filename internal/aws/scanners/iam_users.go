@@ -48,6 +48,11 @@ func (s *IAMUserScanner) Label() string {
 	return "IAM Users"
 }
 
+// IsGlobal implements Scanner interface
+func (s *IAMUserScanner) IsGlobal() bool {
+	return true
+}
+
 // processUser processes a single IAM user and returns a scan result if the user is unused
 func (t *userTask) processUser(ctx context.Context) (*awslib.ScanResult, error) {
 	userName := aws.StringValue(t.user.UserName)
@@ -128,7 +133,10 @@ func (t *userTask) processUser(ctx context.Context) (*awslib.ScanResult, error)
 			ResourceType: t.scanner.Label(),
 			ResourceName: userName,
 			ResourceID:   userARN,
+			ARN:          userARN,
+			CreatedAt:    t.user.CreateDate,
 			Reason:       strings.Join(reasons, "\n"),
+			Confidence:   awslib.ConfidenceMedium,
 			Details:      details,
 		}, nil
 	}
@@ -326,7 +334,7 @@ func (s *IAMUserScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, erro
 	}()
 
 	// List and process users
-	err = iamClient.ListUsersPages(&iam.ListUsersInput{},
+	err = iamClient.ListUsersPagesWithContext(opts.Context(), &iam.ListUsersInput{},
 		func(page *iam.ListUsersOutput, lastPage bool) bool {
 			for _, user := range page.Users {
 				// Skip if we've encountered an error
@@ -0,0 +1,240 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+// KinesisStreamScanner scans for Kinesis Data Streams that receive no data
+type KinesisStreamScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&KinesisStreamScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *KinesisStreamScanner) ArgumentName() string {
+	return "kinesis-streams"
+}
+
+// Label implements Scanner interface
+func (s *KinesisStreamScanner) Label() string {
+	return "Kinesis Streams"
+}
+
+// IsGlobal implements Scanner interface
+func (s *KinesisStreamScanner) IsGlobal() bool {
+	return false
+}
+
+// getStreamMetrics returns the records and bytes written to a stream over the period
+func (s *KinesisStreamScanner) getStreamMetrics(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, streamName string, startTime, endTime time.Time) (map[string]float64, error) {
+	metrics := []utils.MetricConfig{
+		{
+			Namespace:     "AWS/Kinesis",
+			ResourceID:    streamName,
+			DimensionName: "StreamName",
+			MetricName:    "IncomingRecords",
+			Statistic:     "Sum",
+			StartTime:     startTime,
+			EndTime:       endTime,
+		},
+		{
+			Namespace:     "AWS/Kinesis",
+			ResourceID:    streamName,
+			DimensionName: "StreamName",
+			MetricName:    "IncomingBytes",
+			Statistic:     "Sum",
+			StartTime:     startTime,
+			EndTime:       endTime,
+		},
+	}
+
+	results, err := utils.GetResourceMetricsData(opts.Context(), cwClient, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	return results, nil
+}
+
+// calculateStreamCost estimates the cost of a stream. Provisioned streams are billed per open shard,
+// on-demand streams per stream; the data volume charges of an on-demand stream are zero when idle.
+func (s *KinesisStreamScanner) calculateStreamCost(streamMode string, shardCount int64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	count := shardCount
+	if streamMode == kinesis.StreamModeOnDemand {
+		count = 1
+	}
+
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "Kinesis",
+			ResourceSize:  streamMode,
+			Region:        region,
+			CreationTime:  creationTime,
+			InstanceCount: count,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to get Kinesis stream price, using default", map[string]interface{}{
+			"stream_mode": streamMode,
+			"region":      region,
+			"error":       err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.015 * float64(count) // Per shard-hour in us-east-1
+	if streamMode == kinesis.StreamModeOnDemand {
+		hourlyRate = 0.04 // Per stream-hour in us-east-1
+	}
+	hoursRunning := time.Since(creationTime).Hours()
+	lifetime := hourlyRate * hoursRunning
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(lifetime),
+	}
+}
+
+// Scan implements Scanner interface
+func (s *KinesisStreamScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	kinesisClient := kinesis.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var streamNames []string
+	err = kinesisClient.ListStreamsPagesWithContext(opts.Context(), &kinesis.ListStreamsInput{}, func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+		for _, name := range page.StreamNames {
+			streamNames = append(streamNames, aws.StringValue(name))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list Kinesis streams", err, nil)
+		return nil, fmt.Errorf("failed to list Kinesis streams: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, streamName := range streamNames {
+		summaryOutput, err := kinesisClient.DescribeStreamSummaryWithContext(opts.Context(), &kinesis.DescribeStreamSummaryInput{
+			StreamName: aws.String(streamName),
+		})
+		if err != nil {
+			logging.Error("Failed to describe Kinesis stream", err, map[string]interface{}{
+				"stream_name": streamName,
+			})
+			continue
+		}
+		stream := summaryOutput.StreamDescriptionSummary
+
+		// Streams being created or deleted don't bill yet, or won't for much longer
+		status := aws.StringValue(stream.StreamStatus)
+		if status != kinesis.StreamStatusActive && status != kinesis.StreamStatusUpdating {
+			continue
+		}
+
+		// Skip streams too new to have a full metric window
+		creationTime := aws.TimeValue(stream.StreamCreationTimestamp)
+		if creationTime.After(startTime) {
+			continue
+		}
+
+		metrics, err := s.getStreamMetrics(opts, cwClient, streamName, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to get Kinesis stream metrics", err, map[string]interface{}{
+				"stream_name": streamName,
+			})
+			continue
+		}
+		if metrics["IncomingRecords"] > 0 || metrics["IncomingBytes"] > 0 {
+			continue
+		}
+
+		// Streams created before capacity modes existed report no mode and are provisioned
+		streamMode := kinesis.StreamModeProvisioned
+		if stream.StreamModeDetails != nil && aws.StringValue(stream.StreamModeDetails.StreamMode) != "" {
+			streamMode = aws.StringValue(stream.StreamModeDetails.StreamMode)
+		}
+		shardCount := aws.Int64Value(stream.OpenShardCount)
+
+		var reason, recommendation string
+		if streamMode == kinesis.StreamModeOnDemand {
+			reason = fmt.Sprintf("On-demand stream has received no records in the last %d days", opts.DaysUnused)
+			recommendation = "Delete the stream if it is no longer needed"
+		} else {
+			reason = fmt.Sprintf("Provisioned stream with %d shards has received no records in the last %d days", shardCount, opts.DaysUnused)
+			recommendation = "Switch the stream to on-demand capacity, or delete it if it is no longer needed"
+		}
+
+		tags := make(map[string]string)
+		tagsOutput, err := kinesisClient.ListTagsForStreamWithContext(opts.Context(), &kinesis.ListTagsForStreamInput{
+			StreamName: aws.String(streamName),
+		})
+		if err != nil {
+			logging.Debug("Failed to get Kinesis stream tags", map[string]interface{}{
+				"stream_name": streamName,
+				"error":       err.Error(),
+			})
+		} else {
+			for _, tag := range tagsOutput.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+		}
+
+		cost := s.calculateStreamCost(streamMode, shardCount, creationTime, opts.Region)
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: streamName,
+			ResourceID:   streamName,
+			ARN:          aws.StringValue(stream.StreamARN),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":             opts.AccountID,
+				"region":                 opts.Region,
+				"stream_mode":            streamMode,
+				"stream_status":          status,
+				"shard_count":            shardCount,
+				"retention_period_hours": aws.Int64Value(stream.RetentionPeriodHours),
+				"consumer_count":         aws.Int64Value(stream.ConsumerCount),
+				"encryption_type":        aws.StringValue(stream.EncryptionType),
+				"creation_time":          creationTime,
+				"hours_running":          time.Since(creationTime).Hours(),
+				"recommendation":         recommendation,
+			},
+			Tags: tags,
+			Cost: map[string]interface{}{
+				"total": cost,
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,328 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/lambda"
+)
+
+const (
+	// lambdaMinInvocations is the number of invocations in the scan window below which a function's
+	// peak memory is too thin a sample to recommend a lower setting
+	lambdaMinInvocations = 100
+
+	// lambdaMinMemoryMB is the smallest memory setting Lambda allows
+	lambdaMinMemoryMB = 128
+
+	// lambdaMemoryStepMB is the increment recommended memory settings are rounded up to
+	lambdaMemoryStepMB = 64
+
+	// lambdaLogGroupPrefix is the prefix of the log groups Lambda writes function logs to
+	lambdaLogGroupPrefix = "/aws/lambda/"
+
+	// lambdaQueryBatchSize is the number of log groups a single Logs Insights query can search
+	lambdaQueryBatchSize = 50
+
+	// lambdaMemoryQuery aggregates the REPORT line Lambda logs at the end of every invocation
+	lambdaMemoryQuery = `filter @type = "REPORT"
+| stats max(@maxMemoryUsed) as peakMemory, sum(@billedDuration) as billedDuration, count(*) as invocations by @log`
+)
+
+// lambdaMemoryUsage is a function's observed usage over the scan window, from its REPORT log lines
+type lambdaMemoryUsage struct {
+	peakMemoryMB     float64
+	billedDurationMS float64
+	invocations      int64
+}
+
+// LambdaMemoryScanner recommends lower memory settings for Lambda functions whose peak memory use,
+// as reported in their logs, stays well below what they're configured with
+type LambdaMemoryScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&LambdaMemoryScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *LambdaMemoryScanner) ArgumentName() string {
+	return "lambda-memory"
+}
+
+// Label implements Scanner interface
+func (s *LambdaMemoryScanner) Label() string {
+	return "Lambda Memory"
+}
+
+// IsGlobal implements Scanner interface
+func (s *LambdaMemoryScanner) IsGlobal() bool {
+	return false
+}
+
+// getLogGroups returns the names of the Lambda log groups that exist in the region, since a Logs
+// Insights query fails if any of its log groups is missing
+func (s *LambdaMemoryScanner) getLogGroups(opts awslib.ScanOptions, client *cloudwatchlogs.CloudWatchLogs) (map[string]bool, error) {
+	logGroups := make(map[string]bool)
+	err := client.DescribeLogGroupsPagesWithContext(opts.Context(), &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(lambdaLogGroupPrefix),
+	}, func(page *cloudwatchlogs.DescribeLogGroupsOutput, lastPage bool) bool {
+		for _, group := range page.LogGroups {
+			logGroups[aws.StringValue(group.LogGroupName)] = true
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Lambda log groups: %w", err)
+	}
+	return logGroups, nil
+}
+
+// queryMemoryUsage runs a Logs Insights query over a batch of log groups and returns the usage of
+// each, keyed by log group name
+func (s *LambdaMemoryScanner) queryMemoryUsage(opts awslib.ScanOptions, client *cloudwatchlogs.CloudWatchLogs, logGroups []string, startTime, endTime time.Time) (map[string]lambdaMemoryUsage, error) {
+	query, err := client.StartQueryWithContext(opts.Context(), &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: aws.StringSlice(logGroups),
+		QueryString:   aws.String(lambdaMemoryQuery),
+		StartTime:     aws.Int64(startTime.Unix()),
+		EndTime:       aws.Int64(endTime.Unix()),
+		Limit:         aws.Int64(int64(len(logGroups))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Logs Insights query: %w", err)
+	}
+
+	for {
+		output, err := client.GetQueryResultsWithContext(opts.Context(), &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: query.QueryId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Logs Insights query results: %w", err)
+		}
+
+		switch aws.StringValue(output.Status) {
+		case cloudwatchlogs.QueryStatusComplete:
+			return s.parseQueryResults(output.Results), nil
+		case cloudwatchlogs.QueryStatusFailed, cloudwatchlogs.QueryStatusCancelled, cloudwatchlogs.QueryStatusTimeout:
+			return nil, fmt.Errorf("query ended with status %s", aws.StringValue(output.Status))
+		}
+
+		select {
+		case <-opts.Context().Done():
+			return nil, opts.Context().Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// parseQueryResults converts Logs Insights rows into usage keyed by log group name. @log is
+// "account-id:log-group-name" and @maxMemoryUsed is in bytes.
+func (s *LambdaMemoryScanner) parseQueryResults(rows [][]*cloudwatchlogs.ResultField) map[string]lambdaMemoryUsage {
+	usage := make(map[string]lambdaMemoryUsage, len(rows))
+	for _, row := range rows {
+		var logGroup string
+		var u lambdaMemoryUsage
+		for _, field := range row {
+			value := aws.StringValue(field.Value)
+			switch aws.StringValue(field.Field) {
+			case "@log":
+				_, logGroup, _ = strings.Cut(value, ":")
+			case "peakMemory":
+				bytes, _ := strconv.ParseFloat(value, 64)
+				u.peakMemoryMB = bytes / 1_000_000
+			case "billedDuration":
+				u.billedDurationMS, _ = strconv.ParseFloat(value, 64)
+			case "invocations":
+				u.invocations, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+		if logGroup != "" {
+			usage[logGroup] = u
+		}
+	}
+	return usage
+}
+
+// recommendMemoryMB returns the smallest memory setting that fits a function's peak memory use with
+// headroom, rounded up to lambdaMemoryStepMB. It returns false when that isn't below the
+// configured setting.
+func recommendMemoryMB(configuredMB int64, peakMB float64) (int64, bool) {
+	required := math.Ceil(peakMB / rightsizingHeadroom)
+	recommended := int64(math.Ceil(required/lambdaMemoryStepMB)) * lambdaMemoryStepMB
+	if recommended < lambdaMinMemoryMB {
+		recommended = lambdaMinMemoryMB
+	}
+	return recommended, recommended < configuredMB
+}
+
+// calculateMemorySavings estimates the savings of lowering a function's memory, assuming its billed
+// duration stays the same. It returns nil when the GB-second price isn't available.
+func (s *LambdaMemoryScanner) calculateMemorySavings(region, architecture string, configuredMB, recommendedMB int64, billedSecondsPerHour float64, lastModified time.Time) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
+
+	var costs []*awslib.CostBreakdown
+	for _, memoryMB := range []int64{configuredMB, recommendedMB} {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:     "LambdaGBSecond",
+			ResourceSize:     architecture,
+			Region:           region,
+			CreationTime:     lastModified,
+			GBSecondsPerHour: float64(memoryMB) / 1024 * billedSecondsPerHour,
+		})
+		if err != nil || cost == nil {
+			logging.Warn("Failed to calculate Lambda memory savings", map[string]interface{}{
+				"region":    region,
+				"memory_mb": memoryMB,
+				"error":     fmt.Sprint(err),
+			})
+			return nil
+		}
+		costs = append(costs, cost)
+	}
+
+	return &awslib.CostBreakdown{
+		HourlyRate:  costs[0].HourlyRate - costs[1].HourlyRate,
+		DailyRate:   costs[0].DailyRate - costs[1].DailyRate,
+		MonthlyRate: costs[0].MonthlyRate - costs[1].MonthlyRate,
+		YearlyRate:  costs[0].YearlyRate - costs[1].YearlyRate,
+	}
+}
+
+// Scan implements Scanner interface
+func (s *LambdaMemoryScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	lambdaClient := lambda.New(sess)
+	logsClient := cloudwatchlogs.New(sess)
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	var functions []*lambda.FunctionConfiguration
+	err = lambdaClient.ListFunctionsPagesWithContext(opts.Context(), &lambda.ListFunctionsInput{}, func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+		functions = append(functions, page.Functions...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list Lambda functions", err, nil)
+		return nil, fmt.Errorf("failed to list Lambda functions: %w", err)
+	}
+	if len(functions) == 0 {
+		return nil, nil
+	}
+
+	existingLogGroups, err := s.getLogGroups(opts, logsClient)
+	if err != nil {
+		logging.Error("Failed to get Lambda log groups", err, nil)
+		return nil, err
+	}
+
+	// Functions that have never logged have no memory use to judge
+	var logGroups []string
+	for _, function := range functions {
+		logGroup := lambdaLogGroupPrefix + aws.StringValue(function.FunctionName)
+		if existingLogGroups[logGroup] {
+			logGroups = append(logGroups, logGroup)
+		}
+	}
+
+	usage := make(map[string]lambdaMemoryUsage, len(logGroups))
+	for start := 0; start < len(logGroups); start += lambdaQueryBatchSize {
+		end := start + lambdaQueryBatchSize
+		if end > len(logGroups) {
+			end = len(logGroups)
+		}
+		batch, err := s.queryMemoryUsage(opts, logsClient, logGroups[start:end], startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to query Lambda memory usage", err, map[string]interface{}{
+				"log_groups": len(logGroups[start:end]),
+			})
+			return nil, err
+		}
+		for logGroup, u := range batch {
+			usage[logGroup] = u
+		}
+	}
+
+	hoursInWindow := endTime.Sub(startTime).Hours()
+	var results awslib.ScanResults
+	for _, function := range functions {
+		functionName := aws.StringValue(function.FunctionName)
+		u, ok := usage[lambdaLogGroupPrefix+functionName]
+		if !ok || u.invocations < lambdaMinInvocations {
+			logging.Debug("Skipping Lambda function - too few invocations to judge memory use", map[string]interface{}{
+				"function_name": functionName,
+				"invocations":   u.invocations,
+			})
+			continue
+		}
+
+		configuredMB := aws.Int64Value(function.MemorySize)
+		recommendedMB, ok := recommendMemoryMB(configuredMB, u.peakMemoryMB)
+		if !ok {
+			continue
+		}
+
+		architecture := lambda.ArchitectureX8664
+		if len(function.Architectures) > 0 {
+			architecture = aws.StringValue(function.Architectures[0])
+		}
+
+		var lastModified *time.Time
+		if t, err := time.Parse("2006-01-02T15:04:05.000-0700", aws.StringValue(function.LastModified)); err == nil {
+			lastModified = &t
+		}
+
+		details := map[string]interface{}{
+			"account_id":            opts.AccountID,
+			"region":                opts.Region,
+			"finding_type":          "rightsize",
+			"configured_memory_mb":  configuredMB,
+			"peak_memory_used_mb":   math.Round(u.peakMemoryMB*100) / 100,
+			"recommended_memory_mb": recommendedMB,
+			"invocations":           u.invocations,
+			"billed_duration_ms":    u.billedDurationMS,
+			"architecture":          architecture,
+			"runtime":               aws.StringValue(function.Runtime),
+			"days_unused":           opts.DaysUnused,
+		}
+
+		reason := fmt.Sprintf("Rightsizing recommendation, not a deletion: function is configured with %d MB but used at most %.0f MB over %d invocations in the last %d days. Lowering memory to %d MB may increase duration, since CPU scales with memory.",
+			configuredMB, u.peakMemoryMB, u.invocations, opts.DaysUnused, recommendedMB)
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: functionName,
+			ResourceID:   functionName,
+			ARN:          aws.StringValue(function.FunctionArn),
+			CreatedAt:    lastModified,
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+		}
+		billedSecondsPerHour := u.billedDurationMS / 1000 / hoursInWindow
+		if savings := s.calculateMemorySavings(opts.Region, architecture, configuredMB, recommendedMB, billedSecondsPerHour, aws.TimeValue(lastModified)); savings != nil {
+			result.Cost = map[string]interface{}{
+				"total": savings,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,329 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lightsail"
+)
+
+// lightsailCPUThreshold is the average CPU utilization, in percent, below which a running
+// Lightsail instance is considered idle
+const lightsailCPUThreshold = 5.0
+
+// LightsailScanner scans for idle or stopped Lightsail instances and unattached Lightsail static
+// IPs and disks, which sit outside EC2 and so aren't found by the EC2 scanners
+type LightsailScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&LightsailScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *LightsailScanner) ArgumentName() string {
+	return "lightsail-instances"
+}
+
+// Label implements Scanner interface
+func (s *LightsailScanner) Label() string {
+	return "Lightsail Resources"
+}
+
+// IsGlobal implements Scanner interface
+func (s *LightsailScanner) IsGlobal() bool {
+	return false
+}
+
+// getAverageCPU returns an instance's average CPU utilization between startTime and endTime, and
+// false if it reported none
+func (s *LightsailScanner) getAverageCPU(opts awslib.ScanOptions, client *lightsail.Lightsail, instanceName string, startTime, endTime time.Time) (float64, bool, error) {
+	output, err := client.GetInstanceMetricDataWithContext(opts.Context(), &lightsail.GetInstanceMetricDataInput{
+		InstanceName: aws.String(instanceName),
+		MetricName:   aws.String(lightsail.InstanceMetricNameCpuutilization),
+		StartTime:    aws.Time(startTime),
+		EndTime:      aws.Time(endTime),
+		Period:       aws.Int64(86400), // 1 day
+		Statistics:   []*string{aws.String(lightsail.MetricStatisticAverage)},
+		Unit:         aws.String(lightsail.MetricUnitPercent),
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch CPUUtilization metric: %w", err)
+	}
+	if len(output.MetricData) == 0 {
+		return 0, false, nil
+	}
+
+	var sum float64
+	for _, dp := range output.MetricData {
+		sum += aws.Float64Value(dp.Average)
+	}
+	return sum / float64(len(output.MetricData)), true, nil
+}
+
+// calculateCost estimates the cost of a Lightsail resource since it was created, falling back to
+// hourlyRate when the cost estimator is unavailable or fails
+func (s *LightsailScanner) calculateCost(costConfig awslib.ResourceCostConfig, hourlyRate float64) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(costConfig)
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to calculate Lightsail cost, using default", map[string]interface{}{
+			"resource_type": costConfig.ResourceType,
+			"region":        costConfig.Region,
+			"error":         err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hoursRunning := time.Since(costConfig.CreationTime).Hours()
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// convertTags converts Lightsail tags to a map
+func (s *LightsailScanner) convertTags(tags []*lightsail.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return result
+}
+
+// scanInstances returns instances that are stopped, which still bills their bundle, or whose
+// average CPU utilization stayed below lightsailCPUThreshold
+func (s *LightsailScanner) scanInstances(opts awslib.ScanOptions, client *lightsail.Lightsail, startTime, endTime time.Time) (awslib.ScanResults, error) {
+	var instances []*lightsail.Instance
+	input := &lightsail.GetInstancesInput{}
+	for {
+		output, err := client.GetInstancesWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Lightsail instances: %w", err)
+		}
+		instances = append(instances, output.Instances...)
+		if aws.StringValue(output.NextPageToken) == "" {
+			break
+		}
+		input.PageToken = output.NextPageToken
+	}
+
+	var results awslib.ScanResults
+	for _, instance := range instances {
+		instanceName := aws.StringValue(instance.Name)
+
+		// Instances created within the window haven't had the chance to be used yet
+		if aws.TimeValue(instance.CreatedAt).After(startTime) {
+			continue
+		}
+
+		var state string
+		if instance.State != nil {
+			state = aws.StringValue(instance.State.Name)
+		}
+
+		var reason, confidence string
+		switch state {
+		case "stopped":
+			confidence = awslib.ConfidenceHigh
+			reason = fmt.Sprintf("Lightsail instance is stopped, but its bundle is billed whether it runs or not. It is %s old.",
+				utils.FormatTimeDifference(time.Now(), instance.CreatedAt))
+		case "running":
+			cpuAvg, ok, err := s.getAverageCPU(opts, client, instanceName, startTime, endTime)
+			if err != nil {
+				logging.Error("Failed to analyze Lightsail instance usage", err, map[string]interface{}{
+					"instance_name": instanceName,
+				})
+				continue
+			}
+			if !ok || cpuAvg >= lightsailCPUThreshold {
+				continue
+			}
+			confidence = awslib.ConfidenceLow
+			reason = fmt.Sprintf("Very low CPU utilization (%.2f%%) in the last %d days. Lightsail instance is %s old.",
+				cpuAvg, opts.DaysUnused, utils.FormatTimeDifference(time.Now(), instance.CreatedAt))
+		default:
+			continue
+		}
+
+		bundleID := aws.StringValue(instance.BundleId)
+		details := map[string]interface{}{
+			"account_id":              opts.AccountID,
+			"region":                  opts.Region,
+			"lightsail_resource_type": "instance",
+			"state":                   state,
+			"bundle_id":               bundleID,
+			"blueprint_id":            aws.StringValue(instance.BlueprintId),
+			"blueprint_name":          aws.StringValue(instance.BlueprintName),
+			"has_static_ip":           aws.BoolValue(instance.IsStaticIp),
+			"public_ip_address":       aws.StringValue(instance.PublicIpAddress),
+			"days_unused":             opts.DaysUnused,
+		}
+		if instance.Location != nil {
+			details["availability_zone"] = aws.StringValue(instance.Location.AvailabilityZone)
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: instanceName,
+			ResourceID:   instanceName,
+			ARN:          aws.StringValue(instance.Arn),
+			CreatedAt:    instance.CreatedAt,
+			Reason:       reason,
+			Confidence:   confidence,
+			Details:      details,
+			Tags:         s.convertTags(instance.Tags),
+			Cost: map[string]interface{}{
+				"total": s.calculateCost(awslib.ResourceCostConfig{
+					ResourceType: "Lightsail",
+					ResourceSize: bundleID,
+					Region:       opts.Region,
+					CreationTime: aws.TimeValue(instance.CreatedAt),
+				}, 5.0/730), // $5 per month, the smallest Linux bundle with a public IPv4 address
+			},
+		})
+	}
+	return results, nil
+}
+
+// scanStaticIPs returns static IPs not attached to an instance, which are only free while attached
+func (s *LightsailScanner) scanStaticIPs(opts awslib.ScanOptions, client *lightsail.Lightsail) (awslib.ScanResults, error) {
+	var results awslib.ScanResults
+	input := &lightsail.GetStaticIpsInput{}
+	for {
+		output, err := client.GetStaticIpsWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Lightsail static IPs: %w", err)
+		}
+		for _, staticIP := range output.StaticIps {
+			if aws.BoolValue(staticIP.IsAttached) {
+				continue
+			}
+			name := aws.StringValue(staticIP.Name)
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: name,
+				ResourceID:   name,
+				ARN:          aws.StringValue(staticIP.Arn),
+				CreatedAt:    staticIP.CreatedAt,
+				Reason:       "Lightsail static IP is not attached to an instance, and is billed while unattached.",
+				Confidence:   awslib.ConfidenceHigh,
+				Details: map[string]interface{}{
+					"account_id":              opts.AccountID,
+					"region":                  opts.Region,
+					"lightsail_resource_type": "static_ip",
+					"ip_address":              aws.StringValue(staticIP.IpAddress),
+				},
+				Cost: map[string]interface{}{
+					// Unattached static IPs are billed at the same hourly rate as Elastic IPs
+					"total": s.calculateCost(awslib.ResourceCostConfig{
+						ResourceType: "ElasticIP",
+						Region:       opts.Region,
+						CreationTime: aws.TimeValue(staticIP.CreatedAt),
+					}, 0.005), // $0.005 per hour
+				},
+			})
+		}
+		if aws.StringValue(output.NextPageToken) == "" {
+			return results, nil
+		}
+		input.PageToken = output.NextPageToken
+	}
+}
+
+// scanDisks returns block storage disks not attached to an instance
+func (s *LightsailScanner) scanDisks(opts awslib.ScanOptions, client *lightsail.Lightsail, startTime time.Time) (awslib.ScanResults, error) {
+	var results awslib.ScanResults
+	input := &lightsail.GetDisksInput{}
+	for {
+		output, err := client.GetDisksWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Lightsail disks: %w", err)
+		}
+		for _, disk := range output.Disks {
+			if aws.BoolValue(disk.IsAttached) || aws.TimeValue(disk.CreatedAt).After(startTime) {
+				continue
+			}
+			name := aws.StringValue(disk.Name)
+			sizeGB := aws.Int64Value(disk.SizeInGb)
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: name,
+				ResourceID:   name,
+				ARN:          aws.StringValue(disk.Arn),
+				CreatedAt:    disk.CreatedAt,
+				Reason: fmt.Sprintf("Lightsail disk of %d GB is not attached to an instance. It is %s old.",
+					sizeGB, utils.FormatTimeDifference(time.Now(), disk.CreatedAt)),
+				Confidence: awslib.ConfidenceHigh,
+				Details: map[string]interface{}{
+					"account_id":              opts.AccountID,
+					"region":                  opts.Region,
+					"lightsail_resource_type": "disk",
+					"state":                   aws.StringValue(disk.State),
+					"size_gb":                 sizeGB,
+				},
+				Tags: s.convertTags(disk.Tags),
+				Cost: map[string]interface{}{
+					"total": s.calculateCost(awslib.ResourceCostConfig{
+						ResourceType: "LightsailDisk",
+						ResourceSize: sizeGB,
+						Region:       opts.Region,
+						CreationTime: aws.TimeValue(disk.CreatedAt),
+					}, 0.10*float64(sizeGB)/730), // $0.10 per GB-month
+				},
+			})
+		}
+		if aws.StringValue(output.NextPageToken) == "" {
+			return results, nil
+		}
+		input.PageToken = output.NextPageToken
+	}
+}
+
+// Scan implements Scanner interface
+func (s *LightsailScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := lightsail.New(sess)
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	results, err := s.scanInstances(opts, client, startTime, endTime)
+	if err != nil {
+		logging.Error("Failed to scan Lightsail instances", err, nil)
+		return nil, err
+	}
+
+	staticIPs, err := s.scanStaticIPs(opts, client)
+	if err != nil {
+		logging.Error("Failed to scan Lightsail static IPs", err, nil)
+		return nil, err
+	}
+	results = append(results, staticIPs...)
+
+	disks, err := s.scanDisks(opts, client, startTime)
+	if err != nil {
+		logging.Error("Failed to scan Lightsail disks", err, nil)
+		return nil, err
+	}
+	results = append(results, disks...)
+
+	return results, nil
+}
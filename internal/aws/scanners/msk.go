@@ -0,0 +1,239 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/kafka"
+)
+
+// MSKClusterScanner scans for provisioned MSK clusters that have carried no traffic
+type MSKClusterScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&MSKClusterScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *MSKClusterScanner) ArgumentName() string {
+	return "msk-clusters"
+}
+
+// Label implements Scanner interface
+func (s *MSKClusterScanner) Label() string {
+	return "MSK Clusters"
+}
+
+// IsGlobal implements Scanner interface
+func (s *MSKClusterScanner) IsGlobal() bool {
+	return false
+}
+
+// listBrokerIDs returns the IDs of a cluster's brokers, which CloudWatch uses as a metric dimension
+func (s *MSKClusterScanner) listBrokerIDs(opts awslib.ScanOptions, client *kafka.Kafka, clusterArn string) ([]string, error) {
+	var brokerIDs []string
+	err := client.ListNodesPagesWithContext(opts.Context(), &kafka.ListNodesInput{
+		ClusterArn: aws.String(clusterArn),
+	}, func(page *kafka.ListNodesOutput, lastPage bool) bool {
+		for _, node := range page.NodeInfoList {
+			if node.BrokerNodeInfo == nil || node.BrokerNodeInfo.BrokerId == nil {
+				continue
+			}
+			brokerIDs = append(brokerIDs, strconv.FormatFloat(aws.Float64Value(node.BrokerNodeInfo.BrokerId), 'f', -1, 64))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brokers: %w", err)
+	}
+	return brokerIDs, nil
+}
+
+// getMaxBrokerThroughput returns the highest daily peak of a per-broker metric across all brokers.
+// It returns -1 when no broker reported any datapoints.
+func (s *MSKClusterScanner) getMaxBrokerThroughput(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, clusterName string, brokerIDs []string, metricName string, startTime, endTime time.Time) (float64, error) {
+	maxValue := -1.0
+	for _, brokerID := range brokerIDs {
+		output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/Kafka"),
+			MetricName: aws.String(metricName),
+			Dimensions: []*cloudwatch.Dimension{
+				{
+					Name:  aws.String("Cluster Name"),
+					Value: aws.String(clusterName),
+				},
+				{
+					Name:  aws.String("Broker ID"),
+					Value: aws.String(brokerID),
+				},
+			},
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int64(86400), // 1 day
+			Statistics: []*string{aws.String("Maximum")},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+		}
+		for _, dp := range output.Datapoints {
+			maxValue = math.Max(maxValue, aws.Float64Value(dp.Maximum))
+		}
+	}
+	return maxValue, nil
+}
+
+// calculateClusterCost estimates the cost of a cluster's brokers and their storage. It returns nil
+// when there is no price for the broker instance type.
+func (s *MSKClusterScanner) calculateClusterCost(instanceType string, brokers, storageGB int64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
+
+	cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+		ResourceType:  "MSK",
+		ResourceSize:  instanceType,
+		Region:        region,
+		CreationTime:  creationTime,
+		InstanceCount: brokers,
+		StorageSize:   storageGB,
+	})
+	if err != nil {
+		logging.Warn("Failed to calculate MSK cluster cost", map[string]interface{}{
+			"region":        region,
+			"instance_type": instanceType,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+	return cost
+}
+
+// Scan implements Scanner interface
+func (s *MSKClusterScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := kafka.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	// Serverless clusters are billed per partition-hour and data volume, so an idle one costs
+	// little; only provisioned clusters are scanned
+	var clusters []*kafka.Cluster
+	err = client.ListClustersV2PagesWithContext(opts.Context(), &kafka.ListClustersV2Input{
+		ClusterTypeFilter: aws.String(kafka.ClusterTypeProvisioned),
+	}, func(page *kafka.ListClustersV2Output, lastPage bool) bool {
+		clusters = append(clusters, page.ClusterInfoList...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list MSK clusters", err, nil)
+		return nil, fmt.Errorf("failed to list MSK clusters: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, cluster := range clusters {
+		clusterName := aws.StringValue(cluster.ClusterName)
+		clusterArn := aws.StringValue(cluster.ClusterArn)
+		creationTime := aws.TimeValue(cluster.CreationTime)
+
+		// Clusters in transition will change on their own, and new ones don't have a full metric window
+		if aws.StringValue(cluster.State) != kafka.ClusterStateActive || creationTime.After(startTime) {
+			continue
+		}
+		if cluster.Provisioned == nil || cluster.Provisioned.BrokerNodeGroupInfo == nil {
+			continue
+		}
+
+		brokerGroup := cluster.Provisioned.BrokerNodeGroupInfo
+		instanceType := aws.StringValue(brokerGroup.InstanceType)
+		brokers := aws.Int64Value(cluster.Provisioned.NumberOfBrokerNodes)
+		var storagePerBroker int64
+		if brokerGroup.StorageInfo != nil && brokerGroup.StorageInfo.EbsStorageInfo != nil {
+			storagePerBroker = aws.Int64Value(brokerGroup.StorageInfo.EbsStorageInfo.VolumeSize)
+		}
+
+		brokerIDs, err := s.listBrokerIDs(opts, client, clusterArn)
+		if err != nil {
+			logging.Error("Failed to list MSK brokers", err, map[string]interface{}{
+				"cluster_name": clusterName,
+			})
+			continue
+		}
+		if len(brokerIDs) == 0 {
+			continue
+		}
+
+		bytesIn, err := s.getMaxBrokerThroughput(opts, cwClient, clusterName, brokerIDs, "BytesInPerSec", startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze MSK cluster traffic", err, map[string]interface{}{
+				"cluster_name": clusterName,
+			})
+			continue
+		}
+		bytesOut, err := s.getMaxBrokerThroughput(opts, cwClient, clusterName, brokerIDs, "BytesOutPerSec", startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze MSK cluster traffic", err, map[string]interface{}{
+				"cluster_name": clusterName,
+			})
+			continue
+		}
+
+		// A cluster with no datapoints at all can't be told apart from one whose metrics are missing
+		if bytesIn < 0 && bytesOut < 0 {
+			continue
+		}
+		if bytesIn > 0 || bytesOut > 0 {
+			continue
+		}
+
+		tags := aws.StringValueMap(cluster.Tags)
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: clusterName,
+			ResourceID:   clusterName,
+			ARN:          clusterArn,
+			CreatedAt:    aws.Time(creationTime),
+			Reason: fmt.Sprintf("No bytes were produced to or consumed from any of the cluster's %d brokers in the last %d days",
+				brokers, opts.DaysUnused),
+			Confidence: awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":            opts.AccountID,
+				"region":                opts.Region,
+				"cluster_arn":           clusterArn,
+				"kafka_version":         aws.StringValue(cluster.CurrentVersion),
+				"broker_count":          brokers,
+				"instance_type":         instanceType,
+				"storage_per_broker_gb": storagePerBroker,
+				"enhanced_monitoring":   aws.StringValue(cluster.Provisioned.EnhancedMonitoring),
+				"creation_time":         creationTime,
+				"hours_running":         time.Since(creationTime).Hours(),
+			},
+			Tags: tags,
+		}
+		if cost := s.calculateClusterCost(instanceType, brokers, brokers*storagePerBroker, creationTime, opts.Region); cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -30,8 +30,13 @@ func (s *NATGatewayScanner) Label() string {
 	return "NAT Gateways"
 }
 
+// IsGlobal implements Scanner interface
+func (s *NATGatewayScanner) IsGlobal() bool {
+	return false
+}
+
 // fetchMetric fetches a CloudWatch metric for a NAT Gateway
-func (s *NATGatewayScanner) fetchMetric(cwClient *cloudwatch.CloudWatch, natGatewayID string, metricName string, startTime, endTime time.Time) (float64, error) {
+func (s *NATGatewayScanner) fetchMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, natGatewayID string, metricName string, startTime, endTime time.Time) (float64, error) {
 	config := utils.MetricConfig{
 		Namespace:     "AWS/NATGateway",
 		ResourceID:    natGatewayID,
@@ -43,32 +48,32 @@ func (s *NATGatewayScanner) fetchMetric(cwClient *cloudwatch.CloudWatch, natGate
 		Period:        86400, // 1 day
 	}
 
-	return utils.GetResourceMetrics(cwClient, config)
+	return utils.GetResourceMetrics(opts.Context(), cwClient, config)
 }
 
 // analyzeNATGatewayUsage analyzes the usage of a NAT Gateway based on CloudWatch metrics
-func (s *NATGatewayScanner) analyzeNATGatewayUsage(cwClient *cloudwatch.CloudWatch, natGatewayID string, daysUnused int) (bool, string, error) {
+func (s *NATGatewayScanner) analyzeNATGatewayUsage(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, natGatewayID string, daysUnused int) (bool, string, error) {
 	// Calculate time range for metrics
 	endTime := time.Now()
 	startTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
 
 	// Fetch metrics to determine if NAT Gateway is unused
-	bytesInFromSource, err := s.fetchMetric(cwClient, natGatewayID, "BytesInFromSource", startTime, endTime)
+	bytesInFromSource, err := s.fetchMetric(opts, cwClient, natGatewayID, "BytesInFromSource", startTime, endTime)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to fetch BytesInFromSource metric: %w", err)
 	}
 
-	bytesOutToDestination, err := s.fetchMetric(cwClient, natGatewayID, "BytesOutToDestination", startTime, endTime)
+	bytesOutToDestination, err := s.fetchMetric(opts, cwClient, natGatewayID, "BytesOutToDestination", startTime, endTime)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to fetch BytesOutToDestination metric: %w", err)
 	}
 
-	bytesInFromDestination, err := s.fetchMetric(cwClient, natGatewayID, "BytesInFromDestination", startTime, endTime)
+	bytesInFromDestination, err := s.fetchMetric(opts, cwClient, natGatewayID, "BytesInFromDestination", startTime, endTime)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to fetch BytesInFromDestination metric: %w", err)
 	}
 
-	bytesOutToSource, err := s.fetchMetric(cwClient, natGatewayID, "BytesOutToSource", startTime, endTime)
+	bytesOutToSource, err := s.fetchMetric(opts, cwClient, natGatewayID, "BytesOutToSource", startTime, endTime)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to fetch BytesOutToSource metric: %w", err)
 	}
@@ -206,7 +211,7 @@ func (s *NATGatewayScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, e
 		}
 
 		// Check if NAT Gateway is unused
-		isUnused, reason, err := s.analyzeNATGatewayUsage(cwClient, natGatewayID, daysUnused)
+		isUnused, reason, err := s.analyzeNATGatewayUsage(opts, cwClient, natGatewayID, daysUnused)
 		if err != nil {
 			logging.Error("Failed to analyze NAT Gateway usage", err, map[string]interface{}{
 				"nat_gateway_id": natGatewayID,
@@ -258,7 +263,10 @@ func (s *NATGatewayScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, e
 				ResourceType: s.Label(),
 				ResourceName: natGatewayName,
 				ResourceID:   natGatewayID,
+				ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "natgateway/"+natGatewayID),
+				CreatedAt:    natGateway.CreateTime,
 				Reason:       reason,
+				Confidence:   awslib.ConfidenceMedium,
 				Details: map[string]interface{}{
 					"account_id":    opts.AccountID,
 					"region":        opts.Region,
@@ -0,0 +1,271 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// NetworkWasteScanner reports billed networking leftovers under a single label: idle NAT Gateways,
+// unassociated Elastic IPs, idle interface VPC endpoints and idle Transit Gateways. It is a grouping
+// scanner, so it only runs when selected and replaces the scanners it covers.
+type NetworkWasteScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&NetworkWasteScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *NetworkWasteScanner) ArgumentName() string {
+	return "network-waste"
+}
+
+// Label implements Scanner interface
+func (s *NetworkWasteScanner) Label() string {
+	return "Network Waste"
+}
+
+// IsGlobal implements Scanner interface
+func (s *NetworkWasteScanner) IsGlobal() bool {
+	return false
+}
+
+// Covers implements GroupingScanner interface
+func (s *NetworkWasteScanner) Covers() []string {
+	return []string{
+		(&NATGatewayScanner{}).ArgumentName(),
+		(&ElasticIPScanner{}).ArgumentName(),
+		(&TransitGatewayScanner{}).ArgumentName(),
+	}
+}
+
+// networkWasteCheck is one of the checks the scanner runs, identified by the waste_type it reports
+type networkWasteCheck struct {
+	wasteType string
+	scan      func(opts awslib.ScanOptions) (awslib.ScanResults, error)
+}
+
+// estimateHourlyCost calculates the cost of a resource billed per hour for each of count units,
+// falling back to defaultRate per unit if the cost estimator is unavailable, fails or returns zero
+func (s *NetworkWasteScanner) estimateHourlyCost(resourceType string, count int64, defaultRate float64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  resourceType,
+			Region:        region,
+			CreationTime:  creationTime,
+			InstanceCount: count,
+		})
+		if err == nil && costBreakdown.HourlyRate != 0 {
+			return costBreakdown
+		}
+	}
+
+	hoursRunning := time.Since(creationTime).Hours()
+	hourlyRate := defaultRate * float64(count)
+	lifetime := hourlyRate * hoursRunning
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(lifetime),
+	}
+}
+
+// sumMetric returns the sum of a CloudWatch metric between startTime and endTime. Missing
+// datapoints count as zero.
+func (s *NetworkWasteScanner) sumMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, namespace, metricName string, dimensions map[string]string, startTime, endTime time.Time) (float64, error) {
+	var cwDimensions []*cloudwatch.Dimension
+	for name, value := range dimensions {
+		cwDimensions = append(cwDimensions, &cloudwatch.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(value),
+		})
+	}
+
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metricName),
+		Dimensions: cwDimensions,
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Sum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+	}
+
+	var total float64
+	for _, dp := range output.Datapoints {
+		total += aws.Float64Value(dp.Sum)
+	}
+	return total, nil
+}
+
+// scanVPCEndpoints reports interface VPC endpoints that processed no traffic in the window.
+// Gateway endpoints are free, so only interface endpoints are checked.
+func (s *NetworkWasteScanner) scanVPCEndpoints(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	ec2Client := ec2.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-endpoint-type"),
+				Values: []*string{aws.String(ec2.VpcEndpointTypeInterface)},
+			},
+			{
+				Name:   aws.String("vpc-endpoint-state"),
+				Values: []*string{aws.String("available")},
+			},
+		},
+	}
+	var endpoints []*ec2.VpcEndpoint
+	err = ec2Client.DescribeVpcEndpointsPagesWithContext(opts.Context(), input, func(page *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+		endpoints = append(endpoints, page.VpcEndpoints...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPC endpoints: %w", err)
+	}
+
+	var results awslib.ScanResults
+	daysUnused := utils.Max(opts.DaysUnused, 30)
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
+
+	for _, endpoint := range endpoints {
+		endpointID := aws.StringValue(endpoint.VpcEndpointId)
+		creationTime := aws.TimeValue(endpoint.CreationTimestamp)
+
+		// New endpoints don't have a full metric window
+		if creationTime.After(startTime) {
+			continue
+		}
+
+		bytesProcessed, err := s.sumMetric(opts, cwClient, "AWS/PrivateLinkEndpoints", "BytesProcessed", map[string]string{
+			"Endpoint Type":   aws.StringValue(endpoint.VpcEndpointType),
+			"Service Name":    aws.StringValue(endpoint.ServiceName),
+			"VPC Endpoint Id": endpointID,
+			"VPC Id":          aws.StringValue(endpoint.VpcId),
+		}, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze VPC endpoint usage", err, map[string]interface{}{
+				"vpc_endpoint_id": endpointID,
+			})
+			continue
+		}
+		if bytesProcessed > 0 {
+			continue
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range endpoint.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		endpointName := tags["Name"]
+		if endpointName == "" {
+			endpointName = endpointID
+		}
+
+		// Interface endpoints are billed for each Availability Zone, which has one subnet each
+		zoneCount := int64(len(endpoint.SubnetIds))
+		if zoneCount == 0 {
+			zoneCount = 1
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: endpointName,
+			ResourceID:   endpointID,
+			ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "vpc-endpoint/"+endpointID),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       fmt.Sprintf("Interface VPC endpoint processed no traffic in the last %d days", daysUnused),
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":    opts.AccountID,
+				"region":        opts.Region,
+				"waste_type":    "vpc_endpoint",
+				"vpc_id":        aws.StringValue(endpoint.VpcId),
+				"service_name":  aws.StringValue(endpoint.ServiceName),
+				"subnet_ids":    aws.StringValueSlice(endpoint.SubnetIds),
+				"zone_count":    zoneCount,
+				"creation_time": creationTime,
+				"days_unused":   daysUnused,
+			},
+			Tags: tags,
+			Cost: map[string]interface{}{
+				"total": s.estimateHourlyCost("VPCEndpoint", zoneCount, 0.01, creationTime, opts.Region),
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// relabel runs another scanner and reports its findings as network waste of the given type
+func (s *NetworkWasteScanner) relabel(scanner awslib.Scanner, wasteType string) func(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	return func(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+		results, err := scanner.Scan(opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			if results[i].Details == nil {
+				results[i].Details = make(map[string]interface{})
+			}
+			results[i].Details["waste_type"] = wasteType
+			results[i].Details["source_scanner"] = results[i].ResourceType
+			results[i].ResourceType = s.Label()
+		}
+		return results, nil
+	}
+}
+
+// Scan implements Scanner interface
+func (s *NetworkWasteScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	checks := []networkWasteCheck{
+		{wasteType: "nat_gateway", scan: s.relabel(&NATGatewayScanner{}, "nat_gateway")},
+		{wasteType: "elastic_ip", scan: s.relabel(&ElasticIPScanner{}, "elastic_ip")},
+		{wasteType: "vpc_endpoint", scan: s.scanVPCEndpoints},
+		{wasteType: "transit_gateway", scan: s.relabel(&TransitGatewayScanner{}, "transit_gateway")},
+	}
+
+	// A failed check is logged so the others still report; the scan only fails if none could run
+	var results awslib.ScanResults
+	var lastErr error
+	failed := 0
+	for _, check := range checks {
+		checkResults, err := check.scan(opts)
+		if err != nil {
+			logging.Error("Failed to run network waste check", err, map[string]interface{}{
+				"region":     opts.Region,
+				"waste_type": check.wasteType,
+			})
+			lastErr = err
+			failed++
+			continue
+		}
+		results = append(results, checkResults...)
+	}
+	if failed == len(checks) {
+		return nil, fmt.Errorf("all network waste checks failed: %w", lastErr)
+	}
+
+	return results, nil
+}
@@ -2,159 +2,215 @@ package scanners
 
 import (
 	"fmt"
-	"strings"
+	"math"
 	"time"
 
 	awslib "cloudsift/internal/aws"
-	"cloudsift/internal/aws/utils"
 	"cloudsift/internal/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/opensearchservice"
 )
 
-// OpenSearchScanner scans for unused or underutilized OpenSearch clusters
+const (
+	// openSearchIdleRequestRate is the busiest minute, in search or indexing requests, a domain can
+	// have and still be considered idle
+	openSearchIdleRequestRate = 1.0
+	// openSearchIdleCPU is the average CPU utilization below which an idle domain is reported
+	openSearchIdleCPU = 10.0
+	// openSearchDescribeBatch is the most domains DescribeDomains accepts at once
+	openSearchDescribeBatch = 5
+)
+
+// OpenSearchScanner scans for OpenSearch and Elasticsearch domains that serve almost no search or
+// indexing traffic
 type OpenSearchScanner struct{}
 
 func init() {
 	awslib.DefaultRegistry.RegisterScanner(&OpenSearchScanner{})
+	// The scanner was called opensearch before it reported idle domains
+	awslib.DefaultRegistry.RegisterAlias("opensearch", "opensearch-domains")
 }
 
 // ArgumentName implements Scanner interface
 func (s *OpenSearchScanner) ArgumentName() string {
-	return "opensearch"
+	return "opensearch-domains"
 }
 
 // Label implements Scanner interface
 func (s *OpenSearchScanner) Label() string {
-	return "OpenSearch Clusters"
+	return "OpenSearch Domains"
 }
 
-// getClusterMetrics retrieves CloudWatch metrics for an OpenSearch cluster
-func (s *OpenSearchScanner) getClusterMetrics(cwClient *cloudwatch.CloudWatch, domainName string, startTime, endTime time.Time) (map[string]float64, error) {
-	metrics := []utils.MetricConfig{
-		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "CPUUtilization",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
-		},
-		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "FreeStorageSpace",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
-		},
-		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "SearchRate",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
-		},
-		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "IndexingRate",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
-		},
-		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "DocumentCount",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
-		},
+// IsGlobal implements Scanner interface
+func (s *OpenSearchScanner) IsGlobal() bool {
+	return false
+}
+
+// domainActivity is a domain's traffic and load over the scan window
+type domainActivity struct {
+	maxSearchRate   float64 // Busiest minute of search requests
+	maxIndexingRate float64 // Busiest minute of indexing requests
+	avgCPU          float64
+	reported        bool // Whether the domain reported any metrics
+}
+
+// getDomainActivity reads a domain's search rate, indexing rate and CPU utilization between
+// startTime and endTime. OpenSearch metrics are dimensioned by the owning account as well as the
+// domain name.
+func (s *OpenSearchScanner) getDomainActivity(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, domainName, accountID string, startTime, endTime time.Time) (domainActivity, error) {
+	var activity domainActivity
+	dimensions := []*cloudwatch.Dimension{
 		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "DeleteRate",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
+			Name:  aws.String("DomainName"),
+			Value: aws.String(domainName),
 		},
 		{
-			Namespace:     "AWS/ES",
-			ResourceID:    domainName,
-			DimensionName: "DomainName",
-			MetricName:    "JVMMemoryPressure",
-			Statistic:     "Average",
-			StartTime:     startTime.UTC(),
-			EndTime:       endTime.UTC(),
+			Name:  aws.String("ClientId"),
+			Value: aws.String(accountID),
 		},
 	}
 
-	results, err := utils.GetResourceMetricsData(cwClient, metrics)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get metrics: %w", err)
-	}
+	for _, metric := range []struct {
+		name      string
+		statistic string
+	}{
+		{"SearchRate", "Maximum"},
+		{"IndexingRate", "Maximum"},
+		{"CPUUtilization", "Average"},
+	} {
+		output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/ES"),
+			MetricName: aws.String(metric.name),
+			Dimensions: dimensions,
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int64(86400), // 1 day
+			Statistics: []*string{aws.String(metric.statistic)},
+		})
+		if err != nil {
+			return activity, fmt.Errorf("failed to fetch %s metric: %w", metric.name, err)
+		}
+		if len(output.Datapoints) == 0 {
+			continue
+		}
+		activity.reported = true
 
-	// Map the metrics to our expected format
-	mappedResults := map[string]float64{
-		"cpu_utilization": results["CPUUtilization"],
-		"free_storage":    results["FreeStorageSpace"],
-		"search_rate":     results["SearchRate"],
-		"index_rate":      results["IndexingRate"],
-		"doc_count":       results["DocumentCount"],
-		"delete_rate":     results["DeleteRate"],
-		"jvm_memory":      results["JVMMemoryPressure"],
+		var maximum, sum float64
+		for _, dp := range output.Datapoints {
+			maximum = math.Max(maximum, aws.Float64Value(dp.Maximum))
+			sum += aws.Float64Value(dp.Average)
+		}
+		switch metric.name {
+		case "SearchRate":
+			activity.maxSearchRate = maximum
+		case "IndexingRate":
+			activity.maxIndexingRate = maximum
+		case "CPUUtilization":
+			activity.avgCPU = sum / float64(len(output.Datapoints))
+		}
 	}
 
-	return mappedResults, nil
+	return activity, nil
 }
 
-// determineUnusedReasons checks if a cluster is unused or underutilized
-func (s *OpenSearchScanner) determineUnusedReasons(metrics map[string]float64, volumeSize int64, opts awslib.ScanOptions) []string {
-	var reasons []string
+// calculateDomainCost estimates the cost of a domain's data nodes and their EBS storage, plus its
+// dedicated master and UltraWarm nodes. It returns nil when the data nodes can't be priced.
+func (s *OpenSearchScanner) calculateDomainCost(status *opensearchservice.DomainStatus, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
 
-	// Check for completely unused clusters
-	if metrics["search_rate"] == 0 && metrics["index_rate"] == 0 && metrics["delete_rate"] == 0 {
-		if metrics["doc_count"] == 0 {
-			reasons = append(reasons, fmt.Sprintf("Cluster is empty with no search, index, or delete activity in the last %d days.", opts.DaysUnused))
-		} else {
-			reasons = append(reasons, fmt.Sprintf("Cluster has data but no search, index, or delete activity in the last %d days.", opts.DaysUnused))
-		}
+	clusterConfig := status.ClusterConfig
+	instanceCount := aws.Int64Value(clusterConfig.InstanceCount)
+	dataNodes := awslib.ResourceCostConfig{
+		ResourceType:  "OpenSearch",
+		ResourceSize:  aws.StringValue(clusterConfig.InstanceType),
+		Region:        region,
+		CreationTime:  time.Now(), // The OpenSearch API doesn't report when a domain was created
+		InstanceCount: instanceCount,
+	}
+	if status.EBSOptions != nil && aws.BoolValue(status.EBSOptions.EBSEnabled) {
+		dataNodes.VolumeType = aws.StringValue(status.EBSOptions.VolumeType)
+		dataNodes.StorageSize = aws.Int64Value(status.EBSOptions.VolumeSize) * instanceCount
 	}
 
-	// Check for underutilized clusters
-	if metrics["cpu_utilization"] < 10 {
-		reasons = append(reasons, fmt.Sprintf("Very low CPU utilization (%.2f%%) in the last %d days.", metrics["cpu_utilization"], opts.DaysUnused))
+	total, err := awslib.DefaultCostEstimator.CalculateCost(dataNodes)
+	if err != nil {
+		logging.Warn("Failed to calculate OpenSearch domain cost", map[string]interface{}{
+			"region":        region,
+			"domain_name":   aws.StringValue(status.DomainName),
+			"instance_type": aws.StringValue(clusterConfig.InstanceType),
+			"error":         err.Error(),
+		})
+		return nil
 	}
 
-	// Check storage utilization
-	storageUtilization := 100 * (1 - (metrics["free_storage"] / float64(volumeSize*1024*1024*1024)))
-	if storageUtilization < 20 {
-		reasons = append(reasons, fmt.Sprintf("Low storage utilization (%.2f%% used).", storageUtilization))
+	// Dedicated masters and UltraWarm nodes are priced per instance-hour like data nodes
+	var extraNodes []awslib.ResourceCostConfig
+	if aws.BoolValue(clusterConfig.DedicatedMasterEnabled) {
+		extraNodes = append(extraNodes, awslib.ResourceCostConfig{
+			ResourceType:  "OpenSearch",
+			ResourceSize:  aws.StringValue(clusterConfig.DedicatedMasterType),
+			Region:        region,
+			CreationTime:  time.Now(),
+			InstanceCount: aws.Int64Value(clusterConfig.DedicatedMasterCount),
+		})
+	}
+	if aws.BoolValue(clusterConfig.WarmEnabled) {
+		extraNodes = append(extraNodes, awslib.ResourceCostConfig{
+			ResourceType:  "OpenSearch",
+			ResourceSize:  aws.StringValue(clusterConfig.WarmType),
+			Region:        region,
+			CreationTime:  time.Now(),
+			InstanceCount: aws.Int64Value(clusterConfig.WarmCount),
+		})
+	}
+	for _, nodes := range extraNodes {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(nodes)
+		if err != nil {
+			logging.Warn("Failed to calculate OpenSearch node cost", map[string]interface{}{
+				"region":        region,
+				"domain_name":   aws.StringValue(status.DomainName),
+				"instance_type": nodes.ResourceSize,
+				"error":         err.Error(),
+			})
+			continue
+		}
+		addCostBreakdown(total, cost)
 	}
+	return total
+}
 
-	// Check JVM memory pressure
-	if metrics["jvm_memory"] > 85 {
-		reasons = append(reasons, fmt.Sprintf("High JVM memory pressure (%.2f%%).", metrics["jvm_memory"]))
+// describeDomains returns the status of every domain in the region
+func (s *OpenSearchScanner) describeDomains(opts awslib.ScanOptions, client *opensearchservice.OpenSearchService) ([]*opensearchservice.DomainStatus, error) {
+	listOutput, err := client.ListDomainNamesWithContext(opts.Context(), &opensearchservice.ListDomainNamesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domains: %w", err)
 	}
 
-	// Check for low activity clusters
-	avgSearchRate := metrics["search_rate"]
-	avgIndexRate := metrics["index_rate"]
-	if avgSearchRate < 1 && avgIndexRate < 1 && metrics["doc_count"] > 0 {
-		reasons = append(reasons, fmt.Sprintf("Very low activity: %.2f searches/hour, %.2f indexes/hour with %.0f documents in the last %d days.", avgSearchRate, avgIndexRate, metrics["doc_count"], opts.DaysUnused))
+	var names []*string
+	for _, domain := range listOutput.DomainNames {
+		names = append(names, domain.DomainName)
 	}
 
-	return reasons
+	var domains []*opensearchservice.DomainStatus
+	for start := 0; start < len(names); start += openSearchDescribeBatch {
+		end := start + openSearchDescribeBatch
+		if end > len(names) {
+			end = len(names)
+		}
+		output, err := client.DescribeDomainsWithContext(opts.Context(), &opensearchservice.DescribeDomainsInput{
+			DomainNames: names[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe domains: %w", err)
+		}
+		domains = append(domains, output.DomainStatusList...)
+	}
+	return domains, nil
 }
 
 // Scan implements Scanner interface
@@ -168,107 +224,123 @@ func (s *OpenSearchScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, e
 		return nil, fmt.Errorf("failed to create regional session: %w", err)
 	}
 
-	// Create service clients
-	esClient := opensearchservice.New(sess)
+	client := opensearchservice.New(sess)
 	cwClient := cloudwatch.New(sess)
 
-	// Get all OpenSearch domains
+	domains, err := s.describeDomains(opts, client)
+	if err != nil {
+		logging.Error("Failed to describe OpenSearch domains", err, nil)
+		return nil, err
+	}
+
 	var results awslib.ScanResults
 	endTime := time.Now().UTC()
 	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
 
-	// List all domains
-	listOutput, err := esClient.ListDomainNames(&opensearchservice.ListDomainNamesInput{})
-	if err != nil {
-		logging.Error("Failed to list OpenSearch domains", err, nil)
-		return nil, fmt.Errorf("failed to list OpenSearch domains: %w", err)
-	}
+	for _, status := range domains {
+		domainName := aws.StringValue(status.DomainName)
+		domainARN := aws.StringValue(status.ARN)
 
-	for _, domain := range listOutput.DomainNames {
-		domainName := aws.StringValue(domain.DomainName)
-
-		logging.Debug("Analyzing OpenSearch domain", map[string]interface{}{
-			"domain_name": domainName,
-		})
+		// Domains being created, deleted, reconfigured or upgraded report unusual metrics
+		if !aws.BoolValue(status.Created) || aws.BoolValue(status.Deleted) ||
+			aws.BoolValue(status.Processing) || aws.BoolValue(status.UpgradeProcessing) {
+			logging.Debug("Skipping OpenSearch domain that is being changed", map[string]interface{}{
+				"domain_name": domainName,
+			})
+			continue
+		}
 
-		// Get domain details
-		describeOutput, err := esClient.DescribeDomain(&opensearchservice.DescribeDomainInput{
-			DomainName: aws.String(domainName),
-		})
+		parsedARN, err := arn.Parse(domainARN)
 		if err != nil {
-			logging.Error("Failed to describe domain", err, map[string]interface{}{
+			logging.Error("Failed to parse OpenSearch domain ARN", err, map[string]interface{}{
 				"domain_name": domainName,
 			})
 			continue
 		}
 
-		status := describeOutput.DomainStatus
-
-		// Get cluster metrics
-		metrics, err := s.getClusterMetrics(cwClient, domainName, startTime, endTime)
+		activity, err := s.getDomainActivity(opts, cwClient, domainName, parsedARN.AccountID, startTime, endTime)
 		if err != nil {
-			logging.Error("Failed to get cluster metrics", err, map[string]interface{}{
+			logging.Error("Failed to analyze OpenSearch domain activity", err, map[string]interface{}{
 				"domain_name": domainName,
 			})
 			continue
 		}
+		// Without metrics there is nothing to say the domain is idle
+		if !activity.reported {
+			continue
+		}
+		if activity.maxSearchRate >= openSearchIdleRequestRate || activity.maxIndexingRate >= openSearchIdleRequestRate ||
+			activity.avgCPU >= openSearchIdleCPU {
+			continue
+		}
 
-		// Get cluster configuration
-		instanceType := aws.StringValue(status.ClusterConfig.InstanceType)
-		instanceCount := aws.Int64Value(status.ClusterConfig.InstanceCount)
-		volumeType := aws.StringValue(status.EBSOptions.VolumeType)
-		volumeSize := aws.Int64Value(status.EBSOptions.VolumeSize)
-
-		// Determine if cluster is unused/underutilized
-		reasons := s.determineUnusedReasons(metrics, volumeSize, opts)
-
-		if len(reasons) > 0 {
-			// Calculate cost
-			// costConfig := awslib.ResourceCostConfig{
-			// 	ResourceType:  "OpenSearch",
-			// 	ResourceSize:  instanceType,
-			// 	Region:        opts.Region,
-			// 	CreationTime:  time.Now(), // OpenSearch API doesn't provide creation time
-			// 	VolumeType:    volumeType,
-			// 	StorageSize:   volumeSize,
-			// 	InstanceCount: instanceCount,
-			// }
-
-			// cost, err := awslib.DefaultCostEstimator.CalculateCost(costConfig)
-			// if err != nil {
-			// 	logging.Error("Failed to calculate cost", err, map[string]interface{}{
-			// 		"domain_name": domainName,
-			// 	})
-			// }
-
-			details := map[string]interface{}{
-				"InstanceType":   instanceType,
-				"InstanceCount":  instanceCount,
-				"VolumeType":     volumeType,
-				"VolumeSizeGB":   volumeSize,
-				"CPUUtilization": metrics["cpu_utilization"],
-				"SearchRate":     metrics["search_rate"],
-				"IndexRate":      metrics["index_rate"],
-				"DocumentCount":  metrics["doc_count"],
-				"JVMMemory":      metrics["jvm_memory"],
-				"opts.AccountID": opts.AccountID,
-				"Region":         opts.Region,
-			}
-
-			// if cost != nil {
-			// 	details["Cost"] = cost
-			// }
+		clusterConfig := status.ClusterConfig
+		instanceType := aws.StringValue(clusterConfig.InstanceType)
+		instanceCount := aws.Int64Value(clusterConfig.InstanceCount)
+		details := map[string]interface{}{
+			"account_id":               opts.AccountID,
+			"region":                   opts.Region,
+			"domain_id":                aws.StringValue(status.DomainId),
+			"engine_version":           aws.StringValue(status.EngineVersion),
+			"instance_type":            instanceType,
+			"instance_count":           instanceCount,
+			"dedicated_master_enabled": aws.BoolValue(clusterConfig.DedicatedMasterEnabled),
+			"warm_enabled":             aws.BoolValue(clusterConfig.WarmEnabled),
+			"zone_awareness_enabled":   aws.BoolValue(clusterConfig.ZoneAwarenessEnabled),
+			"max_search_rate":          activity.maxSearchRate,
+			"max_indexing_rate":        activity.maxIndexingRate,
+			"avg_cpu_utilization":      math.Round(activity.avgCPU*100) / 100,
+		}
+		if aws.BoolValue(clusterConfig.DedicatedMasterEnabled) {
+			details["dedicated_master_type"] = aws.StringValue(clusterConfig.DedicatedMasterType)
+			details["dedicated_master_count"] = aws.Int64Value(clusterConfig.DedicatedMasterCount)
+		}
+		if aws.BoolValue(clusterConfig.WarmEnabled) {
+			details["warm_type"] = aws.StringValue(clusterConfig.WarmType)
+			details["warm_count"] = aws.Int64Value(clusterConfig.WarmCount)
+		}
+		if status.EBSOptions != nil && aws.BoolValue(status.EBSOptions.EBSEnabled) {
+			volumeSize := aws.Int64Value(status.EBSOptions.VolumeSize)
+			details["volume_type"] = aws.StringValue(status.EBSOptions.VolumeType)
+			details["volume_size_gb"] = volumeSize
+			details["total_storage_gb"] = volumeSize * instanceCount
+		}
 
-			result := awslib.ScanResult{
-				ResourceType: s.Label(),
-				ResourceName: domainName,
-				ResourceID:   aws.StringValue(status.ARN),
-				Reason:       strings.Join(reasons, "\n"),
-				Details:      details,
+		tags := make(map[string]string)
+		tagsOutput, err := client.ListTagsWithContext(opts.Context(), &opensearchservice.ListTagsInput{
+			ARN: status.ARN,
+		})
+		if err != nil {
+			logging.Debug("Failed to list OpenSearch domain tags", map[string]interface{}{
+				"domain_name": domainName,
+				"error":       err.Error(),
+			})
+		} else {
+			for _, tag := range tagsOutput.TagList {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
 			}
+		}
 
-			results = append(results, result)
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: domainName,
+			ResourceID:   domainName,
+			ARN:          domainARN,
+			// DomainStatus has no creation time, so domains are always reported regardless of
+			// --min-age-days, like other resources without a known creation time
+			CreatedAt: nil,
+			Reason: fmt.Sprintf("Domain of %d %s nodes peaked at %.1f searches and %.1f indexing requests per minute with %.1f%% average CPU in the last %d days",
+				instanceCount, instanceType, activity.maxSearchRate, activity.maxIndexingRate, activity.avgCPU, opts.DaysUnused),
+			Confidence: awslib.ConfidenceLow,
+			Details:    details,
+			Tags:       tags,
+		}
+		if cost := s.calculateDomainCost(status, opts.Region); cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
 		}
+		results = append(results, result)
 	}
 
 	return results, nil
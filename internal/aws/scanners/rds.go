@@ -31,6 +31,11 @@ func (s *RDSScanner) Label() string {
 	return "RDS Instances"
 }
 
+// IsGlobal implements Scanner interface
+func (s *RDSScanner) IsGlobal() bool {
+	return false
+}
+
 // Scan implements Scanner interface
 func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	// Get regional session
@@ -48,7 +53,7 @@ func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 
 	// Get all RDS instances
 	var instances []*rds.DBInstance
-	err = rdsClient.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{},
+	err = rdsClient.DescribeDBInstancesPagesWithContext(opts.Context(), &rds.DescribeDBInstancesInput{},
 		func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
 			instances = append(instances, page.DBInstances...)
 			return !lastPage
@@ -63,6 +68,12 @@ func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
 
 	for _, instance := range instances {
+		// DocumentDB and Neptune instances are reported per cluster by their own scanners
+		switch aws.StringValue(instance.Engine) {
+		case "docdb", "neptune":
+			continue
+		}
+
 		instanceID := aws.StringValue(instance.DBInstanceIdentifier)
 		logging.Debug("Analyzing RDS instance", map[string]interface{}{
 			"instance_id": instanceID,
@@ -72,7 +83,7 @@ func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 		hoursRunning := endTime.Sub(aws.TimeValue(instance.InstanceCreateTime)).Hours()
 
 		// Analyze instance usage
-		reasons, err := s.analyzeInstanceUsage(clients.CloudWatch, instance, startTime, endTime)
+		reasons, err := s.analyzeInstanceUsage(opts, clients.CloudWatch, instance, startTime, endTime)
 		if err != nil {
 			logging.Error("Failed to analyze instance usage", err, map[string]interface{}{
 				"instance_id": instanceID,
@@ -119,7 +130,10 @@ func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 				ResourceType: s.Label(),
 				ResourceName: instanceID,
 				ResourceID:   aws.StringValue(instance.DBInstanceArn),
+				ARN:          aws.StringValue(instance.DBInstanceArn),
+				CreatedAt:    instance.InstanceCreateTime,
 				Reason:       strings.Join(reasons, ", "),
+				Confidence:   awslib.ConfidenceMedium,
 				Details:      details,
 			}
 
@@ -153,7 +167,7 @@ func (s *RDSScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 }
 
 // analyzeInstanceUsage checks if an instance is underutilized
-func (s *RDSScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatch, instance *rds.DBInstance, startTime, endTime time.Time) ([]string, error) {
+func (s *RDSScanner) analyzeInstanceUsage(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, instance *rds.DBInstance, startTime, endTime time.Time) ([]string, error) {
 	instanceID := aws.StringValue(instance.DBInstanceIdentifier)
 	var reasons []string
 
@@ -185,7 +199,7 @@ func (s *RDSScanner) analyzeInstanceUsage(cwClient *cloudwatch.CloudWatch, insta
 			},
 		}
 
-		values, err := utils.GetResourceMetricsData(cwClient, config)
+		values, err := utils.GetResourceMetricsData(opts.Context(), cwClient, config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch %s metrics: %w", metric.name, err)
 		}
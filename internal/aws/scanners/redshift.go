@@ -0,0 +1,303 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/redshift"
+)
+
+// RedshiftScanner scans for reserved Redshift nodes that no running cluster uses and for Redshift
+// Serverless workgroups that haven't run any queries
+type RedshiftScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&RedshiftScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *RedshiftScanner) ArgumentName() string {
+	return "redshift"
+}
+
+// Label implements Scanner interface
+func (s *RedshiftScanner) Label() string {
+	return "Redshift"
+}
+
+// IsGlobal implements Scanner interface
+func (s *RedshiftScanner) IsGlobal() bool {
+	return false
+}
+
+// getRunningNodes returns the number of nodes of each node type in the region's available
+// clusters. Paused clusters aren't billed for compute, so their nodes don't use reservations.
+func (s *RedshiftScanner) getRunningNodes(opts awslib.ScanOptions, client *redshift.Redshift) (map[string]int64, error) {
+	nodes := make(map[string]int64)
+	err := client.DescribeClustersPagesWithContext(opts.Context(), &redshift.DescribeClustersInput{}, func(page *redshift.DescribeClustersOutput, lastPage bool) bool {
+		for _, cluster := range page.Clusters {
+			if aws.StringValue(cluster.ClusterStatus) != "available" {
+				continue
+			}
+			nodes[aws.StringValue(cluster.NodeType)] += aws.Int64Value(cluster.NumberOfNodes)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe clusters: %w", err)
+	}
+	return nodes, nil
+}
+
+// calculateUnusedNodeCost estimates the cost of a reservation's unused nodes. It returns nil when
+// there is no price for the node type.
+func (s *RedshiftScanner) calculateUnusedNodeCost(nodeType string, unused int64, startTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator == nil {
+		return nil
+	}
+
+	cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+		ResourceType:  "RedshiftNode",
+		ResourceSize:  nodeType,
+		Region:        region,
+		CreationTime:  startTime,
+		InstanceCount: unused,
+	})
+	if err != nil {
+		logging.Warn("Failed to calculate Redshift node cost", map[string]interface{}{
+			"region":    region,
+			"node_type": nodeType,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+	return cost
+}
+
+// scanReservedNodes reports active reserved nodes that aren't covered by running cluster nodes of
+// the same node type
+func (s *RedshiftScanner) scanReservedNodes(opts awslib.ScanOptions, client *redshift.Redshift) (awslib.ScanResults, error) {
+	var reservations []*redshift.ReservedNode
+	err := client.DescribeReservedNodesPagesWithContext(opts.Context(), &redshift.DescribeReservedNodesInput{}, func(page *redshift.DescribeReservedNodesOutput, lastPage bool) bool {
+		for _, node := range page.ReservedNodes {
+			if aws.StringValue(node.State) == "active" {
+				reservations = append(reservations, node)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe reserved nodes: %w", err)
+	}
+	if len(reservations) == 0 {
+		return nil, nil
+	}
+
+	runningNodes, err := s.getRunningNodes(opts, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Running nodes are applied to the oldest reservations first, so the unused ones are the most
+	// recently purchased
+	sort.SliceStable(reservations, func(i, j int) bool {
+		return aws.TimeValue(reservations[i].StartTime).Before(aws.TimeValue(reservations[j].StartTime))
+	})
+
+	var results awslib.ScanResults
+	now := time.Now().UTC()
+	for _, reservation := range reservations {
+		reservationID := aws.StringValue(reservation.ReservedNodeId)
+		nodeType := aws.StringValue(reservation.NodeType)
+		nodeCount := aws.Int64Value(reservation.NodeCount)
+		startTime := aws.TimeValue(reservation.StartTime)
+
+		covered := nodeCount
+		if runningNodes[nodeType] < covered {
+			covered = runningNodes[nodeType]
+		}
+		runningNodes[nodeType] -= covered
+		unused := nodeCount - covered
+		if unused <= 0 {
+			continue
+		}
+
+		expiresAt := startTime.Add(time.Duration(aws.Int64Value(reservation.Duration)) * time.Second)
+		daysUntilExpiry := int(math.Max(0, math.Floor(expiresAt.Sub(now).Hours()/24)))
+
+		details := map[string]interface{}{
+			"account_id":        opts.AccountID,
+			"region":            opts.Region,
+			"finding_type":      "unused_reserved_nodes",
+			"reserved_node_id":  reservationID,
+			"offering_id":       aws.StringValue(reservation.ReservedNodeOfferingId),
+			"offering_type":     aws.StringValue(reservation.OfferingType),
+			"node_type":         nodeType,
+			"node_count":        nodeCount,
+			"unused_node_count": unused,
+			"fixed_price":       aws.Float64Value(reservation.FixedPrice),
+			"usage_price":       aws.Float64Value(reservation.UsagePrice),
+			"start_time":        startTime.Format(time.RFC3339),
+			"expires_at":        expiresAt.Format(time.RFC3339),
+			"days_until_expiry": daysUntilExpiry,
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: reservationID,
+			ResourceID:   reservationID,
+			CreatedAt:    aws.Time(startTime),
+			Reason: fmt.Sprintf("%d of %d reserved %s nodes aren't used by any running cluster; the reservation expires in %d days",
+				unused, nodeCount, nodeType, daysUntilExpiry),
+			Confidence: awslib.ConfidenceHigh,
+			Details:    details,
+		}
+		if cost := s.calculateUnusedNodeCost(nodeType, unused, startTime, opts.Region); cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// getComputeSeconds returns the RPU-seconds a workgroup used between startTime and endTime
+func (s *RedshiftScanner) getComputeSeconds(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, workgroupName string, startTime, endTime time.Time) (float64, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/Redshift-Serverless"),
+		MetricName: aws.String("ComputeSeconds"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("Workgroup"),
+				Value: aws.String(workgroupName),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Sum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ComputeSeconds metric: %w", err)
+	}
+
+	var total float64
+	for _, dp := range output.Datapoints {
+		total += aws.Float64Value(dp.Sum)
+	}
+	return total, nil
+}
+
+// scanServerlessWorkgroups reports Redshift Serverless workgroups that used no compute for the
+// last DaysUnused days
+func (s *RedshiftScanner) scanServerlessWorkgroups(opts awslib.ScanOptions, serverlessClient *redshiftServerlessClient, cwClient *cloudwatch.CloudWatch) (awslib.ScanResults, error) {
+	workgroups, err := serverlessClient.listWorkgroups(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serverless workgroups: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, workgroup := range workgroups {
+		workgroupName := aws.StringValue(workgroup.WorkgroupName)
+		creationDate := aws.TimeValue(workgroup.CreationDate)
+
+		// Workgroups that are still being created or deleted have nothing to report yet
+		if aws.StringValue(workgroup.Status) != "AVAILABLE" {
+			continue
+		}
+		// New workgroups don't have a full metric window
+		if creationDate.After(startTime) {
+			continue
+		}
+
+		computeSeconds, err := s.getComputeSeconds(opts, cwClient, workgroupName, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze serverless workgroup usage", err, map[string]interface{}{
+				"workgroup_name": workgroupName,
+			})
+			continue
+		}
+		if computeSeconds > 0 {
+			continue
+		}
+
+		baseRPU := aws.Int64Value(workgroup.BaseCapacity)
+		details := map[string]interface{}{
+			"account_id":     opts.AccountID,
+			"region":         opts.Region,
+			"finding_type":   "idle_serverless_workgroup",
+			"workgroup_id":   aws.StringValue(workgroup.WorkgroupID),
+			"namespace_name": aws.StringValue(workgroup.NamespaceName),
+			"base_rpu":       baseRPU,
+			"status":         aws.StringValue(workgroup.Status),
+			"creation_date":  creationDate.Format(time.RFC3339),
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: workgroupName,
+			ResourceID:   workgroupName,
+			ARN:          aws.StringValue(workgroup.WorkgroupArn),
+			CreatedAt:    aws.Time(creationDate),
+			Reason: fmt.Sprintf("Serverless workgroup with a base capacity of %d RPUs used no compute for the last %d days",
+				baseRPU, opts.DaysUnused),
+			Confidence: awslib.ConfidenceMedium,
+			Details:    details,
+			// Serverless compute is only billed while queries run; the namespace's storage is billed
+			// whether or not the workgroup is used
+			Cost: map[string]interface{}{
+				"total": &awslib.CostBreakdown{},
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// Scan implements Scanner interface
+func (s *RedshiftScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := redshift.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	results, err := s.scanReservedNodes(opts, client)
+	if err != nil {
+		logging.Error("Failed to scan reserved Redshift nodes", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, err
+	}
+
+	// Redshift Serverless isn't available in every region that has provisioned Redshift, so a
+	// failure here is logged rather than losing the reserved node findings
+	workgroupResults, err := s.scanServerlessWorkgroups(opts, newRedshiftServerlessClient(sess), cwClient)
+	if err != nil {
+		logging.Warn("Failed to scan Redshift Serverless workgroups", map[string]interface{}{
+			"region": opts.Region,
+			"error":  err.Error(),
+		})
+	}
+	results = append(results, workgroupResults...)
+
+	return results, nil
+}
@@ -0,0 +1,483 @@
+package scanners
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+)
+
+const (
+	commitmentUtilizationThreshold = 80.0  // Reservations and Savings Plans used less than this percentage are flagged
+	savingsPlansCoverageThreshold  = 50.0  // Usage covered by Savings Plans less than this percentage is flagged
+	minUncoveredMonthlySpend       = 100.0 // On-demand spend, per 30 days, too small to be worth a commitment
+	costExplorerDateLayout         = "2006-01-02"
+)
+
+// RIUtilizationScanner scans Cost Explorer for Reserved Instances and Savings Plans that went
+// mostly unused, and for on-demand usage that no Savings Plan covers
+type RIUtilizationScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&RIUtilizationScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *RIUtilizationScanner) ArgumentName() string {
+	return "ri-utilization"
+}
+
+// Label implements Scanner interface
+func (s *RIUtilizationScanner) Label() string {
+	return "RI and Savings Plans"
+}
+
+// IsGlobal implements Scanner interface
+func (s *RIUtilizationScanner) IsGlobal() bool {
+	return true
+}
+
+// parseAmount parses one of Cost Explorer's numeric strings, treating a missing or invalid value as 0
+func parseAmount(value *string) float64 {
+	amount, err := strconv.ParseFloat(aws.StringValue(value), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// commitmentAttribute returns the first of the given attributes Cost Explorer reported for a
+// commitment. Reservation and Savings Plans attributes aren't named consistently, so callers pass
+// every spelling they accept.
+func commitmentAttribute(attributes map[string]*string, keys ...string) string {
+	for _, key := range keys {
+		if value := aws.StringValue(attributes[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseCommitmentTime parses a commitment's start or end date
+func parseCommitmentTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", costExplorerDateLayout} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// commitmentTerm describes a commitment's term, e.g. "1 year", from its start and end dates
+func commitmentTerm(start, end string) string {
+	startTime, ok := parseCommitmentTime(start)
+	if !ok {
+		return ""
+	}
+	endTime, ok := parseCommitmentTime(end)
+	if !ok {
+		return ""
+	}
+	years := int(math.Round(endTime.Sub(startTime).Hours() / (24 * 365)))
+	if years == 1 {
+		return "1 year"
+	}
+	return fmt.Sprintf("%d years", years)
+}
+
+// activeHours returns how many hours of the scanned period a commitment was active for, so one
+// bought partway through isn't charged with the whole period
+func activeHours(start string, periodStart, periodEnd time.Time) float64 {
+	if startTime, ok := parseCommitmentTime(start); ok && startTime.After(periodStart) {
+		periodStart = startTime
+	}
+	return math.Max(periodEnd.Sub(periodStart).Hours(), 1)
+}
+
+// wastedCost spreads the amount a commitment wasted over the hours it was active. The waste recurs
+// for as long as the commitment goes unused, so it has no lifetime.
+func wastedCost(wasted, hours float64) *awslib.CostBreakdown {
+	hourlyRate := wasted / hours
+	return &awslib.CostBreakdown{
+		HourlyRate:  hourlyRate,
+		DailyRate:   hourlyRate * 24,
+		MonthlyRate: hourlyRate * 24 * 30,
+		YearlyRate:  hourlyRate * 24 * 365,
+	}
+}
+
+// isDataUnavailable reports whether Cost Explorer had no data to return, which it reports as an
+// error for accounts without any reservations
+func isDataUnavailable(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == costexplorer.ErrCodeDataUnavailableException
+}
+
+// reservationUsage accumulates a reservation's utilization across Cost Explorer's time periods
+type reservationUsage struct {
+	attributes     map[string]*string
+	purchasedHours float64
+	unusedHours    float64
+	unusedCost     float64
+	amortizedFee   float64
+}
+
+// scanReservations flags Reserved Instances whose reserved hours went mostly unused
+func (s *RIUtilizationScanner) scanReservations(opts awslib.ScanOptions, client *costexplorer.CostExplorer, period *costexplorer.DateInterval, filter *costexplorer.Expression, periodStart, periodEnd time.Time) ([]awslib.ScanResult, error) {
+	usage := make(map[string]*reservationUsage)
+	// Grouped utilization can't set a granularity, so the whole period comes back as one result
+	input := &costexplorer.GetReservationUtilizationInput{
+		TimePeriod: period,
+		Filter:     filter,
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionSubscriptionId),
+			},
+		},
+	}
+	for {
+		output, err := client.GetReservationUtilizationWithContext(opts.Context(), input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get reservation utilization: %w", err)
+		}
+		for _, byTime := range output.UtilizationsByTime {
+			for _, group := range byTime.Groups {
+				if group.Utilization == nil {
+					continue
+				}
+				id := aws.StringValue(group.Value)
+				u, ok := usage[id]
+				if !ok {
+					u = &reservationUsage{attributes: group.Attributes}
+					usage[id] = u
+				}
+				u.purchasedHours += parseAmount(group.Utilization.PurchasedHours)
+				u.unusedHours += parseAmount(group.Utilization.UnusedHours)
+				u.unusedCost += parseAmount(group.Utilization.RICostForUnusedHours)
+				u.amortizedFee += parseAmount(group.Utilization.TotalAmortizedFee)
+			}
+		}
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+
+	ids := make([]string, 0, len(usage))
+	for id := range usage {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var results []awslib.ScanResult
+	for _, id := range ids {
+		u := usage[id]
+		if u.purchasedHours <= 0 {
+			continue
+		}
+		utilization := (u.purchasedHours - u.unusedHours) / u.purchasedHours * 100
+		if utilization >= commitmentUtilizationThreshold {
+			continue
+		}
+		// Older data has no cost for unused hours, so fall back to the unused share of the fee
+		wasted := u.unusedCost
+		if wasted <= 0 {
+			wasted = u.amortizedFee * u.unusedHours / u.purchasedHours
+		}
+
+		attrs := u.attributes
+		start := commitmentAttribute(attrs, "startDateTime", "StartDateTime")
+		end := commitmentAttribute(attrs, "endDateTime", "EndDateTime")
+		instanceType := commitmentAttribute(attrs, "instanceType", "InstanceType")
+		region := commitmentAttribute(attrs, "region", "Region")
+		resourceID := commitmentAttribute(attrs, "leaseId", "LeaseId")
+		if resourceID == "" {
+			resourceID = id
+		}
+
+		details := map[string]interface{}{
+			"account_id":             opts.AccountID,
+			"region":                 opts.Region,
+			"finding_type":           "reservation",
+			"subscription_id":        id,
+			"instance_type":          instanceType,
+			"commitment_region":      region,
+			"platform":               commitmentAttribute(attrs, "platform", "Platform"),
+			"payment_option":         commitmentAttribute(attrs, "subscriptionType", "SubscriptionType"),
+			"term":                   commitmentTerm(start, end),
+			"start_date":             start,
+			"end_date":               end,
+			"utilization_percentage": math.Round(utilization*10) / 10,
+			"purchased_hours":        u.purchasedHours,
+			"unused_hours":           u.unusedHours,
+			"wasted_amount_usd":      wasted,
+		}
+		if count, err := strconv.Atoi(commitmentAttribute(attrs, "numberOfInstances", "NumberOfInstances")); err == nil {
+			details["instance_count"] = count
+			details["recommended_instance_count"] = int(math.Floor(float64(count) * utilization / 100))
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: strings.TrimSpace(fmt.Sprintf("%s %s", instanceType, region)),
+			ResourceID:   resourceID,
+			Reason: fmt.Sprintf("Reserved %s in %s was %.1f%% utilized over the last %d days, wasting $%.2f",
+				instanceType, region, utilization, opts.DaysUnused, wasted),
+			Confidence: awslib.ConfidenceHigh,
+			Details:    details,
+		}
+		if wasted > 0 {
+			result.Cost = map[string]interface{}{
+				"total": wastedCost(wasted, activeHours(start, periodStart, periodEnd)),
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// scanSavingsPlans flags Savings Plans whose hourly commitment went mostly unused
+func (s *RIUtilizationScanner) scanSavingsPlans(opts awslib.ScanOptions, client *costexplorer.CostExplorer, period *costexplorer.DateInterval, filter *costexplorer.Expression, periodStart, periodEnd time.Time) ([]awslib.ScanResult, error) {
+	var plans []*costexplorer.SavingsPlansUtilizationDetail
+	err := client.GetSavingsPlansUtilizationDetailsPagesWithContext(opts.Context(), &costexplorer.GetSavingsPlansUtilizationDetailsInput{
+		TimePeriod: period,
+		Filter:     filter,
+	}, func(page *costexplorer.GetSavingsPlansUtilizationDetailsOutput, lastPage bool) bool {
+		plans = append(plans, page.SavingsPlansUtilizationDetails...)
+		return !lastPage
+	})
+	if err != nil {
+		if isDataUnavailable(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Savings Plans utilization: %w", err)
+	}
+
+	var results []awslib.ScanResult
+	for _, plan := range plans {
+		if plan.Utilization == nil {
+			continue
+		}
+		total := parseAmount(plan.Utilization.TotalCommitment)
+		if total <= 0 {
+			continue
+		}
+		used := parseAmount(plan.Utilization.UsedCommitment)
+		utilization := used / total * 100
+		if utilization >= commitmentUtilizationThreshold {
+			continue
+		}
+		wasted := parseAmount(plan.Utilization.UnusedCommitment)
+
+		arn := aws.StringValue(plan.SavingsPlanArn)
+		planID := arn[strings.LastIndex(arn, "/")+1:]
+		attrs := plan.Attributes
+		start := commitmentAttribute(attrs, "StartDateTime", "startDateTime")
+		end := commitmentAttribute(attrs, "EndDateTime", "endDateTime")
+		planType := commitmentAttribute(attrs, "SavingsPlansType", "savingsPlansType")
+		term := commitmentAttribute(attrs, "PurchaseTerm", "purchaseTerm")
+		if term == "" {
+			term = commitmentTerm(start, end)
+		}
+		hours := activeHours(start, periodStart, periodEnd)
+
+		details := map[string]interface{}{
+			"account_id":             opts.AccountID,
+			"region":                 opts.Region,
+			"finding_type":           "savings_plan",
+			"savings_plan_arn":       arn,
+			"savings_plan_type":      planType,
+			"commitment_region":      commitmentAttribute(attrs, "Region", "region"),
+			"instance_family":        commitmentAttribute(attrs, "InstanceFamily", "instanceFamily"),
+			"payment_option":         commitmentAttribute(attrs, "PaymentOption", "paymentOption"),
+			"term":                   term,
+			"start_date":             start,
+			"end_date":               end,
+			"hourly_commitment":      commitmentAttribute(attrs, "HourlyCommitment", "hourlyCommitment"),
+			"utilization_percentage": math.Round(utilization*10) / 10,
+			"total_commitment_usd":   total,
+			"used_commitment_usd":    used,
+			"wasted_amount_usd":      wasted,
+			// What the plan's usage would have needed, for sizing a replacement when it expires
+			"recommended_hourly_commitment": math.Round(used/hours*1000) / 1000,
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: strings.TrimSpace(fmt.Sprintf("%s %s", planType, planID)),
+			ResourceID:   planID,
+			ARN:          arn,
+			Reason: fmt.Sprintf("Savings Plan was %.1f%% utilized over the last %d days, wasting $%.2f of its commitment",
+				utilization, opts.DaysUnused, wasted),
+			Confidence: awslib.ConfidenceHigh,
+			Details:    details,
+		}
+		if wasted > 0 {
+			result.Cost = map[string]interface{}{
+				"total": wastedCost(wasted, hours),
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// coverageUsage accumulates Savings Plans coverage of a service in a region across time periods
+type coverageUsage struct {
+	service      string
+	region       string
+	onDemandCost float64
+	coveredCost  float64
+	totalCost    float64
+}
+
+// scanCoverage flags services whose usage in a region is mostly billed on-demand even though a
+// Savings Plan could cover it
+func (s *RIUtilizationScanner) scanCoverage(opts awslib.ScanOptions, client *costexplorer.CostExplorer, period *costexplorer.DateInterval, filter *costexplorer.Expression, periodStart, periodEnd time.Time) ([]awslib.ScanResult, error) {
+	usage := make(map[string]*coverageUsage)
+	err := client.GetSavingsPlansCoveragePagesWithContext(opts.Context(), &costexplorer.GetSavingsPlansCoverageInput{
+		TimePeriod:  period,
+		Filter:      filter,
+		Granularity: aws.String(costexplorer.GranularityMonthly),
+		GroupBy: []*costexplorer.GroupDefinition{
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionService),
+			},
+			{
+				Type: aws.String(costexplorer.GroupDefinitionTypeDimension),
+				Key:  aws.String(costexplorer.DimensionRegion),
+			},
+		},
+	}, func(page *costexplorer.GetSavingsPlansCoverageOutput, lastPage bool) bool {
+		for _, coverage := range page.SavingsPlansCoverages {
+			if coverage.Coverage == nil {
+				continue
+			}
+			service := commitmentAttribute(coverage.Attributes, costexplorer.DimensionService, "service")
+			region := commitmentAttribute(coverage.Attributes, costexplorer.DimensionRegion, "region")
+			key := service + "/" + region
+			u, ok := usage[key]
+			if !ok {
+				u = &coverageUsage{service: service, region: region}
+				usage[key] = u
+			}
+			u.onDemandCost += parseAmount(coverage.Coverage.OnDemandCost)
+			u.coveredCost += parseAmount(coverage.Coverage.SpendCoveredBySavingsPlans)
+			u.totalCost += parseAmount(coverage.Coverage.TotalCost)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		if isDataUnavailable(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get Savings Plans coverage: %w", err)
+	}
+
+	keys := make([]string, 0, len(usage))
+	for key := range usage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	days := periodEnd.Sub(periodStart).Hours() / 24
+	var results []awslib.ScanResult
+	for _, key := range keys {
+		u := usage[key]
+		if u.totalCost <= 0 || u.service == "" {
+			continue
+		}
+		coverage := u.coveredCost / u.totalCost * 100
+		monthlyOnDemand := u.onDemandCost / days * 30
+		if coverage >= savingsPlansCoverageThreshold || monthlyOnDemand < minUncoveredMonthlySpend {
+			continue
+		}
+
+		// The saving depends on the commitment bought, so coverage gaps carry no cost of their own
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: strings.TrimSpace(fmt.Sprintf("%s %s", u.service, u.region)),
+			ResourceID:   key,
+			Reason: fmt.Sprintf("Only %.1f%% of %s usage in %s was covered by Savings Plans over the last %d days; $%.2f was billed on-demand",
+				coverage, u.service, u.region, opts.DaysUnused, u.onDemandCost),
+			Confidence: awslib.ConfidenceLow,
+			Details: map[string]interface{}{
+				"account_id":                  opts.AccountID,
+				"region":                      opts.Region,
+				"finding_type":                "coverage_gap",
+				"service":                     u.service,
+				"commitment_region":           u.region,
+				"coverage_percentage":         math.Round(coverage*10) / 10,
+				"on_demand_cost_usd":          u.onDemandCost,
+				"covered_cost_usd":            u.coveredCost,
+				"monthly_on_demand_spend_usd": monthlyOnDemand,
+			},
+		})
+	}
+	return results, nil
+}
+
+// Scan implements Scanner interface
+func (s *RIUtilizationScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Cost Explorer is only served from us-east-1
+	sess, err := awslib.GetSessionInRegion(opts.Session, "us-east-1")
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": "us-east-1",
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := costexplorer.New(sess)
+
+	// Cost Explorer's data ends with yesterday; the period's end date is exclusive
+	periodEnd := time.Now().UTC().Truncate(24 * time.Hour)
+	periodStart := periodEnd.AddDate(0, 0, -opts.DaysUnused)
+	period := &costexplorer.DateInterval{
+		Start: aws.String(periodStart.Format(costExplorerDateLayout)),
+		End:   aws.String(periodEnd.Format(costExplorerDateLayout)),
+	}
+
+	// A management account sees the whole organization's commitments, so only look at this account's
+	var filter *costexplorer.Expression
+	if opts.AccountID != "" {
+		filter = &costexplorer.Expression{
+			Dimensions: &costexplorer.DimensionValues{
+				Key:    aws.String(costexplorer.DimensionLinkedAccount),
+				Values: []*string{aws.String(opts.AccountID)},
+			},
+		}
+	}
+
+	var results awslib.ScanResults
+	for _, scan := range []func(awslib.ScanOptions, *costexplorer.CostExplorer, *costexplorer.DateInterval, *costexplorer.Expression, time.Time, time.Time) ([]awslib.ScanResult, error){
+		s.scanReservations,
+		s.scanSavingsPlans,
+		s.scanCoverage,
+	} {
+		found, err := scan(opts, client, period, filter, periodStart, periodEnd)
+		if err != nil {
+			logging.Error("Failed to analyze commitments", err, map[string]interface{}{
+				"account_id": opts.AccountID,
+			})
+			return nil, err
+		}
+		results = append(results, found...)
+	}
+
+	return results, nil
+}
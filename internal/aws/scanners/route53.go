@@ -0,0 +1,232 @@
+package scanners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// Route53Scanner scans for empty Route53 hosted zones and alias records pointing at deleted resources
+type Route53Scanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&Route53Scanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *Route53Scanner) ArgumentName() string {
+	return "route53-zones"
+}
+
+// Label implements Scanner interface
+func (s *Route53Scanner) Label() string {
+	return "Route53 Hosted Zones"
+}
+
+// IsGlobal implements Scanner interface
+func (s *Route53Scanner) IsGlobal() bool {
+	return true
+}
+
+// listRecordSets returns all record sets in a hosted zone
+func (s *Route53Scanner) listRecordSets(ctx context.Context, client *route53.Route53, zoneID string) ([]*route53.ResourceRecordSet, error) {
+	var recordSets []*route53.ResourceRecordSet
+	err := client.ListResourceRecordSetsPagesWithContext(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+	}, func(page *route53.ListResourceRecordSetsOutput, lastPage bool) bool {
+		recordSets = append(recordSets, page.ResourceRecordSets...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list record sets: %w", err)
+	}
+	return recordSets, nil
+}
+
+// findDanglingAliases returns the names of alias records whose targets no longer exist. Targets
+// in the same zone are checked against the zone's records; other targets are resolved via DNS.
+func (s *Route53Scanner) findDanglingAliases(ctx context.Context, zoneID string, recordSets []*route53.ResourceRecordSet) []string {
+	zoneRecords := make(map[string]bool, len(recordSets))
+	for _, recordSet := range recordSets {
+		zoneRecords[strings.ToLower(aws.StringValue(recordSet.Name))] = true
+	}
+
+	var dangling []string
+	for _, recordSet := range recordSets {
+		alias := recordSet.AliasTarget
+		if alias == nil {
+			continue
+		}
+
+		target := strings.ToLower(aws.StringValue(alias.DNSName))
+		recordName := fmt.Sprintf("%s %s", aws.StringValue(recordSet.Name), aws.StringValue(recordSet.Type))
+
+		if strings.TrimPrefix(aws.StringValue(alias.HostedZoneId), "/hostedzone/") == zoneID {
+			if !zoneRecords[target] {
+				dangling = append(dangling, recordName)
+			}
+			continue
+		}
+
+		_, err := net.DefaultResolver.LookupHost(ctx, strings.TrimSuffix(target, "."))
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			dangling = append(dangling, recordName)
+		}
+	}
+
+	return dangling
+}
+
+// calculateHostedZoneCost estimates the monthly fee for a hosted zone
+func (s *Route53Scanner) calculateHostedZoneCost() (*awslib.CostBreakdown, error) {
+	if awslib.DefaultCostEstimator == nil {
+		// Only use hardcoded values if the cost estimator is not available
+		hourlyRate := 0.50 / 730 // $0.50 per hosted zone per month
+
+		return &awslib.CostBreakdown{
+			HourlyRate:  hourlyRate,
+			DailyRate:   hourlyRate * 24,
+			MonthlyRate: hourlyRate * 24 * 30,
+			YearlyRate:  hourlyRate * 24 * 365,
+		}, nil
+	}
+
+	// Route53 pricing is global; the region only satisfies the estimator's location lookup
+	return awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+		ResourceType: "Route53HostedZone",
+		Region:       "us-east-1",
+	})
+}
+
+// Scan implements Scanner interface
+func (s *Route53Scanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := route53.New(sess)
+	ctx := opts.Context()
+
+	var zones []*route53.HostedZone
+	err = client.ListHostedZonesPagesWithContext(ctx, &route53.ListHostedZonesInput{}, func(page *route53.ListHostedZonesOutput, lastPage bool) bool {
+		zones = append(zones, page.HostedZones...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list Route53 hosted zones", err, nil)
+		return nil, fmt.Errorf("failed to list Route53 hosted zones: %w", err)
+	}
+
+	var results awslib.ScanResults
+
+	for _, zone := range zones {
+		zoneID := strings.TrimPrefix(aws.StringValue(zone.Id), "/hostedzone/")
+		zoneName := aws.StringValue(zone.Name)
+		recordCount := aws.Int64Value(zone.ResourceRecordSetCount)
+
+		var privateZone bool
+		var comment string
+		if zone.Config != nil {
+			privateZone = aws.BoolValue(zone.Config.PrivateZone)
+			comment = aws.StringValue(zone.Config.Comment)
+		}
+
+		var reasons []string
+		var dangling []string
+
+		// A new zone only has its NS and SOA records
+		emptyZone := recordCount <= 2
+		if emptyZone {
+			reasons = append(reasons, "Hosted zone contains only the default NS and SOA records")
+		} else {
+			recordSets, err := s.listRecordSets(ctx, client, zoneID)
+			if err != nil {
+				logging.Error("Failed to list Route53 record sets", err, map[string]interface{}{
+					"hosted_zone_id": zoneID,
+				})
+				continue
+			}
+
+			dangling = s.findDanglingAliases(ctx, zoneID, recordSets)
+			if len(dangling) > 0 {
+				reasons = append(reasons, fmt.Sprintf("Hosted zone has %d alias record(s) pointing to resources that no longer exist", len(dangling)))
+			}
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		// Only an empty zone's fee is waste; a zone with dangling records is still serving the rest
+		var cost *awslib.CostBreakdown
+		if emptyZone {
+			cost, err = s.calculateHostedZoneCost()
+			if err != nil {
+				logging.Error("Failed to calculate Route53 hosted zone cost", err, map[string]interface{}{
+					"hosted_zone_id": zoneID,
+				})
+			}
+		}
+
+		tags := make(map[string]string)
+		tagOutput, err := client.ListTagsForResourceWithContext(ctx, &route53.ListTagsForResourceInput{
+			ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+			ResourceId:   aws.String(zoneID),
+		})
+		if err != nil {
+			logging.Warn("Failed to get Route53 hosted zone tags", map[string]interface{}{
+				"hosted_zone_id": zoneID,
+				"error":          err.Error(),
+			})
+		} else if tagOutput.ResourceTagSet != nil {
+			for _, tag := range tagOutput.ResourceTagSet.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+		}
+
+		details := map[string]interface{}{
+			"account_id":   opts.AccountID,
+			"region":       opts.Region,
+			"record_count": recordCount,
+			"private_zone": privateZone,
+			"comment":      comment,
+		}
+		if len(dangling) > 0 {
+			details["dangling_records"] = dangling
+		}
+
+		result := awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: zoneName,
+			ResourceID:   zoneID,
+			ARN:          awslib.GlobalResourceARN("route53", opts.Region, "", "hostedzone/"+zoneID), // Hosted zone ARNs have no region or account ID
+			Reason:       strings.Join(reasons, "\n"),
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+			Tags:         tags,
+		}
+		if cost != nil {
+			result.Cost = map[string]interface{}{
+				"total": cost,
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
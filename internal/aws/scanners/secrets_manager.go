@@ -0,0 +1,264 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+const (
+	// cloudTrailLookupDays is how far back CloudTrail event history goes
+	cloudTrailLookupDays = 90
+
+	// maxSecretEventPages bounds the CloudTrail pages read per secret while looking for a GetSecretValue call
+	maxSecretEventPages = 5
+)
+
+// SecretsManagerScanner scans for Secrets Manager secrets that are never read
+type SecretsManagerScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&SecretsManagerScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *SecretsManagerScanner) ArgumentName() string {
+	return "secrets-manager"
+}
+
+// Label implements Scanner interface
+func (s *SecretsManagerScanner) Label() string {
+	return "Secrets Manager Secrets"
+}
+
+// IsGlobal implements Scanner interface
+func (s *SecretsManagerScanner) IsGlobal() bool {
+	return false
+}
+
+// calculateSecretCost estimates the cost of a secret and its replicas, which are each billed as a secret
+func (s *SecretsManagerScanner) calculateSecretCost(secretCount int64, creationTime time.Time, region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		cost, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType:  "SecretsManager",
+			Region:        region,
+			CreationTime:  creationTime,
+			InstanceCount: secretCount,
+		})
+		if err == nil {
+			return cost
+		}
+		logging.Warn("Failed to get Secrets Manager price, using default", map[string]interface{}{
+			"region": region,
+			"error":  err.Error(),
+		})
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable or fails
+	hourlyRate := 0.40 / 730 * float64(secretCount) // $0.40 per secret-month in us-east-1
+	hoursRunning := time.Since(creationTime).Hours()
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(hourlyRate * hoursRunning),
+	}
+}
+
+// lastGetSecretValue returns the time of the most recent GetSecretValue call on a secret recorded in
+// CloudTrail since startTime, or nil if there is none. Events are returned newest first, so the
+// search stops at the first match.
+func (s *SecretsManagerScanner) lastGetSecretValue(opts awslib.ScanOptions, ctClient *cloudtrail.CloudTrail, secretARN string, startTime time.Time) (*time.Time, error) {
+	var lastAccess *time.Time
+	pages := 0
+	err := ctClient.LookupEventsPagesWithContext(opts.Context(), &cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{
+				AttributeKey:   aws.String(cloudtrail.LookupAttributeKeyResourceName),
+				AttributeValue: aws.String(secretARN),
+			},
+		},
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(time.Now().UTC()),
+	}, func(page *cloudtrail.LookupEventsOutput, lastPage bool) bool {
+		pages++
+		for _, event := range page.Events {
+			if aws.StringValue(event.EventName) == "GetSecretValue" {
+				lastAccess = event.EventTime
+				return false
+			}
+		}
+		return !lastPage && pages < maxSecretEventPages
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lastAccess, nil
+}
+
+// Scan implements Scanner interface
+func (s *SecretsManagerScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	smClient := secretsmanager.New(sess)
+	ctClient := cloudtrail.New(sess)
+
+	var secrets []*secretsmanager.SecretListEntry
+	err = smClient.ListSecretsPagesWithContext(opts.Context(), &secretsmanager.ListSecretsInput{}, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		secrets = append(secrets, page.SecretList...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list secrets", err, nil)
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	var results awslib.ScanResults
+	startTime := time.Now().UTC().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	// CloudTrail only keeps 90 days of event history, so longer windows are checked as far back as it goes
+	trailStartTime := startTime
+	if opts.DaysUnused > cloudTrailLookupDays {
+		trailStartTime = time.Now().UTC().Add(-cloudTrailLookupDays * 24 * time.Hour)
+	}
+
+	for _, secret := range secrets {
+		secretName := aws.StringValue(secret.Name)
+		secretARN := aws.StringValue(secret.ARN)
+
+		// Replicas are billed with their primary, so a replicated secret is reported once, at the primary
+		if primaryRegion := aws.StringValue(secret.PrimaryRegion); primaryRegion != "" && primaryRegion != opts.Region {
+			continue
+		}
+
+		// Skip secrets too new to have gone unused for the whole window
+		createdDate := aws.TimeValue(secret.CreatedDate)
+		if secret.DeletedDate == nil && createdDate.After(startTime) {
+			continue
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range secret.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		details := map[string]interface{}{
+			"account_id":          opts.AccountID,
+			"region":              opts.Region,
+			"secret_name":         secretName,
+			"created_date":        createdDate.Format(time.RFC3339),
+			"rotation_enabled":    aws.BoolValue(secret.RotationEnabled),
+			"encrypted_with_cmk":  aws.StringValue(secret.KmsKeyId) != "",
+			"owning_service":      aws.StringValue(secret.OwningService),
+			"last_accessed_date":  "",
+			"cloudtrail_searched": false,
+		}
+		if secret.LastAccessedDate != nil {
+			details["last_accessed_date"] = secret.LastAccessedDate.Format(time.RFC3339)
+		}
+		if secret.LastRotatedDate != nil {
+			details["last_rotated_date"] = secret.LastRotatedDate.Format(time.RFC3339)
+		}
+
+		// Secrets already scheduled for deletion are reported as resolved, with no savings left to make
+		if secret.DeletedDate != nil {
+			details["status"] = "scheduled_for_deletion"
+			details["deleted_date"] = secret.DeletedDate.Format(time.RFC3339)
+			results = append(results, awslib.ScanResult{
+				ResourceType: s.Label(),
+				ResourceName: secretName,
+				ResourceID:   secretARN,
+				ARN:          secretARN,
+				CreatedAt:    secret.CreatedDate,
+				Reason:       "Resolved: secret is scheduled for deletion and stops billing once it is deleted",
+				Confidence:   awslib.ConfidenceHigh,
+				Details:      details,
+				Tags:         tags,
+				Cost: map[string]interface{}{
+					"total": &awslib.CostBreakdown{},
+				},
+			})
+			continue
+		}
+
+		// Secrets Manager records the day a secret was last read, which settles most secrets without CloudTrail
+		if secret.LastAccessedDate != nil && secret.LastAccessedDate.After(startTime) {
+			continue
+		}
+
+		// Confirm with CloudTrail, which also catches reads from the last day
+		lastAccess, err := s.lastGetSecretValue(opts, ctClient, secretARN, trailStartTime)
+		if err != nil {
+			logging.Debug("Failed to look up secret access in CloudTrail", map[string]interface{}{
+				"secret_name": secretName,
+				"error":       err.Error(),
+			})
+		} else {
+			details["cloudtrail_searched"] = true
+			if lastAccess != nil {
+				continue
+			}
+		}
+
+		var reason string
+		if secret.LastAccessedDate == nil {
+			reason = fmt.Sprintf("Secret has never been read and is older than %d days", opts.DaysUnused)
+		} else {
+			reason = fmt.Sprintf("Secret has not been read in the last %d days (last accessed %s)",
+				opts.DaysUnused, secret.LastAccessedDate.Format("2006-01-02"))
+		}
+		details["status"] = "unused"
+
+		// Replicas are billed as secrets too, but are only listed on DescribeSecret
+		var replicaRegions []string
+		describeOutput, err := smClient.DescribeSecretWithContext(opts.Context(), &secretsmanager.DescribeSecretInput{
+			SecretId: aws.String(secretARN),
+		})
+		if err != nil {
+			logging.Debug("Failed to describe secret", map[string]interface{}{
+				"secret_name": secretName,
+				"error":       err.Error(),
+			})
+		} else {
+			for _, replica := range describeOutput.ReplicationStatus {
+				replicaRegions = append(replicaRegions, aws.StringValue(replica.Region))
+			}
+		}
+		details["replica_regions"] = replicaRegions
+
+		cost := s.calculateSecretCost(int64(1+len(replicaRegions)), createdDate, opts.Region)
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: secretName,
+			ResourceID:   secretARN,
+			ARN:          secretARN,
+			CreatedAt:    secret.CreatedDate,
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+			Tags:         tags,
+			Cost: map[string]interface{}{
+				"total": cost,
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,106 @@
+package scanners
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSession returns a session that sends every AWS request to server
+func newTestSession(t *testing.T, server *httptest.Server) *session.Session {
+	sess, err := session.NewSession(&aws.Config{
+		Endpoint:    aws.String(server.URL),
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	require.NoError(t, err)
+	return sess
+}
+
+// TestSecretsManagerScan tests that replicas are left to their primary region, that secrets
+// scheduled for deletion are reported as resolved, and that an unused secret is billed with its replicas
+func TestSecretsManagerScan(t *testing.T) {
+	now := time.Now()
+	epoch := func(t time.Time) float64 { return float64(t.Unix()) }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		var response interface{}
+		switch target := r.Header.Get("X-Amz-Target"); {
+		case strings.HasSuffix(target, ".ListSecrets"):
+			response = map[string]interface{}{"SecretList": []map[string]interface{}{
+				{
+					"ARN":           "arn:aws:secretsmanager:us-west-2:123456789012:secret:replicated",
+					"Name":          "replicated",
+					"CreatedDate":   epoch(now.AddDate(0, 0, -200)),
+					"PrimaryRegion": "us-west-2",
+				},
+				{
+					"ARN":         "arn:aws:secretsmanager:us-east-1:123456789012:secret:deleting",
+					"Name":        "deleting",
+					"CreatedDate": epoch(now.AddDate(0, 0, -1)),
+					"DeletedDate": epoch(now),
+				},
+				{
+					"ARN":              "arn:aws:secretsmanager:us-east-1:123456789012:secret:read",
+					"Name":             "read",
+					"CreatedDate":      epoch(now.AddDate(0, 0, -200)),
+					"LastAccessedDate": epoch(now.AddDate(0, 0, -2)),
+				},
+				{
+					"ARN":           "arn:aws:secretsmanager:us-east-1:123456789012:secret:unused",
+					"Name":          "unused",
+					"CreatedDate":   epoch(now.AddDate(0, 0, -200)),
+					"PrimaryRegion": "us-east-1",
+				},
+			}}
+		case strings.HasSuffix(target, ".DescribeSecret"):
+			response = map[string]interface{}{"ReplicationStatus": []map[string]interface{}{
+				{"Region": "us-west-2", "Status": "InSync"},
+			}}
+		case strings.HasSuffix(target, ".LookupEvents"):
+			response = map[string]interface{}{"Events": []interface{}{}}
+		default:
+			t.Errorf("unexpected request %q", target)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	scanner := &SecretsManagerScanner{}
+	results, err := scanner.Scan(awslib.ScanOptions{
+		Region:     "us-east-1",
+		DaysUnused: 30,
+		Session:    newTestSession(t, server),
+		AccountID:  "123456789012",
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	deleting := results[0]
+	assert.Equal(t, "deleting", deleting.ResourceName)
+	assert.Equal(t, "scheduled_for_deletion", deleting.Details["status"])
+	assert.Contains(t, deleting.Reason, "Resolved")
+	assert.Equal(t, awslib.ConfidenceHigh, deleting.Confidence)
+	assert.Zero(t, deleting.Cost["total"].(*awslib.CostBreakdown).MonthlyRate)
+
+	unused := results[1]
+	assert.Equal(t, "unused", unused.ResourceName)
+	assert.Equal(t, "unused", unused.Details["status"])
+	assert.Equal(t, true, unused.Details["cloudtrail_searched"])
+	assert.Equal(t, []string{"us-west-2"}, unused.Details["replica_regions"])
+	assert.InDelta(t, 0.80/730*24*30, unused.Cost["total"].(*awslib.CostBreakdown).MonthlyRate, 0.0001,
+		"the primary and its replica are each billed as a secret")
+}
@@ -27,6 +27,11 @@ func (s *SecurityGroupScanner) Label() string {
 	return "Security Groups"
 }
 
+// IsGlobal implements Scanner interface
+func (s *SecurityGroupScanner) IsGlobal() bool {
+	return false
+}
+
 // Scan implements Scanner interface
 func (s *SecurityGroupScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 	// Get regional session
@@ -43,7 +48,7 @@ func (s *SecurityGroupScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults
 
 	// Get all security groups
 	var securityGroups []*ec2.SecurityGroup
-	err = ec2Client.DescribeSecurityGroupsPages(&ec2.DescribeSecurityGroupsInput{},
+	err = ec2Client.DescribeSecurityGroupsPagesWithContext(opts.Context(), &ec2.DescribeSecurityGroupsInput{},
 		func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
 			securityGroups = append(securityGroups, page.SecurityGroups...)
 			return !lastPage
@@ -80,7 +85,7 @@ func (s *SecurityGroupScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults
 		}
 
 		var associations []*ec2.NetworkInterface
-		err = ec2Client.DescribeNetworkInterfacesPages(input,
+		err = ec2Client.DescribeNetworkInterfacesPagesWithContext(opts.Context(), input,
 			func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
 				associations = append(associations, page.NetworkInterfaces...)
 				return !lastPage
@@ -198,7 +203,9 @@ func (s *SecurityGroupScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults
 				ResourceType: s.Label(),
 				ResourceName: resourceName,
 				ResourceID:   sgID,
+				ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "security-group/"+sgID),
 				Reason:       "Not associated with any resource (EC2 Instance or ENI)",
+				Confidence:   awslib.ConfidenceHigh,
 				Details:      details,
 			}
 
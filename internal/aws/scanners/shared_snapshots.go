@@ -0,0 +1,163 @@
+package scanners
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// SharedSnapshotScanner scans for old EBS snapshots that other accounts, or everyone, can create
+// volumes from
+type SharedSnapshotScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&SharedSnapshotScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *SharedSnapshotScanner) ArgumentName() string {
+	return "shared-snapshots"
+}
+
+// Label implements Scanner interface
+func (s *SharedSnapshotScanner) Label() string {
+	return "Shared Snapshots"
+}
+
+// IsGlobal implements Scanner interface
+func (s *SharedSnapshotScanner) IsGlobal() bool {
+	return false
+}
+
+// getGrantees returns the accounts a snapshot's createVolumePermission grants access to, and
+// whether it is public
+func (s *SharedSnapshotScanner) getGrantees(opts awslib.ScanOptions, client *ec2.EC2, snapshotID string) ([]string, bool, error) {
+	output, err := client.DescribeSnapshotAttributeWithContext(opts.Context(), &ec2.DescribeSnapshotAttributeInput{
+		SnapshotId: aws.String(snapshotID),
+		Attribute:  aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to describe createVolumePermission of snapshot %s: %w", snapshotID, err)
+	}
+
+	var accountIDs []string
+	public := false
+	for _, permission := range output.CreateVolumePermissions {
+		if aws.StringValue(permission.Group) == ec2.PermissionGroupAll {
+			public = true
+		}
+		if userID := aws.StringValue(permission.UserId); userID != "" && userID != opts.AccountID {
+			accountIDs = append(accountIDs, userID)
+		}
+	}
+	sort.Strings(accountIDs)
+	return accountIDs, public, nil
+}
+
+// Scan implements Scanner interface
+func (s *SharedSnapshotScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := ec2.New(sess)
+
+	// Sharing is only visible per snapshot, so only snapshots old enough to report are checked
+	var snapshots []*ec2.Snapshot
+	cutoff := time.Now().Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+	err = client.DescribeSnapshotsPagesWithContext(opts.Context(), &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+	}, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, snapshot := range page.Snapshots {
+			if aws.TimeValue(snapshot.StartTime).Before(cutoff) {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe snapshots", err, nil)
+		return nil, fmt.Errorf("failed to describe snapshots: %w", err)
+	}
+
+	var results awslib.ScanResults
+	snapshotCosts := &EBSSnapshotScanner{}
+
+	for _, snapshot := range snapshots {
+		snapshotID := aws.StringValue(snapshot.SnapshotId)
+
+		accountIDs, public, err := s.getGrantees(opts, client, snapshotID)
+		if err != nil {
+			logging.Error("Failed to check snapshot sharing", err, map[string]interface{}{
+				"snapshot_id": snapshotID,
+			})
+			continue
+		}
+		if !public && len(accountIDs) == 0 {
+			continue
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range snapshot.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		resourceName := aws.StringValue(snapshot.Description)
+		if resourceName == "" {
+			resourceName = snapshotID
+		}
+
+		age := utils.FormatTimeDifference(time.Now(), snapshot.StartTime)
+		var reason string
+		switch {
+		case public:
+			reason = fmt.Sprintf("Snapshot is public, so anyone can create a volume from its data. It is %s old and still billed for storage.", age)
+		case len(accountIDs) == 1:
+			reason = fmt.Sprintf("Snapshot is shared with external account %s, which can create a volume from its data. It is %s old and still billed for storage.", accountIDs[0], age)
+		default:
+			reason = fmt.Sprintf("Snapshot is shared with %d external accounts (%s), which can create volumes from its data. It is %s old and still billed for storage.",
+				len(accountIDs), strings.Join(accountIDs, ", "), age)
+		}
+
+		hoursRunning := time.Since(aws.TimeValue(snapshot.StartTime)).Hours()
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: resourceName,
+			ResourceID:   snapshotID,
+			ARN:          awslib.ResourceARN("ec2", opts.Region, "", "snapshot/"+snapshotID), // Snapshot ARNs have no account ID
+			CreatedAt:    snapshot.StartTime,
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":          opts.AccountID,
+				"region":              opts.Region,
+				"public":              public,
+				"grantee_account_ids": accountIDs,
+				"volume_id":           aws.StringValue(snapshot.VolumeId),
+				"volume_size":         aws.Int64Value(snapshot.VolumeSize),
+				"encrypted":           aws.BoolValue(snapshot.Encrypted),
+				"start_time":          aws.TimeValue(snapshot.StartTime).Format(time.RFC3339),
+				"description":         aws.StringValue(snapshot.Description),
+			},
+			Tags: tags,
+			Cost: map[string]interface{}{
+				"total": snapshotCosts.calculateSnapshotCosts(aws.Int64Value(snapshot.VolumeSize), hoursRunning),
+			},
+		})
+	}
+
+	return results, nil
+}
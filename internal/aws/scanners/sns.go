@@ -0,0 +1,157 @@
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+// SNSTopicScanner scans for SNS topics with no subscribers or no published messages
+type SNSTopicScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&SNSTopicScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *SNSTopicScanner) ArgumentName() string {
+	return "sns-topics"
+}
+
+// Label implements Scanner interface
+func (s *SNSTopicScanner) Label() string {
+	return "SNS Topics"
+}
+
+// IsGlobal implements Scanner interface
+func (s *SNSTopicScanner) IsGlobal() bool {
+	return false
+}
+
+// Scan implements Scanner interface
+func (s *SNSTopicScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	snsClient := sns.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var topicARNs []string
+	err = snsClient.ListTopicsPagesWithContext(opts.Context(), &sns.ListTopicsInput{}, func(page *sns.ListTopicsOutput, lastPage bool) bool {
+		for _, topic := range page.Topics {
+			topicARNs = append(topicARNs, aws.StringValue(topic.TopicArn))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list SNS topics", err, nil)
+		return nil, fmt.Errorf("failed to list SNS topics: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, topicARN := range topicARNs {
+		// The topic name is the last element of its ARN
+		topicName := topicARN[strings.LastIndex(topicARN, ":")+1:]
+
+		attrsOutput, err := snsClient.GetTopicAttributesWithContext(opts.Context(), &sns.GetTopicAttributesInput{
+			TopicArn: aws.String(topicARN),
+		})
+		if err != nil {
+			logging.Error("Failed to get SNS topic attributes", err, map[string]interface{}{
+				"topic_arn": topicARN,
+			})
+			continue
+		}
+		attrs := aws.StringValueMap(attrsOutput.Attributes)
+
+		confirmed := intAttribute(attrs, "SubscriptionsConfirmed")
+		pending := intAttribute(attrs, "SubscriptionsPending")
+
+		published, err := utils.GetResourceMetrics(opts.Context(), cwClient, utils.MetricConfig{
+			Namespace:     "AWS/SNS",
+			ResourceID:    topicName,
+			DimensionName: "TopicName",
+			MetricName:    "NumberOfMessagesPublished",
+			Statistic:     "Sum",
+			StartTime:     startTime,
+			EndTime:       endTime,
+			Period:        86400, // 1 day
+		})
+		if err != nil {
+			logging.Error("Failed to get SNS topic metrics", err, map[string]interface{}{
+				"topic_name": topicName,
+			})
+			continue
+		}
+
+		var reasons []string
+		if confirmed+pending == 0 {
+			reasons = append(reasons, "Topic has no subscriptions")
+		}
+		if published == 0 {
+			reasons = append(reasons, fmt.Sprintf("Topic has not published any messages in the last %d days", opts.DaysUnused))
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		tags := make(map[string]string)
+		tagsOutput, err := snsClient.ListTagsForResourceWithContext(opts.Context(), &sns.ListTagsForResourceInput{
+			ResourceArn: aws.String(topicARN),
+		})
+		if err != nil {
+			logging.Debug("Failed to get SNS topic tags", map[string]interface{}{
+				"topic_name": topicName,
+				"error":      err.Error(),
+			})
+		} else {
+			for _, tag := range tagsOutput.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: topicName,
+			ResourceID:   topicARN,
+			ARN:          topicARN,
+			Reason:       strings.Join(reasons, "; "),
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":               opts.AccountID,
+				"region":                   opts.Region,
+				"display_name":             attrs["DisplayName"],
+				"subscriptions_confirmed":  confirmed,
+				"subscriptions_pending":    pending,
+				"subscriptions_deleted":    intAttribute(attrs, "SubscriptionsDeleted"),
+				"fifo_topic":               attrs["FifoTopic"] == "true",
+				"encrypted":                attrs["KmsMasterKeyId"] != "",
+				"messages_published_daily": published,
+			},
+			Tags: tags,
+			// Unused topics cost nothing; they are reported as clutter rather than for savings
+			Cost: map[string]interface{}{
+				"total": &awslib.CostBreakdown{},
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,206 @@
+package scanners
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// SQSQueueScanner scans for SQS queues that have not sent or received messages
+type SQSQueueScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&SQSQueueScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *SQSQueueScanner) ArgumentName() string {
+	return "sqs-queues"
+}
+
+// Label implements Scanner interface
+func (s *SQSQueueScanner) Label() string {
+	return "SQS Queues"
+}
+
+// IsGlobal implements Scanner interface
+func (s *SQSQueueScanner) IsGlobal() bool {
+	return false
+}
+
+// getQueueMetrics returns the messages sent to and received from a queue over the period
+func (s *SQSQueueScanner) getQueueMetrics(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, queueName string, startTime, endTime time.Time) (map[string]float64, error) {
+	metrics := []utils.MetricConfig{
+		{
+			Namespace:     "AWS/SQS",
+			ResourceID:    queueName,
+			DimensionName: "QueueName",
+			MetricName:    "NumberOfMessagesSent",
+			Statistic:     "Sum",
+			StartTime:     startTime,
+			EndTime:       endTime,
+		},
+		{
+			Namespace:     "AWS/SQS",
+			ResourceID:    queueName,
+			DimensionName: "QueueName",
+			MetricName:    "NumberOfMessagesReceived",
+			Statistic:     "Sum",
+			StartTime:     startTime,
+			EndTime:       endTime,
+		},
+	}
+
+	results, err := utils.GetResourceMetricsData(opts.Context(), cwClient, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	return results, nil
+}
+
+// Scan implements Scanner interface
+func (s *SQSQueueScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	sqsClient := sqs.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var queueURLs []string
+	err = sqsClient.ListQueuesPagesWithContext(opts.Context(), &sqs.ListQueuesInput{}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+		for _, queueURL := range page.QueueUrls {
+			queueURLs = append(queueURLs, aws.StringValue(queueURL))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to list SQS queues", err, nil)
+		return nil, fmt.Errorf("failed to list SQS queues: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, queueURL := range queueURLs {
+		attrsOutput, err := sqsClient.GetQueueAttributesWithContext(opts.Context(), &sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: []*string{aws.String(sqs.QueueAttributeNameAll)},
+		})
+		if err != nil {
+			logging.Error("Failed to get SQS queue attributes", err, map[string]interface{}{
+				"queue_url": queueURL,
+			})
+			continue
+		}
+		attrs := aws.StringValueMap(attrsOutput.Attributes)
+
+		queueARN := attrs[sqs.QueueAttributeNameQueueArn]
+		queueName := queueNameFromURL(queueURL)
+
+		// Skip queues too new to have a full metric window
+		var creationTime time.Time
+		if created := intAttribute(attrs, sqs.QueueAttributeNameCreatedTimestamp); created > 0 {
+			creationTime = time.Unix(created, 0).UTC()
+			if creationTime.After(startTime) {
+				continue
+			}
+		}
+
+		metrics, err := s.getQueueMetrics(opts, cwClient, queueName, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to get SQS queue metrics", err, map[string]interface{}{
+				"queue_name": queueName,
+			})
+			continue
+		}
+		if metrics["NumberOfMessagesSent"] > 0 || metrics["NumberOfMessagesReceived"] > 0 {
+			continue
+		}
+
+		tags := make(map[string]string)
+		tagsOutput, err := sqsClient.ListQueueTagsWithContext(opts.Context(), &sqs.ListQueueTagsInput{
+			QueueUrl: aws.String(queueURL),
+		})
+		if err != nil {
+			logging.Debug("Failed to get SQS queue tags", map[string]interface{}{
+				"queue_name": queueName,
+				"error":      err.Error(),
+			})
+		} else {
+			tags = aws.StringValueMap(tagsOutput.Tags)
+		}
+
+		var hoursRunning float64
+		if !creationTime.IsZero() {
+			hoursRunning = time.Since(creationTime).Hours()
+		}
+
+		details := map[string]interface{}{
+			"account_id":                   opts.AccountID,
+			"region":                       opts.Region,
+			"queue_url":                    queueURL,
+			"fifo_queue":                   attrs[sqs.QueueAttributeNameFifoQueue] == "true",
+			"approximate_messages":         intAttribute(attrs, sqs.QueueAttributeNameApproximateNumberOfMessages),
+			"approximate_messages_delayed": intAttribute(attrs, sqs.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+			"visibility_timeout_seconds":   intAttribute(attrs, sqs.QueueAttributeNameVisibilityTimeout),
+			"message_retention_seconds":    intAttribute(attrs, sqs.QueueAttributeNameMessageRetentionPeriod),
+			"encrypted":                    attrs[sqs.QueueAttributeNameKmsMasterKeyId] != "" || attrs[sqs.QueueAttributeNameSqsManagedSseEnabled] == "true",
+			"has_dead_letter_queue":        attrs[sqs.QueueAttributeNameRedrivePolicy] != "",
+			"hours_running":                hoursRunning,
+		}
+		var createdAt *time.Time
+		if !creationTime.IsZero() {
+			details["creation_time"] = creationTime
+			createdAt = aws.Time(creationTime)
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: queueName,
+			ResourceID:   queueName,
+			ARN:          queueARN,
+			CreatedAt:    createdAt,
+			Reason:       fmt.Sprintf("Queue has not sent or received any messages in the last %d days", opts.DaysUnused),
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+			Tags:         tags,
+			// Idle queues cost nothing; they are reported as clutter rather than for savings
+			Cost: map[string]interface{}{
+				"total": &awslib.CostBreakdown{
+					HoursRunning: aws.Float64(hoursRunning),
+					Lifetime:     aws.Float64(0),
+				},
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// queueNameFromURL returns the queue name, which is the last path element of a queue URL
+func queueNameFromURL(queueURL string) string {
+	return queueURL[strings.LastIndex(queueURL, "/")+1:]
+}
+
+// intAttribute parses a numeric SQS or SNS attribute, returning zero if it is missing
+func intAttribute(attrs map[string]string, name string) int64 {
+	value, _ := strconv.ParseInt(attrs[name], 10, 64)
+	return value
+}
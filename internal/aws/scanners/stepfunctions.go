@@ -0,0 +1,259 @@
+package scanners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/sfn"
+)
+
+// StepFunctionsScanner scans for Step Functions state machines that haven't started any executions
+type StepFunctionsScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&StepFunctionsScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *StepFunctionsScanner) ArgumentName() string {
+	return "stepfunctions"
+}
+
+// Label implements Scanner interface
+func (s *StepFunctionsScanner) Label() string {
+	return "Step Functions State Machines"
+}
+
+// IsGlobal implements Scanner interface
+func (s *StepFunctionsScanner) IsGlobal() bool {
+	return false
+}
+
+// getExecutionsStarted returns the number of executions a state machine started between startTime
+// and endTime. The metric is only published when executions start, so no datapoints means none did.
+func (s *StepFunctionsScanner) getExecutionsStarted(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, stateMachineArn string, startTime, endTime time.Time) (float64, error) {
+	output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/States"),
+		MetricName: aws.String("ExecutionsStarted"),
+		Dimensions: []*cloudwatch.Dimension{
+			{
+				Name:  aws.String("StateMachineArn"),
+				Value: aws.String(stateMachineArn),
+			},
+		},
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int64(86400), // 1 day
+		Statistics: []*string{aws.String("Sum")},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch ExecutionsStarted metric: %w", err)
+	}
+
+	var started float64
+	for _, dp := range output.Datapoints {
+		started += aws.Float64Value(dp.Sum)
+	}
+	return started, nil
+}
+
+// getTriggerRules returns the names of the EventBridge rules on the default event bus that target
+// a state machine
+func (s *StepFunctionsScanner) getTriggerRules(opts awslib.ScanOptions, client *eventbridge.EventBridge, stateMachineArn string) ([]string, error) {
+	ruleNames := []string{}
+	input := &eventbridge.ListRuleNamesByTargetInput{
+		TargetArn: aws.String(stateMachineArn),
+	}
+	for {
+		output, err := client.ListRuleNamesByTargetWithContext(opts.Context(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list EventBridge rules: %w", err)
+		}
+		ruleNames = append(ruleNames, aws.StringValueSlice(output.RuleNames)...)
+		if output.NextToken == nil {
+			return ruleNames, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// getAlarmsByStateMachine returns the names of the CloudWatch alarms on each state machine's metrics,
+// keyed by state machine ARN
+func (s *StepFunctionsScanner) getAlarmsByStateMachine(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch) (map[string][]string, error) {
+	alarms := make(map[string][]string)
+	err := cwClient.DescribeAlarmsPagesWithContext(opts.Context(), &cloudwatch.DescribeAlarmsInput{},
+		func(page *cloudwatch.DescribeAlarmsOutput, lastPage bool) bool {
+			for _, alarm := range page.MetricAlarms {
+				if aws.StringValue(alarm.Namespace) != "AWS/States" {
+					continue
+				}
+				for _, dimension := range alarm.Dimensions {
+					if aws.StringValue(dimension.Name) == "StateMachineArn" {
+						arn := aws.StringValue(dimension.Value)
+						alarms[arn] = append(alarms[arn], aws.StringValue(alarm.AlarmName))
+					}
+				}
+			}
+			return !lastPage
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe CloudWatch alarms: %w", err)
+	}
+	return alarms, nil
+}
+
+// getTags returns a state machine's tags
+func (s *StepFunctionsScanner) getTags(opts awslib.ScanOptions, client *sfn.SFN, stateMachineArn string) map[string]string {
+	tags := make(map[string]string)
+	output, err := client.ListTagsForResourceWithContext(opts.Context(), &sfn.ListTagsForResourceInput{
+		ResourceArn: aws.String(stateMachineArn),
+	})
+	if err != nil {
+		logging.Debug("Failed to list Step Functions state machine tags", map[string]interface{}{
+			"state_machine_arn": stateMachineArn,
+			"error":             err.Error(),
+		})
+		return tags
+	}
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags
+}
+
+// Scan implements Scanner interface
+func (s *StepFunctionsScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	client := sfn.New(sess)
+	cwClient := cloudwatch.New(sess)
+	eventsClient := eventbridge.New(sess)
+
+	var stateMachines []*sfn.StateMachineListItem
+	err = client.ListStateMachinesPagesWithContext(opts.Context(), &sfn.ListStateMachinesInput{},
+		func(page *sfn.ListStateMachinesOutput, lastPage bool) bool {
+			stateMachines = append(stateMachines, page.StateMachines...)
+			return !lastPage
+		})
+	if err != nil {
+		logging.Error("Failed to list Step Functions state machines", err, nil)
+		return nil, fmt.Errorf("failed to list Step Functions state machines: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	// Alarms are looked up once for the region, and only when there is something to report
+	var alarms map[string][]string
+	alarmsChecked := false
+
+	for _, stateMachine := range stateMachines {
+		name := aws.StringValue(stateMachine.Name)
+		stateMachineArn := aws.StringValue(stateMachine.StateMachineArn)
+		creationDate := aws.TimeValue(stateMachine.CreationDate)
+
+		// New state machines don't have a full metric window
+		if creationDate.After(startTime) {
+			continue
+		}
+
+		started, err := s.getExecutionsStarted(opts, cwClient, stateMachineArn, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze Step Functions state machine executions", err, map[string]interface{}{
+				"state_machine_name": name,
+			})
+			continue
+		}
+		if started > 0 {
+			continue
+		}
+
+		triggerRules, err := s.getTriggerRules(opts, eventsClient, stateMachineArn)
+		if err != nil {
+			logging.Debug("Failed to get Step Functions trigger rules", map[string]interface{}{
+				"state_machine_name": name,
+				"error":              err.Error(),
+			})
+			triggerRules = []string{}
+		}
+
+		if !alarmsChecked {
+			alarmsChecked = true
+			alarms, err = s.getAlarmsByStateMachine(opts, cwClient)
+			if err != nil {
+				logging.Debug("Failed to get Step Functions alarms", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+		alarmNames := alarms[stateMachineArn]
+		if alarmNames == nil {
+			alarmNames = []string{}
+		}
+
+		reason := fmt.Sprintf("State machine has not started any executions in the last %d days", opts.DaysUnused)
+		if len(triggerRules) > 0 || len(alarmNames) > 0 {
+			reason += fmt.Sprintf("; %d EventBridge rules and %d CloudWatch alarms still reference it", len(triggerRules), len(alarmNames))
+		}
+
+		details := map[string]interface{}{
+			"account_id":        opts.AccountID,
+			"region":            opts.Region,
+			"state_machine_arn": stateMachineArn,
+			"type":              strings.ToLower(aws.StringValue(stateMachine.Type)), // standard or express
+			"trigger_rules":     triggerRules,
+			"alarm_names":       alarmNames,
+			"executions":        started,
+			"creation_time":     creationDate,
+		}
+
+		// Only standard workflows keep an execution history to look up
+		if aws.StringValue(stateMachine.Type) == sfn.StateMachineTypeStandard {
+			executions, err := client.ListExecutionsWithContext(opts.Context(), &sfn.ListExecutionsInput{
+				StateMachineArn: aws.String(stateMachineArn),
+				MaxResults:      aws.Int64(1),
+			})
+			if err != nil {
+				logging.Debug("Failed to list Step Functions executions", map[string]interface{}{
+					"state_machine_name": name,
+					"error":              err.Error(),
+				})
+			} else if len(executions.Executions) > 0 {
+				details["last_execution_at"] = aws.TimeValue(executions.Executions[0].StartDate).Format(time.RFC3339)
+			}
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: name,
+			ResourceID:   stateMachineArn,
+			ARN:          stateMachineArn,
+			CreatedAt:    aws.Time(creationDate),
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details:      details,
+			Tags:         s.getTags(opts, client, stateMachineArn),
+			// Idle state machines cost nothing; they are reported as clutter rather than for savings
+			Cost: map[string]interface{}{
+				"total": &awslib.CostBreakdown{},
+			},
+		})
+	}
+
+	return results, nil
+}
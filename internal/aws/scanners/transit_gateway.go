@@ -0,0 +1,263 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/aws/utils"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// TransitGatewayScanner scans for Transit Gateways with little or no attachment traffic
+type TransitGatewayScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&TransitGatewayScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *TransitGatewayScanner) ArgumentName() string {
+	return "transit-gateways"
+}
+
+// Label implements Scanner interface
+func (s *TransitGatewayScanner) Label() string {
+	return "Transit Gateways"
+}
+
+// IsGlobal implements Scanner interface
+func (s *TransitGatewayScanner) IsGlobal() bool {
+	return false
+}
+
+// fetchMetric fetches a CloudWatch metric for a Transit Gateway
+func (s *TransitGatewayScanner) fetchMetric(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, transitGatewayID string, metricName string, startTime, endTime time.Time) (float64, error) {
+	config := utils.MetricConfig{
+		Namespace:     "AWS/TransitGateway",
+		ResourceID:    transitGatewayID,
+		DimensionName: "TransitGateway",
+		MetricName:    metricName,
+		Statistic:     "Sum",
+		StartTime:     startTime,
+		EndTime:       endTime,
+		Period:        86400, // 1 day
+	}
+
+	return utils.GetResourceMetrics(opts.Context(), cwClient, config)
+}
+
+// analyzeTransitGatewayUsage analyzes the traffic flowing through a Transit Gateway's attachments
+func (s *TransitGatewayScanner) analyzeTransitGatewayUsage(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, transitGatewayID string, daysUnused int) (bool, string, error) {
+	// Calculate time range for metrics
+	endTime := time.Now()
+	startTime := endTime.Add(-time.Duration(daysUnused) * 24 * time.Hour)
+
+	bytesIn, err := s.fetchMetric(opts, cwClient, transitGatewayID, "BytesIn", startTime, endTime)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to fetch BytesIn metric: %w", err)
+	}
+
+	bytesOut, err := s.fetchMetric(opts, cwClient, transitGatewayID, "BytesOut", startTime, endTime)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to fetch BytesOut metric: %w", err)
+	}
+
+	totalBytes := bytesIn + bytesOut
+
+	if totalBytes == 0 {
+		return true, fmt.Sprintf("Transit Gateway attachments have no traffic in the last %d days", daysUnused), nil
+	}
+
+	// Check for very low traffic (less than 1 MB per day)
+	if totalBytes < 1024*1024 {
+		return true, fmt.Sprintf("Transit Gateway attachments have minimal traffic (%.2f MB/day) in the last %d days", totalBytes/(1024*1024), daysUnused), nil
+	}
+
+	// Not considered unused
+	return false, "", nil
+}
+
+// listAttachments returns the Transit Gateway's attachments, excluding those being deleted
+func (s *TransitGatewayScanner) listAttachments(ec2Client *ec2.EC2, opts awslib.ScanOptions, transitGatewayID string) ([]*ec2.TransitGatewayAttachment, error) {
+	var attachments []*ec2.TransitGatewayAttachment
+	input := &ec2.DescribeTransitGatewayAttachmentsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("transit-gateway-id"),
+				Values: []*string{aws.String(transitGatewayID)},
+			},
+		},
+	}
+
+	err := ec2Client.DescribeTransitGatewayAttachmentsPagesWithContext(opts.Context(), input, func(page *ec2.DescribeTransitGatewayAttachmentsOutput, lastPage bool) bool {
+		for _, attachment := range page.TransitGatewayAttachments {
+			state := aws.StringValue(attachment.State)
+			if state == ec2.TransitGatewayAttachmentStateDeleting || state == ec2.TransitGatewayAttachmentStateDeleted {
+				continue
+			}
+			attachments = append(attachments, attachment)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Transit Gateway attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// calculateTransitGatewayCost calculates the cost of a Transit Gateway from its attachment count
+func (s *TransitGatewayScanner) calculateTransitGatewayCost(transitGateway *ec2.TransitGateway, attachmentCount int, region string) (*awslib.CostBreakdown, error) {
+	creationTime := aws.TimeValue(transitGateway.CreationTime)
+
+	// Transit Gateways are billed per attachment-hour
+	config := awslib.ResourceCostConfig{
+		ResourceType:  "TransitGateway",
+		Region:        region,
+		CreationTime:  creationTime,
+		InstanceCount: int64(attachmentCount),
+	}
+
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(config)
+		if err == nil && (costBreakdown.HourlyRate != 0 || attachmentCount == 0) {
+			return costBreakdown, nil
+		}
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable, fails or returns zero
+	hoursRunning := time.Since(creationTime).Hours()
+	hourlyRate := 0.05 * float64(attachmentCount) // Default hourly rate per attachment as fallback
+	lifetime := hourlyRate * hoursRunning
+
+	return &awslib.CostBreakdown{
+		HourlyRate:   hourlyRate,
+		DailyRate:    hourlyRate * 24,
+		MonthlyRate:  hourlyRate * 24 * 30,
+		YearlyRate:   hourlyRate * 24 * 365,
+		HoursRunning: aws.Float64(hoursRunning),
+		Lifetime:     aws.Float64(lifetime),
+	}, nil
+}
+
+// Scan implements Scanner interface
+func (s *TransitGatewayScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	// Create EC2 and CloudWatch service clients
+	ec2Client := ec2.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	var transitGateways []*ec2.TransitGateway
+	err = ec2Client.DescribeTransitGatewaysPagesWithContext(opts.Context(), &ec2.DescribeTransitGatewaysInput{}, func(page *ec2.DescribeTransitGatewaysOutput, lastPage bool) bool {
+		transitGateways = append(transitGateways, page.TransitGateways...)
+		return !lastPage
+	})
+	if err != nil {
+		logging.Error("Failed to describe Transit Gateways", err, nil)
+		return nil, fmt.Errorf("failed to describe Transit Gateways: %w", err)
+	}
+
+	var results awslib.ScanResults
+
+	// Get days unused from options, default to 30 if not specified
+	daysUnused := utils.Max(opts.DaysUnused, 30)
+
+	for _, transitGateway := range transitGateways {
+		transitGatewayID := aws.StringValue(transitGateway.TransitGatewayId)
+
+		// Skip Transit Gateways that are not in 'available' state
+		if aws.StringValue(transitGateway.State) != ec2.TransitGatewayStateAvailable {
+			logging.Debug("Skipping Transit Gateway not in 'available' state", map[string]interface{}{
+				"transit_gateway_id": transitGatewayID,
+				"state":              aws.StringValue(transitGateway.State),
+			})
+			continue
+		}
+
+		isUnused, reason, err := s.analyzeTransitGatewayUsage(opts, cwClient, transitGatewayID, daysUnused)
+		if err != nil {
+			logging.Error("Failed to analyze Transit Gateway usage", err, map[string]interface{}{
+				"transit_gateway_id": transitGatewayID,
+			})
+			continue
+		}
+		if !isUnused {
+			continue
+		}
+
+		attachments, err := s.listAttachments(ec2Client, opts, transitGatewayID)
+		if err != nil {
+			logging.Error("Failed to list Transit Gateway attachments", err, map[string]interface{}{
+				"transit_gateway_id": transitGatewayID,
+			})
+			continue
+		}
+
+		// Count attachments by resource type (vpc, vpn, peering, ...)
+		attachmentTypes := make(map[string]int)
+		for _, attachment := range attachments {
+			attachmentTypes[aws.StringValue(attachment.ResourceType)]++
+		}
+
+		cost, err := s.calculateTransitGatewayCost(transitGateway, len(attachments), opts.Region)
+		if err != nil {
+			logging.Error("Failed to calculate Transit Gateway cost", err, map[string]interface{}{
+				"transit_gateway_id": transitGatewayID,
+			})
+			continue
+		}
+
+		// Extract all tags
+		tags := make(map[string]string)
+		for _, tag := range transitGateway.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+
+		transitGatewayName := tags["Name"]
+		if transitGatewayName == "" {
+			transitGatewayName = transitGatewayID
+		}
+
+		creationTime := aws.TimeValue(transitGateway.CreationTime)
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: transitGatewayName,
+			ResourceID:   transitGatewayID,
+			ARN:          aws.StringValue(transitGateway.TransitGatewayArn),
+			CreatedAt:    aws.Time(creationTime),
+			Reason:       reason,
+			Confidence:   awslib.ConfidenceMedium,
+			Details: map[string]interface{}{
+				"account_id":          opts.AccountID,
+				"region":              opts.Region,
+				"state":               aws.StringValue(transitGateway.State),
+				"attachment_count":    len(attachments),
+				"attachment_types":    attachmentTypes,
+				"creation_time":       creationTime,
+				"hours_running":       time.Since(creationTime).Hours(),
+				"days_unused":         daysUnused,
+				"transit_gateway_arn": aws.StringValue(transitGateway.TransitGatewayArn),
+			},
+			Tags: tags,
+			Cost: map[string]interface{}{
+				"total": cost,
+			},
+		})
+	}
+
+	return results, nil
+}
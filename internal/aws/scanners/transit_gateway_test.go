@@ -0,0 +1,52 @@
+package scanners
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	awslib "cloudsift/internal/aws"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListAttachments tests that attachments being deleted aren't counted, across pages
+func TestListAttachments(t *testing.T) {
+	pages := map[string]string{
+		"": `<transitGatewayAttachments>
+				<item><transitGatewayAttachmentId>tgw-attach-vpc</transitGatewayAttachmentId><state>available</state></item>
+				<item><transitGatewayAttachmentId>tgw-attach-deleting</transitGatewayAttachmentId><state>deleting</state></item>
+			</transitGatewayAttachments>
+			<nextToken>page-2</nextToken>`,
+		"page-2": `<transitGatewayAttachments>
+				<item><transitGatewayAttachmentId>tgw-attach-deleted</transitGatewayAttachmentId><state>deleted</state></item>
+				<item><transitGatewayAttachmentId>tgw-attach-peering</transitGatewayAttachmentId><state>pendingAcceptance</state></item>
+			</transitGatewayAttachments>`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "DescribeTransitGatewayAttachments", r.Form.Get("Action"))
+		assert.Equal(t, "transit-gateway-id", r.Form.Get("Filter.1.Name"))
+		assert.Equal(t, "tgw-1", r.Form.Get("Filter.1.Value.1"))
+
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<DescribeTransitGatewayAttachmentsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+			<requestId>request-1</requestId>%s</DescribeTransitGatewayAttachmentsResponse>`, pages[r.Form.Get("NextToken")])
+	}))
+	defer server.Close()
+
+	scanner := &TransitGatewayScanner{}
+	attachments, err := scanner.listAttachments(ec2.New(newTestSession(t, server)), awslib.ScanOptions{Region: "us-east-1"}, "tgw-1")
+	require.NoError(t, err)
+
+	var ids []string
+	for _, attachment := range attachments {
+		ids = append(ids, aws.StringValue(attachment.TransitGatewayAttachmentId))
+	}
+	assert.Equal(t, []string{"tgw-attach-vpc", "tgw-attach-peering"}, ids)
+}
@@ -1,6 +1,7 @@
 package scanners
 
 import (
+	"context"
 	"fmt"
 
 	awslib "cloudsift/internal/aws"
@@ -27,8 +28,13 @@ func (s *VPCScanner) Label() string {
 	return "VPCs"
 }
 
+// IsGlobal implements Scanner interface
+func (s *VPCScanner) IsGlobal() bool {
+	return false
+}
+
 // countEC2Instances counts the number of EC2 instances in a VPC
-func (s *VPCScanner) countEC2Instances(ec2Client *ec2.EC2, vpcID string) (int, error) {
+func (s *VPCScanner) countEC2Instances(ctx context.Context, ec2Client *ec2.EC2, vpcID string) (int, error) {
 	input := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			{
@@ -39,7 +45,7 @@ func (s *VPCScanner) countEC2Instances(ec2Client *ec2.EC2, vpcID string) (int, e
 	}
 
 	var instanceCount int
-	err := ec2Client.DescribeInstancesPages(input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+	err := ec2Client.DescribeInstancesPagesWithContext(ctx, input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
 		for _, reservation := range page.Reservations {
 			instanceCount += len(reservation.Instances)
 		}
@@ -54,7 +60,7 @@ func (s *VPCScanner) countEC2Instances(ec2Client *ec2.EC2, vpcID string) (int, e
 }
 
 // countENIs counts the number of ENIs in a VPC
-func (s *VPCScanner) countENIs(ec2Client *ec2.EC2, vpcID string) (int, error) {
+func (s *VPCScanner) countENIs(ctx context.Context, ec2Client *ec2.EC2, vpcID string) (int, error) {
 	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: []*ec2.Filter{
 			{
@@ -65,7 +71,7 @@ func (s *VPCScanner) countENIs(ec2Client *ec2.EC2, vpcID string) (int, error) {
 	}
 
 	eniCount := 0
-	err := ec2Client.DescribeNetworkInterfacesPages(input, func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+	err := ec2Client.DescribeNetworkInterfacesPagesWithContext(ctx, input, func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
 		eniCount += len(page.NetworkInterfaces)
 		return !lastPage
 	})
@@ -78,13 +84,13 @@ func (s *VPCScanner) countENIs(ec2Client *ec2.EC2, vpcID string) (int, error) {
 }
 
 // getVPCResourceCount counts the number of resources in a VPC
-func (s *VPCScanner) getVPCResourceCount(ec2Client *ec2.EC2, vpcID string) (int, error) {
-	instanceCount, err := s.countEC2Instances(ec2Client, vpcID)
+func (s *VPCScanner) getVPCResourceCount(ctx context.Context, ec2Client *ec2.EC2, vpcID string) (int, error) {
+	instanceCount, err := s.countEC2Instances(ctx, ec2Client, vpcID)
 	if err != nil {
 		return 0, err
 	}
 
-	eniCount, err := s.countENIs(ec2Client, vpcID)
+	eniCount, err := s.countENIs(ctx, ec2Client, vpcID)
 	if err != nil {
 		return 0, err
 	}
@@ -109,7 +115,7 @@ func (s *VPCScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 
 	// Describe VPCs
 	input := &ec2.DescribeVpcsInput{}
-	vpcs, err := ec2Client.DescribeVpcs(input)
+	vpcs, err := ec2Client.DescribeVpcsWithContext(opts.Context(), input)
 	if err != nil {
 		logging.Error("Failed to describe VPCs", err, nil)
 		return nil, fmt.Errorf("failed to describe VPCs: %w", err)
@@ -143,7 +149,7 @@ func (s *VPCScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 		}
 
 		// Count resources in VPC
-		resourceCount, err := s.getVPCResourceCount(ec2Client, vpcID)
+		resourceCount, err := s.getVPCResourceCount(opts.Context(), ec2Client, vpcID)
 		if err != nil {
 			logging.Error("Failed to get VPC resource count", err, map[string]interface{}{
 				"vpc_id": vpcID,
@@ -164,7 +170,9 @@ func (s *VPCScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
 				ResourceType: s.Label(),
 				ResourceName: vpcName,
 				ResourceID:   vpcID,
+				ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "vpc/"+vpcID),
 				Reason:       "VPC has no EC2 Instances or ENIs",
+				Confidence:   awslib.ConfidenceHigh,
 				Details: map[string]interface{}{
 					"account_id":     opts.AccountID,
 					"region":         opts.Region,
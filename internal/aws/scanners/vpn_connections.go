@@ -0,0 +1,207 @@
+package scanners
+
+import (
+	"fmt"
+	"time"
+
+	awslib "cloudsift/internal/aws"
+	"cloudsift/internal/logging"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// VPNConnectionScanner scans for Site-to-Site VPN connections whose tunnels are down or carry no
+// traffic
+type VPNConnectionScanner struct{}
+
+func init() {
+	awslib.DefaultRegistry.RegisterScanner(&VPNConnectionScanner{})
+}
+
+// ArgumentName implements Scanner interface
+func (s *VPNConnectionScanner) ArgumentName() string {
+	return "vpn-connections"
+}
+
+// Label implements Scanner interface
+func (s *VPNConnectionScanner) Label() string {
+	return "VPN Connections"
+}
+
+// IsGlobal implements Scanner interface
+func (s *VPNConnectionScanner) IsGlobal() bool {
+	return false
+}
+
+// getTunnelTraffic returns the bytes a VPN connection's tunnels sent and received between
+// startTime and endTime
+func (s *VPNConnectionScanner) getTunnelTraffic(opts awslib.ScanOptions, cwClient *cloudwatch.CloudWatch, vpnConnectionID string, startTime, endTime time.Time) (float64, error) {
+	var total float64
+	for _, metricName := range []string{"TunnelDataIn", "TunnelDataOut"} {
+		output, err := awslib.GetMetricStatistics(opts.Context(), cwClient, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/VPN"),
+			MetricName: aws.String(metricName),
+			Dimensions: []*cloudwatch.Dimension{
+				{
+					Name:  aws.String("VpnId"),
+					Value: aws.String(vpnConnectionID),
+				},
+			},
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int64(86400), // 1 day
+			Statistics: []*string{aws.String("Sum")},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch %s metric: %w", metricName, err)
+		}
+		for _, dp := range output.Datapoints {
+			total += aws.Float64Value(dp.Sum)
+		}
+	}
+	return total, nil
+}
+
+// tunnelsDownSince returns when the last of a connection's tunnels went down, or false if any
+// tunnel is up
+func (s *VPNConnectionScanner) tunnelsDownSince(connection *ec2.VpnConnection) (time.Time, bool) {
+	var downSince time.Time
+	for _, tunnel := range connection.VgwTelemetry {
+		if aws.StringValue(tunnel.Status) != ec2.TelemetryStatusDown {
+			return time.Time{}, false
+		}
+		if changed := aws.TimeValue(tunnel.LastStatusChange); changed.After(downSince) {
+			downSince = changed
+		}
+	}
+	return downSince, len(connection.VgwTelemetry) > 0
+}
+
+// calculateVPNConnectionCost estimates the hourly cost of a VPN connection. Connections don't
+// report when they were created, so no lifetime cost is estimated.
+func (s *VPNConnectionScanner) calculateVPNConnectionCost(region string) *awslib.CostBreakdown {
+	if awslib.DefaultCostEstimator != nil {
+		costBreakdown, err := awslib.DefaultCostEstimator.CalculateCost(awslib.ResourceCostConfig{
+			ResourceType: "VPNConnection",
+			Region:       region,
+			CreationTime: time.Now(),
+		})
+		if err == nil && costBreakdown.HourlyRate != 0 {
+			return costBreakdown
+		}
+	}
+
+	// Fallback to default pricing if cost estimator is unavailable, fails or returns zero
+	hourlyRate := 0.05 // Default hourly rate per connection as fallback
+	return &awslib.CostBreakdown{
+		HourlyRate:  hourlyRate,
+		DailyRate:   hourlyRate * 24,
+		MonthlyRate: hourlyRate * 24 * 30,
+		YearlyRate:  hourlyRate * 24 * 365,
+	}
+}
+
+// Scan implements Scanner interface
+func (s *VPNConnectionScanner) Scan(opts awslib.ScanOptions) (awslib.ScanResults, error) {
+	// Get regional session
+	sess, err := awslib.GetSessionInRegion(opts.Session, opts.Region)
+	if err != nil {
+		logging.Error("Failed to create regional session", err, map[string]interface{}{
+			"region": opts.Region,
+		})
+		return nil, fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	ec2Client := ec2.New(sess)
+	cwClient := cloudwatch.New(sess)
+
+	output, err := ec2Client.DescribeVpnConnectionsWithContext(opts.Context(), &ec2.DescribeVpnConnectionsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: []*string{aws.String(ec2.VpnStateAvailable)},
+			},
+		},
+	})
+	if err != nil {
+		logging.Error("Failed to describe VPN connections", err, nil)
+		return nil, fmt.Errorf("failed to describe VPN connections: %w", err)
+	}
+
+	var results awslib.ScanResults
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Duration(opts.DaysUnused) * 24 * time.Hour)
+
+	for _, connection := range output.VpnConnections {
+		vpnConnectionID := aws.StringValue(connection.VpnConnectionId)
+
+		traffic, err := s.getTunnelTraffic(opts, cwClient, vpnConnectionID, startTime, endTime)
+		if err != nil {
+			logging.Error("Failed to analyze VPN connection usage", err, map[string]interface{}{
+				"vpn_connection_id": vpnConnectionID,
+			})
+			continue
+		}
+		// Tunnels that carried any traffic are in use, whatever their current status
+		if traffic > 0 {
+			continue
+		}
+
+		tunnelStatus := make(map[string]string)
+		for _, tunnel := range connection.VgwTelemetry {
+			tunnelStatus[aws.StringValue(tunnel.OutsideIpAddress)] = aws.StringValue(tunnel.Status)
+		}
+
+		reason := fmt.Sprintf("VPN connection tunnels carried no traffic in the last %d days", opts.DaysUnused)
+		confidence := awslib.ConfidenceMedium
+		downSince, down := s.tunnelsDownSince(connection)
+		if down {
+			confidence = awslib.ConfidenceHigh
+			reason = fmt.Sprintf("VPN connection tunnels have been DOWN since %s and carried no traffic in the last %d days",
+				downSince.Format("2006-01-02"), opts.DaysUnused)
+		}
+
+		tags := make(map[string]string)
+		for _, tag := range connection.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		connectionName := tags["Name"]
+		if connectionName == "" {
+			connectionName = vpnConnectionID
+		}
+
+		details := map[string]interface{}{
+			"account_id":          opts.AccountID,
+			"region":              opts.Region,
+			"customer_gateway_id": aws.StringValue(connection.CustomerGatewayId),
+			"vpn_gateway_id":      aws.StringValue(connection.VpnGatewayId),
+			"transit_gateway_id":  aws.StringValue(connection.TransitGatewayId),
+			"type":                aws.StringValue(connection.Type),
+			"category":            aws.StringValue(connection.Category),
+			"tunnel_status":       tunnelStatus,
+			"tunnels_down":        down,
+			"days_unused":         opts.DaysUnused,
+		}
+		if down {
+			details["down_since"] = downSince.Format(time.RFC3339)
+		}
+
+		results = append(results, awslib.ScanResult{
+			ResourceType: s.Label(),
+			ResourceName: connectionName,
+			ResourceID:   vpnConnectionID,
+			ARN:          awslib.ResourceARN("ec2", opts.Region, opts.AccountID, "vpn-connection/"+vpnConnectionID),
+			Reason:       reason,
+			Confidence:   confidence,
+			Details:      details,
+			Tags:         tags,
+			Cost: map[string]interface{}{
+				"total": s.calculateVPNConnectionCost(opts.Region),
+			},
+		})
+	}
+
+	return results, nil
+}
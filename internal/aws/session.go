@@ -2,10 +2,19 @@ package aws
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sts"
 
@@ -15,6 +24,61 @@ import (
 	"cloudsift/internal/config"
 )
 
+const (
+	// DefaultSessionDuration is how long assumed-role sessions last when --session-duration isn't set
+	DefaultSessionDuration = time.Hour
+
+	// maxChainedSessionDuration is the longest session STS grants a role assumed with credentials
+	// that are themselves from an assumed role
+	maxChainedSessionDuration = time.Hour
+
+	// sessionExpiryWindow is how long before expiry assumed-role credentials are refreshed, so
+	// requests in flight or being retried are never signed with credentials about to expire
+	sessionExpiryWindow = 5 * time.Minute
+)
+
+// SessionDuration returns the configured assumed-role session duration. chained is true when the
+// role is assumed with credentials that are themselves from an assumed role, which STS limits to
+// one-hour sessions.
+func SessionDuration(chained bool) time.Duration {
+	duration := DefaultSessionDuration
+	if config.Config.ScanSessionDuration > 0 {
+		duration = config.Config.ScanSessionDuration
+	}
+	if chained && duration > maxChainedSessionDuration {
+		duration = maxChainedSessionDuration
+	}
+	return duration
+}
+
+// AssumeRoleCredentials returns credentials for roleARN that last the configured session duration
+// and are refreshed automatically shortly before they expire
+func AssumeRoleCredentials(sess client.ConfigProvider, roleARN string, chained bool) *credentials.Credentials {
+	return stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.Duration = SessionDuration(chained)
+		p.ExpiryWindow = sessionExpiryWindow
+	})
+}
+
+// throttleHandler holds the func() called when a request made through a regional session is throttled
+var throttleHandler atomic.Value
+
+// SetThrottleHandler registers a function that is called every time an AWS request made through a
+// session from GetSessionInRegion is throttled, so callers can reduce their request rate
+func SetThrottleHandler(handler func()) {
+	throttleHandler.Store(handler)
+}
+
+// reportThrottle calls the registered throttle handler when a request attempt was throttled
+func reportThrottle(r *request.Request) {
+	if !r.IsErrorThrottle() {
+		return
+	}
+	if handler, ok := throttleHandler.Load().(func()); ok && handler != nil {
+		handler()
+	}
+}
+
 // GetSession creates a new AWS session with optional region and role
 // Deprecated: Use GetSessionChain + GetSessionInRegion instead
 func GetSession(role string, region ...string) (*session.Session, error) {
@@ -23,12 +87,22 @@ func GetSession(role string, region ...string) (*session.Session, error) {
 		cfg = cfg.WithRegion(region[0])
 	}
 
-	// Create base session
-	sess, err := session.NewSession(cfg)
+	// Create base session from the configured profile, which may be an SSO profile
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		Profile:           ResolveProfile(config.Config.Profile),
+		SharedConfigState: session.SharedConfigEnable,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AWS session: %w", err)
 	}
 
+	// Traverse any intermediate accounts first
+	sess, err = AssumeRoleChain(sess, config.Config.AssumeRoleChain)
+	if err != nil {
+		return nil, err
+	}
+
 	// If no role specified, return base session
 	if role == "" {
 		return sess, nil
@@ -41,16 +115,16 @@ func GetSession(role string, region ...string) (*session.Session, error) {
 		return nil, fmt.Errorf("failed to get caller identity: %w", err)
 	}
 
-	// Construct role ARN
-	roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", *identity.Account, role)
+	// Construct role ARN in the caller's partition
+	roleARN := RoleARN(PartitionFromARN(aws.StringValue(identity.Arn)), *identity.Account, role)
 
 	// Create new session with assumed role
-	creds := stscreds.NewCredentials(sess, roleARN)
+	creds := AssumeRoleCredentials(sess, roleARN, len(config.Config.AssumeRoleChain) > 0)
 	return session.NewSession(cfg.WithCredentials(creds))
 }
 
 // GetSessionChain creates a new AWS session with proper role assumption chain:
-// Base Profile -> Organization Role (optional) -> Scanner Role (optional, in target account)
+// Base Profile -> Role Chain (optional) -> Organization Role (optional) -> Scanner Role (optional, in target account)
 func GetSessionChain(organizationRole, scannerRole string, targetAccountID string, region string) (*session.Session, error) {
 	logging.Debug("Creating AWS session chain", map[string]interface{}{
 		"organization_role": organizationRole,
@@ -77,6 +151,23 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 	})
 
 	currentSession := baseSession
+	currentAccountID := *baseIdentity.Account
+
+	// Roles are always assumed within the base identity's partition (aws, aws-cn or aws-us-gov)
+	partition := PartitionFromARN(*baseIdentity.Arn)
+
+	// Traverse any intermediate accounts, such as a security tooling account, before the organization role
+	if len(config.Config.AssumeRoleChain) > 0 {
+		currentSession, err = AssumeRoleChain(currentSession, config.Config.AssumeRoleChain)
+		if err != nil {
+			return nil, err
+		}
+		chainIdentity, err := sts.New(currentSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get role chain identity: %w", err)
+		}
+		currentAccountID = *chainIdentity.Account
+	}
 
 	// Assume organization role if provided
 	if organizationRole != "" {
@@ -84,8 +175,8 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 			"role": organizationRole,
 		})
 
-		orgRoleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", *baseIdentity.Account, organizationRole)
-		orgCreds := stscreds.NewCredentials(currentSession, orgRoleARN)
+		orgRoleARN := RoleARN(partition, currentAccountID, organizationRole)
+		orgCreds := AssumeRoleCredentials(currentSession, orgRoleARN, len(config.Config.AssumeRoleChain) > 0)
 		orgSession, err := session.NewSession(aws.NewConfig().WithCredentials(orgCreds))
 		if err != nil {
 			return nil, fmt.Errorf("failed to assume organization role %s: %w", organizationRole, err)
@@ -106,6 +197,9 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 
 	// Assume scanner role if provided
 	if scannerRole != "" {
+		// The scanner role is assumed from another role's credentials unless it is the first hop
+		chained := organizationRole != "" || len(config.Config.AssumeRoleChain) > 0
+
 		// If target account specified, assume scanner role directly in that account
 		if targetAccountID != "" {
 			logging.Debug("Attempting to assume scanner role in target account", map[string]interface{}{
@@ -113,8 +207,8 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 				"target_account": targetAccountID,
 			})
 
-			scannerRoleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", targetAccountID, scannerRole)
-			scannerCreds := stscreds.NewCredentials(currentSession, scannerRoleARN)
+			scannerRoleARN := RoleARN(partition, targetAccountID, scannerRole)
+			scannerCreds := AssumeRoleCredentials(currentSession, scannerRoleARN, chained)
 			scannerSession, err := session.NewSession(aws.NewConfig().WithCredentials(scannerCreds))
 			if err != nil {
 				return nil, fmt.Errorf("failed to assume scanner role %s in account %s: %w", scannerRole, targetAccountID, err)
@@ -140,8 +234,8 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 				return nil, fmt.Errorf("failed to get identity for scanner role assumption: %w", err)
 			}
 
-			scannerRoleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", *identity.Account, scannerRole)
-			scannerCreds := stscreds.NewCredentials(currentSession, scannerRoleARN)
+			scannerRoleARN := RoleARN(partition, *identity.Account, scannerRole)
+			scannerCreds := AssumeRoleCredentials(currentSession, scannerRoleARN, chained)
 			scannerSession, err := session.NewSession(aws.NewConfig().WithCredentials(scannerCreds))
 			if err != nil {
 				return nil, fmt.Errorf("failed to assume scanner role %s: %w", scannerRole, err)
@@ -164,7 +258,66 @@ func GetSessionChain(organizationRole, scannerRole string, targetAccountID strin
 	return currentSession, nil
 }
 
-// NewSession creates a new AWS session with the specified profile and region
+// AssumeRoleChain assumes each role in order, each from the credentials of the one before it. A role
+// may be a full ARN, to hop into another account, or a role name in the account of the previous hop.
+func AssumeRoleChain(sess *session.Session, roles []string) (*session.Session, error) {
+	for i, role := range roles {
+		roleARN := role
+		if !arn.IsARN(role) {
+			identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get identity for role chain hop %d: %w", i+1, err)
+			}
+			roleARN = RoleARN(PartitionFromARN(*identity.Arn), *identity.Account, role)
+		}
+
+		creds := AssumeRoleCredentials(sess, roleARN, i > 0)
+		hopSession, err := session.NewSession(sess.Config.Copy().WithCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to assume role chain hop %d (%s): %w", i+1, roleARN, err)
+		}
+
+		// Verify the assumption before using it for the next hop
+		identity, err := sts.New(hopSession).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify role chain hop %d (%s): %w", i+1, roleARN, err)
+		}
+		logging.Debug("Assumed role in chain", map[string]interface{}{
+			"hop":      i + 1,
+			"role_arn": *identity.Arn,
+		})
+
+		sess = hopSession
+	}
+
+	return sess, nil
+}
+
+// NewBaseSession creates a session for the configured profile and region, then assumes the
+// configured role chain, if any
+func NewBaseSession(region string) (*session.Session, error) {
+	sess, err := NewSession(config.Config.Profile, region)
+	if err != nil {
+		return nil, err
+	}
+	return AssumeRoleChain(sess, config.Config.AssumeRoleChain)
+}
+
+// ResolveProfile returns the shared config profile to load. The --profile flag defaults to
+// "default", which would otherwise override a profile selected with AWS_PROFILE, such as one
+// logged in with "aws sso login".
+func ResolveProfile(profile string) string {
+	if profile == "" || profile == "default" {
+		if envProfile := os.Getenv("AWS_PROFILE"); envProfile != "" {
+			return envProfile
+		}
+	}
+	return profile
+}
+
+// NewSession creates a new AWS session with the specified profile and region. Profiles are read
+// from the shared config file, so SSO profiles and profiles with a role_arn and source_profile
+// are supported.
 func NewSession(profile string, region string) (*session.Session, error) {
 	cfg := aws.NewConfig()
 	if region != "" {
@@ -174,7 +327,7 @@ func NewSession(profile string, region string) (*session.Session, error) {
 	// Create session options with profile
 	opts := session.Options{
 		Config:            *cfg,
-		Profile:           profile,
+		Profile:           ResolveProfile(profile),
 		SharedConfigState: session.SharedConfigEnable,
 	}
 
@@ -182,7 +335,116 @@ func NewSession(profile string, region string) (*session.Session, error) {
 	return session.NewSessionWithOptions(opts)
 }
 
-// GetSessionInRegion creates a new session in the specified region using credentials from an existing session
+// ProfilePartition returns the partition of the region configured for the active profile or
+// environment. The commercial "aws" partition is assumed when no region is configured.
+func ProfilePartition() string {
+	sess, err := NewSession(config.Config.Profile, "")
+	if err != nil {
+		return endpoints.AwsPartitionID
+	}
+	return PartitionForRegion(aws.StringValue(sess.Config.Region))
+}
+
+// regionSessions holds the base session of each region mapped to its own profile or role with
+// --profile-region-map, keyed by region
+var regionSessions sync.Map
+
+// roleSession is the role a session was assumed into, so the same role can be assumed from a
+// mapped region's base session instead
+type roleSession struct {
+	roleARN     string
+	credentials sync.Map // Credentials for the role assumed from each mapped region's base session, keyed by region
+	derived     sync.Map // Sessions GetSessionInRegion derived from the role session
+}
+
+// sessionRoles holds the role of each registered role session and of the sessions derived from
+// it, until ForgetRoleSession removes them at the end of the scan
+var sessionRoles sync.Map
+
+// SetRegionProfiles creates and verifies a base session for each region in mapping, which maps
+// regions to a shared config profile or a role ARN assumed from the current credentials.
+// GetSessionInRegion uses these sessions for their regions in place of the session it is given;
+// unmapped regions keep using it.
+func SetRegionProfiles(mapping map[string]string) error {
+	regions := make([]string, 0, len(mapping))
+	for region := range mapping {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		profile := mapping[region]
+		var sess *session.Session
+		var err error
+		if arn.IsARN(profile) {
+			sess, err = NewBaseSession(region)
+			if err == nil {
+				sess, err = session.NewSession(sess.Config.Copy().WithCredentials(AssumeRoleCredentials(sess, profile, len(config.Config.AssumeRoleChain) > 0)))
+			}
+		} else {
+			sess, err = NewSession(profile, region)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create session for region %s from %s: %w", region, profile, err)
+		}
+
+		identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		if err != nil {
+			return fmt.Errorf("failed to authenticate region %s with %s: %w", region, profile, err)
+		}
+		logging.Info("Using region-specific credentials", map[string]interface{}{
+			"region":   region,
+			"profile":  profile,
+			"identity": aws.StringValue(identity.Arn),
+		})
+		regionSessions.Store(region, sess)
+	}
+	return nil
+}
+
+// RegisterRoleSession records the role a session was assumed into, so that in regions with their
+// own base session GetSessionInRegion assumes the same role from it
+// own base session GetSessionInRegion assumes the same role from it. ForgetRoleSession must be
+// called once the session is no longer used.
+func RegisterRoleSession(sess *session.Session, roleARN string) {
+	sessionRoles.Store(sess, &roleSession{roleARN: roleARN})
+}
+
+// ForgetRoleSession removes a session registered with RegisterRoleSession, the sessions derived
+// from it and their credentials. It does nothing for sessions that weren't registered.
+func ForgetRoleSession(sess *session.Session) {
+	role, ok := sessionRoles.LoadAndDelete(sess)
+	if !ok {
+		return
+	}
+	role.(*roleSession).derived.Range(func(derived, _ interface{}) bool {
+		sessionRoles.Delete(derived)
+		return true
+	})
+}
+
+// regionCredentials returns the credentials sess should use in a region mapped to its own base
+// session, and the role they are for, if any. It returns nil for unmapped regions.
+func regionCredentials(sess *session.Session, region string) (*credentials.Credentials, *roleSession) {
+	base, ok := regionSessions.Load(region)
+	if !ok {
+		return nil, nil
+	}
+	baseSession := base.(*session.Session)
+
+	value, ok := sessionRoles.Load(sess)
+	if !ok {
+		return baseSession.Config.Credentials, nil
+	}
+	role := value.(*roleSession)
+	// The region's credentials may themselves be from an assumed role, so the session is kept to
+	// the one hour STS allows chained roles
+	creds, _ := role.credentials.LoadOrStore(region, AssumeRoleCredentials(baseSession, role.roleARN, true))
+	return creds.(*credentials.Credentials), role
+}
+
+// GetSessionInRegion creates a new session in the specified region using credentials from an
+// existing session, or from the region's own base session when it has one
 func GetSessionInRegion(sess *session.Session, region string) (*session.Session, error) {
 	if region == "" {
 		return sess, nil
@@ -194,10 +456,23 @@ func GetSessionInRegion(sess *session.Session, region string) (*session.Session,
 	}
 
 	// Create new session with updated region and timeout while preserving other config options
-	newSess, err := session.NewSession(sess.Config.WithRegion(region).WithHTTPClient(httpClient))
+	cfg := sess.Config.Copy().WithRegion(region).WithHTTPClient(httpClient)
+	creds, role := regionCredentials(sess, region)
+	if creds != nil {
+		cfg = cfg.WithCredentials(creds)
+	}
+	newSess, err := session.NewSession(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	// Sessions derived from a role session stay in that role when scanners derive their own
+	if role != nil {
+		role.derived.Store(newSess, struct{}{})
+		sessionRoles.Store(newSess, role)
+	}
+
+	// Let the registered handler know about throttled attempts before the SDK retries them
+	newSess.Handlers.Retry.PushBack(reportThrottle)
 	return newSess, nil
 }
 
@@ -212,11 +487,11 @@ func AssumeRole(targetAccountID, roleName string, sess *session.Session) (*sessi
 		"role":           roleName,
 	})
 
-	// Construct role ARN for target account
-	roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", targetAccountID, roleName)
+	// Construct role ARN for target account, in the same partition as the source session
+	roleARN := RoleARN(SessionPartition(sess), targetAccountID, roleName)
 
 	// Create new session with assumed role
-	creds := stscreds.NewCredentials(sess, roleARN)
+	creds := AssumeRoleCredentials(sess, roleARN, len(config.Config.AssumeRoleChain) > 0)
 	assumedSession, err := session.NewSession(aws.NewConfig().WithCredentials(creds))
 	if err != nil {
 		return nil, fmt.Errorf("failed to assume role %s in account %s: %w", roleName, targetAccountID, err)
@@ -0,0 +1,135 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSSOProfileSession tests that AWS_PROFILE and --profile pick the session's profile and region,
+// and that SSO profiles get their credentials from the SSO provider
+func TestSSOProfileSession(t *testing.T) {
+	// An empty home directory has no cached SSO token, so resolving credentials fails before any
+	// request to AWS is made
+	home := t.TempDir()
+	configFile := filepath.Join(home, "config")
+	require.NoError(t, os.WriteFile(configFile, []byte(`[default]
+region = us-east-1
+
+[profile sso-dev]
+sso_start_url = https://example.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 123456789012
+sso_role_name = CloudSiftReadOnly
+region = eu-west-2
+
+[profile static]
+region = us-west-1
+`), 0600))
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	tests := []struct {
+		name        string
+		envProfile  string
+		profile     string
+		wantProfile string
+		wantRegion  string
+	}{
+		{
+			name:        "AWS_PROFILE used with default profile",
+			envProfile:  "sso-dev",
+			profile:     "default",
+			wantProfile: "sso-dev",
+			wantRegion:  "eu-west-2",
+		},
+		{
+			name:        "explicit SSO profile",
+			profile:     "sso-dev",
+			wantProfile: "sso-dev",
+			wantRegion:  "eu-west-2",
+		},
+		{
+			name:        "explicit profile overrides AWS_PROFILE",
+			envProfile:  "sso-dev",
+			profile:     "static",
+			wantProfile: "static",
+			wantRegion:  "us-west-1",
+		},
+		{
+			name:        "default profile without AWS_PROFILE",
+			profile:     "default",
+			wantProfile: "default",
+			wantRegion:  "us-east-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", tt.envProfile)
+			assert.Equal(t, tt.wantProfile, ResolveProfile(tt.profile))
+
+			sess, err := NewSession(tt.profile, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRegion, aws.StringValue(sess.Config.Region))
+
+			if tt.wantProfile == "sso-dev" {
+				// The session's credentials come from the SSO provider, which needs a cached token
+				_, err := sess.Config.Credentials.Get()
+				var awsErr awserr.Error
+				require.ErrorAs(t, err, &awsErr)
+				assert.Equal(t, ssocreds.ErrCodeSSOProviderInvalidToken, awsErr.Code())
+			}
+		})
+	}
+}
+
+// TestForgetRoleSession tests that role sessions and the sessions derived from them in mapped
+// regions are tracked until the role session is forgotten
+func TestForgetRoleSession(t *testing.T) {
+	base, err := session.NewSession(aws.NewConfig().WithRegion("eu-west-1").WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")))
+	require.NoError(t, err)
+	regionSessions.Store("eu-west-1", base)
+	t.Cleanup(func() { regionSessions.Delete("eu-west-1") })
+
+	roleSess, err := session.NewSession(aws.NewConfig().WithCredentials(credentials.NewStaticCredentials("AKID", "SECRET", "")))
+	require.NoError(t, err)
+	RegisterRoleSession(roleSess, "arn:aws:iam::123456789012:role/Scanner")
+
+	mapped, err := GetSessionInRegion(roleSess, "eu-west-1")
+	require.NoError(t, err)
+	unmapped, err := GetSessionInRegion(roleSess, "us-east-1")
+	require.NoError(t, err)
+
+	// The mapped region's session assumes the role from the region's credentials, and sessions
+	// derived from it again keep using the same credentials
+	assert.NotSame(t, base.Config.Credentials, mapped.Config.Credentials)
+	again, err := GetSessionInRegion(mapped, "eu-west-1")
+	require.NoError(t, err)
+	assert.Same(t, mapped.Config.Credentials, again.Config.Credentials)
+	assert.Same(t, roleSess.Config.Credentials, unmapped.Config.Credentials)
+
+	for _, sess := range []*session.Session{roleSess, mapped, again} {
+		_, ok := sessionRoles.Load(sess)
+		assert.True(t, ok)
+	}
+
+	ForgetRoleSession(roleSess)
+	for _, sess := range []*session.Session{roleSess, mapped, again, unmapped} {
+		_, ok := sessionRoles.Load(sess)
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+)
+
+const (
+	// TagKeyMaxLength is the longest tag key AWS accepts
+	TagKeyMaxLength = 128
+	// TagValueMaxLength is the longest tag value AWS accepts
+	TagValueMaxLength = 256
+)
+
+// invalidTagChars matches characters that aren't allowed in tag values by every service
+var invalidTagChars = regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]+`)
+
+// SanitizeTagValue makes a string safe to use as a tag value by replacing characters some services
+// reject with spaces and truncating it to TagValueMaxLength
+func SanitizeTagValue(value string) string {
+	value = strings.Join(strings.Fields(invalidTagChars.ReplaceAllString(value, " ")), " ")
+	if runes := []rune(value); len(runes) > TagValueMaxLength {
+		value = strings.TrimSpace(string(runes[:TagValueMaxLength]))
+	}
+	return value
+}
+
+// TagResource applies tags to the resource with the given ARN, replacing any existing values of
+// the same keys. IAM users and roles are tagged through IAM, which the Resource Groups Tagging API
+// doesn't cover; everything else is tagged through the tagging API in the resource's region, or the
+// partition's global region for resources without one.
+func TagResource(ctx context.Context, sess *session.Session, resourceARN string, tags map[string]string) error {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return fmt.Errorf("invalid ARN %s: %w", resourceARN, err)
+	}
+
+	region := parsed.Region
+	if region == "" {
+		region = GlobalRegion(parsed.Partition)
+	}
+	regionSession, err := GetSessionInRegion(sess, region)
+	if err != nil {
+		return fmt.Errorf("failed to create regional session: %w", err)
+	}
+
+	if parsed.Service == "iam" {
+		return tagIAMResource(ctx, iam.New(regionSession), parsed, tags)
+	}
+
+	output, err := resourcegroupstaggingapi.New(regionSession).TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+		ResourceARNList: []*string{aws.String(resourceARN)},
+		Tags:            aws.StringMap(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", resourceARN, err)
+	}
+	if failure, ok := output.FailedResourcesMap[resourceARN]; ok {
+		return fmt.Errorf("failed to tag %s: %s: %s", resourceARN, aws.StringValue(failure.ErrorCode), aws.StringValue(failure.ErrorMessage))
+	}
+	return nil
+}
+
+// tagIAMResource tags an IAM user or role, whose name is the last segment of its ARN's resource
+func tagIAMResource(ctx context.Context, client *iam.IAM, parsed arn.ARN, tags map[string]string) error {
+	var iamTags []*iam.Tag
+	for key, value := range tags {
+		iamTags = append(iamTags, &iam.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	resourceType, path, _ := strings.Cut(parsed.Resource, "/")
+	name := path[strings.LastIndex(path, "/")+1:]
+
+	var err error
+	switch resourceType {
+	case "user":
+		_, err = client.TagUserWithContext(ctx, &iam.TagUserInput{UserName: aws.String(name), Tags: iamTags})
+	case "role":
+		_, err = client.TagRoleWithContext(ctx, &iam.TagRoleInput{RoleName: aws.String(name), Tags: iamTags})
+	default:
+		return fmt.Errorf("tagging IAM %s resources is not supported", resourceType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to tag IAM %s %s: %w", resourceType, name, err)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	awsinternal "cloudsift/internal/aws"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 )
@@ -21,7 +23,7 @@ type MetricConfig struct {
 }
 
 // GetResourceMetrics retrieves CloudWatch metrics for a resource using GetMetricStatistics
-func GetResourceMetrics(cwClient *cloudwatch.CloudWatch, config MetricConfig) (float64, error) {
+func GetResourceMetrics(ctx aws.Context, cwClient *cloudwatch.CloudWatch, config MetricConfig) (float64, error) {
 	input := &cloudwatch.GetMetricStatisticsInput{
 		Namespace:  aws.String(config.Namespace),
 		MetricName: aws.String(config.MetricName),
@@ -39,7 +41,7 @@ func GetResourceMetrics(cwClient *cloudwatch.CloudWatch, config MetricConfig) (f
 		},
 	}
 
-	output, err := cwClient.GetMetricStatistics(input)
+	output, err := awsinternal.GetMetricStatistics(ctx, cwClient, input)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get metric statistics: %w", err)
 	}
@@ -65,7 +67,7 @@ func GetResourceMetrics(cwClient *cloudwatch.CloudWatch, config MetricConfig) (f
 }
 
 // GetResourceMetricsData retrieves multiple metrics for a resource using GetMetricData
-func GetResourceMetricsData(cwClient *cloudwatch.CloudWatch, configs []MetricConfig) (map[string]float64, error) {
+func GetResourceMetricsData(ctx aws.Context, cwClient *cloudwatch.CloudWatch, configs []MetricConfig) (map[string]float64, error) {
 	queries := make([]*cloudwatch.MetricDataQuery, len(configs))
 	for i, config := range configs {
 		queries[i] = &cloudwatch.MetricDataQuery{
@@ -93,7 +95,7 @@ func GetResourceMetricsData(cwClient *cloudwatch.CloudWatch, configs []MetricCon
 		EndTime:           aws.Time(configs[0].EndTime),
 	}
 
-	output, err := cwClient.GetMetricData(input)
+	output, err := awsinternal.GetMetricData(ctx, cwClient, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metric data: %w", err)
 	}
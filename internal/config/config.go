@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // GlobalConfig holds the global configuration for the application
 type GlobalConfig struct {
 	// Profile is the AWS profile to use
@@ -11,6 +13,9 @@ type GlobalConfig struct {
 	// ScannerRole is the role name to assume for scanning operations
 	ScannerRole string
 
+	// AssumeRoleChain is the ordered list of roles assumed from the base profile before the organization role
+	AssumeRoleChain []string
+
 	// MaxWorkers defines the maximum number of concurrent workers
 	MaxWorkers int
 
@@ -20,6 +25,9 @@ type GlobalConfig struct {
 	// LogLevel is the level for logging
 	LogLevel string
 
+	// Quiet limits logging to errors and the final scan summary
+	Quiet bool
+
 	// ScanRegions is the list of regions to scan
 	ScanRegions string
 
@@ -50,8 +58,155 @@ type GlobalConfig struct {
 	// ScanIgnoreTags is the map of tags to ignore
 	ScanIgnoreTags map[string]string
 
+	// ScanIgnoreRules is the list of ignore rules scoped to specific scanners or accounts
+	ScanIgnoreRules []IgnoreRule
+
 	// ScanAccounts is the list of account IDs to scan
 	ScanAccounts []string
+
+	// ScanScannerTimeout is the maximum time a single scanner task may run
+	ScanScannerTimeout time.Duration
+
+	// ScanFailOverCost is the estimated monthly cost of findings above which the scan fails (0 disables)
+	ScanFailOverCost float64
+
+	// ScanOutputDir is the base directory for filesystem output
+	ScanOutputDir string
+
+	// ScanReportName is the file name used for the HTML report
+	ScanReportName string
+
+	// ScanProfiles is the list of AWS profiles to scan as standalone accounts
+	ScanProfiles []string
+
+	// ScanIgnoreFile is the path to a YAML or JSON file of ignore rules
+	ScanIgnoreFile string
+
+	// ScanMaxTasksPerAccount is the maximum number of scanner tasks running at once against a single account (0 disables)
+	ScanMaxTasksPerAccount int
+
+	// ScanEmitRemediation is the path to write suggested remediation commands to
+	ScanEmitRemediation string
+
+	// ScanRemediationUncomment leaves destructive remediation commands uncommented
+	ScanRemediationUncomment bool
+
+	// ScanTUI shows a live terminal progress view instead of periodic progress logs
+	ScanTUI bool
+
+	// ScanS3KMSKeyID is the ARN of the KMS key used to encrypt scan output written to S3
+	ScanS3KMSKeyID string
+
+	// ScanIncludeTags limits results to resources carrying at least one of these tags (empty keeps everything)
+	ScanIncludeTags map[string]string
+
+	// ScanFilenameTemplate names JSON output files and S3 keys, e.g. "{date}/{account_id}.json"
+	ScanFilenameTemplate string
+
+	// ScanS3Layout is the S3 key layout: flat, or partitioned for Athena and Glue
+	ScanS3Layout string
+
+	// ScanMaxTasksPerRegion is the maximum number of scanner tasks running at once in a single region (0 disables)
+	ScanMaxTasksPerRegion int
+
+	// ScanCombinedOutput writes all accounts to a single JSON document keyed by account ID instead of one file per account
+	ScanCombinedOutput bool
+
+	// ScanSkipScanners is the list of scanners to leave out, applied after ScanScanners
+	ScanSkipScanners string
+
+	// ScanMinAgeDays drops results for resources created fewer than this many days ago (0 disables)
+	ScanMinAgeDays int
+
+	// ScanOrganizationalUnits limits the scan to accounts in these organizational units, including nested OUs
+	ScanOrganizationalUnits []string
+
+	// ScanWebhookURL is the URL each account's JSON results are POSTed to with http output
+	ScanWebhookURL string
+
+	// ScanWebhookToken is sent as a bearer token with webhook requests
+	ScanWebhookToken string
+
+	// ScanRequireAllAccounts fails the scan when organization accounts can't be listed or the scanner role can't be assumed in some of them, instead of scanning a subset
+	ScanRequireAllAccounts bool
+
+	// ScanReportTitle is the heading of the HTML report
+	ScanReportTitle string
+
+	// ScanReportLogo is an image file or http(s) URL shown beside the HTML report title
+	ScanReportLogo string
+
+	// ScanMaxResultsPerScanner is the maximum number of findings kept per scanner, account and region (0 disables)
+	ScanMaxResultsPerScanner int
+
+	// ScanCheckpoint is the file completed scanner tasks and their findings are checkpointed to so a failed scan can be resumed
+	ScanCheckpoint string
+
+	// ScanCurrency is the ISO 4217 currency cost estimates are reported in
+	ScanCurrency string
+
+	// ScanExchangeRate is the number of units of ScanCurrency one US dollar buys
+	ScanExchangeRate float64
+
+	// ScanOnlyAccountsWithFindings skips writing per-account output for accounts with no findings and no scanner errors
+	ScanOnlyAccountsWithFindings bool
+
+	// ScanEC2Rightsizing makes the EC2 scanner recommend smaller instance types for running instances that aren't idle
+	ScanEC2Rightsizing bool
+
+	// ScanActualSpend looks up each account's actual spend on the services behind its findings in Cost Explorer
+	ScanActualSpend bool
+
+	// ScanS3ACL is the canned ACL applied to S3 output objects, e.g. bucket-owner-full-control; no ACL is sent when empty
+	ScanS3ACL string
+
+	// ScanS3StorageClass is the storage class of S3 output objects, e.g. STANDARD_IA; the bucket default is used when empty
+	ScanS3StorageClass string
+
+	// ScanApplyTags tags each flagged resource with ScanFlagTag and the reason it was flagged after the scan
+	ScanApplyTags bool
+
+	// ScanFlagTag is the KEY=VALUE tag applied to flagged resources with ScanApplyTags; the reason is tagged under KEY:reason
+	ScanFlagTag string
+
+	// ScanDryRun logs the tags ScanApplyTags would apply without applying them
+	ScanDryRun bool
+
+	// ScanAccountsFile is a JSON or CSV file listing the accounts to scan, used instead of listing them through Organizations
+	ScanAccountsFile string
+
+	// ScanGlobalRegion is the region global scanners such as IAM and the cost estimator are called in; the partition's global region is used when empty
+	ScanGlobalRegion string
+
+	// ScanCompress gzips JSON output files and S3 objects, appending .gz to their names
+	ScanCompress bool
+
+	// ScanReportRegions is the list of regions to report findings from after the scan; a leading ! excludes a region
+	ScanReportRegions string
+
+	// ScanBaseline is a previous scan's JSON output; scanners whose finding count or cost changed by more than ScanAnomalyThreshold percent are flagged
+	ScanBaseline string
+
+	// ScanAnomalyThreshold is the percent change from ScanBaseline in a scanner's finding count or monthly cost that is flagged
+	ScanAnomalyThreshold float64
+
+	// ScanEmitIgnored is the path to write findings left out by the ignore rules and filters to, with the rule that matched
+	ScanEmitIgnored string
+
+	// ScanOutputConcurrency is the number of accounts whose results are written or uploaded at once
+	ScanOutputConcurrency int
+
+	// ScanEmitCloudWatch publishes finding counts and estimated savings as CloudWatch custom metrics at the end of a scan
+	ScanEmitCloudWatch bool
+
+	// ScanSessionDuration is how long each assumed-role session lasts before the SDK refreshes it
+	ScanSessionDuration time.Duration
+
+	// ScanProfileRegionMap maps regions to the profile or role ARN whose credentials they are scanned with
+	ScanProfileRegionMap map[string]string
+
+	// ScanMinConfidence is the lowest finding confidence that is reported: low, medium or high
+	ScanMinConfidence string
 }
 
 // Config is the global configuration instance
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// accountIDPattern matches 12-digit AWS account IDs
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// IgnoreRule lists resources to leave out of scan results. Matching is case-insensitive.
+type IgnoreRule struct {
+	// ResourceIDs is the list of resource IDs to ignore
+	ResourceIDs []string `yaml:"resource_ids" json:"resource_ids"`
+
+	// ResourceNames is the list of resource names to ignore
+	ResourceNames []string `yaml:"resource_names" json:"resource_names"`
+
+	// Tags is the map of tags to ignore; a resource matches when it has any of them
+	Tags map[string]string `yaml:"tags" json:"tags"`
+
+	// Scanners limits the rule to these scanners, by argument name or label (empty means all)
+	Scanners []string `yaml:"scanners" json:"scanners"`
+
+	// Accounts limits the rule to these account IDs (empty means all)
+	Accounts []string `yaml:"accounts" json:"accounts"`
+}
+
+// IgnoreFile is the layout of the file passed to --ignore-file. The top-level lists apply to every
+// scanner and account, while each entry in Rules can be scoped to specific scanners and accounts.
+type IgnoreFile struct {
+	IgnoreRule `yaml:",inline"`
+
+	// Rules is the list of scoped ignore rules
+	Rules []IgnoreRule `yaml:"rules" json:"rules"`
+}
+
+// LoadIgnoreFile reads ignore rules from a YAML or JSON file. Files ending in .json are parsed as
+// JSON; anything else is parsed as YAML.
+func LoadIgnoreFile(path string) (*IgnoreFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+
+	var ignoreFile IgnoreFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &ignoreFile)
+	} else {
+		err = yaml.Unmarshal(data, &ignoreFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ignore file %s: %w", path, err)
+	}
+
+	return &ignoreFile, nil
+}
+
+// LoadScanIgnoreRules reads the scoped ignore rules from the ignore.rules list of the scan section
+// of the config file, so teams can keep their own rules without a separate ignore file
+func LoadScanIgnoreRules() ([]IgnoreRule, error) {
+	if !viper.IsSet("scan.ignore.rules") {
+		return nil, nil
+	}
+	rules, err := decodeIgnoreRules(viper.Get("scan.ignore.rules"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan.ignore.rules: %w", err)
+	}
+	return rules, nil
+}
+
+// MergeInto adds the file's unscoped lists to the config's ignore lists and appends its scoped rules
+func (f *IgnoreFile) MergeInto(c *GlobalConfig) {
+	c.ScanIgnoreResourceIDs = append(c.ScanIgnoreResourceIDs, f.ResourceIDs...)
+	c.ScanIgnoreResourceNames = append(c.ScanIgnoreResourceNames, f.ResourceNames...)
+	if len(f.Tags) > 0 && c.ScanIgnoreTags == nil {
+		c.ScanIgnoreTags = make(map[string]string, len(f.Tags))
+	}
+	for key, value := range f.Tags {
+		c.ScanIgnoreTags[key] = value
+	}
+	c.ScanIgnoreRules = append(c.ScanIgnoreRules, f.Rules...)
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo reports whether the rule is in scope for a scanner and account
+func (r IgnoreRule) AppliesTo(scannerArgumentName, scannerLabel, accountID string) bool {
+	if len(r.Scanners) > 0 && !containsFold(r.Scanners, scannerArgumentName) && !containsFold(r.Scanners, scannerLabel) {
+		return false
+	}
+	if len(r.Accounts) > 0 && !containsFold(r.Accounts, accountID) {
+		return false
+	}
+	return true
+}
+
+// Validate reports a rule that matches nothing, account IDs that aren't 12 digits and scanners that
+// isValidScanner rejects. Scanners may be given by argument name or label.
+func (r IgnoreRule) Validate(isValidScanner func(name string) bool) []error {
+	var errs []error
+	if len(r.ResourceIDs) == 0 && len(r.ResourceNames) == 0 && len(r.Tags) == 0 {
+		errs = append(errs, fmt.Errorf("rule has no resource_ids, resource_names or tags, so it ignores nothing"))
+	}
+	for _, scanner := range r.Scanners {
+		if !isValidScanner(scanner) {
+			errs = append(errs, fmt.Errorf("unknown scanner %q", scanner))
+		}
+	}
+	for _, account := range r.Accounts {
+		if !accountIDPattern.MatchString(account) {
+			errs = append(errs, fmt.Errorf("invalid account ID %q: expected 12 digits", account))
+		}
+	}
+	return errs
+}
+
+// Matches reports whether a resource matches any of the rule's IDs, names or tags
+func (r IgnoreRule) Matches(resourceID, resourceName string, tags map[string]string) bool {
+	_, matched := r.Match(resourceID, resourceName, tags)
+	return matched
+}
+
+// Match returns the entry of the rule a resource matches, such as "resource_id: vol-1" or
+// "tag: env=dev", and whether it matched at all
+func (r IgnoreRule) Match(resourceID, resourceName string, tags map[string]string) (string, bool) {
+	if containsFold(r.ResourceIDs, resourceID) {
+		return "resource_id: " + resourceID, true
+	}
+	if containsFold(r.ResourceNames, resourceName) {
+		return "resource_name: " + resourceName, true
+	}
+
+	// Check the rule's tags in order so the same entry is reported every time
+	ignoreKeys := make([]string, 0, len(r.Tags))
+	for ignoreKey := range r.Tags {
+		ignoreKeys = append(ignoreKeys, ignoreKey)
+	}
+	sort.Strings(ignoreKeys)
+	for _, ignoreKey := range ignoreKeys {
+		for tagKey, tagValue := range tags {
+			if strings.EqualFold(tagKey, ignoreKey) && strings.EqualFold(tagValue, r.Tags[ignoreKey]) {
+				return fmt.Sprintf("tag: %s=%s", tagKey, tagValue), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ScanPreset is a named scan configuration from the scans section of the config file
+type ScanPreset struct {
+	// Flags maps scan flag names to the preset's value for them, formatted as on the command line
+	Flags map[string]string
+
+	// IgnoreRules is the list of scoped ignore rules from the preset's ignore.rules section
+	IgnoreRules []IgnoreRule
+}
+
+// ScanPresetNames returns the names of the scan presets in the config file, sorted
+func ScanPresetNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("scans") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadScanPreset reads a named preset from the scans section of the config file. Preset keys use
+// the same names as the scan section, e.g. days_unused or ignore.tags.
+func LoadScanPreset(name string) (*ScanPreset, error) {
+	key := "scans." + strings.ToLower(name)
+	if !viper.IsSet(key) {
+		available := ScanPresetNames()
+		if len(available) == 0 {
+			return nil, fmt.Errorf("scan preset %q not found: the config file has no scans section", name)
+		}
+		return nil, fmt.Errorf("scan preset %q not found (available: %s)", name, strings.Join(available, ", "))
+	}
+
+	sub := viper.Sub(key)
+	if sub == nil {
+		return nil, fmt.Errorf("scan preset %q must be a map of scan settings", name)
+	}
+
+	// Flatten the preset into setting names like days_unused or ignore.tags. Only the ignore
+	// section nests; tag maps and rule lists below it are values, not settings.
+	settings := make(map[string]interface{})
+	for setting, value := range sub.AllSettings() {
+		if nested, ok := value.(map[string]interface{}); ok && setting == "ignore" {
+			for child, childValue := range nested {
+				settings[setting+"."+child] = childValue
+			}
+			continue
+		}
+		settings[setting] = value
+	}
+
+	preset := &ScanPreset{Flags: make(map[string]string)}
+	for setting, value := range settings {
+		// Scoped rules have no flag, so they are decoded like the rules of an ignore file
+		if setting == "ignore.rules" {
+			rules, err := decodeIgnoreRules(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore.rules in scan preset %q: %w", name, err)
+			}
+			preset.IgnoreRules = rules
+			continue
+		}
+
+		flagName, ok := flagNames["scan."+setting]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q in scan preset %q", setting, name)
+		}
+		preset.Flags[flagName] = presetFlagValue(value)
+	}
+
+	return preset, nil
+}
+
+// presetFlagValue formats a config value the way it would be passed on the command line. Lists
+// become comma-separated values and maps become comma-separated KEY=VALUE pairs.
+func presetFlagValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for key, item := range v {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, item))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// decodeIgnoreRules converts a decoded config value into ignore rules
+func decodeIgnoreRules(value interface{}) ([]IgnoreRule, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var rules []IgnoreRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/spf13/viper"
+)
+
+// otherKeys are config keys that are valid but have no flag on the scan command or root command
+var otherKeys = []string{
+	"list.format",
+	"scan.ignore.rules",
+}
+
+// roleNamePattern matches IAM role names, optionally prefixed with a path
+var roleNamePattern = regexp.MustCompile(`^([\w+=,.@-]+/)*[\w+=,.@-]{1,64}$`)
+
+// Keys returns every known config key, sorted
+func Keys() []string {
+	keys := append([]string(nil), otherKeys...)
+	for key := range flagNames {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FlagName returns the name of the command line flag that sets a config key
+func FlagName(key string) (string, bool) {
+	name, ok := flagNames[key]
+	return name, ok
+}
+
+// UnknownKeys returns the keys set in the config file that CloudSift doesn't recognize, sorted.
+// Scan presets are left to LoadScanPreset, which rejects unknown settings itself.
+func UnknownKeys() []string {
+	known := make(map[string]bool)
+	for _, key := range Keys() {
+		known[key] = true
+	}
+
+	var unknown []string
+	for _, key := range viper.AllKeys() {
+		// Tag maps are flattened into one key per tag
+		if known[key] || strings.HasPrefix(key, "scans.") || strings.HasPrefix(key, "scan.ignore.tags.") {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// SectionFlagValues returns the current value of every key in a config section that has a flag,
+// keyed by flag name and formatted as on the command line
+func SectionFlagValues(section string) map[string]string {
+	values := make(map[string]string)
+	for key, flagName := range flagNames {
+		if !strings.HasPrefix(key, section+".") || !viper.IsSet(key) {
+			continue
+		}
+		values[flagName] = presetFlagValue(viper.Get(key))
+	}
+	return values
+}
+
+// ValidateRoleName checks that a role is a well-formed IAM role name, optionally with a path. When
+// allowARN is set, a full IAM role ARN is accepted as well.
+func ValidateRoleName(role string, allowARN bool) error {
+	if allowARN && arn.IsARN(role) {
+		parsed, err := arn.Parse(role)
+		if err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+			return fmt.Errorf("invalid role ARN %q: expected an IAM role ARN such as arn:aws:iam::111122223333:role/ScannerRole", role)
+		}
+		return nil
+	}
+	if !roleNamePattern.MatchString(role) {
+		return fmt.Errorf("invalid role name %q: role names are 1-64 letters, digits and +=,.@_- characters", role)
+	}
+	return nil
+}
@@ -18,28 +18,82 @@ type parameterSource struct {
 	Source string
 }
 
+// flagNames maps config keys to flag names
+var flagNames = map[string]string{
+	"aws.profile":                      "profile",
+	"aws.organization_role":            "organization-role",
+	"aws.scanner_role":                 "scanner-role",
+	"aws.assume_role_chain":            "assume-role-chain",
+	"app.max_workers":                  "max-workers",
+	"app.log_format":                   "log-format",
+	"app.log_level":                    "log-level",
+	"app.quiet":                        "quiet",
+	"scan.regions":                     "regions",
+	"scan.scanners":                    "scanners",
+	"scan.output":                      "output",
+	"scan.output_format":               "output-format",
+	"scan.bucket":                      "bucket",
+	"scan.bucket_region":               "bucket-region",
+	"scan.days_unused":                 "days-unused",
+	"scan.scanner_timeout":             "scanner-timeout",
+	"scan.fail_over_cost":              "fail-over-cost",
+	"scan.output_dir":                  "output-dir",
+	"scan.report_name":                 "report-name",
+	"scan.profiles":                    "profiles",
+	"scan.ignore_file":                 "ignore-file",
+	"scan.max_tasks_per_account":       "max-tasks-per-account",
+	"scan.emit_remediation":            "emit-remediation",
+	"scan.remediation_uncomment":       "remediation-uncomment",
+	"scan.tui":                         "tui",
+	"scan.s3_kms_key_id":               "s3-kms-key-id",
+	"scan.include_tags":                "include-tags",
+	"scan.filename_template":           "filename-template",
+	"scan.s3_layout":                   "s3-layout",
+	"scan.max_tasks_per_region":        "max-tasks-per-region",
+	"scan.combined_output":             "combined-output",
+	"scan.skip_scanners":               "skip-scanners",
+	"scan.min_age_days":                "min-age-days",
+	"scan.organizational_units":        "organizational-units",
+	"scan.webhook_url":                 "webhook-url",
+	"scan.webhook_token":               "webhook-token",
+	"scan.require_all_accounts":        "require-all-accounts",
+	"scan.report_title":                "report-title",
+	"scan.report_logo":                 "report-logo",
+	"scan.max_results_per_scanner":     "max-results-per-scanner",
+	"scan.checkpoint":                  "checkpoint",
+	"scan.currency":                    "currency",
+	"scan.exchange_rate":               "exchange-rate",
+	"scan.only_accounts_with_findings": "only-accounts-with-findings",
+	"scan.ec2_rightsizing":             "ec2-rightsizing",
+	"scan.actual_spend":                "actual-spend",
+	"scan.s3_acl":                      "s3-acl",
+	"scan.s3_storage_class":            "s3-storage-class",
+	"scan.apply_tags":                  "apply-tags",
+	"scan.flag_tag":                    "flag-tag",
+	"scan.dry_run":                     "dry-run",
+	"scan.accounts_file":               "accounts-file",
+	"scan.global_region":               "global-region",
+	"scan.compress":                    "compress",
+	"scan.report_regions":              "report-regions",
+	"scan.baseline":                    "baseline",
+	"scan.anomaly_threshold":           "anomaly-threshold",
+	"scan.emit_ignored":                "emit-ignored",
+	"scan.output_concurrency":          "output-concurrency",
+	"scan.emit_cloudwatch":             "emit-cloudwatch",
+	"scan.session_duration":            "session-duration",
+	"scan.profile_region_map":          "profile-region-map",
+	"scan.min_confidence":              "min-confidence",
+	"scan.accounts":                    "accounts",
+	"scan.ignore.resource_ids":         "ignore-resource-ids",
+	"scan.ignore.resource_names":       "ignore-resource-names",
+	"scan.ignore.tags":                 "ignore-tags",
+}
+
 // getParameterSource determines where a parameter value came from (config file, env var, flag, or default)
 func getParameterSource(key string, cmd *cobra.Command) parameterSource {
 	flagValue := viper.Get(key)
 	envKey := "CLOUDSIFT_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
 
-	// Map config keys to flag names
-	flagNames := map[string]string{
-		"aws.profile":           "profile",
-		"aws.organization_role": "organization-role",
-		"aws.scanner_role":      "scanner-role",
-		"app.max_workers":       "max-workers",
-		"app.log_format":        "log-format",
-		"app.log_level":         "log-level",
-		"scan.regions":          "regions",
-		"scan.scanners":         "scanners",
-		"scan.output":           "output",
-		"scan.output_format":    "output-format",
-		"scan.bucket":           "bucket",
-		"scan.bucket_region":    "bucket-region",
-		"scan.days_unused":      "days-unused",
-	}
-
 	// Get the flag name from the map, or convert the key if not found
 	flagName := flagNames[key]
 	if flagName == "" {
@@ -91,9 +145,11 @@ func LogConfigurationSources(shouldLog bool, cmd *cobra.Command) {
 		"aws.profile",
 		"aws.organization_role",
 		"aws.scanner_role",
+		"aws.assume_role_chain",
 		"app.max_workers",
 		"app.log_format",
 		"app.log_level",
+		"app.quiet",
 		"scan.regions",
 		"scan.scanners",
 		"scan.output",
@@ -101,6 +157,54 @@ func LogConfigurationSources(shouldLog bool, cmd *cobra.Command) {
 		"scan.bucket",
 		"scan.bucket_region",
 		"scan.days_unused",
+		"scan.scanner_timeout",
+		"scan.fail_over_cost",
+		"scan.output_dir",
+		"scan.report_name",
+		"scan.profiles",
+		"scan.ignore_file",
+		"scan.max_tasks_per_account",
+		"scan.emit_remediation",
+		"scan.remediation_uncomment",
+		"scan.tui",
+		"scan.s3_kms_key_id",
+		"scan.include_tags",
+		"scan.filename_template",
+		"scan.s3_layout",
+		"scan.max_tasks_per_region",
+		"scan.combined_output",
+		"scan.skip_scanners",
+		"scan.min_age_days",
+		"scan.organizational_units",
+		"scan.webhook_url",
+		"scan.webhook_token",
+		"scan.require_all_accounts",
+		"scan.report_title",
+		"scan.report_logo",
+		"scan.max_results_per_scanner",
+		"scan.checkpoint",
+		"scan.currency",
+		"scan.exchange_rate",
+		"scan.only_accounts_with_findings",
+		"scan.ec2_rightsizing",
+		"scan.actual_spend",
+		"scan.s3_acl",
+		"scan.s3_storage_class",
+		"scan.apply_tags",
+		"scan.flag_tag",
+		"scan.dry_run",
+		"scan.accounts_file",
+		"scan.global_region",
+		"scan.compress",
+		"scan.report_regions",
+		"scan.baseline",
+		"scan.anomaly_threshold",
+		"scan.emit_ignored",
+		"scan.output_concurrency",
+		"scan.emit_cloudwatch",
+		"scan.session_duration",
+		"scan.profile_region_map",
+		"scan.min_confidence",
 	}
 
 	// Log the source of each parameter
@@ -128,9 +232,11 @@ func InitConfig(shouldLog bool, cmd *cobra.Command) error {
 	viper.SetDefault("aws.profile", "default")
 	viper.SetDefault("aws.organization_role", "")
 	viper.SetDefault("aws.scanner_role", "")
+	viper.SetDefault("aws.assume_role_chain", []string{})
 	viper.SetDefault("app.max_workers", 8)
 	viper.SetDefault("app.log_format", "text")
 	viper.SetDefault("app.log_level", "INFO")
+	viper.SetDefault("app.quiet", false)
 	viper.SetDefault("scan.regions", "")
 	viper.SetDefault("scan.scanners", "")
 	viper.SetDefault("scan.output", "filesystem")
@@ -138,6 +244,54 @@ func InitConfig(shouldLog bool, cmd *cobra.Command) error {
 	viper.SetDefault("scan.bucket", "")
 	viper.SetDefault("scan.bucket_region", "")
 	viper.SetDefault("scan.days_unused", 90)
+	viper.SetDefault("scan.scanner_timeout", "3m")
+	viper.SetDefault("scan.fail_over_cost", 0)
+	viper.SetDefault("scan.output_dir", "")
+	viper.SetDefault("scan.report_name", "")
+	viper.SetDefault("scan.profiles", "")
+	viper.SetDefault("scan.ignore_file", "")
+	viper.SetDefault("scan.max_tasks_per_account", 0)
+	viper.SetDefault("scan.emit_remediation", "")
+	viper.SetDefault("scan.remediation_uncomment", false)
+	viper.SetDefault("scan.tui", false)
+	viper.SetDefault("scan.s3_kms_key_id", "")
+	viper.SetDefault("scan.include_tags", "")
+	viper.SetDefault("scan.filename_template", "")
+	viper.SetDefault("scan.s3_layout", "flat")
+	viper.SetDefault("scan.max_tasks_per_region", 0)
+	viper.SetDefault("scan.combined_output", false)
+	viper.SetDefault("scan.skip_scanners", "")
+	viper.SetDefault("scan.min_age_days", 0)
+	viper.SetDefault("scan.organizational_units", "")
+	viper.SetDefault("scan.webhook_url", "")
+	viper.SetDefault("scan.webhook_token", "")
+	viper.SetDefault("scan.require_all_accounts", false)
+	viper.SetDefault("scan.report_title", "")
+	viper.SetDefault("scan.report_logo", "")
+	viper.SetDefault("scan.max_results_per_scanner", 0)
+	viper.SetDefault("scan.checkpoint", "")
+	viper.SetDefault("scan.currency", "USD")
+	viper.SetDefault("scan.exchange_rate", 0)
+	viper.SetDefault("scan.only_accounts_with_findings", false)
+	viper.SetDefault("scan.ec2_rightsizing", false)
+	viper.SetDefault("scan.actual_spend", false)
+	viper.SetDefault("scan.s3_acl", "")
+	viper.SetDefault("scan.s3_storage_class", "")
+	viper.SetDefault("scan.apply_tags", false)
+	viper.SetDefault("scan.flag_tag", "cloudsift:flagged=true")
+	viper.SetDefault("scan.dry_run", false)
+	viper.SetDefault("scan.accounts_file", "")
+	viper.SetDefault("scan.global_region", "")
+	viper.SetDefault("scan.compress", true)
+	viper.SetDefault("scan.report_regions", "")
+	viper.SetDefault("scan.baseline", "")
+	viper.SetDefault("scan.anomaly_threshold", 50.0)
+	viper.SetDefault("scan.emit_ignored", "")
+	viper.SetDefault("scan.output_concurrency", 10)
+	viper.SetDefault("scan.emit_cloudwatch", false)
+	viper.SetDefault("scan.session_duration", "1h")
+	viper.SetDefault("scan.profile_region_map", "")
+	viper.SetDefault("scan.min_confidence", "low")
 
 	// Try to read config file but don't error if not found
 	if err := viper.ReadInConfig(); err != nil {
@@ -198,6 +352,7 @@ app:
   max_workers: 8  # Maximum number of concurrent workers
   log_format: text  # Log output format (text or json)
   log_level: INFO  # Set logging level (DEBUG, INFO, WARN, ERROR)
+  quiet: false  # Only log errors and the final scan summary
 
 # Scan Command Configuration
 scan:
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -52,6 +53,7 @@ type Logger struct {
 	out         io.Writer
 	level       Level
 	format      Format
+	quiet       bool // Only errors and the final scan summary are written
 	lastLogTime time.Time
 	logMutex    sync.RWMutex
 }
@@ -60,6 +62,7 @@ type Logger struct {
 type LogConfig struct {
 	Level  Level
 	Format Format
+	Quiet  bool // Suppress everything except errors and the final scan summary, including progress
 }
 
 // Account represents an AWS account
@@ -89,6 +92,31 @@ var (
 func Configure(config LogConfig) {
 	defaultLogger.level = config.Level
 	defaultLogger.format = config.Format
+	defaultLogger.quiet = config.Quiet
+	if config.Quiet {
+		defaultLogger.level = ERROR
+	}
+}
+
+// ParseFormat converts a --log-format value to a Format
+func ParseFormat(format string) (Format, error) {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return Text, nil
+	case "json":
+		return JSON, nil
+	default:
+		return Text, fmt.Errorf("invalid log format: %s (must be 'text' or 'json')", format)
+	}
+}
+
+// SetOutput redirects log output to w and returns the previous writer
+func SetOutput(w io.Writer) io.Writer {
+	defaultLogger.logMutex.Lock()
+	defer defaultLogger.logMutex.Unlock()
+	previous := defaultLogger.out
+	defaultLogger.out = w
+	return previous
 }
 
 type logEntry struct {
@@ -99,11 +127,18 @@ type logEntry struct {
 }
 
 func (l *Logger) log(level Level, msg string, data interface{}) {
-	// Always show PROGRESS level, otherwise respect level setting
+	// Always show PROGRESS level unless quiet, otherwise respect level setting
+	if level == PROGRESS && l.quiet {
+		return
+	}
 	if level != PROGRESS && level < l.level {
 		return
 	}
+	l.write(level, msg, data)
+}
 
+// write outputs a log entry without checking the level
+func (l *Logger) write(level Level, msg string, data interface{}) {
 	// Update last log time for non-PROGRESS logs
 	if level != PROGRESS {
 		l.logMutex.Lock()
@@ -111,6 +146,10 @@ func (l *Logger) log(level Level, msg string, data interface{}) {
 		l.logMutex.Unlock()
 	}
 
+	l.logMutex.RLock()
+	out := l.out
+	l.logMutex.RUnlock()
+
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 
 	if l.format == JSON {
@@ -120,7 +159,7 @@ func (l *Logger) log(level Level, msg string, data interface{}) {
 			Message:   msg,
 			Data:      data,
 		}
-		if err := json.NewEncoder(l.out).Encode(entry); err != nil {
+		if err := json.NewEncoder(out).Encode(entry); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to encode log entry: %v\n", err)
 		}
 		return
@@ -144,11 +183,11 @@ func (l *Logger) log(level Level, msg string, data interface{}) {
 	}
 
 	levelStr := levelColor.Sprintf("%-5s", level.String())
-	fmt.Fprintf(l.out, "%s %s: %s", timestamp, levelStr, msg)
+	fmt.Fprintf(out, "%s %s: %s", timestamp, levelStr, msg)
 	if data != nil {
-		fmt.Fprintf(l.out, " %+v", data)
+		fmt.Fprintf(out, " %+v", data)
 	}
-	fmt.Fprintln(l.out)
+	fmt.Fprintln(out)
 }
 
 func (l *Logger) Debug(msg string, data ...interface{}) {
@@ -244,6 +283,11 @@ func (l *Logger) ScanComplete(totalResults int) {
 	data := map[string]interface{}{
 		"total_results": totalResults,
 	}
+	// The summary is the one informational line kept in quiet mode
+	if l.quiet {
+		l.write(INFO, "Scan operation complete", data)
+		return
+	}
 	l.Info("Scan operation complete", data)
 }
 
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	awsinternal "cloudsift/internal/aws"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+)
+
+const (
+	// CloudWatchNamespace is the namespace scan metrics are published under
+	CloudWatchNamespace = "CloudSift"
+
+	// cloudWatchBatchSize is the number of metrics sent in each PutMetricData call
+	cloudWatchBatchSize = 20
+)
+
+// CloudWatchSink publishes scan findings as CloudWatch custom metrics, so alarms can fire when
+// waste crosses a threshold
+type CloudWatchSink struct {
+	client    cloudwatchiface.CloudWatchAPI
+	namespace string
+}
+
+// NewCloudWatchSink creates a sink that publishes to namespace using client
+func NewCloudWatchSink(client cloudwatchiface.CloudWatchAPI, namespace string) *CloudWatchSink {
+	if namespace == "" {
+		namespace = CloudWatchNamespace
+	}
+	return &CloudWatchSink{client: client, namespace: namespace}
+}
+
+// cloudWatchKey identifies the findings of one scanner in one account and region
+type cloudWatchKey struct {
+	accountID string
+	region    string
+	scanner   string
+}
+
+// cloudWatchTotals is the number of findings and their estimated monthly cost in US dollars
+type cloudWatchTotals struct {
+	count       int
+	monthlyCost float64
+}
+
+// Publish sends UnusedResources and EstimatedMonthlySavings for each account, region and scanner
+// with findings, and for each account as a whole. Account totals are sent for every account
+// scanned, including zero, so alarms on them always have data.
+func (s *CloudWatchSink) Publish(ctx context.Context, results []awsinternal.ScanResult, accountIDs []string, scannedAt time.Time) error {
+	totals := make(map[cloudWatchKey]*cloudWatchTotals)
+	accountTotals := make(map[string]*cloudWatchTotals, len(accountIDs))
+	for _, accountID := range accountIDs {
+		accountTotals[accountID] = &cloudWatchTotals{}
+	}
+
+	for _, result := range results {
+		key := cloudWatchKey{accountID: result.AccountID, region: result.Region, scanner: result.ResourceType}
+		if totals[key] == nil {
+			totals[key] = &cloudWatchTotals{}
+		}
+		if accountTotals[result.AccountID] == nil {
+			accountTotals[result.AccountID] = &cloudWatchTotals{}
+		}
+
+		// Savings are always in US dollars so alarm thresholds don't depend on --currency
+		var monthlyCost float64
+		if total := awsinternal.USDCost(result.Cost); total != nil {
+			monthlyCost = total.MonthlyRate
+		}
+		for _, t := range []*cloudWatchTotals{totals[key], accountTotals[result.AccountID]} {
+			t.count++
+			t.monthlyCost += monthlyCost
+		}
+	}
+
+	keys := make([]cloudWatchKey, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].accountID != keys[j].accountID {
+			return keys[i].accountID < keys[j].accountID
+		}
+		if keys[i].region != keys[j].region {
+			return keys[i].region < keys[j].region
+		}
+		return keys[i].scanner < keys[j].scanner
+	})
+	sortedAccountIDs := make([]string, 0, len(accountTotals))
+	for accountID := range accountTotals {
+		sortedAccountIDs = append(sortedAccountIDs, accountID)
+	}
+	sort.Strings(sortedAccountIDs)
+
+	var data []*cloudwatch.MetricDatum
+	addMetrics := func(t *cloudWatchTotals, dimensions []*cloudwatch.Dimension) {
+		data = append(data,
+			&cloudwatch.MetricDatum{
+				MetricName: aws.String("UnusedResources"),
+				Dimensions: dimensions,
+				Timestamp:  aws.Time(scannedAt),
+				Value:      aws.Float64(float64(t.count)),
+				Unit:       aws.String(cloudwatch.StandardUnitCount),
+			},
+			&cloudwatch.MetricDatum{
+				MetricName: aws.String("EstimatedMonthlySavings"),
+				Dimensions: dimensions,
+				Timestamp:  aws.Time(scannedAt),
+				Value:      aws.Float64(t.monthlyCost),
+				Unit:       aws.String(cloudwatch.StandardUnitNone),
+			},
+		)
+	}
+	for _, accountID := range sortedAccountIDs {
+		addMetrics(accountTotals[accountID], []*cloudwatch.Dimension{
+			{Name: aws.String("AccountId"), Value: aws.String(accountID)},
+		})
+	}
+	for _, key := range keys {
+		addMetrics(totals[key], []*cloudwatch.Dimension{
+			{Name: aws.String("AccountId"), Value: aws.String(key.accountID)},
+			{Name: aws.String("Region"), Value: aws.String(key.region)},
+			{Name: aws.String("Scanner"), Value: aws.String(key.scanner)},
+		})
+	}
+
+	for start := 0; start < len(data); start += cloudWatchBatchSize {
+		end := start + cloudWatchBatchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := s.client.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(s.namespace),
+			MetricData: data[start:end],
+		}); err != nil {
+			return fmt.Errorf("failed to publish CloudWatch metrics: %w", err)
+		}
+	}
+	return nil
+}
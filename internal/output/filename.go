@@ -0,0 +1,82 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// filenamePlaceholder matches a {field} placeholder in a filename template
+var filenamePlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// filenameFields lists the placeholders a filename template may use
+var filenameFields = map[string]func(f filenameValues) string{
+	"account_id":   func(f filenameValues) string { return f.accountID },
+	"account_name": func(f filenameValues) string { return f.accountName },
+	"date":         func(f filenameValues) string { return f.time.Format("2006-01-02") },
+	"year":         func(f filenameValues) string { return f.time.Format("2006") },
+	"month":        func(f filenameValues) string { return f.time.Format("01") },
+	"day":          func(f filenameValues) string { return f.time.Format("02") },
+	"time":         func(f filenameValues) string { return f.time.Format("15-04-05") },
+	"timestamp":    func(f filenameValues) string { return f.time.UTC().Format("20060102T150405Z") },
+	"format":       func(f filenameValues) string { return f.format },
+}
+
+// filenameValues holds the values substituted into a filename template
+type filenameValues struct {
+	accountID   string
+	accountName string
+	time        time.Time
+	format      string
+}
+
+// ValidateFilenameTemplate checks that a filename template only uses known placeholders. When
+// perAccount is set each account is written to its own file, so the template must name the account
+// or every account would overwrite the same file.
+func ValidateFilenameTemplate(template string, perAccount bool) error {
+	namesAccount := false
+	for _, match := range filenamePlaceholder.FindAllStringSubmatch(template, -1) {
+		if _, ok := filenameFields[match[1]]; !ok {
+			return fmt.Errorf("unknown filename template field {%s}; valid fields are %s", match[1], strings.Join(FilenameTemplateFields(), ", "))
+		}
+		if match[1] == "account_id" || match[1] == "account_name" {
+			namesAccount = true
+		}
+	}
+	if strings.Contains(template, "..") {
+		return fmt.Errorf("filename template must not contain '..'")
+	}
+	if perAccount && template != "" && !namesAccount {
+		return fmt.Errorf("filename template must include {account_id} or {account_name} so each account is written to its own file")
+	}
+	return nil
+}
+
+// FilenameTemplateFields returns the placeholders a filename template may use, in braces
+func FilenameTemplateFields() []string {
+	return []string{"{account_id}", "{account_name}", "{date}", "{year}", "{month}", "{day}", "{time}", "{timestamp}", "{format}"}
+}
+
+// expandFilenameTemplate fills in a filename template. Values are cleaned so an account name can't
+// add directories; slashes in the template itself are kept so templates can describe a folder layout.
+func expandFilenameTemplate(template string, values filenameValues) string {
+	return filenamePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		field, ok := filenameFields[strings.Trim(placeholder, "{}")]
+		if !ok {
+			return placeholder
+		}
+		return sanitizePathElement(field(values))
+	})
+}
+
+// sanitizePathElement replaces characters that are awkward in file names and S3 keys
+func sanitizePathElement(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '-'
+		}
+		return r
+	}, value)
+}
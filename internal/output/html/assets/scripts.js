@@ -68,6 +68,11 @@ function getResourceTypeData() {
     return { labels, data };
 }
 
+// Get the symbol the report's costs are shown with
+function currencySymbol() {
+    return document.body.dataset.currencySymbol || '$';
+}
+
 // Get data for cost breakdown chart based on period
 function getCostData(period) {
     const table = document.getElementById('combined-costs');
@@ -92,7 +97,7 @@ function getCostData(period) {
         const cells = rows[i].getElementsByTagName('td');
         if (cells.length > columnIndex) {
             const resourceType = cells[0].textContent.trim();
-            const costText = cells[columnIndex].textContent.replace(/[$,]/g, '').trim();
+            const costText = cells[columnIndex].textContent.replace(/[^0-9.]/g, '');
             const cost = parseFloat(costText);
             
             if (!isNaN(cost) && cost > 0) {
@@ -130,7 +135,7 @@ function updateCostChart(period) {
         data: {
             labels: costData.labels,
             datasets: [{
-                label: `${periodLabel} Cost ($)`,
+                label: `${periodLabel} Cost (${currencySymbol().trim()})`,
                 data: costData.data,
                 backgroundColor: 'rgba(60, 52, 156, 0.7)',
                 borderColor: 'rgb(60, 52, 156)',
@@ -149,7 +154,7 @@ function updateCostChart(period) {
                     callbacks: {
                         label: (context) => {
                             const value = context.raw;
-                            return `$${value.toLocaleString(undefined, {
+                            return `${currencySymbol()}${value.toLocaleString(undefined, {
                                 minimumFractionDigits: 2,
                                 maximumFractionDigits: 2
                             })}`;
@@ -165,7 +170,7 @@ function updateCostChart(period) {
                     },
                     ticks: {
                         callback: (value) => {
-                            return '$' + value.toLocaleString(undefined, {
+                            return currencySymbol() + value.toLocaleString(undefined, {
                                 minimumFractionDigits: 2,
                                 maximumFractionDigits: 2
                             });
@@ -251,10 +256,10 @@ function sortTable(table, column) {
     // Add appropriate class to current header
     currentHeader.classList.add(isAscending ? 'sorted-asc' : 'sorted-desc');
 
-    // Sort the rows
+    // Sort the rows, preferring a cell's raw sort value over its display text
     rows.sort((a, b) => {
-        const aValue = a.cells[column].textContent.trim();
-        const bValue = b.cells[column].textContent.trim();
+        const aValue = (a.cells[column].dataset.sortValue ?? a.cells[column].textContent).trim();
+        const bValue = (b.cells[column].dataset.sortValue ?? b.cells[column].textContent).trim();
         
         // Check if the values are numbers (including currency)
         const aNum = parseFloat(aValue.replace(/[^0-9.-]+/g, ''));
@@ -273,46 +278,113 @@ function sortTable(table, column) {
     rows.forEach(row => tbody.appendChild(row));
 }
 
-// Search functionality
+// Search and filter functionality
+const filterControlIds = ['filter-scanner', 'filter-account', 'filter-region', 'filter-confidence', 'filter-tag-key', 'filter-tag-value', 'filter-min-cost'];
+
+// Confidence levels in increasing order, for the minimum confidence filter
+const confidenceRanks = { low: 1, medium: 2, high: 3 };
+
 function initializeSearch() {
     const searchInput = document.getElementById('search-input');
-    const clearButton = document.getElementById('clear-search');
-    
+
     if (searchInput) {
-        searchInput.addEventListener('input', () => {
-            filterTable();
-            clearButton.style.display = searchInput.value ? 'inline' : 'none';
-        });
+        searchInput.addEventListener('input', filterTable);
     }
+
+    filterControlIds.forEach(id => {
+        const control = document.getElementById(id);
+        if (control) {
+            control.addEventListener(control.tagName === 'SELECT' ? 'change' : 'input', filterTable);
+        }
+    });
+
+    filterTable();
+}
+
+// Get the current value of a filter control
+function filterValue(id) {
+    const control = document.getElementById(id);
+    return control ? control.value.trim() : '';
+}
+
+// Check whether a row's tags match the tag key/value filter
+function matchesTagFilter(row, tagKey, tagValue) {
+    if (!tagKey && !tagValue) return true;
+
+    let tags = {};
+    try {
+        tags = JSON.parse(row.dataset.tags || '{}');
+    } catch (e) {
+        return false;
+    }
+
+    const value = tagValue.toLowerCase();
+    const valueMatches = v => !value || String(v).toLowerCase().includes(value);
+
+    if (tagKey) {
+        return Object.prototype.hasOwnProperty.call(tags, tagKey) && valueMatches(tags[tagKey]);
+    }
+    return Object.values(tags).some(valueMatches);
 }
 
 function filterTable() {
-    const input = document.getElementById('search-input');
-    const filter = input.value.toLowerCase();
     const table = document.getElementById('scan-table');
-    const rows = table.getElementsByTagName('tr');
-    
-    for (let i = 1; i < rows.length; i++) {
-        const cells = rows[i].getElementsByTagName('td');
-        let rowVisible = false;
-        
-        for (let cell of cells) {
-            if (cell.textContent.toLowerCase().includes(filter)) {
-                rowVisible = true;
-                break;
-            }
+    if (!table) return;
+
+    const search = filterValue('search-input').toLowerCase();
+    const scanner = filterValue('filter-scanner');
+    const account = filterValue('filter-account');
+    const region = filterValue('filter-region');
+    const minConfidence = confidenceRanks[filterValue('filter-confidence')] || 0;
+    const tagKey = filterValue('filter-tag-key');
+    const tagValue = filterValue('filter-tag-value');
+    const minCostText = filterValue('filter-min-cost');
+    const minCost = minCostText === '' ? NaN : parseFloat(minCostText);
+
+    const rows = table.querySelectorAll('tbody tr');
+    let visible = 0;
+
+    rows.forEach(row => {
+        const cells = row.getElementsByTagName('td');
+        // Free-text search only covers the Name and Resource ID columns
+        const name = cells[3] ? cells[3].textContent.toLowerCase() : '';
+        const resourceId = cells[4] ? cells[4].textContent.toLowerCase() : '';
+
+        let rowVisible = !search || name.includes(search) || resourceId.includes(search);
+        rowVisible = rowVisible && (!scanner || row.dataset.scanner === scanner);
+        rowVisible = rowVisible && (!account || row.dataset.account === account);
+        rowVisible = rowVisible && (!region || row.dataset.region === region);
+        rowVisible = rowVisible && (confidenceRanks[row.dataset.confidence] || 0) >= minConfidence;
+        rowVisible = rowVisible && matchesTagFilter(row, tagKey, tagValue);
+
+        if (rowVisible && !isNaN(minCost)) {
+            // Findings without a cost estimate never meet a minimum cost
+            const cost = parseFloat(row.dataset.cost);
+            rowVisible = !isNaN(cost) && cost >= minCost;
         }
-        
-        rows[i].style.display = rowVisible ? '' : 'none';
+
+        row.style.display = rowVisible ? '' : 'none';
+        if (rowVisible) visible++;
+    });
+
+    const active = search || filterControlIds.some(id => filterValue(id) !== '');
+    const clearButton = document.getElementById('clear-search');
+    if (clearButton) {
+        clearButton.style.display = active ? 'inline' : 'none';
+    }
+
+    const count = document.getElementById('filter-count');
+    if (count) {
+        count.textContent = `Showing ${visible} of ${rows.length}`;
     }
 }
 
 function clearSearch() {
-    const searchInput = document.getElementById('search-input');
-    const clearButton = document.getElementById('clear-search');
-    
-    searchInput.value = '';
-    clearButton.style.display = 'none';
+    document.getElementById('search-input').value = '';
+    filterControlIds.forEach(id => {
+        const control = document.getElementById(id);
+        if (control) control.value = '';
+    });
     filterTable();
 }
 
@@ -321,17 +393,15 @@ function scrollToUnusedResources(event, resourceType) {
     event.preventDefault();
     
     const section = document.getElementById('unused-resources');
-    const searchInput = document.getElementById('search-input');
+    const scannerFilter = document.getElementById('filter-scanner');
     
-    if (section && searchInput) {
+    if (section && scannerFilter) {
         section.scrollIntoView({ behavior: 'smooth', block: 'start' });
         
-        // After scrolling, set the search filter
+        // After scrolling, filter the table to the resource type
         setTimeout(() => {
-            searchInput.value = resourceType;
-            searchInput.focus();
+            scannerFilter.value = resourceType;
             filterTable();
-            document.getElementById('clear-search').style.display = 'inline';
         }, 500);
     }
 }
@@ -405,8 +475,8 @@ function exportToCSV() {
     });
     csvContent += headers.join(',') + '\n';
 
-    // Get data rows
-    rows.slice(1).forEach(row => {
+    // Get data rows, leaving out any hidden by the filters
+    rows.slice(1).filter(row => row.style.display !== 'none').forEach(row => {
         const cells = Array.from(row.querySelectorAll('td'));
         const rowData = cells.map((cell, index) => {
             // For the Actions column (last column), get the details JSON
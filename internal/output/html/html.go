@@ -3,12 +3,16 @@ package html
 import (
 	"bytes"
 	"embed"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,29 +27,50 @@ var content embed.FS
 type TemplateData struct {
 	AccountsAndRegions map[string][]string
 	AccountNames       map[string]string
+	Regions            []string // Regions with findings, for the findings table's region filter
+	TagKeys            []string // Tag keys of findings, for the findings table's tag filter
 	ResourceTypeCounts map[string]int
 	CombinedCosts      map[string]map[string]interface{}
 	ScanMetrics        ScanMetrics
 	Resources          []Resource
+	Leaderboard        []LeaderboardEntry
+	Errors             []aws.ScanError
+	LogoURL            template.URL
+	CurrencySymbol     string
 	Styles             template.CSS
 	Scripts            template.JS
 }
 
 // ScanMetrics represents metrics about the scan operation
 type ScanMetrics struct {
-	TotalScans         int       `json:"total_scans"`
-	CompletedScans     int64     `json:"completed_scans"`
-	FailedScans        int64     `json:"failed_scans"`
-	AvgScansPerSecond  float64   `json:"avg_scans_per_second"`
-	TotalRunTime       float64   `json:"total_run_time"`
-	CompletedAt        time.Time `json:"completed_at"`
-	PeakWorkers        int64     `json:"peak_workers"`
-	MaxWorkers         int       `json:"max_workers"`
-	WorkerUtilization  float64   `json:"worker_utilization"`
-	AvgExecutionTimeMs int64     `json:"avg_execution_time_ms"`
-	TasksPerSecond     float64   `json:"tasks_per_second"`
+	TotalScans         int                    `json:"total_scans"`
+	CompletedScans     int64                  `json:"completed_scans"`
+	FailedScans        int64                  `json:"failed_scans"`
+	AvgScansPerSecond  float64                `json:"avg_scans_per_second"`
+	TotalRunTime       float64                `json:"total_run_time"`
+	CompletedAt        time.Time              `json:"completed_at"`
+	PeakWorkers        int64                  `json:"peak_workers"`
+	MaxWorkers         int                    `json:"max_workers"`
+	WorkerUtilization  float64                `json:"worker_utilization"`
+	AvgExecutionTimeMs int64                  `json:"avg_execution_time_ms"`
+	TasksPerSecond     float64                `json:"tasks_per_second"`
+	ThrottleEvents     int64                  `json:"throttle_events"`     // AWS requests that were throttled
+	ThrottleReductions int64                  `json:"throttle_reductions"` // Times worker concurrency was reduced due to throttling
+	MetricCacheHits    int64                  `json:"metric_cache_hits"`   // CloudWatch requests served from the shared metric cache
+	MetricCacheMisses  int64                  `json:"metric_cache_misses"` // CloudWatch requests sent to AWS
+	Version            string                 `json:"version"`             // CloudSift build that produced the report
+	ReportTitle        string                 `json:"report_title"`        // Heading shown at the top of the report
+	ReportLogo         string                 `json:"report_logo"`         // Path or http(s) URL of an image shown beside the title
+	Truncated          []aws.TruncatedResults `json:"truncated,omitempty"` // Scanner tasks that hit --max-results-per-scanner
+	Currency           string                 `json:"currency"`            // Currency the report's costs are in, USD when empty
 }
 
+// DefaultReportTitle is the report heading used when no title is configured
+const DefaultReportTitle = "CloudSift Scan Report"
+
+// leaderboardSize is how many findings the savings leaderboard ranks
+const leaderboardSize = 10
+
 // Resource represents a single resource in the scan results
 type Resource struct {
 	AccountID    string
@@ -55,11 +80,27 @@ type Resource struct {
 	Name         string
 	ResourceID   string
 	Reason       template.HTML
+	Confidence   string
 	DetailsJSON  template.JS
+	MonthlyCost  float64
+	HasCost      bool   // Whether the finding has an estimated cost, as opposed to costing nothing
+	TagsJSON     string // Tags as a JSON object, used by the findings table's tag filter
 }
 
-// WriteHTML writes scan results to an HTML file
-func WriteHTML(results []aws.ScanResult, outputPath string, metrics ScanMetrics) error {
+// LeaderboardEntry is a single finding ranked by its estimated monthly cost
+type LeaderboardEntry struct {
+	Rank         int
+	AccountID    string
+	AccountName  string
+	Region       string
+	ResourceType string
+	Name         string
+	ResourceID   string
+	MonthlyCost  float64
+}
+
+// WriteHTML writes scan results and any scanner task errors to an HTML file
+func WriteHTML(results []aws.ScanResult, outputPath string, metrics ScanMetrics, scanErrors []aws.ScanError) error {
 	// Read template files
 	tmpl, err := template.New("scan_report.html").Funcs(template.FuncMap{
 		"join": strings.Join,
@@ -78,6 +119,7 @@ func WriteHTML(results []aws.ScanResult, outputPath string, metrics ScanMetrics)
 		"formatYearlyCost":   formatYearlyCost,
 		"formatLifetimeCost": formatLifetimeCost,
 		"formatDuration":     formatDuration,
+		"confidenceRank":     aws.ConfidenceRank,
 		"add": func(a, b interface{}) float64 {
 			// Convert both values to float64
 			var aFloat, bFloat float64
@@ -127,6 +169,28 @@ func WriteHTML(results []aws.ScanResult, outputPath string, metrics ScanMetrics)
 	data.ScanMetrics.WorkerUtilization = metrics.WorkerUtilization
 	data.ScanMetrics.AvgExecutionTimeMs = metrics.AvgExecutionTimeMs
 	data.ScanMetrics.TasksPerSecond = metrics.TasksPerSecond
+	data.ScanMetrics.ThrottleEvents = metrics.ThrottleEvents
+	data.ScanMetrics.ThrottleReductions = metrics.ThrottleReductions
+	data.ScanMetrics.MetricCacheHits = metrics.MetricCacheHits
+	data.ScanMetrics.MetricCacheMisses = metrics.MetricCacheMisses
+	data.ScanMetrics.Version = metrics.Version
+	data.ScanMetrics.ReportTitle = metrics.ReportTitle
+	if data.ScanMetrics.ReportTitle == "" {
+		data.ScanMetrics.ReportTitle = DefaultReportTitle
+	}
+	data.ScanMetrics.ReportLogo = metrics.ReportLogo
+	if metrics.ReportLogo != "" {
+		logoURL, err := LogoURL(metrics.ReportLogo)
+		if err != nil {
+			return err
+		}
+		data.LogoURL = logoURL
+	}
+	data.Errors = scanErrors
+	data.CurrencySymbol = aws.CurrencySymbol(aws.BaseCurrency)
+	if metrics.Currency != "" {
+		data.CurrencySymbol = aws.CurrencySymbol(metrics.Currency)
+	}
 	data.Styles = template.CSS(styles)
 	data.Scripts = template.JS(scripts)
 
@@ -171,19 +235,15 @@ func processResults(results []aws.ScanResult) TemplateData {
 		},
 	}
 
+	regions := make(map[string]bool)
+	tagKeys := make(map[string]bool)
+
 	// Process each result
 	for _, result := range results {
 		// Extract account ID and region
 		accountID := result.AccountID
 		accountName := result.AccountName
-		region := ""
-
-		// Try to get region from details
-		if reg, ok := result.Details["Region"].(string); ok {
-			region = reg
-		} else if reg, ok := result.Details["region"].(string); ok {
-			region = reg
-		}
+		region := result.Region
 
 		// Update account mappings
 		if accountID != "" {
@@ -261,7 +321,17 @@ func processResults(results []aws.ScanResult) TemplateData {
 			}
 		}
 
-		detailsJSON, err := json.Marshal(result.Details)
+		// Show the ARN alongside the details without modifying the result's own map
+		details := result.Details
+		if result.ARN != "" {
+			details = make(map[string]interface{}, len(result.Details)+1)
+			for key, value := range result.Details {
+				details[key] = value
+			}
+			details["arn"] = result.ARN
+		}
+
+		detailsJSON, err := json.Marshal(details)
 		if err != nil {
 			logging.Debug("Error marshaling details to JSON", map[string]interface{}{
 				"error":   err,
@@ -270,7 +340,22 @@ func processResults(results []aws.ScanResult) TemplateData {
 			detailsJSON = []byte("{}")
 		}
 
-		data.Resources = append(data.Resources, Resource{
+		tags := result.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		for key := range tags {
+			tagKeys[key] = true
+		}
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			tagsJSON = []byte("{}")
+		}
+		if region != "" {
+			regions[region] = true
+		}
+
+		resource := Resource{
 			AccountID:    accountID,
 			AccountName:  accountName,
 			Region:       region,
@@ -278,13 +363,89 @@ func processResults(results []aws.ScanResult) TemplateData {
 			Name:         resourceName,
 			ResourceID:   resourceID,
 			Reason:       template.HTML(strings.ReplaceAll(result.Reason, ".", ".<br>")),
+			Confidence:   result.ConfidenceLevel(),
 			DetailsJSON:  template.JS(detailsJSON),
-		})
+			TagsJSON:     string(tagsJSON),
+		}
+		if total, ok := result.Cost["total"].(*aws.CostBreakdown); ok && total != nil {
+			resource.MonthlyCost = total.MonthlyRate
+			resource.HasCost = true
+		}
+		data.Resources = append(data.Resources, resource)
 	}
 
+	data.Regions = sortedKeys(regions)
+	data.TagKeys = sortedKeys(tagKeys)
+	data.Leaderboard = buildLeaderboard(data.Resources, results)
+
 	return data
 }
 
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildLeaderboard ranks the findings with the highest estimated monthly cost. resources holds the
+// display fields of each result, in the same order as results.
+func buildLeaderboard(resources []Resource, results []aws.ScanResult) []LeaderboardEntry {
+	var entries []LeaderboardEntry
+	for i, result := range results {
+		total, ok := result.Cost["total"].(*aws.CostBreakdown)
+		if !ok || total == nil || total.MonthlyRate <= 0 {
+			continue
+		}
+		resource := resources[i]
+		entries = append(entries, LeaderboardEntry{
+			AccountID:    resource.AccountID,
+			AccountName:  resource.AccountName,
+			Region:       resource.Region,
+			ResourceType: resource.ResourceType,
+			Name:         resource.Name,
+			ResourceID:   resource.ResourceID,
+			MonthlyCost:  total.MonthlyRate,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].MonthlyCost > entries[j].MonthlyCost
+	})
+	if len(entries) > leaderboardSize {
+		entries = entries[:leaderboardSize]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// LogoURL returns the image source for a report logo. http(s) URLs are used as is; local files are
+// embedded as a data URI so the report stays a single self-contained file.
+func LogoURL(logo string) (template.URL, error) {
+	if strings.HasPrefix(logo, "https://") || strings.HasPrefix(logo, "http://") {
+		return template.URL(logo), nil
+	}
+
+	image, err := os.ReadFile(logo)
+	if err != nil {
+		return "", fmt.Errorf("error reading report logo: %v", err)
+	}
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(logo)))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(image)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", fmt.Errorf("report logo %s is not an image (detected %s)", logo, mimeType)
+	}
+
+	return template.URL("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(image)), nil
+}
+
 // NOTE: The following functions are currently unused but maintained for future use
 // in the cost breakdown calculation system. They will be used when we implement
 // the detailed cost breakdown view in the HTML output.
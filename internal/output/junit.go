@@ -0,0 +1,165 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	awsinternal "cloudsift/internal/aws"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the findings of one scanner
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a single flagged resource, or a scanner task that failed
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+// junitMessage is the failure or error attached to a test case
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes findings as a JUnit XML report. Each scanner becomes a test suite and each
+// flagged resource a failing test case; scanner tasks that failed are reported as errors.
+func WriteJUnitReport(w io.Writer, results []awsinternal.ScanResult, scanErrors []awsinternal.ScanError, durationSeconds float64) error {
+	suites := make(map[string]*junitTestSuite)
+	suite := func(name string) *junitTestSuite {
+		if s, ok := suites[name]; ok {
+			return s
+		}
+		s := &junitTestSuite{Name: name}
+		suites[name] = s
+		return s
+	}
+
+	for _, result := range results {
+		accountID := detailString(result.Details, "account_id")
+		region := result.Region
+
+		name := result.ResourceID
+		if result.ResourceName != "" && result.ResourceName != result.ResourceID {
+			name = fmt.Sprintf("%s (%s)", result.ResourceName, result.ResourceID)
+		}
+
+		var monthly float64
+		symbol := awsinternal.CurrencySymbol(awsinternal.BaseCurrency)
+		if total, ok := result.Cost["total"].(*awsinternal.CostBreakdown); ok && total != nil {
+			monthly = total.MonthlyRate
+			symbol = total.CurrencySymbol()
+		}
+
+		var body strings.Builder
+		fmt.Fprintf(&body, "Account: %s\nRegion: %s\nResource: %s\n", accountID, region, name)
+		if result.ARN != "" {
+			fmt.Fprintf(&body, "ARN: %s\n", result.ARN)
+		}
+		fmt.Fprintf(&body, "Estimated monthly cost: %s%.2f\n", symbol, monthly)
+		fmt.Fprintf(&body, "Confidence: %s\n", result.ConfidenceLevel())
+
+		s := suite(result.ResourceType)
+		s.Tests++
+		s.Failures++
+		s.TestCases = append(s.TestCases, junitTestCase{
+			Name:      name,
+			ClassName: strings.Trim(accountID+"."+region, "."),
+			Failure: &junitMessage{
+				Message: fmt.Sprintf("%s (estimated %s%.2f/month)", result.Reason, symbol, monthly),
+				Type:    result.ResourceType,
+				Body:    body.String(),
+			},
+		})
+	}
+
+	for _, scanError := range scanErrors {
+		s := suite(scanError.Scanner)
+		s.Tests++
+		s.Errors++
+		s.TestCases = append(s.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s scan", scanError.Scanner),
+			ClassName: strings.Trim(scanError.AccountID+"."+scanError.Region, "."),
+			Error: &junitMessage{
+				Message: scanError.Error,
+				Type:    "ScanError",
+			},
+		})
+	}
+
+	report := junitTestSuites{Name: "cloudsift", Time: durationSeconds}
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := suites[name]
+		sort.SliceStable(s.TestCases, func(i, j int) bool {
+			if s.TestCases[i].ClassName != s.TestCases[j].ClassName {
+				return s.TestCases[i].ClassName < s.TestCases[j].ClassName
+			}
+			return s.TestCases[i].Name < s.TestCases[j].Name
+		})
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		report.Errors += s.Errors
+		report.Suites = append(report.Suites, *s)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJUnit writes findings as a JUnit XML report to path
+func WriteJUnit(results []awsinternal.ScanResult, path string, scanErrors []awsinternal.ScanError, durationSeconds float64) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	defer f.Close()
+
+	if err := WriteJUnitReport(f, results, scanErrors, durationSeconds); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,818 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	awsinternal "cloudsift/internal/aws"
+)
+
+// RemediationAction is a suggested AWS CLI fix for a single finding. CloudSift never runs these
+// commands itself; they are written out for a person to review and run.
+type RemediationAction struct {
+	AccountID    string   `json:"account_id"`
+	AccountName  string   `json:"account_name"`
+	Profile      string   `json:"profile,omitempty"`
+	Region       string   `json:"region"`
+	ResourceType string   `json:"resource_type"`
+	ResourceID   string   `json:"resource_id"`
+	ResourceName string   `json:"resource_name"`
+	ARN          string   `json:"arn,omitempty"`
+	Description  string   `json:"description"`
+	Confidence   string   `json:"confidence"` // How sure the scanner is that the resource is waste
+	Commands     []string `json:"commands"`
+	Dangerous    bool     `json:"dangerous"` // Deletes or otherwise can't be undone
+}
+
+// remediation describes the fix for a finding before region and profile are applied
+type remediation struct {
+	description string
+	commands    [][]string // AWS CLI arguments, without the leading "aws"
+	dangerous   bool
+}
+
+// remediationBuilders maps scanner labels to a function suggesting the fix for one of their findings
+var remediationBuilders = map[string]func(result awsinternal.ScanResult) remediation{
+	"API Gateway APIs": func(r awsinternal.ScanResult) remediation {
+		apiID := detailString(r.Details, "api_id")
+		// A used API with an idle cached stage only needs its cache turned off
+		if detailString(r.Details, "finding_type") == "idle_cache" {
+			return remediation{
+				description: "Disable the stage's cache cluster",
+				commands: [][]string{{"apigateway", "update-stage", "--rest-api-id", apiID, "--stage-name", detailString(r.Details, "stage_name"),
+					"--patch-operations", "op=replace,path=/cacheClusterEnabled,value=false"}},
+			}
+		}
+		if detailString(r.Details, "api_type") == "HTTP" {
+			return remediation{
+				description: "Delete the HTTP API",
+				commands:    [][]string{{"apigatewayv2", "delete-api", "--api-id", apiID}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Delete the REST API",
+			commands:    [][]string{{"apigateway", "delete-rest-api", "--rest-api-id", apiID}},
+			dangerous:   true,
+		}
+	},
+	"Amazon MQ Brokers": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the broker along with its queues and messages",
+			commands:    [][]string{{"mq", "delete-broker", "--broker-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"AMIs": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Deregister the AMI, then delete its snapshots",
+			commands:    [][]string{{"ec2", "deregister-image", "--image-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"App Runner Services": func(r awsinternal.ScanResult) remediation {
+		// Copilot keeps its own stack for the service, so it should be removed through Copilot
+		if app := detailString(r.Details, "copilot_application"); app != "" {
+			return remediation{
+				description: "Delete the service with copilot svc delete --app " + app + " --name " +
+					detailString(r.Details, "copilot_service") + " so Copilot's stack is removed too",
+				dangerous: true,
+			}
+		}
+		if detailString(r.Details, "status") == "PAUSED" {
+			return remediation{
+				description: "Delete the paused service",
+				commands:    [][]string{{"apprunner", "delete-service", "--service-arn", r.ARN}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Pause the service so its instances are no longer billed, or delete it if it is no longer needed",
+			commands:    [][]string{{"apprunner", "pause-service", "--service-arn", r.ARN}},
+		}
+	},
+	"Aurora Serverless Clusters": func(r awsinternal.ScanResult) remediation {
+		// v1 clusters can pause themselves when idle; v2 clusters can't, so an unused one is stopped
+		if detailString(r.Details, "serverless_version") == "v1" {
+			return remediation{
+				description: "Enable auto-pause so the cluster scales to zero ACUs when idle",
+				commands: [][]string{{"rds", "modify-db-cluster", "--db-cluster-identifier", r.ResourceID,
+					"--scaling-configuration", "AutoPause=true,SecondsUntilAutoPause=300", "--apply-immediately"}},
+			}
+		}
+		return remediation{
+			description: "Stop the cluster",
+			commands:    [][]string{{"rds", "stop-db-cluster", "--db-cluster-identifier", r.ResourceID}},
+		}
+	},
+	"AWS Backup Recovery Points": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the recovery point; its source resource no longer exists",
+			commands: [][]string{{"backup", "delete-recovery-point", "--backup-vault-name", detailString(r.Details, "backup_vault"),
+				"--recovery-point-arn", r.ResourceID}},
+			dangerous: true,
+		}
+	},
+	"Batch Compute Environments": func(r awsinternal.ScanResult) remediation {
+		// An environment that still runs jobs only needs its floor removed
+		if detailString(r.Details, "finding_type") == "vcpu_floor" {
+			return remediation{
+				description: "Set the minimum vCPUs to 0 so the environment scales to zero between jobs",
+				commands: [][]string{{"batch", "update-compute-environment", "--compute-environment", r.ResourceID,
+					"--compute-resources", "minvCpus=0"}},
+			}
+		}
+		var commands [][]string
+		if detailString(r.Details, "state") != "DISABLED" {
+			commands = append(commands, []string{"batch", "update-compute-environment", "--compute-environment", r.ResourceID, "--state", "DISABLED"})
+		}
+		commands = append(commands, []string{"batch", "delete-compute-environment", "--compute-environment", r.ResourceID})
+		return remediation{
+			description: "Disable and delete the compute environment (remove it from its job queues first)",
+			commands:    commands,
+			dangerous:   true,
+		}
+	},
+	"Beanstalk Application Versions": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the application version and its source bundle",
+			commands: [][]string{{"elasticbeanstalk", "delete-application-version",
+				"--application-name", detailString(r.Details, "application_name"),
+				"--version-label", detailString(r.Details, "version_label"),
+				"--delete-source-bundle"}},
+			dangerous: true,
+		}
+	},
+	"Capacity Reservations": func(r awsinternal.ScanResult) remediation {
+		// Instances still using the reservation keep their capacity when it shrinks to fit them
+		used := detailNumber(r.Details, "total_instance_count") - detailNumber(r.Details, "unused_instance_count")
+		if used > 0 {
+			return remediation{
+				description: fmt.Sprintf("Reduce the reservation to the %d instances that use it", used),
+				commands: [][]string{{"ec2", "modify-capacity-reservation", "--capacity-reservation-id", r.ResourceID,
+					"--instance-count", fmt.Sprintf("%d", used)}},
+			}
+		}
+		return remediation{
+			description: "Cancel the capacity reservation",
+			commands:    [][]string{{"ec2", "cancel-capacity-reservation", "--capacity-reservation-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"CloudFormation Stacks": func(r awsinternal.ScanResult) remediation {
+		if detailString(r.Details, "finding_type") == "drifted" {
+			return remediation{
+				description: "Review the drifted resources, then update the template to match or the resources to match the template",
+				commands:    [][]string{{"cloudformation", "describe-stack-resource-drifts", "--stack-name", r.ResourceName}},
+			}
+		}
+		// A failed update rollback leaves a working stack behind once the rollback is finished
+		if detailString(r.Details, "stack_status") == "UPDATE_ROLLBACK_FAILED" {
+			return remediation{
+				description: "Fix the resources that blocked the rollback, then continue it",
+				commands:    [][]string{{"cloudformation", "continue-update-rollback", "--stack-name", r.ResourceName}},
+			}
+		}
+		description := "Delete the stack along with the resources it still owns"
+		if detailString(r.Details, "stack_status") == "DELETE_FAILED" {
+			description = "Retry deleting the stack; resources that can't be deleted can be kept with --retain-resources"
+		}
+		return remediation{
+			description: description,
+			commands:    [][]string{{"cloudformation", "delete-stack", "--stack-name", r.ResourceName}},
+			dangerous:   true,
+		}
+	},
+	"CloudHSM Clusters": func(r awsinternal.ScanResult) remediation {
+		// A cluster can only be deleted once its HSMs are gone
+		var commands [][]string
+		for _, hsmID := range detailStrings(r.Details, "hsm_ids") {
+			commands = append(commands, []string{"cloudhsmv2", "delete-hsm", "--cluster-id", r.ResourceID, "--hsm-id", hsmID})
+		}
+		commands = append(commands, []string{"cloudhsmv2", "delete-cluster", "--cluster-id", r.ResourceID})
+		return remediation{
+			description: "Delete the cluster's HSMs, then the cluster; CloudHSM backs up the last HSM deleted so the cluster can be restored",
+			commands:    commands,
+			dangerous:   true,
+		}
+	},
+	"DataSync Tasks": func(r awsinternal.ScanResult) remediation {
+		description := "Delete the task; its locations and agents can be deleted separately once no other task uses them"
+		if instanceIDs := detailStrings(r.Details, "agent_instance_ids"); len(instanceIDs) > 0 {
+			description += fmt.Sprintf(", and agent instances %s terminated", strings.Join(instanceIDs, ", "))
+		}
+		return remediation{
+			description: description,
+			commands:    [][]string{{"datasync", "delete-task", "--task-arn", r.ARN}},
+			dangerous:   true,
+		}
+	},
+	"DocumentDB Clusters": func(r awsinternal.ScanResult) remediation {
+		return dbClusterRemediation("docdb", r)
+	},
+	"Neptune Clusters": func(r awsinternal.ScanResult) remediation {
+		return dbClusterRemediation("neptune", r)
+	},
+	"DynamoDB Tables": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the table",
+			commands:    [][]string{{"dynamodb", "delete-table", "--table-name", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"EBS Snapshots": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the snapshot",
+			commands:    [][]string{{"ec2", "delete-snapshot", "--snapshot-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"EBS Volumes": func(r awsinternal.ScanResult) remediation {
+		// Over-provisioned volumes are still in use, so they are modified rather than deleted
+		if recommendedType := detailString(r.Details, "recommended_volume_type"); recommendedType != "" {
+			args := []string{"ec2", "modify-volume", "--volume-id", r.ResourceID, "--volume-type", recommendedType}
+			if iops := detailNumber(r.Details, "recommended_iops"); iops > 0 {
+				args = append(args, "--iops", fmt.Sprintf("%d", iops))
+			}
+			return remediation{
+				description: fmt.Sprintf("Change the volume to %s with the recommended IOPS", recommendedType),
+				commands:    [][]string{args},
+			}
+		}
+		// Volumes on stopped instances have to be detached before they can be deleted
+		if instanceID := detailString(r.Details, "attached_instance_id"); instanceID != "" {
+			return remediation{
+				description: fmt.Sprintf("Snapshot the volume, detach it from stopped instance %s, then delete it", instanceID),
+				commands: [][]string{
+					{"ec2", "create-snapshot", "--volume-id", r.ResourceID, "--description", "Backup before cloudsift remediation"},
+					{"ec2", "detach-volume", "--volume-id", r.ResourceID, "--instance-id", instanceID},
+					{"ec2", "delete-volume", "--volume-id", r.ResourceID},
+				},
+				dangerous: true,
+			}
+		}
+		return remediation{
+			description: "Snapshot the volume for safekeeping, then delete it",
+			commands: [][]string{
+				{"ec2", "create-snapshot", "--volume-id", r.ResourceID, "--description", "Backup before cloudsift remediation"},
+				{"ec2", "delete-volume", "--volume-id", r.ResourceID},
+			},
+			dangerous: true,
+		}
+	},
+	"EC2 Instances": func(r awsinternal.ScanResult) remediation {
+		// Rightsizing keeps the instance, so it is resized with a stop and start
+		if detailString(r.Details, "finding_type") == "rightsize" {
+			return remediation{
+				description: "Resize the instance to " + detailString(r.Details, "recommended_instance_type") + "; it is unavailable while stopped",
+				commands: [][]string{
+					{"ec2", "stop-instances", "--instance-ids", r.ResourceID},
+					{"ec2", "wait", "instance-stopped", "--instance-ids", r.ResourceID},
+					{"ec2", "modify-instance-attribute", "--instance-id", r.ResourceID,
+						"--instance-type", "Value=" + detailString(r.Details, "recommended_instance_type")},
+					{"ec2", "start-instances", "--instance-ids", r.ResourceID},
+				},
+			}
+		}
+		if detailString(r.Details, "state") == "stopped" {
+			return remediation{
+				description: "Terminate the stopped instance",
+				commands:    [][]string{{"ec2", "terminate-instances", "--instance-ids", r.ResourceID}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Stop the idle instance",
+			commands:    [][]string{{"ec2", "stop-instances", "--instance-ids", r.ResourceID}},
+		}
+	},
+	"ECR Images": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the image",
+			commands: [][]string{{"ecr", "batch-delete-image", "--repository-name", detailString(r.Details, "repository_name"),
+				"--image-ids", "imageDigest=" + detailString(r.Details, "image_digest")}},
+			dangerous: true,
+		}
+	},
+	"ECS Services": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Scale the service to zero tasks; delete it if it is no longer needed",
+			commands: [][]string{{"ecs", "update-service", "--cluster", detailString(r.Details, "cluster_name"),
+				"--service", r.ResourceName, "--desired-count", "0"}},
+		}
+	},
+	"EFS File Systems": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the file system once its mount targets are removed",
+			commands:    [][]string{{"efs", "delete-file-system", "--file-system-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"Elastic IPs": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Release the address",
+			commands:    [][]string{{"ec2", "release-address", "--allocation-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"EventBridge Rules": func(r awsinternal.ScanResult) remediation {
+		eventBus := detailString(r.Details, "event_bus_name")
+		removeTargets := []string{"events", "remove-targets", "--rule", r.ResourceName, "--event-bus-name", eventBus, "--ids"}
+		if detailString(r.Details, "finding_type") == "missing_targets" {
+			return remediation{
+				description: "Remove the targets that no longer exist from the rule",
+				commands:    [][]string{append(removeTargets, detailStrings(r.Details, "missing_target_ids")...)},
+			}
+		}
+		// A rule's targets have to be removed before it can be deleted
+		var commands [][]string
+		if targetIDs := detailStrings(r.Details, "target_ids"); len(targetIDs) > 0 {
+			commands = append(commands, append(removeTargets, targetIDs...))
+		}
+		commands = append(commands, []string{"events", "delete-rule", "--name", r.ResourceName, "--event-bus-name", eventBus})
+		return remediation{
+			description: "Delete the rule",
+			commands:    commands,
+			dangerous:   true,
+		}
+	},
+	"IAM Access Keys": func(r awsinternal.ScanResult) remediation {
+		// Deactivating a key can be undone, so these are left uncommented
+		var commands [][]string
+		accessKeys, _ := r.Details["AccessKeys"].([]map[string]interface{})
+		for _, key := range accessKeys {
+			if id, ok := key["AccessKeyId"].(string); ok && id != "" {
+				commands = append(commands, []string{"iam", "update-access-key", "--user-name", r.ResourceName, "--access-key-id", id, "--status", "Inactive"})
+			}
+		}
+		return remediation{
+			description: "Deactivate the user's unused access keys",
+			commands:    commands,
+		}
+	},
+	"IAM Roles": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the role once its policies and instance profiles are detached",
+			commands:    [][]string{{"iam", "delete-role", "--role-name", r.ResourceName}},
+			dangerous:   true,
+		}
+	},
+	"IAM Users": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the user once its keys, login profile and policies are removed",
+			commands:    [][]string{{"iam", "delete-user", "--user-name", r.ResourceName}},
+			dangerous:   true,
+		}
+	},
+	"Kinesis Streams": func(r awsinternal.ScanResult) remediation {
+		// Provisioned streams can move to on-demand capacity without losing data
+		if detailString(r.Details, "stream_mode") == "PROVISIONED" && r.ARN != "" {
+			return remediation{
+				description: "Switch the stream to on-demand capacity, or delete it if it is no longer needed",
+				commands:    [][]string{{"kinesis", "update-stream-mode", "--stream-arn", r.ARN, "--stream-mode-details", "StreamMode=ON_DEMAND"}},
+			}
+		}
+		return remediation{
+			description: "Delete the stream",
+			commands:    [][]string{{"kinesis", "delete-stream", "--stream-name", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"Lambda Memory": func(r awsinternal.ScanResult) remediation {
+		memory := strconv.FormatInt(detailNumber(r.Details, "recommended_memory_mb"), 10)
+		return remediation{
+			description: "Lower the function's memory to " + memory + " MB and watch its duration",
+			commands:    [][]string{{"lambda", "update-function-configuration", "--function-name", r.ResourceID, "--memory-size", memory}},
+		}
+	},
+	"Lightsail Resources": func(r awsinternal.ScanResult) remediation {
+		switch detailString(r.Details, "lightsail_resource_type") {
+		case "static_ip":
+			return remediation{
+				description: "Release the static IP",
+				commands:    [][]string{{"lightsail", "release-static-ip", "--static-ip-name", r.ResourceID}},
+				dangerous:   true,
+			}
+		case "disk":
+			return remediation{
+				description: "Snapshot the disk if its data is needed, then delete it",
+				commands:    [][]string{{"lightsail", "delete-disk", "--disk-name", r.ResourceID}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Snapshot the instance if it may be needed again, then delete it",
+			commands: [][]string{
+				{"lightsail", "create-instance-snapshot", "--instance-name", r.ResourceID, "--instance-snapshot-name", r.ResourceID + "-final"},
+				{"lightsail", "delete-instance", "--instance-name", r.ResourceID},
+			},
+			dangerous: true,
+		}
+	},
+	"Load Balancers": func(r awsinternal.ScanResult) remediation {
+		if detailString(r.Details, "type") == "classic" {
+			return remediation{
+				description: "Delete the classic load balancer",
+				commands:    [][]string{{"elb", "delete-load-balancer", "--load-balancer-name", r.ResourceID}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Delete the load balancer",
+			commands:    [][]string{{"elbv2", "delete-load-balancer", "--load-balancer-arn", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"MSK Clusters": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the cluster",
+			commands:    [][]string{{"kafka", "delete-cluster", "--cluster-arn", r.ARN}},
+			dangerous:   true,
+		}
+	},
+	"NAT Gateways": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the NAT gateway",
+			commands:    [][]string{{"ec2", "delete-nat-gateway", "--nat-gateway-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"Network Waste": func(r awsinternal.ScanResult) remediation {
+		switch detailString(r.Details, "waste_type") {
+		case "nat_gateway":
+			return remediation{
+				description: "Delete the NAT gateway",
+				commands:    [][]string{{"ec2", "delete-nat-gateway", "--nat-gateway-id", r.ResourceID}},
+				dangerous:   true,
+			}
+		case "elastic_ip":
+			return remediation{
+				description: "Release the address",
+				commands:    [][]string{{"ec2", "release-address", "--allocation-id", r.ResourceID}},
+				dangerous:   true,
+			}
+		case "vpc_endpoint":
+			return remediation{
+				description: "Delete the VPC endpoint",
+				commands:    [][]string{{"ec2", "delete-vpc-endpoints", "--vpc-endpoint-ids", r.ResourceID}},
+				dangerous:   true,
+			}
+		}
+		return remediation{
+			description: "Delete the transit gateway once its attachments are removed",
+			commands:    [][]string{{"ec2", "delete-transit-gateway", "--transit-gateway-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"OpenSearch Domains": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the domain",
+			commands:    [][]string{{"opensearch", "delete-domain", "--domain-name", r.ResourceName}},
+			dangerous:   true,
+		}
+	},
+	"RDS Instances": func(r awsinternal.ScanResult) remediation {
+		// Stopping keeps the data and can be undone; RDS starts stopped instances again after 7 days
+		return remediation{
+			description: "Stop the idle instance",
+			commands:    [][]string{{"rds", "stop-db-instance", "--db-instance-identifier", r.ResourceName}},
+		}
+	},
+	"RI and Savings Plans": func(r awsinternal.ScanResult) remediation {
+		// Commitments can't be cancelled, so the fix is to use them or buy less when they expire
+		switch detailString(r.Details, "finding_type") {
+		case "savings_plan":
+			recommended, _ := r.Details["recommended_hourly_commitment"].(float64)
+			return remediation{
+				description: fmt.Sprintf("Move eligible usage onto the Savings Plan before it expires on %s, and renew it at no more than $%.3f an hour",
+					detailString(r.Details, "end_date"), recommended),
+			}
+		case "coverage_gap":
+			return remediation{
+				description: fmt.Sprintf("Consider a Savings Plan for %s usage in %s; review AWS's purchase recommendation",
+					detailString(r.Details, "service"), detailString(r.Details, "commitment_region")),
+				commands: [][]string{{"ce", "get-savings-plans-purchase-recommendation", "--savings-plans-type", "COMPUTE_SP",
+					"--term-in-years", "ONE_YEAR", "--payment-option", "NO_UPFRONT", "--lookback-period-in-days", "THIRTY_DAYS"}},
+			}
+		}
+		return remediation{
+			description: fmt.Sprintf("Run matching %s usage in %s before the reservation expires on %s, exchange it if it is convertible, or sell it on the Reserved Instance Marketplace",
+				detailString(r.Details, "instance_type"), detailString(r.Details, "commitment_region"), detailString(r.Details, "end_date")),
+		}
+	},
+	"Redshift": func(r awsinternal.ScanResult) remediation {
+		// Reserved nodes can't be cancelled, only put to use until they expire
+		if detailString(r.Details, "finding_type") == "unused_reserved_nodes" {
+			return remediation{
+				description: fmt.Sprintf("Resize or create a cluster to use %d more %s nodes before the reservation expires on %s",
+					detailNumber(r.Details, "unused_node_count"), detailString(r.Details, "node_type"), detailString(r.Details, "expires_at")),
+			}
+		}
+		return remediation{
+			description: "Delete the serverless workgroup; its namespace and data are kept",
+			commands:    [][]string{{"redshift-serverless", "delete-workgroup", "--workgroup-name", r.ResourceName}},
+			dangerous:   true,
+		}
+	},
+	"Route53 Hosted Zones": func(r awsinternal.ScanResult) remediation {
+		// Zones with dangling aliases are still in use; the stale records need a person to review them
+		if _, ok := r.Details["dangling_records"]; ok {
+			return remediation{description: "Review and remove the dangling alias records"}
+		}
+		return remediation{
+			description: "Delete the empty hosted zone",
+			commands:    [][]string{{"route53", "delete-hosted-zone", "--id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"Security Groups": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the security group",
+			commands:    [][]string{{"ec2", "delete-security-group", "--group-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"Secrets Manager Secrets": func(r awsinternal.ScanResult) remediation {
+		if detailString(r.Details, "status") == "scheduled_for_deletion" {
+			return remediation{description: "Already scheduled for deletion; no action needed"}
+		}
+		// Deleted secrets can be restored during the recovery window
+		return remediation{
+			description: "Schedule the secret for deletion with a 30 day recovery window",
+			commands:    [][]string{{"secretsmanager", "delete-secret", "--secret-id", r.ARN, "--recovery-window-in-days", "30"}},
+			dangerous:   true,
+		}
+	},
+	"Shared Snapshots": func(r awsinternal.ScanResult) remediation {
+		// Removing the sharing can be undone, unlike deleting the snapshot, which is left to the owner
+		removePermission := func(grantees ...string) []string {
+			return append([]string{"ec2", "modify-snapshot-attribute", "--snapshot-id", r.ResourceID,
+				"--attribute", "createVolumePermission", "--operation-type", "remove"}, grantees...)
+		}
+		var commands [][]string
+		if public, _ := r.Details["public"].(bool); public {
+			commands = append(commands, removePermission("--group-names", "all"))
+		}
+		if accountIDs := detailStrings(r.Details, "grantee_account_ids"); len(accountIDs) > 0 {
+			commands = append(commands, removePermission(append([]string{"--user-ids"}, accountIDs...)...))
+		}
+		return remediation{
+			description: "Stop sharing the snapshot, then delete it if it's no longer needed",
+			commands:    commands,
+		}
+	},
+	"SNS Topics": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the topic and any remaining subscriptions",
+			commands:    [][]string{{"sns", "delete-topic", "--topic-arn", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"SQS Queues": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the queue",
+			commands:    [][]string{{"sqs", "delete-queue", "--queue-url", detailString(r.Details, "queue_url")}},
+			dangerous:   true,
+		}
+	},
+	"Step Functions State Machines": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the state machine; remove its EventBridge rules and CloudWatch alarms separately",
+			commands:    [][]string{{"stepfunctions", "delete-state-machine", "--state-machine-arn", r.ARN}},
+			dangerous:   true,
+		}
+	},
+	"Transit Gateways": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the transit gateway once its attachments are removed",
+			commands:    [][]string{{"ec2", "delete-transit-gateway", "--transit-gateway-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"VPCs": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the VPC once its subnets, gateways and endpoints are removed",
+			commands:    [][]string{{"ec2", "delete-vpc", "--vpc-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+	"VPN Connections": func(r awsinternal.ScanResult) remediation {
+		return remediation{
+			description: "Delete the VPN connection; its customer gateway can be deleted separately once unused",
+			commands:    [][]string{{"ec2", "delete-vpn-connection", "--vpn-connection-id", r.ResourceID}},
+			dangerous:   true,
+		}
+	},
+}
+
+// dbClusterRemediation stops an idle DocumentDB or Neptune cluster, or deletes a stopped one
+// along with its instances
+func dbClusterRemediation(service string, r awsinternal.ScanResult) remediation {
+	if detailString(r.Details, "status") != "stopped" {
+		return remediation{
+			description: "Stop the idle cluster",
+			commands:    [][]string{{service, "stop-db-cluster", "--db-cluster-identifier", r.ResourceID}},
+		}
+	}
+
+	// Clusters can only be deleted once their instances are gone, and instances only while the cluster is running
+	commands := [][]string{{service, "start-db-cluster", "--db-cluster-identifier", r.ResourceID}}
+	instanceIDs, _ := r.Details["instance_ids"].([]string)
+	for _, instanceID := range instanceIDs {
+		commands = append(commands, []string{service, "delete-db-instance", "--db-instance-identifier", instanceID})
+	}
+	commands = append(commands, []string{service, "delete-db-cluster", "--db-cluster-identifier", r.ResourceID, "--skip-final-snapshot"})
+
+	return remediation{
+		description: "Delete the stopped cluster and its instances",
+		commands:    commands,
+		dangerous:   true,
+	}
+}
+
+// detailString returns a string value from a finding's details, or "" when missing
+func detailString(details map[string]interface{}, key string) string {
+	value, _ := details[key].(string)
+	return value
+}
+
+// detailStrings returns a list of strings from a finding's details, or nil when missing. Lists
+// read back from JSON, such as checkpointed findings, hold interface{} values.
+func detailStrings(details map[string]interface{}, key string) []string {
+	switch value := details[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if str, ok := v.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// detailNumber returns an integer value from a finding's details, or 0 when missing
+func detailNumber(details map[string]interface{}, key string) int64 {
+	switch value := details[key].(type) {
+	case int:
+		return int64(value)
+	case int64:
+		return value
+	case float64:
+		return int64(value)
+	}
+	return 0
+}
+
+// shellQuote quotes an argument for a POSIX shell when it contains anything other than safe characters
+func shellQuote(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@,+", r))
+	}) == -1 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// BuildRemediationActions suggests a fix for each finding, ordered by account, resource type, region
+// and resource ID. Findings from scanners without a known fix get an action with no commands.
+func BuildRemediationActions(results []awsinternal.ScanResult) []RemediationAction {
+	actions := make([]RemediationAction, 0, len(results))
+	for _, result := range results {
+		region := result.Region
+		profile := detailString(result.Details, "profile")
+
+		fix := remediation{description: "No automated remediation available; review manually"}
+		if build, ok := remediationBuilders[result.ResourceType]; ok {
+			fix = build(result)
+		}
+
+		action := RemediationAction{
+			AccountID:    result.AccountID,
+			AccountName:  result.AccountName,
+			Profile:      profile,
+			Region:       region,
+			ResourceType: result.ResourceType,
+			ResourceID:   result.ResourceID,
+			ResourceName: result.ResourceName,
+			ARN:          result.ARN,
+			Description:  fix.description,
+			Confidence:   result.ConfidenceLevel(),
+			Commands:     []string{},
+			Dangerous:    fix.dangerous,
+		}
+
+		for _, args := range fix.commands {
+			command := []string{"aws"}
+			for _, arg := range args {
+				command = append(command, shellQuote(arg))
+			}
+			// Global resources such as IAM and Route53 don't take a region
+			if region != "" && region != "global" {
+				command = append(command, "--region", shellQuote(region))
+			}
+			if profile != "" {
+				command = append(command, "--profile", shellQuote(profile))
+			}
+			action.Commands = append(action.Commands, strings.Join(command, " "))
+		}
+
+		actions = append(actions, action)
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		a, b := actions[i], actions[j]
+		if a.AccountID != b.AccountID {
+			return a.AccountID < b.AccountID
+		}
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ResourceID < b.ResourceID
+	})
+
+	return actions
+}
+
+// WriteRemediationScript writes actions as a shell script. Dangerous commands are commented out
+// unless uncommentDangerous is set.
+func WriteRemediationScript(w io.Writer, actions []RemediationAction, uncommentDangerous bool) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Remediation commands suggested by CloudSift on %s\n", time.Now().UTC().Format(time.RFC3339))
+	b.WriteString("# Review every command before running this script. Commands that delete resources\n")
+	if uncommentDangerous {
+		b.WriteString("# are marked DANGEROUS and WILL RUN.\n")
+	} else {
+		b.WriteString("# are marked DANGEROUS and commented out; rerun with --remediation-uncomment to enable them.\n")
+	}
+	b.WriteString("# Commands without --profile use the credentials of the shell running the script.\n")
+	b.WriteString("set -e\n")
+
+	for _, action := range actions {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "# %s %s (%s) in account %s (%s), region %s, %s confidence\n",
+			action.ResourceType, action.ResourceID, action.ResourceName, action.AccountID, action.AccountName, action.Region, action.Confidence)
+		if action.Dangerous {
+			fmt.Fprintf(&b, "# DANGEROUS: %s\n", action.Description)
+		} else {
+			fmt.Fprintf(&b, "# %s\n", action.Description)
+		}
+		for _, command := range action.Commands {
+			if action.Dangerous && !uncommentDangerous {
+				b.WriteString("# ")
+			}
+			b.WriteString(command + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteRemediation writes suggested remediation commands for results to path: a JSON action list
+// when path ends in .json, otherwise a shell script
+func WriteRemediation(results []awsinternal.ScanResult, path string, uncommentDangerous bool) error {
+	actions := BuildRemediationActions(results)
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create remediation directory: %w", err)
+		}
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.MarshalIndent(actions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal remediation actions: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write remediation actions: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create remediation script: %w", err)
+	}
+	defer f.Close()
+
+	if err := WriteRemediationScript(f, actions, uncommentDangerous); err != nil {
+		return fmt.Errorf("failed to write remediation script: %w", err)
+	}
+	return nil
+}
@@ -6,16 +6,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	awsutil "cloudsift/internal/aws"
+	"cloudsift/internal/config"
+	"cloudsift/internal/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/schollz/progressbar/v3"
@@ -26,6 +31,7 @@ const (
 	defaultRetryDelay        = 2 * time.Second
 	defaultPartSize          = 5 * 1024 * 1024 // 5MB
 	defaultConcurrentUploads = 5
+	defaultWebhookTimeout    = 30 * time.Second
 )
 
 // RetryConfig holds retry configuration
@@ -48,18 +54,74 @@ const (
 	FileSystem Type = "filesystem"
 	// S3 represents S3 bucket output
 	S3 Type = "s3"
+	// HTTP represents an HTTP POST to a webhook URL
+	HTTP Type = "http"
 )
 
+// Layout is the key layout used for S3 output
+type Layout string
+
+const (
+	// FlatLayout writes one gzipped JSON document per account under YYYY/MM/DD/<accountId>/
+	FlatLayout Layout = "flat"
+	// PartitionedLayout writes gzipped JSON lines, one finding per line, under Hive-style
+	// scan_date=YYYY-MM-DD/account_id=<accountId>/ prefixes that Athena and Glue can prune
+	PartitionedLayout Layout = "partitioned"
+)
+
+// ValidateS3ACL checks that an ACL is a canned ACL S3 accepts for objects. An empty ACL is valid and
+// leaves objects with the bucket's default ownership.
+func ValidateS3ACL(acl string) error {
+	if acl == "" {
+		return nil
+	}
+	for _, valid := range s3.ObjectCannedACL_Values() {
+		if acl == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --s3-acl %q: must be one of %s", acl, strings.Join(s3.ObjectCannedACL_Values(), ", "))
+}
+
+// ValidateS3StorageClass checks that a storage class is one S3 accepts for uploads. An empty storage
+// class is valid and uses STANDARD.
+func ValidateS3StorageClass(storageClass string) error {
+	if storageClass == "" {
+		return nil
+	}
+	for _, valid := range s3.StorageClass_Values() {
+		if storageClass == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --s3-storage-class %q: must be one of %s", storageClass, strings.Join(s3.StorageClass_Values(), ", "))
+}
+
 // Config holds output configuration
 type Config struct {
 	Type             Type
 	S3Bucket         string
 	S3Region         string
+	S3KMSKeyID       string // KMS key used to encrypt S3 objects; the AWS managed key is used when empty
+	S3ACL            string // Canned ACL applied to S3 objects; none is sent when empty
+	S3StorageClass   string // Storage class of S3 objects; the bucket default is used when empty
+	Uncompressed     bool   // Write plain JSON instead of gzipping it; files and S3 objects are gzipped by default
 	OutputDir        string
 	Retry            *RetryConfig
 	Upload           *UploadConfig
 	Region           string
 	OrganizationRole string // Role to assume for S3 operations
+	// FilenameTemplate names output files, e.g. "{account_id}-{date}.json". See FilenameTemplateFields
+	// for the placeholders. The default YYYY/MM/DD/<accountId>/HH-MM-SS-0700.json.gz layout is used when empty.
+	FilenameTemplate string
+	// S3Layout selects the S3 key layout; FlatLayout is used when empty
+	S3Layout Layout
+	// WebhookURL is the URL results are POSTed to for HTTP output
+	WebhookURL string
+	// WebhookToken is sent as a bearer token with webhook requests; no Authorization header is sent when empty
+	WebhookToken string
+	// HTTPClient sends webhook requests; a client with a 30 second timeout is used when nil
+	HTTPClient *http.Client
 }
 
 // Writer handles writing scan results to different destinations
@@ -88,6 +150,10 @@ func NewWriter(config Config) *Writer {
 	if config.Type == FileSystem && config.OutputDir == "" {
 		config.OutputDir = "output"
 	}
+
+	if config.Type == HTTP && config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultWebhookTimeout}
+	}
 	return &Writer{config: config}
 }
 
@@ -101,27 +167,101 @@ func (w *Writer) getAccountID(accountID string) string {
 // getFilePath returns the file path in the format:
 // filesystem: output/YYYY/MM/DD/<accountId>/HH-MM-SS-0700.json.gz
 // s3: YYYY/MM/DD/<accountId>/HH-MM-SS-0700.json.gz
-func (w *Writer) getFilePath(accountID string, t time.Time) string {
+// When a filename template is configured it replaces everything below the output directory.
+func (w *Writer) getFilePath(accountID, accountName string, t time.Time) string {
 	// Extract just the numeric account ID
 	accountID = w.getAccountID(accountID)
 
-	// Format the filename with account ID and timestamp
-	fileName := t.Format("15-04-05-0700") + ".json.gz"
+	var relPath string
+	if w.config.FilenameTemplate != "" {
+		relPath = expandFilenameTemplate(w.config.FilenameTemplate, filenameValues{
+			accountID:   accountID,
+			accountName: accountName,
+			time:        t,
+			format:      "json",
+		})
+		// Gzipped output always ends in .gz, so make sure the name says so
+		relPath = w.compressedName(relPath)
+	} else {
+		// Format the filename with account ID and timestamp
+		fileName := w.compressedName(t.Format("15-04-05-0700") + ".json")
+
+		// Format the date path as YYYY/MM/DD
+		datePath := t.Format("2006/01/02")
 
-	// Format the date path as YYYY/MM/DD
-	datePath := t.Format("2006/01/02")
+		// Use the account ID as a folder under the date
+		relPath = filepath.Join(datePath, accountID, fileName)
+	}
 
-	// Construct the path
 	if w.config.Type == FileSystem {
-		// In filesystem, create the directory structure with account ID as a folder
-		return filepath.Join(w.config.OutputDir, datePath, accountID, fileName)
+		return filepath.Join(w.config.OutputDir, relPath)
 	}
 	// For S3, use the same structure without the base directory
-	return filepath.Join(datePath, accountID, fileName)
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(relPath)), "/")
 }
 
-// compressData compresses the input data using gzip
+// getPartitionedPath returns the S3 key for an account's findings in the partitioned layout:
+// scan_date=YYYY-MM-DD/account_id=<accountId>/results.jsonl.gz. A filename template names the
+// object inside the partition. Later scans on the same day replace the object, so each partition
+// holds one result set.
+func (w *Writer) getPartitionedPath(accountID, accountName string, t time.Time) string {
+	accountID = w.getAccountID(accountID)
+
+	fileName := "results.jsonl"
+	if w.config.FilenameTemplate != "" {
+		fileName = expandFilenameTemplate(w.config.FilenameTemplate, filenameValues{
+			accountID:   accountID,
+			accountName: accountName,
+			time:        t,
+			format:      "jsonl",
+		})
+	}
+	fileName = w.compressedName(fileName)
+
+	relPath := filepath.Join("scan_date="+t.Format("2006-01-02"), "account_id="+accountID, fileName)
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(relPath)), "/")
+}
+
+// partitionedRecord is one finding in the partitioned layout. Monthly cost is lifted out of the
+// nested cost map so queries don't have to parse it.
+type partitionedRecord struct {
+	awsutil.ScanResult
+	MonthlyCost float64   `json:"monthly_cost"`
+	Currency    string    `json:"currency"` // Currency of monthly_cost
+	ScannedAt   time.Time `json:"scanned_at"`
+}
+
+// newPartitionedRecord builds the partitioned layout record for a finding
+func newPartitionedRecord(result awsutil.ScanResult, scannedAt time.Time) partitionedRecord {
+	record := partitionedRecord{ScanResult: result, ScannedAt: scannedAt, Currency: awsutil.BaseCurrency}
+	switch total := result.Cost["total"].(type) {
+	case *awsutil.CostBreakdown:
+		if total != nil {
+			record.MonthlyCost = total.MonthlyRate
+			if total.Currency != "" {
+				record.Currency = total.Currency
+			}
+		}
+	case awsutil.CostBreakdown:
+		record.MonthlyCost = total.MonthlyRate
+	}
+	return record
+}
+
+// compressedName appends .gz to a file name when output is gzipped and it doesn't already end in it
+func (w *Writer) compressedName(name string) string {
+	if w.config.Uncompressed || strings.HasSuffix(name, ".gz") {
+		return name
+	}
+	return name + ".gz"
+}
+
+// compressData compresses the input data using gzip, or returns it as is when output is uncompressed
 func (w *Writer) compressData(data []byte) ([]byte, error) {
+	if w.config.Uncompressed {
+		return data, nil
+	}
+
 	var buf bytes.Buffer
 	gz := gzip.NewWriter(&buf)
 
@@ -136,14 +276,19 @@ func (w *Writer) compressData(data []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Write writes the scan results to the configured destination
-func (w *Writer) Write(accountID string, results interface{}) error {
+// Write writes the scan results for an account to the configured destination
+func (w *Writer) Write(accountID, accountName string, results interface{}) error {
 	// Convert results to JSON
 	data, err := json.MarshalIndent(results, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal results: %w", err)
 	}
 
+	// Webhooks receive the JSON document as is
+	if w.config.Type == HTTP {
+		return w.postWithRetry(accountID, data)
+	}
+
 	// Compress the data
 	compressedData, err := w.compressData(data)
 	if err != nil {
@@ -151,7 +296,7 @@ func (w *Writer) Write(accountID string, results interface{}) error {
 	}
 
 	now := time.Now()
-	path := w.getFilePath(accountID, now)
+	path := w.getFilePath(accountID, accountName, now)
 
 	switch w.config.Type {
 	case FileSystem:
@@ -163,7 +308,44 @@ func (w *Writer) Write(accountID string, results interface{}) error {
 	}
 }
 
-// writeToFileSystem writes compressed data to the local filesystem
+// CombinedName stands in for the account ID and name in the path of a combined report
+const CombinedName = "combined"
+
+// WriteCombined writes results covering every account to a single file. The path is built like a
+// per-account path with CombinedName in place of the account, e.g. YYYY/MM/DD/combined/HH-MM-SS-0700.json.gz.
+func (w *Writer) WriteCombined(results interface{}) error {
+	return w.Write(CombinedName, CombinedName, results)
+}
+
+// WriteRecords writes an account's findings to S3 as gzipped JSON lines in the partitioned layout
+func (w *Writer) WriteRecords(accountID, accountName string, results []awsutil.ScanResult) error {
+	now := time.Now()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, result := range results {
+		if err := encoder.Encode(newPartitionedRecord(result, now)); err != nil {
+			return fmt.Errorf("failed to marshal result %s: %w", result.ResourceID, err)
+		}
+	}
+
+	compressedData, err := w.compressData(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	path := w.getPartitionedPath(accountID, accountName, now)
+	switch w.config.Type {
+	case FileSystem:
+		return w.writeToFileSystem(filepath.Join(w.config.OutputDir, path), compressedData)
+	case S3:
+		return w.writeToS3WithRetry(path, compressedData)
+	default:
+		return fmt.Errorf("unsupported output type: %s", w.config.Type)
+	}
+}
+
+// writeToFileSystem writes data to the local filesystem
 func (w *Writer) writeToFileSystem(path string, data []byte) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(path)
@@ -203,10 +385,72 @@ func (w *Writer) writeToS3WithRetry(path string, data []byte) error {
 		w.config.Retry.MaxRetries, lastErr)
 }
 
+// postWithRetry POSTs an account's JSON results to the webhook URL. Network errors, 429 and 5xx
+// responses are retried; other non-2xx responses fail straight away, since repeating them won't help.
+func (w *Writer) postWithRetry(accountID string, data []byte) error {
+	if w.config.WebhookURL == "" {
+		return fmt.Errorf("webhook URL not specified")
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < w.config.Retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			logging.Warn("Retrying webhook request", map[string]interface{}{
+				"account_id": accountID,
+				"attempt":    attempt + 1,
+				"error":      lastErr.Error(),
+			})
+			time.Sleep(w.config.Retry.RetryDelay)
+		}
+
+		retryable, err := w.post(accountID, data)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+	}
+	return fmt.Errorf("failed to post results to webhook: %w", lastErr)
+}
+
+// post sends one webhook request, reporting whether a failure is worth retrying
+func (w *Writer) post(accountID string, data []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.config.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Cloudsift-Account-Id", w.getAccountID(accountID))
+	if w.config.WebhookToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.config.WebhookToken)
+	}
+
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	// Include the start of the response body, which usually says what was wrong
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	logging.Warn("Webhook returned a non-2xx response", map[string]interface{}{
+		"account_id":  accountID,
+		"status_code": resp.StatusCode,
+	})
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
 // getRoleARN returns the full ARN for a role. If the input is already an ARN, returns it as is.
 func getRoleARN(sess *session.Session, roleName string) (string, error) {
 	// If it's already an ARN, return it
-	if strings.HasPrefix(roleName, "arn:aws:iam::") {
+	if arn.IsARN(roleName) {
 		return roleName, nil
 	}
 
@@ -217,8 +461,8 @@ func getRoleARN(sess *session.Session, roleName string) (string, error) {
 		return "", fmt.Errorf("failed to get account ID: %w", err)
 	}
 
-	// Construct the role ARN
-	return fmt.Sprintf("arn:aws:iam::%s:role/%s", *result.Account, roleName), nil
+	// Construct the role ARN in the caller's partition
+	return awsutil.RoleARN(awsutil.PartitionFromARN(aws.StringValue(result.Arn)), *result.Account, roleName), nil
 }
 
 // writeToS3 writes data to an S3 bucket with progress tracking
@@ -245,6 +489,7 @@ func (w *Writer) writeToS3(path string, data []byte) error {
 		input := &sts.AssumeRoleInput{
 			RoleArn:         aws.String(roleARN),
 			RoleSessionName: aws.String(roleSessionName),
+			DurationSeconds: aws.Int64(int64(awsutil.SessionDuration(len(config.Config.AssumeRoleChain) > 0).Seconds())),
 		}
 
 		// Assume the role
@@ -291,12 +536,26 @@ func (w *Writer) writeToS3(path string, data []byte) error {
 	}
 
 	// Upload the file with server-side encryption
-	_, err = uploader.Upload(&s3manager.UploadInput{
+	input := &s3manager.UploadInput{
 		Bucket:               aws.String(w.config.S3Bucket),
 		Key:                  aws.String(path),
 		Body:                 reader,
 		ServerSideEncryption: aws.String("aws:kms"),
-	})
+		ContentType:          aws.String("application/json"),
+	}
+	if !w.config.Uncompressed {
+		input.ContentEncoding = aws.String("gzip")
+	}
+	if w.config.S3KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(w.config.S3KMSKeyID)
+	}
+	if w.config.S3ACL != "" {
+		input.ACL = aws.String(w.config.S3ACL)
+	}
+	if w.config.S3StorageClass != "" {
+		input.StorageClass = aws.String(w.config.S3StorageClass)
+	}
+	_, err = uploader.Upload(input)
 
 	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
@@ -16,16 +16,26 @@ var (
 	GoVersion string
 )
 
+// devVersion is reported when the version was not injected at build time
+const devVersion = "dev"
+
 // String returns the full version string
 func String() string {
 	if GitCommit != "" && BuildTime != "" {
+		commit := GitCommit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
 		return fmt.Sprintf("%s (commit: %s built: %s with: %s)",
-			Version, GitCommit[:8], BuildTime, GoVersion)
+			ShortString(), commit, BuildTime, GoVersion)
 	}
-	return Version
+	return ShortString()
 }
 
 // ShortString returns just the version number
 func ShortString() string {
+	if Version == "" {
+		return devVersion
+	}
 	return Version
 }
@@ -8,6 +8,22 @@ import (
 	"time"
 
 	"cloudsift/internal/config"
+	"cloudsift/internal/logging"
+)
+
+// DefaultTaskTimeout is the maximum time a single task may run before its context is cancelled.
+// It is generous enough to accommodate rate limiting backoff.
+const DefaultTaskTimeout = 3 * time.Minute
+
+const (
+	// throttleWindow is the period over which throttle events are counted
+	throttleWindow = 10 * time.Second
+
+	// throttleThreshold is the number of throttle events within throttleWindow that halves concurrency
+	throttleThreshold = 5
+
+	// recoveryInterval is how long concurrency must go without throttling before it grows by one task
+	recoveryInterval = 30 * time.Second
 )
 
 // TaskMetrics tracks performance metrics for a task
@@ -28,6 +44,9 @@ type PoolMetrics struct {
 	PeakWorkers        int64
 	AverageExecutionMs int64
 	TotalExecutionMs   int64
+	ThrottleEvents     int64 // AWS requests that were throttled while tasks ran
+	ThrottleReductions int64 // Times concurrency was reduced because of sustained throttling
+	ConcurrencyLimit   int64 // Number of tasks currently allowed to run at once
 	mu                 sync.RWMutex
 }
 
@@ -47,18 +66,47 @@ type Pool struct {
 	metrics       *PoolMetrics
 	activeWorkers int64
 	stopping      int32 // Using atomic for thread-safe access
+	taskTimeout   int64 // Task timeout in nanoseconds, using atomic for thread-safe access
+
+	// Adaptive concurrency: sustained throttling halves the number of tasks allowed to run at once,
+	// which then grows back one task at a time once throttling stops
+	limitMu        sync.Mutex
+	limitCond      *sync.Cond
+	limit          int         // Tasks currently allowed to run at once
+	running        int         // Tasks currently running
+	throttleEvents []time.Time // Throttle events within the current throttle window
+	lastThrottle   time.Time
+	lastAdjust     time.Time
 }
 
 // NewPool creates a new worker pool with the specified number of workers
 func NewPool(maxWorkers int) *Pool {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Pool{
-		maxWorkers: maxWorkers,
-		tasks:      make(chan Task, maxWorkers*2), // Buffer the channel to prevent blocking
-		ctx:        ctx,
-		cancel:     cancel,
-		metrics:    &PoolMetrics{},
+	p := &Pool{
+		maxWorkers:  maxWorkers,
+		tasks:       make(chan Task, maxWorkers*2), // Buffer the channel to prevent blocking
+		ctx:         ctx,
+		cancel:      cancel,
+		metrics:     &PoolMetrics{},
+		taskTimeout: int64(DefaultTaskTimeout),
+		limit:       maxWorkers,
 	}
+	p.limitCond = sync.NewCond(&p.limitMu)
+	return p
+}
+
+// SetTaskTimeout changes the maximum time a single task may run.
+// Tasks that are already running keep the timeout they started with.
+func (p *Pool) SetTaskTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultTaskTimeout
+	}
+	atomic.StoreInt64(&p.taskTimeout, int64(timeout))
+}
+
+// TaskTimeout returns the maximum time a single task may run
+func (p *Pool) TaskTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&p.taskTimeout))
 }
 
 // Start starts the worker pool
@@ -100,7 +148,92 @@ func (p *Pool) GetMetrics() PoolMetrics {
 		PeakWorkers:        p.metrics.PeakWorkers,
 		AverageExecutionMs: p.metrics.TotalExecutionMs / max(p.metrics.CompletedTasks, 1),
 		TotalExecutionMs:   p.metrics.TotalExecutionMs,
+		ThrottleEvents:     atomic.LoadInt64(&p.metrics.ThrottleEvents),
+		ThrottleReductions: atomic.LoadInt64(&p.metrics.ThrottleReductions),
+		ConcurrencyLimit:   int64(p.concurrencyLimit()),
+	}
+}
+
+// ReportThrottle records that an AWS request was throttled. When throttling is sustained, the
+// number of tasks allowed to run at once is halved so the pool stops making it worse.
+func (p *Pool) ReportThrottle() {
+	atomic.AddInt64(&p.metrics.ThrottleEvents, 1)
+
+	p.limitMu.Lock()
+	defer p.limitMu.Unlock()
+
+	now := time.Now()
+	p.lastThrottle = now
+
+	// Only count events within the throttle window
+	recent := p.throttleEvents[:0]
+	for _, t := range p.throttleEvents {
+		if now.Sub(t) < throttleWindow {
+			recent = append(recent, t)
+		}
+	}
+	p.throttleEvents = append(recent, now)
+
+	if len(p.throttleEvents) < throttleThreshold || p.limit == 1 {
+		return
+	}
+
+	previous := p.limit
+	p.limit = int(max(int64(p.limit/2), 1))
+	p.throttleEvents = p.throttleEvents[:0]
+	p.lastAdjust = now
+	atomic.AddInt64(&p.metrics.ThrottleReductions, 1)
+
+	logging.Warn("Reducing worker concurrency due to AWS throttling", map[string]interface{}{
+		"previous_limit": previous,
+		"new_limit":      p.limit,
+		"max_workers":    p.maxWorkers,
+	})
+}
+
+// concurrencyLimit returns the number of tasks currently allowed to run at once
+func (p *Pool) concurrencyLimit() int {
+	p.limitMu.Lock()
+	defer p.limitMu.Unlock()
+	return p.limit
+}
+
+// recoverConcurrency grows the concurrency limit by one task once throttling has stopped for a
+// while. Must be called with limitMu held.
+func (p *Pool) recoverConcurrency(now time.Time) {
+	if p.limit >= p.maxWorkers || now.Sub(p.lastAdjust) < recoveryInterval || now.Sub(p.lastThrottle) < recoveryInterval {
+		return
+	}
+
+	p.limit++
+	p.lastAdjust = now
+
+	logging.Debug("Increasing worker concurrency after throttling subsided", map[string]interface{}{
+		"new_limit":   p.limit,
+		"max_workers": p.maxWorkers,
+	})
+}
+
+// acquire blocks until the concurrency limit allows another task to run
+func (p *Pool) acquire() {
+	p.limitMu.Lock()
+	defer p.limitMu.Unlock()
+
+	p.recoverConcurrency(time.Now())
+	for p.running >= p.limit {
+		p.limitCond.Wait()
 	}
+	p.running++
+}
+
+// release marks a task started with acquire as finished
+func (p *Pool) release() {
+	p.limitMu.Lock()
+	p.running--
+	p.recoverConcurrency(time.Now())
+	p.limitMu.Unlock()
+
+	p.limitCond.Broadcast()
 }
 
 func max(a, b int64) int64 {
@@ -164,15 +297,19 @@ func (p *Pool) worker() {
 				return
 			}
 
+			// Wait for a slot under the adaptive concurrency limit
+			p.acquire()
+
 			// Track task metrics
 			start := time.Now()
 
 			// Create a child context for the task that is cancelled when either:
 			// 1. The pool is stopping (p.ctx is cancelled)
-			// 2. The task times out (3 minute timeout to accommodate rate limiting backoff)
-			taskCtx, cancel := context.WithTimeout(p.ctx, 3*time.Minute)
+			// 2. The task times out (defaults to 3 minutes to accommodate rate limiting backoff)
+			taskCtx, cancel := context.WithTimeout(p.ctx, p.TaskTimeout())
 			err := task(taskCtx)
 			cancel()
+			p.release()
 
 			executionMs := time.Since(start).Milliseconds()
 
@@ -195,7 +332,7 @@ func (p *Pool) worker() {
 						return
 					}
 					// Create a new timeout context since pool context is already cancelled
-					taskCtx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+					taskCtx, cancel := context.WithTimeout(context.Background(), p.TaskTimeout())
 					if err := task(taskCtx); err != nil {
 						atomic.AddInt64(&p.metrics.FailedTasks, 1)
 					}
@@ -256,6 +393,90 @@ func (p *Pool) ExecuteTasks(tasks []Task) {
 	wg.Wait()
 }
 
+// KeyedTask is a task that shares concurrency limits with other tasks of the same key and group.
+// In a scan the key is the account and the group is the region.
+type KeyedTask struct {
+	Key   string
+	Group string
+	Task  Task
+}
+
+// ExecuteKeyedTasks executes tasks like ExecuteTasks, but runs at most perKeyLimit tasks with the
+// same key and at most perGroupLimit tasks in the same group at once. Tasks are handed out
+// round-robin across groups so one slow group can't fill the pool ahead of the others. Tasks over
+// a limit wait in a queue without holding a worker, so other tasks keep running. A limit of 0 or
+// less means no limit.
+func (p *Pool) ExecuteKeyedTasks(tasks []KeyedTask, perKeyLimit, perGroupLimit int) {
+	// Update total task count
+	p.metrics.mu.Lock()
+	p.metrics.TotalTasks += int64(len(tasks))
+	p.metrics.mu.Unlock()
+
+	// Queue tasks per group, keeping groups in the order they first appear
+	queues := make(map[string][]KeyedTask)
+	var groups []string
+	for _, t := range tasks {
+		if _, ok := queues[t.Group]; !ok {
+			groups = append(groups, t.Group)
+		}
+		queues[t.Group] = append(queues[t.Group], t)
+	}
+
+	// Finished tasks report themselves here; buffered so workers never block on it
+	finished := make(chan KeyedTask, len(tasks))
+	runningKeys := make(map[string]int)
+	runningGroups := make(map[string]int)
+
+	// next removes and returns the first queued task in a group whose key is under its limit
+	next := func(group string) (KeyedTask, bool) {
+		if perGroupLimit > 0 && runningGroups[group] >= perGroupLimit {
+			return KeyedTask{}, false
+		}
+		for i, t := range queues[group] {
+			if perKeyLimit > 0 && runningKeys[t.Key] >= perKeyLimit {
+				continue
+			}
+			queues[group] = append(queues[group][:i], queues[group][i+1:]...)
+			return t, true
+		}
+		return KeyedTask{}, false
+	}
+
+	// dispatch submits one task per group in turn until every group is empty or at a limit. Only
+	// this goroutine submits, so a full task channel never blocks a worker that is trying to finish.
+	dispatch := func() {
+		for submitted := true; submitted; {
+			submitted = false
+			for _, group := range groups {
+				t, ok := next(group)
+				if !ok {
+					continue
+				}
+				runningKeys[t.Key]++
+				runningGroups[t.Group]++
+				submitted = true
+
+				p.Submit(func(ctx context.Context) error {
+					defer func() { finished <- t }()
+					return t.Task(ctx)
+				})
+			}
+		}
+	}
+
+	dispatch()
+	for remaining := len(tasks); remaining > 0; remaining-- {
+		select {
+		case t := <-finished:
+			runningKeys[t.Key]--
+			runningGroups[t.Group]--
+			dispatch()
+		case <-p.ctx.Done():
+			return // Pool is shutting down
+		}
+	}
+}
+
 var (
 	// singleton instance of the pool
 	sharedPool *Pool
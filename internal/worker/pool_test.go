@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReportThrottle tests that sustained throttling halves the concurrency limit, never below one
+func TestReportThrottle(t *testing.T) {
+	pool := NewPool(8)
+
+	// Fewer throttles than the threshold leave the limit alone
+	for i := 0; i < throttleThreshold-1; i++ {
+		pool.ReportThrottle()
+	}
+	metrics := pool.GetMetrics()
+	assert.Equal(t, int64(8), metrics.ConcurrencyLimit)
+	assert.Equal(t, int64(throttleThreshold-1), metrics.ThrottleEvents)
+	assert.Equal(t, int64(0), metrics.ThrottleReductions)
+
+	pool.ReportThrottle()
+	metrics = pool.GetMetrics()
+	assert.Equal(t, int64(4), metrics.ConcurrencyLimit)
+	assert.Equal(t, int64(1), metrics.ThrottleReductions)
+
+	// Each reduction starts a new count, and the limit bottoms out at one
+	for i := 0; i < throttleThreshold*5; i++ {
+		pool.ReportThrottle()
+	}
+	metrics = pool.GetMetrics()
+	assert.Equal(t, int64(1), metrics.ConcurrencyLimit)
+	assert.Equal(t, int64(3), metrics.ThrottleReductions, "8 -> 4 -> 2 -> 1, then no more reductions")
+	assert.Equal(t, int64(throttleThreshold*6), metrics.ThrottleEvents)
+}
+
+// TestRecoverConcurrency tests that the limit grows back one task per quiet interval up to maxWorkers
+func TestRecoverConcurrency(t *testing.T) {
+	pool := NewPool(3)
+	for i := 0; i < throttleThreshold; i++ {
+		pool.ReportThrottle()
+	}
+	assert.Equal(t, int64(1), pool.GetMetrics().ConcurrencyLimit)
+
+	pool.limitMu.Lock()
+	start := pool.lastThrottle
+
+	// Nothing recovers until throttling has stopped for a full interval
+	pool.recoverConcurrency(start.Add(recoveryInterval / 2))
+	assert.Equal(t, 1, pool.limit)
+
+	pool.recoverConcurrency(start.Add(recoveryInterval))
+	assert.Equal(t, 2, pool.limit)
+
+	// A second step needs another interval since the last adjustment
+	pool.recoverConcurrency(start.Add(recoveryInterval + time.Second))
+	assert.Equal(t, 2, pool.limit)
+
+	for i := 2; i <= 5; i++ {
+		pool.recoverConcurrency(start.Add(time.Duration(i) * recoveryInterval))
+	}
+	assert.Equal(t, 3, pool.limit, "recovery stops at maxWorkers")
+	pool.limitMu.Unlock()
+}
+
+// TestAcquireWaitsForRelease tests that a task over the concurrency limit waits until a running
+// task releases its slot
+func TestAcquireWaitsForRelease(t *testing.T) {
+	pool := NewPool(1)
+	pool.acquire()
+
+	acquired := make(chan struct{})
+	go func() {
+		pool.acquire()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire must block while the only slot is taken")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	pool.release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("release must wake the waiting task")
+	}
+	pool.release()
+}
+
+// TestExecuteKeyedTasksPerKeyLimit tests that no more than perKeyLimit tasks with the same key run
+// at once, even with workers to spare
+func TestExecuteKeyedTasksPerKeyLimit(t *testing.T) {
+	pool := NewPool(8)
+	pool.Start()
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	running := make(map[string]int)
+	peak := make(map[string]int)
+	done := 0
+
+	var tasks []KeyedTask
+	for i := 0; i < 24; i++ {
+		key := fmt.Sprintf("account-%d", i%2)
+		tasks = append(tasks, KeyedTask{
+			Key:   key,
+			Group: fmt.Sprintf("region-%d", i%3),
+			Task: func(ctx context.Context) error {
+				mu.Lock()
+				running[key]++
+				if running[key] > peak[key] {
+					peak[key] = running[key]
+				}
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				defer mu.Unlock()
+				running[key]--
+				done++
+				return nil
+			},
+		})
+	}
+
+	pool.ExecuteKeyedTasks(tasks, 2, 0)
+
+	assert.Equal(t, len(tasks), done)
+	assert.Equal(t, map[string]int{"account-0": 2, "account-1": 2}, peak)
+	assert.Equal(t, int64(len(tasks)), pool.GetMetrics().TotalTasks)
+}
+
+// TestExecuteKeyedTasksPerGroupLimit tests that a slow group is held to perGroupLimit tasks at once,
+// leaving the rest of the pool free for other groups to finish
+func TestExecuteKeyedTasksPerGroupLimit(t *testing.T) {
+	pool := NewPool(4)
+	pool.Start()
+	defer pool.Stop()
+
+	var mu sync.Mutex
+	slowRunning, slowPeak, fastDone := 0, 0, 0
+	unblock := make(chan struct{})
+	fastFinished := make(chan struct{})
+
+	var tasks []KeyedTask
+	for i := 0; i < 6; i++ {
+		tasks = append(tasks, KeyedTask{
+			Key:   fmt.Sprintf("account-%d", i),
+			Group: "slow",
+			Task: func(ctx context.Context) error {
+				mu.Lock()
+				slowRunning++
+				if slowRunning > slowPeak {
+					slowPeak = slowRunning
+				}
+				mu.Unlock()
+
+				<-unblock
+
+				mu.Lock()
+				slowRunning--
+				mu.Unlock()
+				return nil
+			},
+		})
+	}
+	for i := 0; i < 6; i++ {
+		tasks = append(tasks, KeyedTask{
+			Key:   fmt.Sprintf("account-%d", i),
+			Group: "fast",
+			Task: func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				fastDone++
+				if fastDone == 6 {
+					close(fastFinished)
+				}
+				return nil
+			},
+		})
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		pool.ExecuteKeyedTasks(tasks, 0, 2)
+		close(finished)
+	}()
+
+	// The fast group finishes while the slow group still holds its two slots
+	select {
+	case <-fastFinished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast group did not finish while the slow group was blocked")
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slowRunning == 2
+	}, time.Second, 5*time.Millisecond)
+
+	close(unblock)
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow group did not finish once unblocked")
+	}
+	assert.Equal(t, 2, slowPeak, "the slow group never runs more than perGroupLimit tasks")
+}
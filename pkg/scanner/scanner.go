@@ -0,0 +1,69 @@
+// Package scanner lets programs that embed CloudSift add their own resource scanners.
+//
+// Register scanners from your own main package before running the CLI. They are listed by
+// "cloudsift list scanners", run by "cloudsift scan" by default, and can be selected with
+// --scanners like any built-in scanner:
+//
+//	func main() {
+//		if err := scanner.Register("idle-widgets", func() scanner.Scanner {
+//			return &WidgetScanner{}
+//		}); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := cmd.Execute(); err != nil {
+//			os.Exit(1)
+//		}
+//	}
+package scanner
+
+import (
+	awsinternal "cloudsift/internal/aws"
+)
+
+// Scanner is implemented by resource scanners. ArgumentName must return the name the scanner is
+// registered under, Label a human-readable name that is also used as each result's ResourceType,
+// and IsGlobal whether the scanner covers a global service and so runs once per account rather
+// than once per region.
+//
+// Scan is called concurrently for each account and region being scanned. Its ScanOptions carry a
+// session already configured for the account's role chain and region, and a context that should
+// be passed to AWS API calls so scanner timeouts and cancellation are honored. Scan should return
+// only unused resources; returning an error marks that account and region as failed for the
+// scanner without stopping the rest of the scan.
+type Scanner = awsinternal.Scanner
+
+// Factory creates a scanner. It is called once, when the scanner is registered.
+type Factory = awsinternal.ScannerFactory
+
+// ScanOptions is passed to Scanner.Scan
+type ScanOptions = awsinternal.ScanOptions
+
+// ScanResult is a single unused resource found by a scanner
+type ScanResult = awsinternal.ScanResult
+
+// ScanResults is the list of resources a scanner returns
+type ScanResults = awsinternal.ScanResults
+
+// CostBreakdown is the estimated cost of a resource, stored under the "total" key of
+// ScanResult.Cost
+type CostBreakdown = awsinternal.CostBreakdown
+
+// Confidence levels for ScanResult.Confidence, from near-certain waste to a judgment call worth a
+// manual review. Results that leave it empty are treated as ConfidenceMedium.
+const (
+	ConfidenceHigh   = awsinternal.ConfidenceHigh
+	ConfidenceMedium = awsinternal.ConfidenceMedium
+	ConfidenceLow    = awsinternal.ConfidenceLow
+)
+
+// Register adds a scanner to the set CloudSift runs. Names may contain lowercase letters, digits
+// and hyphens. It returns an error if the name is invalid, doesn't match the scanner's
+// ArgumentName, or is already taken by a built-in or previously registered scanner.
+func Register(name string, factory Factory) error {
+	return awsinternal.DefaultRegistry.RegisterScannerFactory(name, factory)
+}
+
+// Names returns the sorted names of all registered scanners, including built-in ones
+func Names() []string {
+	return awsinternal.DefaultRegistry.ListScanners()
+}